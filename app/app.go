@@ -7,9 +7,10 @@ import (
 	"runtime"
 	"time"
 
+	"github.com/Hoosat-Oy/HTND/app/dbmigration"
 	"github.com/Hoosat-Oy/HTND/infrastructure/config"
 	"github.com/Hoosat-Oy/HTND/infrastructure/db/database"
-	"github.com/Hoosat-Oy/HTND/infrastructure/db/database/ldb"
+	"github.com/Hoosat-Oy/HTND/infrastructure/db/database/backend"
 	"github.com/Hoosat-Oy/HTND/infrastructure/logger"
 	"github.com/Hoosat-Oy/HTND/infrastructure/os/execenv"
 	"github.com/Hoosat-Oy/HTND/infrastructure/os/limits"
@@ -21,8 +22,7 @@ import (
 )
 
 const (
-	leveldbCacheSizeMiB = 256
-	defaultDataDirname  = "datadir2"
+	defaultDataDirname = "datadir2"
 )
 
 var desiredLimits = &limits.DesiredLimits{
@@ -122,6 +122,24 @@ func (app *htndApp) main(startedChan chan<- struct{}) error {
 		return nil
 	}
 
+	err = dbmigration.Run(databaseContext, app.cfg.DryRunMigrations)
+	if err != nil {
+		log.Errorf("Migrating the database failed: %+v", err)
+		return err
+	}
+	if app.cfg.DryRunMigrations {
+		return nil
+	}
+
+	if app.cfg.ExportSnapshot != "" || app.cfg.ExportBlocks != "" {
+		err := export(app.cfg, databaseContext)
+		if err != nil {
+			log.Errorf("Exporting failed: %+v", err)
+			return err
+		}
+		return nil
+	}
+
 	// Create componentManager and start it.
 	componentManager, err := NewComponentManager(app.cfg, databaseContext, interrupt)
 	if err != nil {
@@ -180,7 +198,7 @@ func openDB(cfg *config.Config) (database.Database, error) {
 	}
 
 	log.Infof("Loading database from '%s'", dbPath)
-	db, err := ldb.NewLevelDB(dbPath, leveldbCacheSizeMiB)
+	db, err := backend.Open(cfg.DbType, dbPath, cfg.DatabaseCacheMiB, cfg.DbSync)
 	if err != nil {
 		return nil, err
 	}
@@ -460,6 +460,38 @@ func BlockWithTrustedDataToDomainBlockWithTrustedData(block *MsgBlockWithTrusted
 	}
 }
 
+// BlockWithTrustedDataV4ToDomainBlockWithTrustedData converts *MsgBlockWithTrustedDataV4 and the *MsgTrustedData
+// its indices point into to *externalapi.BlockWithTrustedData
+func BlockWithTrustedDataV4ToDomainBlockWithTrustedData(block *MsgBlockWithTrustedDataV4,
+	data *MsgTrustedData) (*externalapi.BlockWithTrustedData, error) {
+
+	blockWithTrustedData := &externalapi.BlockWithTrustedData{
+		Block:        MsgBlockToDomainBlock(block.Block),
+		DAAWindow:    make([]*externalapi.TrustedDataDataDAAHeader, 0, len(block.DAAWindowIndices)),
+		GHOSTDAGData: make([]*externalapi.BlockGHOSTDAGDataHashPair, 0, len(block.GHOSTDAGDataIndices)),
+	}
+
+	for _, index := range block.DAAWindowIndices {
+		if index >= uint64(len(data.DAAWindow)) {
+			return nil, errors.Errorf("DAA window index %d is out of range of the %d trusted DAA window blocks",
+				index, len(data.DAAWindow))
+		}
+		blockWithTrustedData.DAAWindow = append(blockWithTrustedData.DAAWindow,
+			TrustedDataDataDAABlockV4ToTrustedDataDataDAAHeader(data.DAAWindow[index]))
+	}
+
+	for _, index := range block.GHOSTDAGDataIndices {
+		if index >= uint64(len(data.GHOSTDAGData)) {
+			return nil, errors.Errorf("GHOSTDAG data index %d is out of range of the %d trusted GHOSTDAG data entries",
+				index, len(data.GHOSTDAGData))
+		}
+		blockWithTrustedData.GHOSTDAGData = append(blockWithTrustedData.GHOSTDAGData,
+			GHOSTDAGHashPairToDomainGHOSTDAGHashPair(data.GHOSTDAGData[index]))
+	}
+
+	return blockWithTrustedData, nil
+}
+
 // TrustedDataDataDAABlockV4ToTrustedDataDataDAAHeader converts *TrustedDataDAAHeader to *externalapi.TrustedDataDataDAAHeader
 func TrustedDataDataDAABlockV4ToTrustedDataDataDAAHeader(daaBlock *TrustedDataDAAHeader) *externalapi.TrustedDataDataDAAHeader {
 	return &externalapi.TrustedDataDataDAAHeader{
@@ -73,6 +73,9 @@ const (
 	CmdRequestIBDChainBlockLocator
 	CmdIBDChainBlockLocator
 	CmdRequestAnticone
+	CmdCompactBlock
+	CmdRequestBlockTransactions
+	CmdBlockTransactions
 
 	// rpc
 	CmdGetCurrentNetworkRequestMessage
@@ -163,6 +166,20 @@ const (
 	CmdGetMempoolEntriesByAddressesResponseMessage
 	CmdGetCoinSupplyRequestMessage
 	CmdGetCoinSupplyResponseMessage
+	CmdGetBansRequestMessage
+	CmdGetBansResponseMessage
+	CmdGetWhitelistRequestMessage
+	CmdGetWhitelistResponseMessage
+	CmdAddToWhitelistRequestMessage
+	CmdAddToWhitelistResponseMessage
+	CmdRemoveFromWhitelistRequestMessage
+	CmdRemoveFromWhitelistResponseMessage
+	CmdGetCacheStatsRequestMessage
+	CmdGetCacheStatsResponseMessage
+	CmdGetDatabaseStatsRequestMessage
+	CmdGetDatabaseStatsResponseMessage
+	CmdCompactDatabaseRequestMessage
+	CmdCompactDatabaseResponseMessage
 )
 
 // ProtocolMessageCommandToString maps all MessageCommands to their string representation
@@ -210,6 +227,9 @@ var ProtocolMessageCommandToString = map[MessageCommand]string{
 	CmdRequestIBDChainBlockLocator:                 "RequestIBDChainBlockLocator",
 	CmdIBDChainBlockLocator:                        "IBDChainBlockLocator",
 	CmdRequestAnticone:                             "RequestAnticone",
+	CmdCompactBlock:                                "CompactBlock",
+	CmdRequestBlockTransactions:                    "RequestBlockTransactions",
+	CmdBlockTransactions:                           "BlockTransactions",
 }
 
 // RPCMessageCommandToString maps all MessageCommands to their string representation
@@ -300,6 +320,20 @@ var RPCMessageCommandToString = map[MessageCommand]string{
 	CmdGetMempoolEntriesByAddressesResponseMessage:                "GetMempoolEntriesByAddressesResponse",
 	CmdGetCoinSupplyRequestMessage:                                "GetCoinSupplyRequest",
 	CmdGetCoinSupplyResponseMessage:                               "GetCoinSupplyResponse",
+	CmdGetBansRequestMessage:                                      "GetBansRequest",
+	CmdGetBansResponseMessage:                                     "GetBansResponse",
+	CmdGetWhitelistRequestMessage:                                 "GetWhitelistRequest",
+	CmdGetWhitelistResponseMessage:                                "GetWhitelistResponse",
+	CmdAddToWhitelistRequestMessage:                               "AddToWhitelistRequest",
+	CmdAddToWhitelistResponseMessage:                              "AddToWhitelistResponse",
+	CmdRemoveFromWhitelistRequestMessage:                          "RemoveFromWhitelistRequest",
+	CmdRemoveFromWhitelistResponseMessage:                         "RemoveFromWhitelistResponse",
+	CmdGetCacheStatsRequestMessage:                                "GetCacheStatsRequest",
+	CmdGetCacheStatsResponseMessage:                               "GetCacheStatsResponse",
+	CmdGetDatabaseStatsRequestMessage:                             "GetDatabaseStatsRequest",
+	CmdGetDatabaseStatsResponseMessage:                            "GetDatabaseStatsResponse",
+	CmdCompactDatabaseRequestMessage:                              "CompactDatabaseRequest",
+	CmdCompactDatabaseResponseMessage:                             "CompactDatabaseResponse",
 }
 
 // Message is an interface that describes a hoosat message. A type that
@@ -0,0 +1,39 @@
+package appmessage
+
+// PrefilledTransaction is a transaction that is sent in full as part of a
+// MsgCompactBlock, along with its index in the block
+type PrefilledTransaction struct {
+	Index uint32
+	Tx    *MsgTx
+}
+
+// MsgCompactBlock implements the Message interface and represents a hoosat
+// CompactBlock message. It is sent instead of a MsgBlock to peers that support
+// compact blocks, and carries the short IDs of the block transactions instead of
+// the transactions themselves. The receiver rebuilds the block from its mempool.
+type MsgCompactBlock struct {
+	baseMessage
+	Header                MsgBlockHeader
+	ShortIDNonce          uint64
+	ShortIDs              []uint64
+	PrefilledTransactions []*PrefilledTransaction
+}
+
+// Command returns the protocol command string for the message. This is part
+// of the Message interface implementation.
+func (msg *MsgCompactBlock) Command() MessageCommand {
+	return CmdCompactBlock
+}
+
+// NewMsgCompactBlock returns a new hoosat CompactBlock message that conforms to
+// the Message interface. See MsgCompactBlock for details.
+func NewMsgCompactBlock(header *MsgBlockHeader, shortIDNonce uint64, shortIDs []uint64,
+	prefilledTransactions []*PrefilledTransaction) *MsgCompactBlock {
+
+	return &MsgCompactBlock{
+		Header:                *header,
+		ShortIDNonce:          shortIDNonce,
+		ShortIDs:              shortIDs,
+		PrefilledTransactions: prefilledTransactions,
+	}
+}
@@ -5,11 +5,34 @@
 package appmessage
 
 import (
+	"bytes"
+	"encoding/base32"
 	"net"
+	"strconv"
+	"strings"
 
 	"github.com/Hoosat-Oy/HTND/util/mstime"
+	"github.com/pkg/errors"
+	"golang.org/x/crypto/sha3"
 )
 
+// OnionV3PublicKeySize is the size of the ed25519 public key that identifies
+// a Tor v3 onion service
+const OnionV3PublicKeySize = 32
+
+const (
+	onionV3Version      = 3
+	onionV3ChecksumSize = 2
+	onionV3Suffix       = ".onion"
+)
+
+// onionCatPrefix is the prefix of the fd87:d87e:eb43::/48 OnionCat range. Onion
+// addresses are given an IP in this range, so that they can be handled like any
+// other address where only an IP is needed.
+var onionCatPrefix = []byte{0xfd, 0x87, 0xd8, 0x7e, 0xeb, 0x43}
+
+var onionBase32Encoding = base32.StdEncoding.WithPadding(base32.NoPadding)
+
 // NetAddress defines information about a peer on the network including the time
 // it was last seen, the services it supports, its IP address, and port.
 type NetAddress struct {
@@ -22,6 +45,84 @@ type NetAddress struct {
 	// Port the peer is using. This is encoded in big endian on the appmessage
 	// which differs from most everything else.
 	Port uint16
+
+	// OnionV3PublicKey is the public key of the Tor v3 onion service of the peer.
+	// It's nil for peers that are reached by IP. For onion addresses, IP is an
+	// OnionCat IP derived from the public key.
+	OnionV3PublicKey []byte
+}
+
+// NewNetAddressOnionV3 returns a new NetAddress of the Tor v3 onion service with the
+// given public key and port
+func NewNetAddressOnionV3(publicKey []byte, port uint16) *NetAddress {
+	na := NewNetAddressIPPort(onionCatIP(publicKey), port)
+	na.OnionV3PublicKey = publicKey
+	return na
+}
+
+// IsOnionV3 returns whether the NetAddress is the address of a Tor v3 onion service
+func (na *NetAddress) IsOnionV3() bool {
+	return len(na.OnionV3PublicKey) == OnionV3PublicKeySize
+}
+
+// Host returns the .onion host name for onion addresses, and the IP otherwise
+func (na *NetAddress) Host() string {
+	if na.IsOnionV3() {
+		return OnionV3Host(na.OnionV3PublicKey)
+	}
+	return na.IP.String()
+}
+
+// DialAddress returns the host:port string used to connect to the NetAddress
+func (na *NetAddress) DialAddress() string {
+	return net.JoinHostPort(na.Host(), strconv.Itoa(int(na.Port)))
+}
+
+func onionCatIP(publicKey []byte) net.IP {
+	ip := make(net.IP, net.IPv6len)
+	copy(ip, onionCatPrefix)
+	copy(ip[len(onionCatPrefix):], publicKey)
+	return ip
+}
+
+func onionV3Checksum(publicKey []byte) []byte {
+	hasher := sha3.New256()
+	hasher.Write([]byte(".onion checksum"))
+	hasher.Write(publicKey)
+	hasher.Write([]byte{onionV3Version})
+	return hasher.Sum(nil)[:onionV3ChecksumSize]
+}
+
+// OnionV3Host returns the .onion host name of the Tor v3 onion service with the given public key
+func OnionV3Host(publicKey []byte) string {
+	encoded := make([]byte, 0, OnionV3PublicKeySize+onionV3ChecksumSize+1)
+	encoded = append(encoded, publicKey...)
+	encoded = append(encoded, onionV3Checksum(publicKey)...)
+	encoded = append(encoded, onionV3Version)
+	return strings.ToLower(onionBase32Encoding.EncodeToString(encoded)) + onionV3Suffix
+}
+
+// ParseOnionV3Host returns the public key of the given Tor v3 .onion host name
+func ParseOnionV3Host(host string) ([]byte, error) {
+	if !strings.HasSuffix(host, onionV3Suffix) {
+		return nil, errors.Errorf("%s is not an onion host", host)
+	}
+	decoded, err := onionBase32Encoding.DecodeString(strings.ToUpper(strings.TrimSuffix(host, onionV3Suffix)))
+	if err != nil {
+		return nil, errors.Wrapf(err, "%s is not a valid onion host", host)
+	}
+	if len(decoded) != OnionV3PublicKeySize+onionV3ChecksumSize+1 {
+		return nil, errors.Errorf("%s is not a v3 onion host", host)
+	}
+	publicKey := decoded[:OnionV3PublicKeySize]
+	checksum := decoded[OnionV3PublicKeySize : OnionV3PublicKeySize+onionV3ChecksumSize]
+	if decoded[len(decoded)-1] != onionV3Version {
+		return nil, errors.Errorf("%s has an unsupported onion version %d", host, decoded[len(decoded)-1])
+	}
+	if !bytes.Equal(checksum, onionV3Checksum(publicKey)) {
+		return nil, errors.Errorf("%s has an invalid checksum", host)
+	}
+	return publicKey, nil
 }
 
 // TCPAddress converts the NetAddress to *net.TCPAddr
@@ -60,5 +161,8 @@ func NewNetAddress(addr *net.TCPAddr) *NetAddress {
 }
 
 func (na NetAddress) String() string {
+	if na.IsOnionV3() {
+		return na.DialAddress()
+	}
 	return na.TCPAddress().String()
 }
@@ -26,3 +26,30 @@ func TestNetAddress(t *testing.T) {
 			port)
 	}
 }
+
+// TestOnionV3NetAddress tests parsing and formatting Tor v3 onion addresses.
+func TestOnionV3NetAddress(t *testing.T) {
+	const host = "pg6mmjiyjmcrsslvykfwnntlaru7p5svn6y2ymmju6nubxndf4pscryd.onion"
+
+	publicKey, err := ParseOnionV3Host(host)
+	if err != nil {
+		t.Fatalf("ParseOnionV3Host: %s", err)
+	}
+	na := NewNetAddressOnionV3(publicKey, 42421)
+	if !na.IsOnionV3() {
+		t.Fatalf("IsOnionV3: expected an onion address")
+	}
+	if na.DialAddress() != host+":42421" {
+		t.Errorf("DialAddress: got %s, want %s:42421", na.DialAddress(), host)
+	}
+	if !na.IP.Equal(net.ParseIP("fd87:d87e:eb43:79bc:c625:184b:519:4975")) {
+		t.Errorf("IP: got %s, which is not the OnionCat IP of the public key", na.IP)
+	}
+
+	// Changing a single character must fail the checksum
+	invalidHost := "ph" + host[2:]
+	_, err = ParseOnionV3Host(invalidHost)
+	if err == nil {
+		t.Errorf("ParseOnionV3Host: expected an error for %s", invalidHost)
+	}
+}
@@ -0,0 +1,39 @@
+package appmessage
+
+// AddToWhitelistRequestMessage is an appmessage corresponding to
+// its respective RPC message
+type AddToWhitelistRequestMessage struct {
+	baseMessage
+	Subnet string
+	Reason string
+}
+
+// Command returns the protocol command string for the message
+func (msg *AddToWhitelistRequestMessage) Command() MessageCommand {
+	return CmdAddToWhitelistRequestMessage
+}
+
+// NewAddToWhitelistRequestMessage returns a instance of the message
+func NewAddToWhitelistRequestMessage(subnet string, reason string) *AddToWhitelistRequestMessage {
+	return &AddToWhitelistRequestMessage{
+		Subnet: subnet,
+		Reason: reason,
+	}
+}
+
+// AddToWhitelistResponseMessage is an appmessage corresponding to
+// its respective RPC message
+type AddToWhitelistResponseMessage struct {
+	baseMessage
+	Error *RPCError
+}
+
+// Command returns the protocol command string for the message
+func (msg *AddToWhitelistResponseMessage) Command() MessageCommand {
+	return CmdAddToWhitelistResponseMessage
+}
+
+// NewAddToWhitelistResponseMessage returns a instance of the message
+func NewAddToWhitelistResponseMessage() *AddToWhitelistResponseMessage {
+	return &AddToWhitelistResponseMessage{}
+}
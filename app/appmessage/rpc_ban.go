@@ -5,7 +5,11 @@ package appmessage
 type BanRequestMessage struct {
 	baseMessage
 
-	IP string
+	IP     string
+	Reason string
+	// DurationSeconds is how long the ban lasts. 0 means the node's --banduration,
+	// and a negative duration bans forever
+	DurationSeconds int64
 }
 
 // Command returns the protocol command string for the message
@@ -14,9 +18,11 @@ func (msg *BanRequestMessage) Command() MessageCommand {
 }
 
 // NewBanRequestMessage returns an instance of the message
-func NewBanRequestMessage(ip string) *BanRequestMessage {
+func NewBanRequestMessage(ip string, reason string, durationSeconds int64) *BanRequestMessage {
 	return &BanRequestMessage{
-		IP: ip,
+		IP:              ip,
+		Reason:          reason,
+		DurationSeconds: durationSeconds,
 	}
 }
 
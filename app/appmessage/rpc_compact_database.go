@@ -0,0 +1,40 @@
+package appmessage
+
+// CompactDatabaseRequestMessage is an appmessage corresponding to
+// its respective RPC message
+type CompactDatabaseRequestMessage struct {
+	baseMessage
+}
+
+// Command returns the protocol command string for the message
+func (msg *CompactDatabaseRequestMessage) Command() MessageCommand {
+	return CmdCompactDatabaseRequestMessage
+}
+
+// NewCompactDatabaseRequestMessage returns a instance of the message
+func NewCompactDatabaseRequestMessage() *CompactDatabaseRequestMessage {
+	return &CompactDatabaseRequestMessage{}
+}
+
+// CompactDatabaseResponseMessage is an appmessage corresponding to
+// its respective RPC message
+type CompactDatabaseResponseMessage struct {
+	baseMessage
+	Started    bool
+	Compaction *DatabaseCompactionState
+
+	Error *RPCError
+}
+
+// Command returns the protocol command string for the message
+func (msg *CompactDatabaseResponseMessage) Command() MessageCommand {
+	return CmdCompactDatabaseResponseMessage
+}
+
+// NewCompactDatabaseResponseMessage returns a instance of the message
+func NewCompactDatabaseResponseMessage(started bool, compaction *DatabaseCompactionState) *CompactDatabaseResponseMessage {
+	return &CompactDatabaseResponseMessage{
+		Started:    started,
+		Compaction: compaction,
+	}
+}
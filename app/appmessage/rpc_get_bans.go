@@ -0,0 +1,47 @@
+package appmessage
+
+// GetBansRequestMessage is an appmessage corresponding to
+// its respective RPC message
+type GetBansRequestMessage struct {
+	baseMessage
+}
+
+// Command returns the protocol command string for the message
+func (msg *GetBansRequestMessage) Command() MessageCommand {
+	return CmdGetBansRequestMessage
+}
+
+// NewGetBansRequestMessage returns a instance of the message
+func NewGetBansRequestMessage() *GetBansRequestMessage {
+	return &GetBansRequestMessage{}
+}
+
+// GetBansResponseMessage is an appmessage corresponding to
+// its respective RPC message
+type GetBansResponseMessage struct {
+	baseMessage
+	Bans []*BanEntry
+
+	Error *RPCError
+}
+
+// Command returns the protocol command string for the message
+func (msg *GetBansResponseMessage) Command() MessageCommand {
+	return CmdGetBansResponseMessage
+}
+
+// NewGetBansResponseMessage returns a instance of the message
+func NewGetBansResponseMessage(bans []*BanEntry) *GetBansResponseMessage {
+	return &GetBansResponseMessage{
+		Bans: bans,
+	}
+}
+
+// BanEntry describes the ban of an IP
+type BanEntry struct {
+	IP       string
+	Reason   string
+	BannedAt int64
+	// ExpiresAt is 0 if the ban never expires
+	ExpiresAt int64
+}
@@ -0,0 +1,52 @@
+package appmessage
+
+// GetCacheStatsRequestMessage is an appmessage corresponding to
+// its respective RPC message
+type GetCacheStatsRequestMessage struct {
+	baseMessage
+}
+
+// Command returns the protocol command string for the message
+func (msg *GetCacheStatsRequestMessage) Command() MessageCommand {
+	return CmdGetCacheStatsRequestMessage
+}
+
+// NewGetCacheStatsRequestMessage returns a instance of the message
+func NewGetCacheStatsRequestMessage() *GetCacheStatsRequestMessage {
+	return &GetCacheStatsRequestMessage{}
+}
+
+// GetCacheStatsResponseMessage is an appmessage corresponding to
+// its respective RPC message
+type GetCacheStatsResponseMessage struct {
+	baseMessage
+	Caches      []*CacheStats
+	CachePreset string
+	// CacheMemMiB is 0 if the caches have their default sizes
+	CacheMemMiB uint64
+
+	Error *RPCError
+}
+
+// Command returns the protocol command string for the message
+func (msg *GetCacheStatsResponseMessage) Command() MessageCommand {
+	return CmdGetCacheStatsResponseMessage
+}
+
+// NewGetCacheStatsResponseMessage returns a instance of the message
+func NewGetCacheStatsResponseMessage(caches []*CacheStats, cachePreset string, cacheMemMiB uint64) *GetCacheStatsResponseMessage {
+	return &GetCacheStatsResponseMessage{
+		Caches:      caches,
+		CachePreset: cachePreset,
+		CacheMemMiB: cacheMemMiB,
+	}
+}
+
+// CacheStats holds the usage statistics of a cache of the consensus stores
+type CacheStats struct {
+	Name     string
+	Size     uint64
+	Capacity uint64
+	Hits     uint64
+	Misses   uint64
+}
@@ -47,4 +47,16 @@ type GetConnectedPeerInfoMessage struct {
 	AdvertisedProtocolVersion uint32
 	TimeConnected             int64
 	IsIBDPeer                 bool
+	BanScore                  uint32
+	BytesSent                 uint64
+	BytesReceived             uint64
+	MessageBandwidth          []*PeerMessageBandwidth
+}
+
+// PeerMessageBandwidth holds the amount of bytes sent to and received from
+// a peer in messages of a single P2P command
+type PeerMessageBandwidth struct {
+	Command       string
+	BytesSent     uint64
+	BytesReceived uint64
 }
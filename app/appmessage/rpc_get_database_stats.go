@@ -0,0 +1,75 @@
+package appmessage
+
+// GetDatabaseStatsRequestMessage is an appmessage corresponding to
+// its respective RPC message
+type GetDatabaseStatsRequestMessage struct {
+	baseMessage
+	Collect bool
+}
+
+// Command returns the protocol command string for the message
+func (msg *GetDatabaseStatsRequestMessage) Command() MessageCommand {
+	return CmdGetDatabaseStatsRequestMessage
+}
+
+// NewGetDatabaseStatsRequestMessage returns a instance of the message
+func NewGetDatabaseStatsRequestMessage(collect bool) *GetDatabaseStatsRequestMessage {
+	return &GetDatabaseStatsRequestMessage{
+		Collect: collect,
+	}
+}
+
+// GetDatabaseStatsResponseMessage is an appmessage corresponding to
+// its respective RPC message
+type GetDatabaseStatsResponseMessage struct {
+	baseMessage
+	Buckets    []*DatabaseBucketStats
+	DiskSize   uint64
+	Compaction *DatabaseCompactionState
+	Collection *DatabaseStatsCollectionState
+
+	Error *RPCError
+}
+
+// Command returns the protocol command string for the message
+func (msg *GetDatabaseStatsResponseMessage) Command() MessageCommand {
+	return CmdGetDatabaseStatsResponseMessage
+}
+
+// NewGetDatabaseStatsResponseMessage returns a instance of the message
+func NewGetDatabaseStatsResponseMessage(buckets []*DatabaseBucketStats, diskSize uint64,
+	compaction *DatabaseCompactionState, collection *DatabaseStatsCollectionState) *GetDatabaseStatsResponseMessage {
+
+	return &GetDatabaseStatsResponseMessage{
+		Buckets:    buckets,
+		DiskSize:   diskSize,
+		Compaction: compaction,
+		Collection: collection,
+	}
+}
+
+// DatabaseBucketStats holds the amount and size of the keys in a bucket
+// of the node database
+type DatabaseBucketStats struct {
+	Owner    string
+	Store    string
+	Bucket   string
+	KeyCount uint64
+	Size     uint64
+}
+
+// DatabaseCompactionState holds the state of the compaction of the node database
+type DatabaseCompactionState struct {
+	IsCompacting bool
+	Progress     uint32
+	LastError    string
+}
+
+// DatabaseStatsCollectionState holds the state of the collection of the
+// bucket stats of the node database
+type DatabaseStatsCollectionState struct {
+	IsCollecting bool
+	// CollectedAt is 0 if no collection completed yet
+	CollectedAt int64
+	LastError   string
+}
@@ -5,6 +5,8 @@ package appmessage
 type GetUTXOsByAddressesRequestMessage struct {
 	baseMessage
 	Addresses []string
+	Cursor    string
+	Limit     uint32
 }
 
 // Command returns the protocol command string for the message
@@ -19,11 +21,22 @@ func NewGetUTXOsByAddressesRequestMessage(addresses []string) *GetUTXOsByAddress
 	}
 }
 
+// NewGetUTXOsByAddressesPageRequestMessage returns a instance of the message
+// that requests a page of up to limit UTXOs, starting from the given cursor
+func NewGetUTXOsByAddressesPageRequestMessage(addresses []string, cursor string, limit uint32) *GetUTXOsByAddressesRequestMessage {
+	return &GetUTXOsByAddressesRequestMessage{
+		Addresses: addresses,
+		Cursor:    cursor,
+		Limit:     limit,
+	}
+}
+
 // GetUTXOsByAddressesResponseMessage is an appmessage corresponding to
 // its respective RPC message
 type GetUTXOsByAddressesResponseMessage struct {
 	baseMessage
-	Entries []*UTXOsByAddressesEntry
+	Entries    []*UTXOsByAddressesEntry
+	NextCursor string
 
 	Error *RPCError
 }
@@ -34,8 +47,9 @@ func (msg *GetUTXOsByAddressesResponseMessage) Command() MessageCommand {
 }
 
 // NewGetUTXOsByAddressesResponseMessage returns a instance of the message
-func NewGetUTXOsByAddressesResponseMessage(entries []*UTXOsByAddressesEntry) *GetUTXOsByAddressesResponseMessage {
+func NewGetUTXOsByAddressesResponseMessage(entries []*UTXOsByAddressesEntry, nextCursor string) *GetUTXOsByAddressesResponseMessage {
 	return &GetUTXOsByAddressesResponseMessage{
-		Entries: entries,
+		Entries:    entries,
+		NextCursor: nextCursor,
 	}
 }
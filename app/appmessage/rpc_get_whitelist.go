@@ -0,0 +1,46 @@
+package appmessage
+
+// GetWhitelistRequestMessage is an appmessage corresponding to
+// its respective RPC message
+type GetWhitelistRequestMessage struct {
+	baseMessage
+}
+
+// Command returns the protocol command string for the message
+func (msg *GetWhitelistRequestMessage) Command() MessageCommand {
+	return CmdGetWhitelistRequestMessage
+}
+
+// NewGetWhitelistRequestMessage returns a instance of the message
+func NewGetWhitelistRequestMessage() *GetWhitelistRequestMessage {
+	return &GetWhitelistRequestMessage{}
+}
+
+// GetWhitelistResponseMessage is an appmessage corresponding to
+// its respective RPC message
+type GetWhitelistResponseMessage struct {
+	baseMessage
+	Entries []*WhitelistEntry
+
+	Error *RPCError
+}
+
+// Command returns the protocol command string for the message
+func (msg *GetWhitelistResponseMessage) Command() MessageCommand {
+	return CmdGetWhitelistResponseMessage
+}
+
+// NewGetWhitelistResponseMessage returns a instance of the message
+func NewGetWhitelistResponseMessage(entries []*WhitelistEntry) *GetWhitelistResponseMessage {
+	return &GetWhitelistResponseMessage{
+		Entries: entries,
+	}
+}
+
+// WhitelistEntry describes a subnet whose peers are never banned
+type WhitelistEntry struct {
+	Subnet       string
+	Reason       string
+	AddedAt      int64
+	IsFromConfig bool
+}
@@ -0,0 +1,37 @@
+package appmessage
+
+// RemoveFromWhitelistRequestMessage is an appmessage corresponding to
+// its respective RPC message
+type RemoveFromWhitelistRequestMessage struct {
+	baseMessage
+	Subnet string
+}
+
+// Command returns the protocol command string for the message
+func (msg *RemoveFromWhitelistRequestMessage) Command() MessageCommand {
+	return CmdRemoveFromWhitelistRequestMessage
+}
+
+// NewRemoveFromWhitelistRequestMessage returns a instance of the message
+func NewRemoveFromWhitelistRequestMessage(subnet string) *RemoveFromWhitelistRequestMessage {
+	return &RemoveFromWhitelistRequestMessage{
+		Subnet: subnet,
+	}
+}
+
+// RemoveFromWhitelistResponseMessage is an appmessage corresponding to
+// its respective RPC message
+type RemoveFromWhitelistResponseMessage struct {
+	baseMessage
+	Error *RPCError
+}
+
+// Command returns the protocol command string for the message
+func (msg *RemoveFromWhitelistResponseMessage) Command() MessageCommand {
+	return CmdRemoveFromWhitelistResponseMessage
+}
+
+// NewRemoveFromWhitelistResponseMessage returns a instance of the message
+func NewRemoveFromWhitelistResponseMessage() *RemoveFromWhitelistResponseMessage {
+	return &RemoveFromWhitelistResponseMessage{}
+}
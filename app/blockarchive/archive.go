@@ -0,0 +1,267 @@
+// Package blockarchive exports the blocks of an archival node to a file, and
+// imports them back into another node.
+//
+// A block archive is a messagefile with a MsgBlock record for every block in
+// the DAG, in topological order: every block comes after all of its parents.
+// The records are followed by an index of the offsets of the blocks in the
+// file, so that the blocks can be looked up by hash without reading the whole
+// archive:
+//
+//	uint64 block count
+//	block count * (32-byte block hash, uint64 offset of its record)
+//	uint64 offset of the index
+package blockarchive
+
+import (
+	"github.com/Hoosat-Oy/HTND/app/appmessage"
+	"github.com/Hoosat-Oy/HTND/app/messagefile"
+	"github.com/Hoosat-Oy/HTND/domain/consensus/model/externalapi"
+	"github.com/Hoosat-Oy/HTND/domain/consensus/utils/consensushashing"
+	"github.com/Hoosat-Oy/HTND/domain/dagconfig"
+	"github.com/pkg/errors"
+)
+
+// archiveFormat identifies block archive files
+var archiveFormat = &messagefile.Format{
+	Name:    "block archive",
+	Magic:   []byte("htnarchive"),
+	Version: 1,
+}
+
+// indexEntrySize is the size of a single entry in the index
+const indexEntrySize = externalapi.DomainHashSize + 8
+
+// writer writes blocks to a block archive and keeps their index
+type writer struct {
+	file        *messagefile.Writer
+	blockHashes []*externalapi.DomainHash
+	offsets     map[externalapi.DomainHash]uint64
+}
+
+func createWriter(path string, networkName string) (*writer, error) {
+	file, err := messagefile.Create(path, archiveFormat, networkName)
+	if err != nil {
+		return nil, err
+	}
+	return &writer{
+		file:    file,
+		offsets: make(map[externalapi.DomainHash]uint64),
+	}, nil
+}
+
+func (w *writer) contains(blockHash *externalapi.DomainHash) bool {
+	_, ok := w.offsets[*blockHash]
+	return ok
+}
+
+func (w *writer) blockCount() int {
+	return len(w.blockHashes)
+}
+
+func (w *writer) writeBlock(block *externalapi.DomainBlock) error {
+	blockHash := consensushashing.BlockHash(block)
+	if w.contains(blockHash) {
+		return errors.Errorf("block %s was already written to the block archive", blockHash)
+	}
+
+	offset := w.file.Offset()
+	err := w.file.WriteMessage(appmessage.DomainBlockToMsgBlock(block))
+	if err != nil {
+		return err
+	}
+	w.blockHashes = append(w.blockHashes, blockHash)
+	w.offsets[*blockHash] = offset
+	return nil
+}
+
+func (w *writer) finish() error {
+	err := w.file.WriteEnd()
+	if err != nil {
+		return err
+	}
+
+	indexOffset := w.file.Offset()
+	err = w.file.WriteUint64(uint64(len(w.blockHashes)))
+	if err != nil {
+		return err
+	}
+	for _, blockHash := range w.blockHashes {
+		err = w.file.Write(blockHash.ByteSlice())
+		if err != nil {
+			return err
+		}
+		err = w.file.WriteUint64(w.offsets[*blockHash])
+		if err != nil {
+			return err
+		}
+	}
+	err = w.file.WriteUint64(indexOffset)
+	if err != nil {
+		return err
+	}
+
+	return w.file.Finish()
+}
+
+func (w *writer) abort() {
+	w.file.Abort()
+}
+
+// Reader reads the blocks of a block archive, either in the order they were
+// exported or by hash
+type Reader struct {
+	file          *messagefile.Reader
+	recordsOffset uint64
+	blockHashes   []*externalapi.DomainHash
+	offsets       map[externalapi.DomainHash]uint64
+}
+
+// Open opens the block archive in the given path and reads its index. The
+// archive has to belong to the network of the given params.
+//
+// Open doesn't verify the checksum of the archive. Use VerifyChecksum for that.
+func Open(path string, params *dagconfig.Params) (*Reader, error) {
+	file, err := messagefile.Open(path, archiveFormat, params.Name)
+	if err != nil {
+		return nil, err
+	}
+	r := &Reader{
+		file:          file,
+		recordsOffset: file.Offset(),
+	}
+
+	err = r.readIndex()
+	if err != nil {
+		r.Close()
+		return nil, err
+	}
+	err = r.file.Seek(r.recordsOffset)
+	if err != nil {
+		r.Close()
+		return nil, err
+	}
+	return r, nil
+}
+
+func (r *Reader) readIndex() error {
+	trailerOffset := r.file.Size() - messagefile.ChecksumSize - 8
+	if trailerOffset < int64(r.recordsOffset) {
+		return errors.New("the block archive is truncated")
+	}
+	err := r.file.Seek(uint64(trailerOffset))
+	if err != nil {
+		return err
+	}
+	indexOffset, err := r.file.ReadUint64()
+	if err != nil {
+		return err
+	}
+	if indexOffset < r.recordsOffset || indexOffset+8 > uint64(trailerOffset) {
+		return errors.Errorf("invalid block archive index offset %d", indexOffset)
+	}
+
+	err = r.file.Seek(indexOffset)
+	if err != nil {
+		return err
+	}
+	blockCount, err := r.file.ReadUint64()
+	if err != nil {
+		return err
+	}
+	indexSize := uint64(trailerOffset) - indexOffset - 8
+	if indexSize%indexEntrySize != 0 || indexSize/indexEntrySize != blockCount {
+		return errors.Errorf("the block archive index of %d blocks doesn't match its size", blockCount)
+	}
+
+	r.blockHashes = make([]*externalapi.DomainHash, blockCount)
+	r.offsets = make(map[externalapi.DomainHash]uint64, blockCount)
+	var serializedHash [externalapi.DomainHashSize]byte
+	for i := range r.blockHashes {
+		err = r.file.Read(serializedHash[:])
+		if err != nil {
+			return err
+		}
+		offset, err := r.file.ReadUint64()
+		if err != nil {
+			return err
+		}
+		if offset < r.recordsOffset || offset >= indexOffset {
+			return errors.Errorf("invalid offset %d of block %x", offset, serializedHash)
+		}
+		blockHash := externalapi.NewDomainHashFromByteArray(&serializedHash)
+		r.blockHashes[i] = blockHash
+		r.offsets[*blockHash] = offset
+	}
+	return nil
+}
+
+// BlockCount returns the amount of blocks in the archive
+func (r *Reader) BlockCount() int {
+	return len(r.blockHashes)
+}
+
+// BlockHashes returns the hashes of the blocks in the archive, in the order
+// they were exported
+func (r *Reader) BlockHashes() []*externalapi.DomainHash {
+	return r.blockHashes
+}
+
+// Contains returns whether the block with the given hash is in the archive
+func (r *Reader) Contains(blockHash *externalapi.DomainHash) bool {
+	_, ok := r.offsets[*blockHash]
+	return ok
+}
+
+// NextBlock returns the next block in the archive, or nil once all the blocks
+// were read
+func (r *Reader) NextBlock() (*externalapi.DomainBlock, error) {
+	message, err := r.file.ReadMessage()
+	if err != nil {
+		return nil, err
+	}
+	if message == nil {
+		return nil, nil
+	}
+	msgBlock, ok := message.(*appmessage.MsgBlock)
+	if !ok {
+		return nil, errors.Errorf("unexpected %s record in the block archive", message.Command())
+	}
+	return appmessage.MsgBlockToDomainBlock(msgBlock), nil
+}
+
+// BlockByHash returns the block with the given hash, and whether it's in the
+// archive. NextBlock continues from the block after it.
+func (r *Reader) BlockByHash(blockHash *externalapi.DomainHash) (*externalapi.DomainBlock, bool, error) {
+	offset, ok := r.offsets[*blockHash]
+	if !ok {
+		return nil, false, nil
+	}
+	err := r.file.Seek(offset)
+	if err != nil {
+		return nil, false, err
+	}
+	block, err := r.NextBlock()
+	if err != nil {
+		return nil, false, err
+	}
+	if block == nil || !consensushashing.BlockHash(block).Equal(blockHash) {
+		return nil, false, errors.Errorf("the block archive index points block %s to another record", blockHash)
+	}
+	return block, true, nil
+}
+
+// Rewind moves the reader back to the first block in the archive
+func (r *Reader) Rewind() error {
+	return r.file.Seek(r.recordsOffset)
+}
+
+// Close closes the archive
+func (r *Reader) Close() {
+	r.file.Close()
+}
+
+// VerifyChecksum checks that the block archive in the given path wasn't
+// corrupted
+func VerifyChecksum(path string) error {
+	return messagefile.VerifyChecksum(path, archiveFormat)
+}
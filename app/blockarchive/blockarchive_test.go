@@ -0,0 +1,109 @@
+package blockarchive
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/Hoosat-Oy/HTND/domain/consensus"
+	"github.com/Hoosat-Oy/HTND/domain/consensus/model/externalapi"
+	"github.com/Hoosat-Oy/HTND/domain/consensus/utils/testutils"
+)
+
+func TestExportAndImport(t *testing.T) {
+	testutils.ForAllNets(t, true, func(t *testing.T, consensusConfig *consensus.Config) {
+		factory := consensus.NewFactory()
+		syncer, teardownSyncer, err := factory.NewTestConsensus(consensusConfig, "TestExportAndImportSyncer")
+		if err != nil {
+			t.Fatalf("Error setting up consensus: %+v", err)
+		}
+		defer teardownSyncer(false)
+
+		// genesis <- a <- c
+		//         <- b
+		genesisHash := consensusConfig.GenesisHash
+		a, _, err := syncer.AddBlock([]*externalapi.DomainHash{genesisHash}, nil, nil)
+		if err != nil {
+			t.Fatalf("AddBlock: %+v", err)
+		}
+		b, _, err := syncer.AddBlock([]*externalapi.DomainHash{genesisHash}, nil, nil)
+		if err != nil {
+			t.Fatalf("AddBlock: %+v", err)
+		}
+		c, _, err := syncer.AddBlock([]*externalapi.DomainHash{a}, nil, nil)
+		if err != nil {
+			t.Fatalf("AddBlock: %+v", err)
+		}
+
+		path := filepath.Join(t.TempDir(), "archive")
+		err = Export(syncer, &consensusConfig.Params, path)
+		if err != nil {
+			t.Fatalf("Export: %+v", err)
+		}
+
+		r, err := Open(path, &consensusConfig.Params)
+		if err != nil {
+			t.Fatalf("Open: %+v", err)
+		}
+		defer r.Close()
+		if r.BlockCount() != 4 {
+			t.Fatalf("expected 4 blocks in the archive, got %d", r.BlockCount())
+		}
+		exported := make(map[externalapi.DomainHash]int)
+		for i, blockHash := range r.BlockHashes() {
+			exported[*blockHash] = i
+		}
+		for _, blockHash := range []*externalapi.DomainHash{genesisHash, a, b, c} {
+			if _, ok := exported[*blockHash]; !ok {
+				t.Fatalf("block %s is missing from the archive", blockHash)
+			}
+		}
+		if exported[*genesisHash] != 0 || exported[*a] > exported[*c] {
+			t.Fatalf("the blocks of the archive aren't in topological order")
+		}
+		block, found, err := r.BlockByHash(b)
+		if err != nil {
+			t.Fatalf("BlockByHash: %+v", err)
+		}
+		if !found || !block.Header.DirectParents()[0].Equal(genesisHash) {
+			t.Fatalf("BlockByHash returned an unexpected block for %s", b)
+		}
+
+		syncee, teardownSyncee, err := factory.NewTestConsensus(consensusConfig, "TestExportAndImportSyncee")
+		if err != nil {
+			t.Fatalf("Error setting up consensus: %+v", err)
+		}
+		defer teardownSyncee(false)
+
+		err = Import(syncee, &consensusConfig.Params, path)
+		if err != nil {
+			t.Fatalf("Import: %+v", err)
+		}
+		syncerVirtualSelectedParent, err := syncer.GetVirtualSelectedParent()
+		if err != nil {
+			t.Fatalf("GetVirtualSelectedParent: %+v", err)
+		}
+		synceeVirtualSelectedParent, err := syncee.GetVirtualSelectedParent()
+		if err != nil {
+			t.Fatalf("GetVirtualSelectedParent: %+v", err)
+		}
+		if !synceeVirtualSelectedParent.Equal(syncerVirtualSelectedParent) {
+			t.Fatalf("expected the virtual selected parent %s after the import, got %s",
+				syncerVirtualSelectedParent, synceeVirtualSelectedParent)
+		}
+		for _, blockHash := range []*externalapi.DomainHash{a, b, c} {
+			blockInfo, err := syncee.GetBlockInfo(blockHash)
+			if err != nil {
+				t.Fatalf("GetBlockInfo: %+v", err)
+			}
+			if !blockInfo.HasBody() {
+				t.Fatalf("block %s wasn't imported", blockHash)
+			}
+		}
+
+		// Importing the same archive again does nothing
+		err = Import(syncee, &consensusConfig.Params, path)
+		if err != nil {
+			t.Fatalf("Import: %+v", err)
+		}
+	})
+}
@@ -0,0 +1,153 @@
+package blockarchive
+
+import (
+	"sort"
+
+	"github.com/Hoosat-Oy/HTND/domain/consensus/model/externalapi"
+	"github.com/Hoosat-Oy/HTND/domain/dagconfig"
+	"github.com/Hoosat-Oy/HTND/infrastructure/logger"
+	"github.com/pkg/errors"
+)
+
+// hashesChunkSize is the amount of block hashes requested from the consensus
+// at once. It has to be at least MergeSetSizeLimit + 1.
+const hashesChunkSize = 1 << 10
+
+// exportLogInterval is the amount of blocks between progress logs
+const exportLogInterval = 10_000
+
+// Export writes every block of the given consensus to a block archive in the
+// given path, in topological order. The blocks are the merge sets of the
+// virtual selected chain from the genesis up to the virtual selected parent,
+// followed by the blocks in the anticone of the virtual selected parent.
+//
+// Only archival nodes keep the bodies of the blocks below the pruning point,
+// so exporting from any other node fails.
+//
+// NOTE: No blocks may be added to the consensus while this is called.
+func Export(consensus externalapi.Consensus, params *dagconfig.Params, path string) error {
+	onEnd := logger.LogAndMeasureExecutionTime(log, "Export")
+	defer onEnd()
+
+	log.Infof("Exporting the blocks to the block archive %s", path)
+	w, err := createWriter(path, params.Name)
+	if err != nil {
+		return err
+	}
+	err = exportBlocks(consensus, params, w)
+	if err == nil {
+		err = w.finish()
+	}
+	if err != nil {
+		w.abort()
+		return err
+	}
+
+	log.Infof("Exported %d blocks to %s", w.blockCount(), path)
+	return nil
+}
+
+func exportBlocks(consensus externalapi.Consensus, params *dagconfig.Params, w *writer) error {
+	err := exportBlock(consensus, params.GenesisHash, w)
+	if err != nil {
+		return err
+	}
+
+	virtualSelectedParent, err := consensus.GetVirtualSelectedParent()
+	if err != nil {
+		return err
+	}
+	lowHash := params.GenesisHash
+	for !lowHash.Equal(virtualSelectedParent) {
+		blockHashes, highHash, err := consensus.GetHashesBetween(lowHash, virtualSelectedParent, hashesChunkSize)
+		if err != nil {
+			return err
+		}
+		for _, blockHash := range blockHashes {
+			if w.contains(blockHash) {
+				continue
+			}
+			err = exportBlock(consensus, blockHash, w)
+			if err != nil {
+				return err
+			}
+		}
+		lowHash = highHash
+	}
+
+	anticone, err := virtualSelectedParentAnticone(consensus, virtualSelectedParent, w)
+	if err != nil {
+		return err
+	}
+	for _, blockHash := range anticone {
+		err = exportBlock(consensus, blockHash, w)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// virtualSelectedParentAnticone returns the blocks in the anticone of the
+// virtual selected parent that weren't exported yet, sorted by blue work.
+// Every block has more blue work than its parents, so this order is
+// topological.
+func virtualSelectedParentAnticone(consensus externalapi.Consensus,
+	virtualSelectedParent *externalapi.DomainHash, w *writer) ([]*externalapi.DomainHash, error) {
+
+	tips, err := consensus.Tips()
+	if err != nil {
+		return nil, err
+	}
+
+	var anticone []*externalapi.DomainHash
+	blockInfos := make(map[externalapi.DomainHash]*externalapi.BlockInfo)
+	for _, tip := range tips {
+		if tip.Equal(virtualSelectedParent) {
+			continue
+		}
+		tipAnticone, err := consensus.GetAnticone(virtualSelectedParent, tip, 0)
+		if err != nil {
+			return nil, err
+		}
+		for _, blockHash := range tipAnticone {
+			if _, ok := blockInfos[*blockHash]; ok || w.contains(blockHash) {
+				continue
+			}
+			blockInfo, err := consensus.GetBlockInfo(blockHash)
+			if err != nil {
+				return nil, err
+			}
+			blockInfos[*blockHash] = blockInfo
+			anticone = append(anticone, blockHash)
+		}
+	}
+
+	sort.Slice(anticone, func(i, j int) bool {
+		blueWorkComparison := blockInfos[*anticone[i]].BlueWork.Cmp(blockInfos[*anticone[j]].BlueWork)
+		if blueWorkComparison != 0 {
+			return blueWorkComparison < 0
+		}
+		return anticone[i].Less(anticone[j])
+	})
+	return anticone, nil
+}
+
+func exportBlock(consensus externalapi.Consensus, blockHash *externalapi.DomainHash, w *writer) error {
+	block, found, err := consensus.GetBlock(blockHash)
+	if err != nil {
+		return err
+	}
+	if !found {
+		return errors.Errorf("block %s has no body -- only archival nodes keep every block", blockHash)
+	}
+	err = w.writeBlock(block)
+	if err != nil {
+		return err
+	}
+
+	if w.blockCount()%exportLogInterval == 0 {
+		log.Infof("Exported %d blocks", w.blockCount())
+	}
+	return nil
+}
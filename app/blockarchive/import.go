@@ -0,0 +1,128 @@
+package blockarchive
+
+import (
+	"github.com/Hoosat-Oy/HTND/domain/consensus/model/externalapi"
+	"github.com/Hoosat-Oy/HTND/domain/consensus/utils/consensushashing"
+	"github.com/Hoosat-Oy/HTND/domain/dagconfig"
+	"github.com/Hoosat-Oy/HTND/infrastructure/logger"
+	"github.com/pkg/errors"
+)
+
+// Import replays the blocks of the block archive in the given path into the
+// given consensus. The blocks are validated like blocks received during IBD,
+// and the virtual is resolved once they're all in.
+//
+// Blocks that are already in the DAG are skipped, so an interrupted import
+// continues where it stopped, and a node that keeps --import-blocks in its
+// configuration restarts normally.
+//
+// NOTE: No blocks may be added to the consensus while this is called.
+func Import(consensus externalapi.Consensus, params *dagconfig.Params, path string) error {
+	onEnd := logger.LogAndMeasureExecutionTime(log, "Import")
+	defer onEnd()
+
+	r, err := Open(path, params)
+	if err != nil {
+		return errors.Wrapf(err, "failed opening the block archive %s", path)
+	}
+	defer r.Close()
+
+	missingBlockCount, err := countMissingBlocks(consensus, r)
+	if err != nil {
+		return err
+	}
+	if missingBlockCount == 0 {
+		log.Infof("All the %d blocks of the block archive %s are already in the DAG. Skipping the import",
+			r.BlockCount(), path)
+		return nil
+	}
+
+	log.Infof("Verifying the checksum of the block archive %s", path)
+	err = VerifyChecksum(path)
+	if err != nil {
+		return err
+	}
+
+	log.Infof("Importing %d of the %d blocks of the block archive %s", missingBlockCount, r.BlockCount(), path)
+	highestDAAScore, err := importBlocks(consensus, r, missingBlockCount)
+	if err != nil {
+		return errors.Wrapf(err, "failed importing the block archive %s", path)
+	}
+
+	err = resolveVirtual(consensus, highestDAAScore)
+	if err != nil {
+		return err
+	}
+	log.Infof("Imported the block archive %s", path)
+	return nil
+}
+
+func countMissingBlocks(consensus externalapi.Consensus, r *Reader) (int, error) {
+	missingBlockCount := 0
+	for _, blockHash := range r.BlockHashes() {
+		blockInfo, err := consensus.GetBlockInfo(blockHash)
+		if err != nil {
+			return 0, err
+		}
+		if !blockInfo.HasBody() {
+			missingBlockCount++
+		}
+	}
+	return missingBlockCount, nil
+}
+
+func importBlocks(consensus externalapi.Consensus, r *Reader, missingBlockCount int) (highestDAAScore uint64, err error) {
+	importedBlockCount := 0
+	percents := 0
+	for {
+		block, err := r.NextBlock()
+		if err != nil {
+			return 0, err
+		}
+		if block == nil {
+			break
+		}
+
+		blockHash := consensushashing.BlockHash(block)
+		blockInfo, err := consensus.GetBlockInfo(blockHash)
+		if err != nil {
+			return 0, err
+		}
+		if blockInfo.HasBody() {
+			continue
+		}
+
+		err = consensus.ValidateAndInsertBlock(block, false, new(externalapi.DomainHash))
+		if err != nil {
+			return 0, errors.Wrapf(err, "failed inserting block %s", blockHash)
+		}
+		if block.Header.DAAScore() > highestDAAScore {
+			highestDAAScore = block.Header.DAAScore()
+		}
+
+		importedBlockCount++
+		newPercents := 100 * importedBlockCount / missingBlockCount
+		if newPercents > percents {
+			percents = newPercents
+			log.Infof("Imported %d%% of the blocks", percents)
+		}
+	}
+	return highestDAAScore, nil
+}
+
+func resolveVirtual(consensus externalapi.Consensus, estimatedVirtualDAAScoreTarget uint64) error {
+	return consensus.ResolveVirtual(func(virtualDAAScoreStart uint64, virtualDAAScore uint64) {
+		var percents int
+		if estimatedVirtualDAAScoreTarget <= virtualDAAScoreStart {
+			percents = 100
+		} else {
+			percents = int(float64(virtualDAAScore-virtualDAAScoreStart) / float64(estimatedVirtualDAAScoreTarget-virtualDAAScoreStart) * 100)
+		}
+		if percents < 0 {
+			percents = 0
+		} else if percents > 100 {
+			percents = 100
+		}
+		log.Infof("Resolving virtual. Estimated progress: %d%%", percents)
+	})
+}
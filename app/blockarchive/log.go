@@ -0,0 +1,7 @@
+package blockarchive
+
+import (
+	"github.com/Hoosat-Oy/HTND/infrastructure/logger"
+)
+
+var log = logger.RegisterSubSystem("BARC")
@@ -2,14 +2,21 @@ package app
 
 import (
 	"fmt"
+	"net"
+	"path/filepath"
+	"strconv"
 	"sync/atomic"
 
 	"github.com/Hoosat-Oy/HTND/domain/consensus/model/externalapi"
+	"github.com/pkg/errors"
 
 	"github.com/Hoosat-Oy/HTND/domain/miningmanager/mempool"
 
+	"github.com/Hoosat-Oy/HTND/app/appmessage"
+	"github.com/Hoosat-Oy/HTND/app/blockarchive"
 	"github.com/Hoosat-Oy/HTND/app/protocol"
 	"github.com/Hoosat-Oy/HTND/app/rpc"
+	"github.com/Hoosat-Oy/HTND/app/snapshot"
 	"github.com/Hoosat-Oy/HTND/domain"
 	"github.com/Hoosat-Oy/HTND/domain/consensus"
 	"github.com/Hoosat-Oy/HTND/domain/utxoindex"
@@ -19,17 +26,28 @@ import (
 	"github.com/Hoosat-Oy/HTND/infrastructure/network/connmanager"
 	"github.com/Hoosat-Oy/HTND/infrastructure/network/netadapter"
 	"github.com/Hoosat-Oy/HTND/infrastructure/network/netadapter/id"
+	"github.com/Hoosat-Oy/HTND/infrastructure/network/noise"
+	"github.com/Hoosat-Oy/HTND/infrastructure/network/portmapper"
+	"github.com/Hoosat-Oy/HTND/infrastructure/network/torcontrol"
 	"github.com/Hoosat-Oy/HTND/util/panics"
 )
 
+// p2pKeyFilename is the name of the file in the app directory that stores the
+// key that identifies this node in encrypted P2P connections
+const p2pKeyFilename = "p2p.key"
+
 // ComponentManager is a wrapper for all the htnd services
 type ComponentManager struct {
 	cfg               *config.Config
+	db                infrastructuredatabase.Database
 	addressManager    *addressmanager.AddressManager
 	protocolManager   *protocol.Manager
 	rpcManager        *rpc.Manager
+	utxoIndex         *utxoindex.UTXOIndex
 	connectionManager *connmanager.ConnectionManager
 	netAdapter        *netadapter.NetAdapter
+	portMapper        *portmapper.PortMapper
+	torController     *torcontrol.Controller
 
 	started, shutdown int32
 }
@@ -49,6 +67,10 @@ func (a *ComponentManager) Start() {
 	}
 
 	a.connectionManager.Start()
+
+	if a.portMapper != nil {
+		a.portMapper.Start()
+	}
 }
 
 // Stop gracefully shuts down all the htnd services.
@@ -61,6 +83,17 @@ func (a *ComponentManager) Stop() {
 
 	log.Warnf("htnd shutting down")
 
+	if a.portMapper != nil {
+		a.portMapper.Stop()
+	}
+
+	if a.torController != nil {
+		err := a.torController.Close()
+		if err != nil {
+			log.Errorf("Error closing the Tor control connection: %+v", err)
+		}
+	}
+
 	a.connectionManager.Stop()
 
 	err := a.netAdapter.Stop()
@@ -71,6 +104,15 @@ func (a *ComponentManager) Stop() {
 	a.protocolManager.Close()
 	close(a.protocolManager.Context().Domain().ConsensusEventsChannel())
 
+	if a.utxoIndex != nil {
+		a.utxoIndex.Close()
+	}
+
+	err = markDatabaseClosedCleanly(a.db)
+	if err != nil {
+		log.Errorf("Error marking the database as cleanly closed: %+v", err)
+	}
+
 	return
 }
 
@@ -79,10 +121,20 @@ func (a *ComponentManager) Stop() {
 func NewComponentManager(cfg *config.Config, db infrastructuredatabase.Database, interrupt chan<- struct{}) (
 	*ComponentManager, error) {
 
+	hadUncleanShutdown, err := markDatabaseInUse(db)
+	if err != nil {
+		return nil, err
+	}
+	if hadUncleanShutdown {
+		log.Warnf("htnd wasn't shut down cleanly. The UTXO set will be verified against its commitment")
+	}
+
 	consensusConfig := consensus.Config{
 		Params:                          *cfg.ActiveNetParams,
 		IsArchival:                      cfg.IsArchivalNode,
 		EnableSanityCheckPruningUTXOSet: cfg.EnableSanityCheckPruningUTXOSet,
+		VerifyVirtualUTXOSet:            hadUncleanShutdown,
+		CacheMemoryBudget:               cfg.CacheMem * 1024 * 1024,
 	}
 	mempoolConfig := mempool.DefaultConfig(&consensusConfig.Params)
 	mempoolConfig.MaximumOrphanTransactionCount = cfg.MaxOrphanTxs
@@ -93,6 +145,33 @@ func NewComponentManager(cfg *config.Config, db infrastructuredatabase.Database,
 		return nil, err
 	}
 
+	// The snapshot and the block archive are imported before the UTXO index
+	// starts, so that the index is rebuilt from the imported UTXO set
+	if cfg.ImportSnapshot != "" {
+		err = snapshot.Import(domain, cfg.NetParams(), cfg.ImportSnapshot)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if cfg.ImportBlocks != "" {
+		if !cfg.IsArchivalNode {
+			log.Warnf("Importing the block archive %s into a node that isn't archival. "+
+				"Blocks below the pruning point will be pruned", cfg.ImportBlocks)
+		}
+		err = blockarchive.Import(domain.Consensus(), cfg.NetParams(), cfg.ImportBlocks)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if !cfg.NoP2PEncryption {
+		cfg.P2PStaticKey, err = noise.LoadOrCreateStaticKey(filepath.Join(cfg.AppDir, p2pKeyFilename))
+		if err != nil {
+			return nil, err
+		}
+		log.Infof("P2P identity: %x", cfg.P2PStaticKey.PublicKey())
+	}
+
 	netAdapter, err := netadapter.NewNetAdapter(cfg)
 	if err != nil {
 		return nil, err
@@ -121,15 +200,36 @@ func NewComponentManager(cfg *config.Config, db infrastructuredatabase.Database,
 	if err != nil {
 		return nil, err
 	}
-	rpcManager := setupRPC(cfg, domain, netAdapter, protocolManager, connectionManager, addressManager, utxoIndex, domain.ConsensusEventsChannel(), interrupt)
+	rpcManager := setupRPC(cfg, domain, netAdapter, protocolManager, connectionManager, addressManager, utxoIndex, db,
+		domain.ConsensusEventsChannel(), interrupt)
+
+	var portMapper *portmapper.PortMapper
+	if cfg.Upnp && !cfg.DisableListen {
+		portMapper, err = setupPortMapper(cfg, addressManager)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	var torController *torcontrol.Controller
+	if cfg.ListenOnion {
+		torController, err = setupOnionService(cfg, addressManager)
+		if err != nil {
+			return nil, err
+		}
+	}
 
 	return &ComponentManager{
 		cfg:               cfg,
+		db:                db,
 		protocolManager:   protocolManager,
 		rpcManager:        rpcManager,
+		utxoIndex:         utxoIndex,
 		connectionManager: connectionManager,
 		netAdapter:        netAdapter,
 		addressManager:    addressManager,
+		portMapper:        portMapper,
+		torController:     torController,
 	}, nil
 
 }
@@ -142,6 +242,7 @@ func setupRPC(
 	connectionManager *connmanager.ConnectionManager,
 	addressManager *addressmanager.AddressManager,
 	utxoIndex *utxoindex.UTXOIndex,
+	db infrastructuredatabase.Database,
 	consensusEventsChan chan externalapi.ConsensusEvent,
 	shutDownChan chan<- struct{},
 ) *rpc.Manager {
@@ -154,6 +255,8 @@ func setupRPC(
 		connectionManager,
 		addressManager,
 		utxoIndex,
+		db,
+		databasePath(cfg),
 		consensusEventsChan,
 		shutDownChan,
 	)
@@ -163,6 +266,62 @@ func setupRPC(
 	return rpcManager
 }
 
+// setupPortMapper returns a port mapper for the port of the first P2P listener, that
+// advertises the external address of the mapping to peers
+func setupPortMapper(cfg *config.Config, addressManager *addressmanager.AddressManager) (*portmapper.PortMapper, error) {
+	_, portString, err := net.SplitHostPort(cfg.Listeners[0])
+	if err != nil {
+		return nil, err
+	}
+	port, err := strconv.ParseUint(portString, 10, 16)
+	if err != nil {
+		return nil, errors.Wrapf(err, "invalid port in listener %s", cfg.Listeners[0])
+	}
+
+	return portmapper.New(uint16(port), func(ip net.IP, port uint16) {
+		err := addressManager.AddLocalAddress(appmessage.NewNetAddressIPPort(ip, port), addressmanager.UpnpPrio)
+		if err != nil {
+			log.Warnf("Not advertising the mapped address %s: %s", ip, err)
+		}
+	}), nil
+}
+
+// setupOnionService creates an ephemeral onion service that forwards to the first P2P
+// listener, and advertises its address to peers. The service is removed by Tor when
+// the returned controller is closed.
+func setupOnionService(cfg *config.Config, addressManager *addressmanager.AddressManager) (*torcontrol.Controller, error) {
+	host, portString, err := net.SplitHostPort(cfg.Listeners[0])
+	if err != nil {
+		return nil, err
+	}
+	port, err := strconv.ParseUint(portString, 10, 16)
+	if err != nil {
+		return nil, errors.Wrapf(err, "invalid port in listener %s", cfg.Listeners[0])
+	}
+	if ip := net.ParseIP(host); host == "" || (ip != nil && ip.IsUnspecified()) {
+		host = "127.0.0.1"
+	}
+
+	torController, err := torcontrol.Connect(cfg.TorControl, cfg.TorPassword)
+	if err != nil {
+		return nil, err
+	}
+	publicKey, err := torController.AddOnion(uint16(port), net.JoinHostPort(host, portString))
+	if err != nil {
+		torController.Close()
+		return nil, err
+	}
+
+	onionAddress := appmessage.NewNetAddressOnionV3(publicKey, uint16(port))
+	err = addressManager.AddLocalAddress(onionAddress, addressmanager.ManualPrio)
+	if err != nil {
+		torController.Close()
+		return nil, err
+	}
+	log.Infof("Advertising the onion address %s", onionAddress.DialAddress())
+	return torController, nil
+}
+
 // P2PNodeID returns the network ID associated with this ComponentManager
 func (a *ComponentManager) P2PNodeID() *id.ID {
 	return a.netAdapter.ID()
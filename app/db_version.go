@@ -27,7 +27,8 @@ func checkDatabaseVersion(dbPath string) (err error) {
 	}
 
 	if databaseVersion != currentDatabaseVersion {
-		// TODO: Once there's more then one database version, it might make sense to add upgrade logic at this point
+		// Changes to the records in the database are upgraded in place by the schema
+		// migrations of dbmigration, so this version only changes with the database layout
 		return errors.Errorf("Invalid database version %d. Expected version: %d", databaseVersion, currentDatabaseVersion)
 	}
 
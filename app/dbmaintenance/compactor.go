@@ -0,0 +1,160 @@
+package dbmaintenance
+
+import (
+	"bytes"
+	"sort"
+	"sync"
+
+	"github.com/Hoosat-Oy/HTND/infrastructure/db/database"
+	"github.com/Hoosat-Oy/HTND/infrastructure/logger"
+)
+
+// CompactionState is the state of the compaction of a Compactor
+type CompactionState struct {
+	// IsCompacting is whether a compaction is running
+	IsCompacting bool
+	// Progress is the progress of the running compaction, in percents.
+	// It's 100 once a compaction completes.
+	Progress uint32
+	// LastError is the error the last compaction failed with, or an
+	// empty string if it didn't fail
+	LastError string
+}
+
+// Compactor compacts a database in the background, while the node
+// keeps using it
+type Compactor struct {
+	db database.Database
+
+	lock  sync.Mutex
+	state CompactionState
+}
+
+// NewCompactor returns a new Compactor for the given database
+func NewCompactor(db database.Database) *Compactor {
+	return &Compactor{db: db}
+}
+
+// Start starts compacting the database in the background. It returns false
+// without doing anything if a compaction is already running.
+//
+// Databases that implement database.RangeCompactor are compacted one range of
+// buckets at a time, so the progress of the compaction can be tracked. Any
+// other database is compacted at once, and may block its writers until the
+// compaction is done.
+func (c *Compactor) Start() bool {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if c.state.IsCompacting {
+		return false
+	}
+	c.state = CompactionState{IsCompacting: true}
+
+	spawn("Compactor.compact", func() {
+		err := c.compact()
+
+		c.lock.Lock()
+		defer c.lock.Unlock()
+		c.state.IsCompacting = false
+		if err != nil {
+			log.Errorf("Failed compacting the database: %s", err)
+			c.state.LastError = err.Error()
+			return
+		}
+		c.state.Progress = 100
+	})
+	return true
+}
+
+// State returns the state of the compaction
+func (c *Compactor) State() CompactionState {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	return c.state
+}
+
+func (c *Compactor) setProgress(progress uint32) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	c.state.Progress = progress
+}
+
+func (c *Compactor) compact() error {
+	onEnd := logger.LogAndMeasureExecutionTime(log, "Compactor.compact")
+	defer onEnd()
+
+	rangeCompactor, ok := c.db.(database.RangeCompactor)
+	if !ok {
+		log.Infof("Compacting the database")
+		return c.db.Compact()
+	}
+
+	log.Infof("Collecting the stats of the database buckets")
+	allStats, err := CollectStats(c.db)
+	if err != nil {
+		return err
+	}
+
+	ranges := compactionRanges(allStats)
+	totalSize := uint64(0)
+	for _, compactionRange := range ranges {
+		totalSize += compactionRange.size
+	}
+
+	log.Infof("Compacting the database in %d ranges", len(ranges))
+	compactedSize := uint64(0)
+	progress := uint32(0)
+	for _, compactionRange := range ranges {
+		err := rangeCompactor.CompactRange(compactionRange.start, compactionRange.limit)
+		if err != nil {
+			return err
+		}
+
+		compactedSize += compactionRange.size
+		newProgress := uint32(100 * compactedSize / totalSize)
+		if newProgress > progress {
+			progress = newProgress
+			c.setProgress(progress)
+			log.Infof("Compacted %d%% of the database", progress)
+		}
+	}
+	return nil
+}
+
+// compactionRange is a range of keys that is compacted at once
+type compactionRange struct {
+	start []byte
+	limit []byte
+	// size is the approximate size of the keys in the range, which
+	// weighs the range in the progress of the compaction
+	size uint64
+}
+
+// compactionRanges splits the whole key space of the database into ranges
+// that start at the buckets of the given stats. Keys that were deleted
+// since the stats were collected, such as the keys of a consensus that was
+// deleted altogether, fall in the ranges as well.
+func compactionRanges(allStats []*BucketStats) []*compactionRange {
+	if len(allStats) == 0 {
+		return []*compactionRange{{start: nil, limit: nil, size: 1}}
+	}
+
+	sortedStats := make([]*BucketStats, len(allStats))
+	copy(sortedStats, allStats)
+	sort.Slice(sortedStats, func(i, j int) bool {
+		return bytes.Compare(sortedStats[i].keyPrefix, sortedStats[j].keyPrefix) < 0
+	})
+
+	ranges := make([]*compactionRange, len(sortedStats))
+	for i, bucketStats := range sortedStats {
+		ranges[i] = &compactionRange{start: bucketStats.keyPrefix, size: bucketStats.Size + 1}
+		if i > 0 {
+			ranges[i-1].limit = bucketStats.keyPrefix
+		}
+	}
+	ranges[0].start = nil
+	return ranges
+}
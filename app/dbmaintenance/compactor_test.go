@@ -0,0 +1,46 @@
+package dbmaintenance
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Hoosat-Oy/HTND/infrastructure/db/database"
+	"github.com/Hoosat-Oy/HTND/infrastructure/db/database/ldb"
+)
+
+func TestCompactor(t *testing.T) {
+	db, err := ldb.NewLevelDB(t.TempDir(), 8)
+	if err != nil {
+		t.Fatalf("NewLevelDB: %+v", err)
+	}
+	defer db.Close()
+
+	for _, name := range []string{"peers", "utxo-index"} {
+		bucket := database.MakeBucket([]byte(name))
+		for i := 0; i < 5; i++ {
+			err := db.Put(bucket.Key([]byte{byte(i)}), []byte("value"))
+			if err != nil {
+				t.Fatalf("Put: %+v", err)
+			}
+		}
+	}
+
+	compactor := NewCompactor(db)
+	if !compactor.Start() {
+		t.Fatalf("Start unexpectedly didn't start a compaction")
+	}
+	deadline := time.Now().Add(10 * time.Second)
+	for compactor.State().IsCompacting {
+		if time.Now().After(deadline) {
+			t.Fatalf("Compaction didn't finish in time")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	state := compactor.State()
+	if state.LastError != "" {
+		t.Fatalf("Compaction failed: %s", state.LastError)
+	}
+	if state.Progress != 100 {
+		t.Fatalf("Expected progress 100 after the compaction, got %d", state.Progress)
+	}
+}
@@ -0,0 +1,9 @@
+package dbmaintenance
+
+import (
+	"github.com/Hoosat-Oy/HTND/infrastructure/logger"
+	"github.com/Hoosat-Oy/HTND/util/panics"
+)
+
+var log = logger.RegisterSubSystem("DBMT")
+var spawn = panics.GoroutineWrapperFunc(log)
@@ -0,0 +1,225 @@
+package dbmaintenance
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/Hoosat-Oy/HTND/domain/prefixmanager"
+	"github.com/Hoosat-Oy/HTND/infrastructure/db/database"
+	"github.com/pkg/errors"
+)
+
+// bucketSeparator separates the buckets of a database key
+const bucketSeparator = '/'
+
+// The owners of the database buckets
+const (
+	// NodeOwner owns the buckets that are outside of any consensus, such
+	// as the UTXO index and the address manager
+	NodeOwner = "node"
+
+	// ConsensusOwner owns the buckets of the active consensus
+	ConsensusOwner = "consensus"
+
+	// StagingConsensusOwner owns the buckets of the staging consensus, which
+	// exists while a pruning point UTXO set is being imported
+	StagingConsensusOwner = "staging consensus"
+)
+
+// storesByBucket maps the bucket and key names of the consensus datastructure
+// stores to the store that owns them
+var storesByBucket = map[string]string{
+	"acceptance-data":                       "acceptancedatastore",
+	"block-headers":                         "blockheaderstore",
+	"block-headers-count":                   "blockheaderstore",
+	"block-relations":                       "blockrelationstore",
+	"block-statuses":                        "blockstatusstore",
+	"blocks":                                "blockstore",
+	"blocks-count":                          "blockstore",
+	"virtual-utxo-set":                      "consensusstatestore",
+	"tips":                                  "consensusstatestore",
+	"importing-pruning-point-utxo-set":      "consensusstatestore",
+	"daa-score":                             "daablocksstore",
+	"daa-added-blocks":                      "daablocksstore",
+	"daa-window":                            "daawindowstore",
+	"finality-points":                       "finalitystore",
+	"block-ghostdag-data":                   "ghostdagdatastore",
+	"block-with-trusted-data-ghostdag-data": "ghostdagdatastore",
+	"chain-block-hash-by-index":             "headersselectedchainstore",
+	"chain-block-index-by-hash":             "headersselectedchainstore",
+	"highest-chain-block-index":             "headersselectedchainstore",
+	"headers-selected-tip":                  "headersselectedtipstore",
+	"merge-depth-roots":                     "mergedepthrootstore",
+	"multisets":                             "multisetstore",
+	"pruning-block-index":                   "pruningstore",
+	"candidate-pruning-point-hash":          "pruningstore",
+	"pruning-point-utxo-set":                "pruningstore",
+	"updating-pruning-point-utxo-set":       "pruningstore",
+	"pruning-point-by-index":                "pruningstore",
+	"imported-pruning-point-utxos":          "pruningstore",
+	"imported-pruning-point-multiset":       "pruningstore",
+	"reachability-data":                     "reachabilitydatastore",
+	"reachability-reindex-root":             "reachabilitydatastore",
+	"utxo-diffs":                            "utxodiffstore",
+	"utxo-diff-children":                    "utxodiffstore",
+}
+
+// BucketStats is the amount and size of the entries of a database bucket
+type BucketStats struct {
+	// Owner is NodeOwner, ConsensusOwner or StagingConsensusOwner
+	Owner string
+	// Store is the consensus datastructure store that owns the bucket,
+	// or "-" if there's none
+	Store string
+	// Bucket is the name of the bucket, relative to its consensus prefix
+	Bucket string
+	// KeyCount is the amount of keys in the bucket
+	KeyCount uint64
+	// Size is the total size of the keys and values in the bucket. The
+	// database compresses its files, so this is only an approximation
+	// of the disk space the bucket takes.
+	Size uint64
+
+	// keyPrefix is the path of the bucket in the database
+	keyPrefix []byte
+}
+
+// CollectStats goes over every key of the given database and returns the
+// stats of its buckets, sorted by owner, store and bucket. The node may keep
+// writing to the database while this is called. Only leveldb reads the keys
+// from a snapshot of the database, while bbolt reads them in chunks, so the
+// stats of buckets that are written to meanwhile are only approximate.
+func CollectStats(db database.Database) ([]*BucketStats, error) {
+	activePrefix, hasActivePrefix, err := prefixmanager.ActivePrefix(db)
+	if err != nil {
+		return nil, err
+	}
+	inactivePrefix, hasInactivePrefix, err := prefixmanager.InactivePrefix(db)
+	if err != nil {
+		return nil, err
+	}
+	var activePrefixPath, inactivePrefixPath []byte
+	if hasActivePrefix {
+		activePrefixPath = database.MakeBucket(activePrefix.Serialize()).Path()
+	}
+	if hasInactivePrefix {
+		inactivePrefixPath = database.MakeBucket(inactivePrefix.Serialize()).Path()
+	}
+
+	cursor, err := db.Cursor(database.MakeBucket(nil))
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close()
+
+	statsByBucket := make(map[string]*BucketStats)
+	for ok := cursor.First(); ok; ok = cursor.Next() {
+		key, err := cursor.Key()
+		if err != nil {
+			return nil, err
+		}
+		value, err := cursor.Value()
+		if err != nil {
+			return nil, err
+		}
+
+		keyBytes := key.Bytes()
+		owner := NodeOwner
+		var ownerPath []byte
+		switch {
+		case activePrefixPath != nil && bytes.HasPrefix(keyBytes, activePrefixPath):
+			owner = ConsensusOwner
+			ownerPath = activePrefixPath
+		case inactivePrefixPath != nil && bytes.HasPrefix(keyBytes, inactivePrefixPath):
+			owner = StagingConsensusOwner
+			ownerPath = inactivePrefixPath
+		}
+		relativeKey := keyBytes[len(ownerPath):]
+
+		bucket, bucketPathLength := keyBucket(relativeKey)
+		statsKey := owner + string(bucketSeparator) + bucket
+		bucketStats, ok := statsByBucket[statsKey]
+		if !ok {
+			store := "-"
+			if owner != NodeOwner {
+				store = storeOfBucket(bucket)
+			}
+			keyPrefix := make([]byte, len(ownerPath)+bucketPathLength)
+			copy(keyPrefix, keyBytes)
+			bucketStats = &BucketStats{Owner: owner, Store: store, Bucket: bucket, keyPrefix: keyPrefix}
+			statsByBucket[statsKey] = bucketStats
+		}
+		bucketStats.KeyCount++
+		bucketStats.Size += uint64(len(relativeKey) + len(value))
+	}
+
+	allStats := make([]*BucketStats, 0, len(statsByBucket))
+	for _, bucketStats := range statsByBucket {
+		allStats = append(allStats, bucketStats)
+	}
+	sort.Slice(allStats, func(i, j int) bool {
+		if allStats[i].Owner != allStats[j].Owner {
+			return allStats[i].Owner < allStats[j].Owner
+		}
+		if allStats[i].Store != allStats[j].Store {
+			return allStats[i].Store < allStats[j].Store
+		}
+		return allStats[i].Bucket < allStats[j].Bucket
+	})
+	return allStats, nil
+}
+
+// keyBucket returns the name of the bucket of the given key, relative to its
+// consensus prefix, along with the length of the path of the bucket in the key.
+// Keys without a bucket are their own bucket. The per-level stores are prefixed
+// with a single level byte, which is printed as a number.
+func keyBucket(key []byte) (string, int) {
+	// A level byte that equals the separator isn't followed by another separator
+	if len(key) >= 1 && key[0] == bucketSeparator {
+		bucket, pathLength := keyBucket(key[1:])
+		return fmt.Sprintf("level-%d%c%s", bucketSeparator, bucketSeparator, bucket), 1 + pathLength
+	}
+	if len(key) >= 2 && key[1] == bucketSeparator {
+		bucket, pathLength := keyBucket(key[2:])
+		return fmt.Sprintf("level-%d%c%s", key[0], bucketSeparator, bucket), 2 + pathLength
+	}
+
+	separatorIndex := bytes.IndexByte(key, bucketSeparator)
+	if separatorIndex < 0 {
+		return string(key), len(key)
+	}
+	return string(key[:separatorIndex]), separatorIndex + 1
+}
+
+func storeOfBucket(bucket string) string {
+	separatorIndex := bytes.LastIndexByte([]byte(bucket), bucketSeparator)
+	if store, ok := storesByBucket[bucket[separatorIndex+1:]]; ok {
+		return store
+	}
+	return "-"
+}
+
+// DiskSize returns the total size of the files in the given database directory
+func DiskSize(path string) (uint64, error) {
+	size := uint64(0)
+	err := filepath.Walk(path, func(_ string, info os.FileInfo, err error) error {
+		if err != nil {
+			// The database may remove its files while they're walked
+			if os.IsNotExist(err) {
+				return nil
+			}
+			return err
+		}
+		if !info.IsDir() {
+			size += uint64(info.Size())
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, errors.WithStack(err)
+	}
+	return size, nil
+}
@@ -0,0 +1,85 @@
+package dbmaintenance
+
+import (
+	"sync"
+
+	"github.com/Hoosat-Oy/HTND/infrastructure/db/database"
+	"github.com/Hoosat-Oy/HTND/infrastructure/logger"
+	"github.com/Hoosat-Oy/HTND/util/mstime"
+)
+
+// StatsCollectionState is the state of the stats collection of a StatsCollector
+type StatsCollectionState struct {
+	// IsCollecting is whether a collection is running
+	IsCollecting bool
+	// Buckets are the stats of the last successful collection, or nil if
+	// no collection completed yet
+	Buckets []*BucketStats
+	// CollectedAt is when the last successful collection completed. It's
+	// the zero time if no collection completed yet.
+	CollectedAt mstime.Time
+	// LastError is the error the last collection failed with, or an
+	// empty string if it didn't fail
+	LastError string
+}
+
+// StatsCollector collects the stats of the buckets of a database in the
+// background, and keeps the result of the last collection. Collecting the
+// stats reads every key of the database, so it's never done implicitly.
+type StatsCollector struct {
+	db database.Database
+
+	lock  sync.Mutex
+	state StatsCollectionState
+}
+
+// NewStatsCollector returns a new StatsCollector for the given database
+func NewStatsCollector(db database.Database) *StatsCollector {
+	return &StatsCollector{db: db}
+}
+
+// Start starts collecting the stats of the database in the background. It
+// returns false without doing anything if a collection is already running.
+// The stats of the previous collection remain available until the new
+// collection completes.
+func (c *StatsCollector) Start() bool {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if c.state.IsCollecting {
+		return false
+	}
+	c.state.IsCollecting = true
+	c.state.LastError = ""
+
+	spawn("StatsCollector.collect", func() {
+		allStats, err := c.collect()
+
+		c.lock.Lock()
+		defer c.lock.Unlock()
+		c.state.IsCollecting = false
+		if err != nil {
+			log.Errorf("Failed collecting the database stats: %s", err)
+			c.state.LastError = err.Error()
+			return
+		}
+		c.state.Buckets = allStats
+		c.state.CollectedAt = mstime.Now()
+	})
+	return true
+}
+
+// State returns the state of the stats collection
+func (c *StatsCollector) State() StatsCollectionState {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	return c.state
+}
+
+func (c *StatsCollector) collect() ([]*BucketStats, error) {
+	onEnd := logger.LogAndMeasureExecutionTime(log, "StatsCollector.collect")
+	defer onEnd()
+
+	return CollectStats(c.db)
+}
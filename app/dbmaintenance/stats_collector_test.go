@@ -0,0 +1,58 @@
+package dbmaintenance
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Hoosat-Oy/HTND/infrastructure/db/database"
+	"github.com/Hoosat-Oy/HTND/infrastructure/db/database/ldb"
+)
+
+func TestStatsCollector(t *testing.T) {
+	db, err := ldb.NewLevelDB(t.TempDir(), 8)
+	if err != nil {
+		t.Fatalf("NewLevelDB: %+v", err)
+	}
+	defer db.Close()
+
+	collector := NewStatsCollector(db)
+	state := collector.State()
+	if state.IsCollecting || state.Buckets != nil || !state.CollectedAt.IsZero() {
+		t.Fatalf("Expected no stats before the first collection, got %+v", state)
+	}
+
+	collect := func() StatsCollectionState {
+		if !collector.Start() {
+			t.Fatalf("Start unexpectedly didn't start a collection")
+		}
+		deadline := time.Now().Add(10 * time.Second)
+		for collector.State().IsCollecting {
+			if time.Now().After(deadline) {
+				t.Fatalf("Collection didn't finish in time")
+			}
+			time.Sleep(10 * time.Millisecond)
+		}
+		state := collector.State()
+		if state.LastError != "" {
+			t.Fatalf("Collection failed: %s", state.LastError)
+		}
+		if state.CollectedAt.IsZero() {
+			t.Fatalf("Expected the collection time to be set after the collection")
+		}
+		return state
+	}
+
+	state = collect()
+	if len(state.Buckets) != 0 {
+		t.Fatalf("Expected no buckets in an empty database, got %d", len(state.Buckets))
+	}
+
+	err = db.Put(database.MakeBucket([]byte("peers")).Key([]byte{0}), []byte("value"))
+	if err != nil {
+		t.Fatalf("Put: %+v", err)
+	}
+	state = collect()
+	if len(state.Buckets) != 1 || state.Buckets[0].Bucket != "peers" || state.Buckets[0].KeyCount != 1 {
+		t.Fatalf("Expected a single key in bucket peers, got %+v", state.Buckets)
+	}
+}
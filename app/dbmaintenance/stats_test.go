@@ -0,0 +1,49 @@
+package dbmaintenance
+
+import (
+	"testing"
+
+	"github.com/Hoosat-Oy/HTND/infrastructure/db/database"
+	"github.com/Hoosat-Oy/HTND/infrastructure/db/database/ldb"
+)
+
+func TestCollectStats(t *testing.T) {
+	db, err := ldb.NewLevelDB(t.TempDir(), 8)
+	if err != nil {
+		t.Fatalf("NewLevelDB: %+v", err)
+	}
+	defer db.Close()
+
+	buckets := map[string]int{"peers": 3, "utxo-index": 5}
+	for name, keyCount := range buckets {
+		bucket := database.MakeBucket([]byte(name))
+		for i := 0; i < keyCount; i++ {
+			err := db.Put(bucket.Key([]byte{byte(i)}), []byte("value"))
+			if err != nil {
+				t.Fatalf("Put: %+v", err)
+			}
+		}
+	}
+
+	allStats, err := CollectStats(db)
+	if err != nil {
+		t.Fatalf("CollectStats: %+v", err)
+	}
+	if len(allStats) != len(buckets) {
+		t.Fatalf("Expected stats of %d buckets, got %d", len(buckets), len(allStats))
+	}
+	for _, bucketStats := range allStats {
+		if bucketStats.Owner != NodeOwner {
+			t.Fatalf("Expected bucket %s to be owned by %s, got %s", bucketStats.Bucket, NodeOwner, bucketStats.Owner)
+		}
+		expectedKeyCount := uint64(buckets[bucketStats.Bucket])
+		if bucketStats.KeyCount != expectedKeyCount {
+			t.Fatalf("Expected %d keys in bucket %s, got %d", expectedKeyCount, bucketStats.Bucket, bucketStats.KeyCount)
+		}
+		// Every key is the bucket name, a separator and a single byte
+		expectedSize := expectedKeyCount * uint64(len(bucketStats.Bucket)+2+len("value"))
+		if bucketStats.Size != expectedSize {
+			t.Fatalf("Expected bucket %s to be %d bytes, got %d", bucketStats.Bucket, expectedSize, bucketStats.Size)
+		}
+	}
+}
@@ -0,0 +1,163 @@
+package dbmigration
+
+import (
+	"encoding/binary"
+	"strconv"
+
+	"github.com/Hoosat-Oy/HTND/domain/prefixmanager"
+	"github.com/Hoosat-Oy/HTND/infrastructure/db/database"
+	"github.com/pkg/errors"
+)
+
+var schemaVersionKey = database.MakeBucket(nil).Key([]byte("schema-version"))
+var migrationProgressBucket = database.MakeBucket([]byte("schema-migration-progress"))
+
+// Migration is an upgrade step of the database schema
+type Migration struct {
+	// Version is the schema version of the database after the migration
+	Version uint32
+	// Description is shown in the logs and by dry runs
+	Description string
+	// Migrate upgrades the database from the previous schema version. It
+	// may store its progress in progressKey, which is kept until the
+	// migration is done.
+	Migrate func(db database.Database, progressKey *database.Key) error
+}
+
+// LatestSchemaVersion returns the schema version of databases that all the
+// migrations were applied to
+func LatestSchemaVersion() uint32 {
+	if len(migrations) == 0 {
+		return baseSchemaVersion
+	}
+	return migrations[len(migrations)-1].Version
+}
+
+// SchemaVersion returns the schema version of the given database. Databases
+// that were created before the schema version was stored have baseSchemaVersion,
+// and new databases have the latest schema version.
+func SchemaVersion(db database.Database) (uint32, error) {
+	serializedVersion, err := db.Get(schemaVersionKey)
+	if err == nil {
+		if len(serializedVersion) != 4 {
+			return 0, errors.Errorf("invalid schema version length %d", len(serializedVersion))
+		}
+		return binary.LittleEndian.Uint32(serializedVersion), nil
+	}
+	if !database.IsNotFoundError(err) {
+		return 0, err
+	}
+
+	// Every database that was ever used by a node has an active consensus prefix
+	_, hasActivePrefix, err := prefixmanager.ActivePrefix(db)
+	if err != nil {
+		return 0, err
+	}
+	if !hasActivePrefix {
+		return LatestSchemaVersion(), nil
+	}
+	return baseSchemaVersion, nil
+}
+
+// PendingMigrations returns the migrations that weren't applied to the given
+// database yet, in the order they have to be applied
+func PendingMigrations(db database.Database) ([]*Migration, error) {
+	version, err := SchemaVersion(db)
+	if err != nil {
+		return nil, err
+	}
+	return pendingMigrationsFrom(version)
+}
+
+func pendingMigrationsFrom(version uint32) ([]*Migration, error) {
+	latestVersion := LatestSchemaVersion()
+	if version > latestVersion {
+		return nil, errors.Errorf("the database has schema version %d, which is newer than the "+
+			"latest version %d known to this version of htnd", version, latestVersion)
+	}
+
+	pendingMigrations := make([]*Migration, 0)
+	for _, migration := range migrations {
+		if migration.Version > version {
+			pendingMigrations = append(pendingMigrations, migration)
+		}
+	}
+	return pendingMigrations, nil
+}
+
+// Run applies the pending migrations to the given database, in order, and
+// stores the schema version after each of them. A migration that was
+// interrupted is resumed the next time Run is called.
+//
+// If dryRun is set, the pending migrations are only logged and the database
+// isn't changed.
+func Run(db database.Database, dryRun bool) error {
+	version, err := SchemaVersion(db)
+	if err != nil {
+		return err
+	}
+	pendingMigrations, err := pendingMigrationsFrom(version)
+	if err != nil {
+		return err
+	}
+
+	if dryRun {
+		if len(pendingMigrations) == 0 {
+			log.Infof("The database schema is up to date at version %d", version)
+			return nil
+		}
+		log.Infof("The database schema is at version %d. %d migrations are pending:", version, len(pendingMigrations))
+		for _, migration := range pendingMigrations {
+			log.Infof("Migration to version %d: %s", migration.Version, migration.Description)
+		}
+		return nil
+	}
+
+	for _, migration := range pendingMigrations {
+		log.Infof("Migrating the database schema to version %d: %s", migration.Version, migration.Description)
+		progressKey := migrationProgressKey(migration.Version)
+		err := migration.Migrate(db, progressKey)
+		if err != nil {
+			return errors.Wrapf(err, "failed migrating the database schema to version %d", migration.Version)
+		}
+		err = completeMigration(db, migration.Version, progressKey)
+		if err != nil {
+			return err
+		}
+		log.Infof("Migrated the database schema to version %d", migration.Version)
+	}
+
+	// New databases, and databases that are already up to date, are
+	// marked with the latest version
+	return putSchemaVersion(db, LatestSchemaVersion())
+}
+
+func migrationProgressKey(version uint32) *database.Key {
+	return migrationProgressBucket.Key([]byte(strconv.FormatUint(uint64(version), 10)))
+}
+
+// completeMigration stores the schema version that the migration upgraded
+// the database to and deletes its progress, in a single transaction
+func completeMigration(db database.Database, version uint32, progressKey *database.Key) error {
+	dbTransaction, err := db.Begin()
+	if err != nil {
+		return err
+	}
+	defer dbTransaction.RollbackUnlessClosed()
+
+	err = putSchemaVersion(dbTransaction, version)
+	if err != nil {
+		return err
+	}
+	err = dbTransaction.Delete(progressKey)
+	if err != nil {
+		return err
+	}
+	return dbTransaction.Commit()
+}
+
+func putSchemaVersion(dataAccessor database.DataAccessor, version uint32) error {
+	serializedVersion := make([]byte, 4)
+	binary.LittleEndian.PutUint32(serializedVersion, version)
+	return dataAccessor.Put(schemaVersionKey, serializedVersion)
+}
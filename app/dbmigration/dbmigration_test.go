@@ -0,0 +1,144 @@
+package dbmigration
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/Hoosat-Oy/HTND/infrastructure/db/database"
+	"github.com/Hoosat-Oy/HTND/infrastructure/db/database/ldb"
+	"github.com/pkg/errors"
+)
+
+var activePrefixKey = database.MakeBucket(nil).Key([]byte("active-prefix"))
+
+func newDatabaseForTest(t *testing.T, isExisting bool) database.Database {
+	db, err := ldb.NewLevelDB(t.TempDir(), 8)
+	if err != nil {
+		t.Fatalf("NewLevelDB: %+v", err)
+	}
+	if isExisting {
+		err = db.Put(activePrefixKey, []byte{0})
+		if err != nil {
+			t.Fatalf("Put: %+v", err)
+		}
+	}
+	return db
+}
+
+func expectSchemaVersion(t *testing.T, db database.Database, expectedVersion uint32) {
+	version, err := SchemaVersion(db)
+	if err != nil {
+		t.Fatalf("SchemaVersion: %+v", err)
+	}
+	if version != expectedVersion {
+		t.Fatalf("Expected schema version %d, got %d", expectedVersion, version)
+	}
+}
+
+func TestRun(t *testing.T) {
+	var appliedVersions []uint32
+	failVersion3 := true
+	originalMigrations := migrations
+	defer func() { migrations = originalMigrations }()
+	migrations = []*Migration{
+		{
+			Version: 2,
+			Migrate: func(db database.Database, progressKey *database.Key) error {
+				appliedVersions = append(appliedVersions, 2)
+				return nil
+			},
+		},
+		{
+			Version: 3,
+			Migrate: func(db database.Database, progressKey *database.Key) error {
+				appliedVersions = append(appliedVersions, 3)
+				if failVersion3 {
+					err := db.Put(progressKey, []byte("halfway"))
+					if err != nil {
+						return err
+					}
+					return errors.New("interrupted")
+				}
+				progress, err := db.Get(progressKey)
+				if err != nil {
+					return err
+				}
+				if string(progress) != "halfway" {
+					return errors.Errorf("unexpected progress %s", progress)
+				}
+				return nil
+			},
+		},
+	}
+
+	db := newDatabaseForTest(t, true)
+	defer db.Close()
+	expectSchemaVersion(t, db, baseSchemaVersion)
+
+	err := Run(db, true)
+	if err != nil {
+		t.Fatalf("Run: %+v", err)
+	}
+	if len(appliedVersions) != 0 {
+		t.Fatalf("A dry run applied migrations %v", appliedVersions)
+	}
+
+	err = Run(db, false)
+	if err == nil || !strings.Contains(err.Error(), "interrupted") {
+		t.Fatalf("Expected the interrupted migration to fail Run, got: %v", err)
+	}
+	expectSchemaVersion(t, db, 2)
+
+	failVersion3 = false
+	err = Run(db, false)
+	if err != nil {
+		t.Fatalf("Run: %+v", err)
+	}
+	expectSchemaVersion(t, db, 3)
+	expectedAppliedVersions := []uint32{2, 3, 3}
+	if len(appliedVersions) != len(expectedAppliedVersions) {
+		t.Fatalf("Expected applied migrations %v, got %v", expectedAppliedVersions, appliedVersions)
+	}
+	for i := range appliedVersions {
+		if appliedVersions[i] != expectedAppliedVersions[i] {
+			t.Fatalf("Expected applied migrations %v, got %v", expectedAppliedVersions, appliedVersions)
+		}
+	}
+
+	hasProgress, err := db.Has(migrationProgressKey(3))
+	if err != nil {
+		t.Fatalf("Has: %+v", err)
+	}
+	if hasProgress {
+		t.Fatalf("The progress of a completed migration wasn't deleted")
+	}
+
+	// A database with a newer schema than the latest migration can't be opened
+	err = putSchemaVersion(db, 4)
+	if err != nil {
+		t.Fatalf("putSchemaVersion: %+v", err)
+	}
+	err = Run(db, false)
+	if err == nil {
+		t.Fatalf("Run unexpectedly succeeded on a database with a newer schema")
+	}
+}
+
+func TestRunNewDatabase(t *testing.T) {
+	db := newDatabaseForTest(t, false)
+	defer db.Close()
+
+	pendingMigrations, err := PendingMigrations(db)
+	if err != nil {
+		t.Fatalf("PendingMigrations: %+v", err)
+	}
+	if len(pendingMigrations) != 0 {
+		t.Fatalf("Expected no pending migrations for a new database, got %d", len(pendingMigrations))
+	}
+
+	err = Run(db, false)
+	if err != nil {
+		t.Fatalf("Run: %+v", err)
+	}
+	expectSchemaVersion(t, db, LatestSchemaVersion())
+}
@@ -0,0 +1,23 @@
+package dbmigration
+
+import (
+	"github.com/Hoosat-Oy/HTND/domain/utxoindex"
+)
+
+// baseSchemaVersion is the schema version of databases that were created
+// before the schema version was stored in the database
+const baseSchemaVersion = 1
+
+// migrations are the upgrade steps of the database schema, in the order of
+// their versions. A migration may be interrupted at any point, and has to
+// resume from its progress key the next time it runs.
+//
+// Append new migrations to the end of the list, with the next version. Never
+// change or remove a released migration.
+var migrations = []*Migration{
+	{
+		Version:     2,
+		Description: "Add the per-address balance and UTXO count aggregates to the UTXO index",
+		Migrate:     utxoindex.MigrateAggregates,
+	},
+}
@@ -0,0 +1,41 @@
+package app
+
+import (
+	"github.com/Hoosat-Oy/HTND/app/blockarchive"
+	"github.com/Hoosat-Oy/HTND/app/snapshot"
+	"github.com/Hoosat-Oy/HTND/domain"
+	"github.com/Hoosat-Oy/HTND/domain/consensus"
+	"github.com/Hoosat-Oy/HTND/domain/miningmanager/mempool"
+	"github.com/Hoosat-Oy/HTND/infrastructure/config"
+	"github.com/Hoosat-Oy/HTND/infrastructure/db/database"
+	"github.com/pkg/errors"
+)
+
+// export writes the files given with --export-snapshot and --export-blocks.
+// No other component is started, so the DAG doesn't change while they're
+// written.
+func export(cfg *config.Config, db database.Database) error {
+	consensusConfig := consensus.Config{
+		Params:            *cfg.ActiveNetParams,
+		IsArchival:        cfg.IsArchivalNode,
+		CacheMemoryBudget: cfg.CacheMem * 1024 * 1024,
+	}
+	domain, err := domain.New(&consensusConfig, mempool.DefaultConfig(&consensusConfig.Params), db)
+	if err != nil {
+		return err
+	}
+
+	if cfg.ExportSnapshot != "" {
+		err = snapshot.Export(domain.Consensus(), cfg.NetParams(), cfg.ExportSnapshot)
+		if err != nil {
+			return errors.Wrap(err, "failed exporting the snapshot")
+		}
+	}
+	if cfg.ExportBlocks != "" {
+		err = blockarchive.Export(domain.Consensus(), cfg.NetParams(), cfg.ExportBlocks)
+		if err != nil {
+			return errors.Wrap(err, "failed exporting the blocks")
+		}
+	}
+	return nil
+}
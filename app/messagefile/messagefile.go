@@ -0,0 +1,367 @@
+// Package messagefile reads and writes files of length-prefixed P2P messages,
+// such as pruning point snapshots and block archives.
+//
+// A file starts with the magic bytes and version of its format, and the name
+// of the network it belongs to. It's followed by records, each of which is a
+// little-endian uint32 length and a serialized protowire.HoosatdMessage. An
+// empty record marks the end of the records. The format may add its own data
+// after the records, and the file ends with the SHA256 checksum of everything
+// before it.
+package messagefile
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"encoding/binary"
+	"hash"
+	"io"
+	"os"
+
+	"github.com/Hoosat-Oy/HTND/app/appmessage"
+	"github.com/Hoosat-Oy/HTND/infrastructure/network/netadapter/server/grpcserver/protowire"
+	"github.com/pkg/errors"
+	"google.golang.org/protobuf/proto"
+)
+
+// maxRecordSize is the size limit of a single record. It matches the P2P
+// message size limit, since every record is a P2P message.
+const maxRecordSize = 1024 * 1024 * 1024 // 1GB
+
+// ChecksumSize is the size of the checksum at the end of a file
+const ChecksumSize = sha256.Size
+
+// tempFileSuffix is appended to the path of a file while it's written, so
+// that an interrupted write doesn't leave a truncated file behind
+const tempFileSuffix = ".tmp"
+
+// Format identifies the kind of data in a file
+type Format struct {
+	Name    string
+	Magic   []byte
+	Version uint32
+}
+
+// Writer writes a file of messages
+type Writer struct {
+	path     string
+	file     *os.File
+	buffered *bufio.Writer
+	hasher   hash.Hash
+	out      io.Writer
+	offset   uint64
+}
+
+// Create starts writing a file of the given format to the given path. The
+// file only appears in the path once Finish is called.
+func Create(path string, format *Format, networkName string) (*Writer, error) {
+	file, err := os.Create(path + tempFileSuffix)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	buffered := bufio.NewWriter(file)
+	hasher := sha256.New()
+	w := &Writer{
+		path:     path,
+		file:     file,
+		buffered: buffered,
+		hasher:   hasher,
+		out:      io.MultiWriter(buffered, hasher),
+	}
+
+	err = w.writeHeader(format, networkName)
+	if err != nil {
+		w.Abort()
+		return nil, err
+	}
+	return w, nil
+}
+
+func (w *Writer) writeHeader(format *Format, networkName string) error {
+	err := w.Write(format.Magic)
+	if err != nil {
+		return err
+	}
+	err = w.WriteUint32(format.Version)
+	if err != nil {
+		return err
+	}
+	err = w.WriteUint32(uint32(len(networkName)))
+	if err != nil {
+		return err
+	}
+	return w.Write([]byte(networkName))
+}
+
+// Offset returns the offset in the file of the next byte written
+func (w *Writer) Offset() uint64 {
+	return w.offset
+}
+
+// Write writes raw data to the file
+func (w *Writer) Write(data []byte) error {
+	n, err := w.out.Write(data)
+	w.offset += uint64(n)
+	return errors.WithStack(err)
+}
+
+// WriteUint32 writes a little-endian uint32 to the file
+func (w *Writer) WriteUint32(value uint32) error {
+	var serializedValue [4]byte
+	binary.LittleEndian.PutUint32(serializedValue[:], value)
+	return w.Write(serializedValue[:])
+}
+
+// WriteUint64 writes a little-endian uint64 to the file
+func (w *Writer) WriteUint64(value uint64) error {
+	var serializedValue [8]byte
+	binary.LittleEndian.PutUint64(serializedValue[:], value)
+	return w.Write(serializedValue[:])
+}
+
+// WriteMessage writes the given message as a record
+func (w *Writer) WriteMessage(message appmessage.Message) error {
+	protoMessage, err := protowire.FromAppMessage(message)
+	if err != nil {
+		return err
+	}
+	serializedMessage, err := proto.Marshal(protoMessage)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	if len(serializedMessage) > maxRecordSize {
+		return errors.Errorf("%s message of %d bytes exceeds the record size limit of %d bytes",
+			message.Command(), len(serializedMessage), maxRecordSize)
+	}
+
+	err = w.WriteUint32(uint32(len(serializedMessage)))
+	if err != nil {
+		return err
+	}
+	return w.Write(serializedMessage)
+}
+
+// WriteEnd marks the end of the records
+func (w *Writer) WriteEnd() error {
+	return w.WriteUint32(0)
+}
+
+// Finish writes the checksum and moves the file to its final path
+func (w *Writer) Finish() error {
+	_, err := w.buffered.Write(w.hasher.Sum(nil))
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	err = w.buffered.Flush()
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	err = w.file.Sync()
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	err = w.file.Close()
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	return errors.WithStack(os.Rename(w.path+tempFileSuffix, w.path))
+}
+
+// Abort closes and removes a file that wasn't finished
+func (w *Writer) Abort() {
+	_ = w.file.Close()
+	_ = os.Remove(w.path + tempFileSuffix)
+}
+
+// Reader reads a file of messages
+type Reader struct {
+	format   *Format
+	file     *os.File
+	buffered *bufio.Reader
+	size     int64
+	offset   uint64
+}
+
+// Open opens the file in the given path, and makes sure that it has the
+// given format and belongs to the given network
+func Open(path string, format *Format, networkName string) (*Reader, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	fileInfo, err := file.Stat()
+	if err != nil {
+		_ = file.Close()
+		return nil, errors.WithStack(err)
+	}
+	r := &Reader{
+		format:   format,
+		file:     file,
+		buffered: bufio.NewReader(file),
+		size:     fileInfo.Size(),
+	}
+
+	err = r.readHeader(networkName)
+	if err != nil {
+		r.Close()
+		return nil, err
+	}
+	return r, nil
+}
+
+func (r *Reader) readHeader(networkName string) error {
+	magic := make([]byte, len(r.format.Magic))
+	err := r.Read(magic)
+	if err != nil || !bytes.Equal(magic, r.format.Magic) {
+		return errors.Errorf("the file is not a %s", r.format.Name)
+	}
+
+	version, err := r.ReadUint32()
+	if err != nil {
+		return err
+	}
+	if version != r.format.Version {
+		return errors.Errorf("unsupported %s version %d -- supported version: %d",
+			r.format.Name, version, r.format.Version)
+	}
+
+	networkNameLength, err := r.ReadUint32()
+	if err != nil {
+		return err
+	}
+	if networkNameLength > maxRecordSize {
+		return errors.Errorf("invalid network name length %d", networkNameLength)
+	}
+	fileNetworkName := make([]byte, networkNameLength)
+	err = r.Read(fileNetworkName)
+	if err != nil {
+		return err
+	}
+	if string(fileNetworkName) != networkName {
+		return errors.Errorf("the %s belongs to %s and can't be used with %s",
+			r.format.Name, fileNetworkName, networkName)
+	}
+	return nil
+}
+
+// Size returns the size of the file
+func (r *Reader) Size() int64 {
+	return r.size
+}
+
+// Seek moves the reader to the given offset in the file
+func (r *Reader) Seek(offset uint64) error {
+	if offset > uint64(r.size) {
+		return errors.Errorf("offset %d is beyond the end of the %s", offset, r.format.Name)
+	}
+	_, err := r.file.Seek(int64(offset), io.SeekStart)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	r.buffered.Reset(r.file)
+	r.offset = offset
+	return nil
+}
+
+// Offset returns the offset in the file of the next byte read
+func (r *Reader) Offset() uint64 {
+	return r.offset
+}
+
+// Read fills data with the next bytes of the file
+func (r *Reader) Read(data []byte) error {
+	n, err := io.ReadFull(r.buffered, data)
+	r.offset += uint64(n)
+	if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+		return errors.Errorf("the %s is truncated", r.format.Name)
+	}
+	return errors.WithStack(err)
+}
+
+// ReadUint32 reads a little-endian uint32
+func (r *Reader) ReadUint32() (uint32, error) {
+	var serializedValue [4]byte
+	err := r.Read(serializedValue[:])
+	if err != nil {
+		return 0, err
+	}
+	return binary.LittleEndian.Uint32(serializedValue[:]), nil
+}
+
+// ReadUint64 reads a little-endian uint64
+func (r *Reader) ReadUint64() (uint64, error) {
+	var serializedValue [8]byte
+	err := r.Read(serializedValue[:])
+	if err != nil {
+		return 0, err
+	}
+	return binary.LittleEndian.Uint64(serializedValue[:]), nil
+}
+
+// ReadMessage returns the message of the next record, or nil when there are
+// no records left
+func (r *Reader) ReadMessage() (appmessage.Message, error) {
+	recordSize, err := r.ReadUint32()
+	if err != nil {
+		return nil, err
+	}
+	if recordSize == 0 {
+		return nil, nil
+	}
+	if recordSize > maxRecordSize {
+		return nil, errors.Errorf("record of %d bytes exceeds the record size limit of %d bytes",
+			recordSize, maxRecordSize)
+	}
+
+	serializedMessage := make([]byte, recordSize)
+	err = r.Read(serializedMessage)
+	if err != nil {
+		return nil, err
+	}
+	protoMessage := &protowire.HoosatdMessage{}
+	err = proto.Unmarshal(serializedMessage, protoMessage)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed deserializing a %s record", r.format.Name)
+	}
+	return protoMessage.ToAppMessage()
+}
+
+// Close closes the file
+func (r *Reader) Close() {
+	_ = r.file.Close()
+}
+
+// VerifyChecksum checks that the content of the file of the given format in
+// the given path matches the checksum at its end
+func VerifyChecksum(path string, format *Format) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	defer file.Close()
+
+	fileInfo, err := file.Stat()
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	contentSize := fileInfo.Size() - ChecksumSize
+	if contentSize < int64(len(format.Magic)) {
+		return errors.Errorf("the %s is truncated", format.Name)
+	}
+
+	hasher := sha256.New()
+	_, err = io.Copy(hasher, io.LimitReader(file, contentSize))
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	checksum := make([]byte, ChecksumSize)
+	_, err = io.ReadFull(file, checksum)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	if !bytes.Equal(hasher.Sum(nil), checksum) {
+		return errors.Errorf("the %s is corrupted: its checksum doesn't match its content", format.Name)
+	}
+	return nil
+}
@@ -0,0 +1,174 @@
+package messagefile
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/Hoosat-Oy/HTND/app/appmessage"
+	"github.com/Hoosat-Oy/HTND/domain/consensus/model/externalapi"
+)
+
+var testFormat = &Format{
+	Name:    "test file",
+	Magic:   []byte("htntest"),
+	Version: 1,
+}
+
+func writeTestFile(t *testing.T, path string, messages []appmessage.Message) []uint64 {
+	w, err := Create(path, testFormat, "hoosat-testnet")
+	if err != nil {
+		t.Fatalf("Create: %+v", err)
+	}
+	offsets := make([]uint64, len(messages))
+	for i, message := range messages {
+		offsets[i] = w.Offset()
+		err = w.WriteMessage(message)
+		if err != nil {
+			t.Fatalf("WriteMessage: %+v", err)
+		}
+	}
+	err = w.WriteEnd()
+	if err != nil {
+		t.Fatalf("WriteEnd: %+v", err)
+	}
+	err = w.Finish()
+	if err != nil {
+		t.Fatalf("Finish: %+v", err)
+	}
+	return offsets
+}
+
+func TestMessageFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "file")
+	pruningPointHash := externalapi.NewDomainHashFromByteArray(&[externalapi.DomainHashSize]byte{1})
+	offsets := writeTestFile(t, path, []appmessage.Message{
+		appmessage.NewMsgRequestPruningPointUTXOSet(pruningPointHash),
+		appmessage.NewMsgDoneHeaders(),
+	})
+
+	_, err := os.Stat(path + tempFileSuffix)
+	if !os.IsNotExist(err) {
+		t.Fatalf("the temporary file wasn't removed: %v", err)
+	}
+
+	err = VerifyChecksum(path, testFormat)
+	if err != nil {
+		t.Fatalf("VerifyChecksum: %+v", err)
+	}
+
+	r, err := Open(path, testFormat, "hoosat-testnet")
+	if err != nil {
+		t.Fatalf("Open: %+v", err)
+	}
+	defer r.Close()
+	if r.Offset() != offsets[0] {
+		t.Fatalf("unexpected offset %d of the first record, expected %d", r.Offset(), offsets[0])
+	}
+
+	message, err := r.ReadMessage()
+	if err != nil {
+		t.Fatalf("ReadMessage: %+v", err)
+	}
+	msgRequestPruningPointUTXOSet, ok := message.(*appmessage.MsgRequestPruningPointUTXOSet)
+	if !ok {
+		t.Fatalf("unexpected message %s", message.Command())
+	}
+	if !msgRequestPruningPointUTXOSet.PruningPointHash.Equal(pruningPointHash) {
+		t.Fatalf("unexpected pruning point hash %s", msgRequestPruningPointUTXOSet.PruningPointHash)
+	}
+
+	message, err = r.ReadMessage()
+	if err != nil {
+		t.Fatalf("ReadMessage: %+v", err)
+	}
+	if _, ok := message.(*appmessage.MsgDoneHeaders); !ok {
+		t.Fatalf("unexpected message %s", message.Command())
+	}
+
+	message, err = r.ReadMessage()
+	if err != nil {
+		t.Fatalf("ReadMessage: %+v", err)
+	}
+	if message != nil {
+		t.Fatalf("expected the end of the records, got %s", message.Command())
+	}
+
+	err = r.Seek(offsets[1])
+	if err != nil {
+		t.Fatalf("Seek: %+v", err)
+	}
+	message, err = r.ReadMessage()
+	if err != nil {
+		t.Fatalf("ReadMessage: %+v", err)
+	}
+	if _, ok := message.(*appmessage.MsgDoneHeaders); !ok {
+		t.Fatalf("unexpected message %s after seeking", message.Command())
+	}
+}
+
+func TestMessageFileWrongFormat(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "file")
+	writeTestFile(t, path, []appmessage.Message{appmessage.NewMsgDoneHeaders()})
+
+	_, err := Open(path, testFormat, "hoosat-mainnet")
+	if err == nil || !strings.Contains(err.Error(), "belongs to hoosat-testnet") {
+		t.Fatalf("expected a wrong network error, got: %v", err)
+	}
+
+	otherFormat := &Format{Name: "other file", Magic: []byte("htnother"), Version: 1}
+	_, err = Open(path, otherFormat, "hoosat-testnet")
+	if err == nil || !strings.Contains(err.Error(), "not a other file") {
+		t.Fatalf("expected a wrong format error, got: %v", err)
+	}
+
+	newerFormat := &Format{Name: testFormat.Name, Magic: testFormat.Magic, Version: 2}
+	_, err = Open(path, newerFormat, "hoosat-testnet")
+	if err == nil || !strings.Contains(err.Error(), "unsupported test file version 1") {
+		t.Fatalf("expected an unsupported version error, got: %v", err)
+	}
+}
+
+func TestMessageFileCorruption(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "file")
+	writeTestFile(t, path, []appmessage.Message{appmessage.NewMsgDoneHeaders()})
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("ReadFile: %+v", err)
+	}
+
+	corrupted := append([]byte{}, content...)
+	corrupted[len(testFormat.Magic)+5] ^= 0xff
+	err = os.WriteFile(path, corrupted, 0600)
+	if err != nil {
+		t.Fatalf("WriteFile: %+v", err)
+	}
+	err = VerifyChecksum(path, testFormat)
+	if err == nil || !strings.Contains(err.Error(), "checksum") {
+		t.Fatalf("expected a checksum error, got: %v", err)
+	}
+
+	err = os.WriteFile(path, content[:len(content)-ChecksumSize-4], 0600)
+	if err != nil {
+		t.Fatalf("WriteFile: %+v", err)
+	}
+	err = VerifyChecksum(path, testFormat)
+	if err == nil {
+		t.Fatalf("expected a truncated file to fail the checksum verification")
+	}
+	r, err := Open(path, testFormat, "hoosat-testnet")
+	if err != nil {
+		t.Fatalf("Open: %+v", err)
+	}
+	defer r.Close()
+	_, err = r.ReadMessage()
+	if err != nil {
+		t.Fatalf("ReadMessage: %+v", err)
+	}
+	_, err = r.ReadMessage()
+	if err == nil || !strings.Contains(err.Error(), "truncated") {
+		t.Fatalf("expected a truncation error, got: %v", err)
+	}
+}
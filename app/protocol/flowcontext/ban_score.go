@@ -0,0 +1,62 @@
+package flowcontext
+
+import (
+	peerpkg "github.com/Hoosat-Oy/HTND/app/protocol/peer"
+	"github.com/Hoosat-Oy/HTND/app/protocol/protocolerrors"
+	"github.com/Hoosat-Oy/HTND/infrastructure/network/netadapter"
+)
+
+// The weights that protocol violations add to the ban score of a peer. Violations that can't
+// be explained by an honest peer increase the persistent part of the score, while violations
+// that honest peers occasionally commit increase the transient part, which decays over time.
+const (
+	// BanScoreInvalidBlock is the persistent weight of relaying an invalid block or inv
+	BanScoreInvalidBlock = 100
+
+	// BanScoreInvalidBlockHeader is the persistent weight of sending an invalid block header
+	BanScoreInvalidBlockHeader = 100
+
+	// BanScoreUnrequestedBlock is the transient weight of sending a block that wasn't requested
+	BanScoreUnrequestedBlock = 20
+
+	// BanScoreInvalidTransaction is the transient weight of relaying an invalid transaction
+	BanScoreInvalidTransaction = 10
+
+	// BanScoreAddressSpam is the transient weight of sending more addresses than allowed
+	BanScoreAddressSpam = 20
+)
+
+// AddBanScore increases the ban score of the given peer because of the misbehavior described
+// by reason. If the score reaches the ban threshold, a banning ProtocolError is returned, and
+// otherwise nil is returned and the peer may keep being served. Peers from whitelisted
+// networks are exempt.
+func (f *FlowContext) AddBanScore(peer *peerpkg.Peer, persistent uint32, transient uint32, reason string) error {
+	if f.IsWhitelisted(peer.Connection()) {
+		log.Debugf("Misbehaving whitelisted peer %s: %s", peer, reason)
+		return nil
+	}
+
+	score := peer.IncreaseBanScore(persistent, transient)
+	banThreshold := f.cfg.BanThreshold
+	if score >= banThreshold {
+		return protocolerrors.Errorf(true, "ban score of %d reached the ban threshold of %d: %s",
+			score, banThreshold, reason)
+	}
+
+	if score > banThreshold/2 {
+		log.Warnf("Misbehaving peer %s: %s -- ban score increased to %d", peer, reason, score)
+	} else {
+		log.Debugf("Misbehaving peer %s: %s -- ban score increased to %d", peer, reason, score)
+	}
+	return nil
+}
+
+// IsWhitelisted returns whether the given connection belongs to one of the networks that
+// were whitelisted with --whitelist or through the RPC
+func (f *FlowContext) IsWhitelisted(netConnection *netadapter.NetConnection) bool {
+	if netConnection == nil {
+		return false
+	}
+
+	return f.addressManager.IsWhitelisted(netConnection.NetAddress().IP)
+}
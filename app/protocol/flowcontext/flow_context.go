@@ -58,9 +58,12 @@ type FlowContext struct {
 	orphans      map[externalapi.DomainHash]*externalapi.DomainBlock
 	orphansMutex sync.RWMutex
 
-	transactionIDsToPropagate        []*externalapi.DomainTransactionID
-	lastTransactionIDPropagationTime time.Time
-	transactionIDPropagationLock     sync.Mutex
+	transactionAnnouncementQueues      map[id.ID]*transactionAnnouncementQueue
+	nextInboundTransactionAnnouncement time.Time
+	transactionAnnouncementLock        sync.Mutex
+
+	stemTransactionPeers map[externalapi.DomainTransactionID]*peerpkg.Peer
+	stemTransactionsLock sync.RWMutex
 
 	shutdownChan chan struct{}
 }
@@ -70,19 +73,19 @@ func New(cfg *config.Config, domain domain.Domain, addressManager *addressmanage
 	netAdapter *netadapter.NetAdapter, connectionManager *connmanager.ConnectionManager) *FlowContext {
 
 	return &FlowContext{
-		cfg:                              cfg,
-		netAdapter:                       netAdapter,
-		domain:                           domain,
-		addressManager:                   addressManager,
-		connectionManager:                connectionManager,
-		sharedRequestedTransactions:      NewSharedRequestedTransactions(),
-		sharedRequestedBlocks:            NewSharedRequestedBlocks(),
-		peers:                            make(map[id.ID]*peerpkg.Peer),
-		orphans:                          make(map[externalapi.DomainHash]*externalapi.DomainBlock),
-		timeStarted:                      mstime.Now().UnixMilliseconds(),
-		transactionIDsToPropagate:        []*externalapi.DomainTransactionID{},
-		lastTransactionIDPropagationTime: time.Now(),
-		shutdownChan:                     make(chan struct{}),
+		cfg:                           cfg,
+		netAdapter:                    netAdapter,
+		domain:                        domain,
+		addressManager:                addressManager,
+		connectionManager:             connectionManager,
+		sharedRequestedTransactions:   NewSharedRequestedTransactions(),
+		sharedRequestedBlocks:         NewSharedRequestedBlocks(),
+		peers:                         make(map[id.ID]*peerpkg.Peer),
+		orphans:                       make(map[externalapi.DomainHash]*externalapi.DomainBlock),
+		timeStarted:                   mstime.Now().UnixMilliseconds(),
+		transactionAnnouncementQueues: make(map[id.ID]*transactionAnnouncementQueue),
+		stemTransactionPeers:          make(map[externalapi.DomainTransactionID]*peerpkg.Peer),
+		shutdownChan:                  make(chan struct{}),
 	}
 }
 
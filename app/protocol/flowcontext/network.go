@@ -38,6 +38,7 @@ func (f *FlowContext) AddToPeers(peer *peerpkg.Peer) error {
 	}
 
 	f.peers[*peer.ID()] = peer
+	f.addTransactionAnnouncementQueue(peer)
 
 	return nil
 }
@@ -48,6 +49,26 @@ func (f *FlowContext) RemoveFromPeers(peer *peerpkg.Peer) {
 	defer f.peersMutex.Unlock()
 
 	delete(f.peers, *peer.ID())
+	f.removeTransactionAnnouncementQueue(peer)
+}
+
+// PeerInfo returns the information the connection manager needs in order to choose
+// which inbound peer to evict. It returns false if the peer of the given connection
+// isn't ready yet.
+func (f *FlowContext) PeerInfo(connection *netadapter.NetConnection) (*connmanager.PeerInfo, bool) {
+	f.peersMutex.RLock()
+	defer f.peersMutex.RUnlock()
+
+	for _, peer := range f.peers {
+		if peer.Connection() == connection {
+			return &connmanager.PeerInfo{
+				LastPingDuration:   peer.LastPingDuration(),
+				LastNovelBlockTime: peer.LastNovelBlockTime(),
+				TimeConnected:      peer.TimeConnected(),
+			}, true
+		}
+	}
+	return nil, false
 }
 
 // readyPeerConnections returns the NetConnections of all the ready peers.
@@ -0,0 +1,118 @@
+package flowcontext
+
+import (
+	"math/rand"
+	"time"
+
+	peerpkg "github.com/Hoosat-Oy/HTND/app/protocol/peer"
+	"github.com/Hoosat-Oy/HTND/domain/consensus/model/externalapi"
+)
+
+const (
+	// stemEmbargoMinimum is the minimum time a stem transaction is kept from being
+	// announced to all peers
+	stemEmbargoMinimum = 10 * time.Second
+
+	// stemEmbargoAverageExtension is the average random time that is added to stemEmbargoMinimum
+	stemEmbargoAverageExtension = 5 * time.Second
+)
+
+// stemTransactions forwards the given transactions to a single random outbound peer, and only announces them
+// to all peers once their embargo is over. By then the stem peer has most likely relayed them onwards, so
+// observers can't easily tell that they originated here. If there are no outbound peers, the transactions
+// are announced to all peers right away.
+func (f *FlowContext) stemTransactions(transactionIDs []*externalapi.DomainTransactionID) error {
+	stemPeer := f.randomOutboundPeer()
+	if stemPeer == nil {
+		log.Debugf("No outbound peers to stem %d transactions through -- announcing them to all peers",
+			len(transactionIDs))
+		return f.EnqueueTransactionIDsForPropagation(transactionIDs)
+	}
+
+	f.stemTransactionsLock.Lock()
+	for _, transactionID := range transactionIDs {
+		f.stemTransactionPeers[*transactionID] = stemPeer
+	}
+	f.stemTransactionsLock.Unlock()
+
+	log.Debugf("Stemming %d transactions through %s", len(transactionIDs), stemPeer)
+	err := f.announceTransactions(stemPeer, transactionIDs)
+	if err != nil {
+		return err
+	}
+
+	embargo := stemEmbargoMinimum + poissonDelay(stemEmbargoAverageExtension)
+	time.AfterFunc(embargo, func() {
+		f.fluffStemTransactions(transactionIDs)
+	})
+	return nil
+}
+
+// fluffStemTransactions ends the stem phase of the given transactions and
+// announces the ones that are still in the mempool to all peers
+func (f *FlowContext) fluffStemTransactions(transactionIDs []*externalapi.DomainTransactionID) {
+	f.stemTransactionsLock.Lock()
+	for _, transactionID := range transactionIDs {
+		delete(f.stemTransactionPeers, *transactionID)
+	}
+	f.stemTransactionsLock.Unlock()
+
+	transactionIDsInMempool := make([]*externalapi.DomainTransactionID, 0, len(transactionIDs))
+	for _, transactionID := range transactionIDs {
+		_, _, ok := f.Domain().MiningManager().GetTransaction(transactionID, true, false)
+		if ok {
+			transactionIDsInMempool = append(transactionIDsInMempool, transactionID)
+		}
+	}
+	if len(transactionIDsInMempool) == 0 {
+		return
+	}
+
+	log.Debugf("The embargo of %d stem transactions is over -- announcing them to all peers",
+		len(transactionIDsInMempool))
+	err := f.EnqueueTransactionIDsForPropagation(transactionIDsInMempool)
+	if err != nil {
+		log.Warnf("Could not announce stem transactions: %s", err)
+	}
+}
+
+// filterStemTransactions returns the given transaction IDs without the ones that are in their stem phase
+func (f *FlowContext) filterStemTransactions(transactionIDs []*externalapi.DomainTransactionID) []*externalapi.DomainTransactionID {
+	f.stemTransactionsLock.RLock()
+	defer f.stemTransactionsLock.RUnlock()
+
+	if len(f.stemTransactionPeers) == 0 {
+		return transactionIDs
+	}
+	filtered := make([]*externalapi.DomainTransactionID, 0, len(transactionIDs))
+	for _, transactionID := range transactionIDs {
+		if _, ok := f.stemTransactionPeers[*transactionID]; !ok {
+			filtered = append(filtered, transactionID)
+		}
+	}
+	return filtered
+}
+
+// ShouldHideTransaction returns whether the given transaction is in its stem phase and was
+// stemmed through a peer other than the given one. Such transactions must not be sent to the
+// given peer, or it could learn that they originated here.
+func (f *FlowContext) ShouldHideTransaction(transactionID *externalapi.DomainTransactionID, peer *peerpkg.Peer) bool {
+	f.stemTransactionsLock.RLock()
+	defer f.stemTransactionsLock.RUnlock()
+
+	stemPeer, ok := f.stemTransactionPeers[*transactionID]
+	return ok && stemPeer != peer
+}
+
+func (f *FlowContext) randomOutboundPeer() *peerpkg.Peer {
+	var outboundPeers []*peerpkg.Peer
+	for _, peer := range f.Peers() {
+		if peer.IsOutbound() {
+			outboundPeers = append(outboundPeers, peer)
+		}
+	}
+	if len(outboundPeers) == 0 {
+		return nil
+	}
+	return outboundPeers[rand.Intn(len(outboundPeers))]
+}
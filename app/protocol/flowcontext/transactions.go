@@ -1,17 +1,26 @@
 package flowcontext
 
 import (
+	"math/rand"
 	"time"
 
 	"github.com/Hoosat-Oy/HTND/app/appmessage"
+	peerpkg "github.com/Hoosat-Oy/HTND/app/protocol/peer"
 	"github.com/Hoosat-Oy/HTND/domain/consensus/model/externalapi"
 	"github.com/Hoosat-Oy/HTND/domain/consensus/utils/consensushashing"
+	"github.com/Hoosat-Oy/HTND/infrastructure/network/netadapter"
 )
 
-// TransactionIDPropagationInterval is the interval between transaction IDs propagations
-const TransactionIDPropagationInterval = 500 * time.Millisecond
+// transactionAnnouncementQueue holds the IDs of the transactions that are waiting
+// to be announced to a single peer
+type transactionAnnouncementQueue struct {
+	peer           *peerpkg.Peer
+	transactionIDs []*externalapi.DomainTransactionID
+	timer          *time.Timer
+}
 
 // AddTransaction adds transaction to the mempool and propagates it.
+// With --txstem the transaction is first only forwarded to a single outbound peer.
 func (f *FlowContext) AddTransaction(tx *externalapi.DomainTransaction, allowOrphan bool) error {
 	acceptedTransactions, err := f.Domain().MiningManager().ValidateAndInsertTransaction(tx, true, allowOrphan)
 	if err != nil {
@@ -19,6 +28,9 @@ func (f *FlowContext) AddTransaction(tx *externalapi.DomainTransaction, allowOrp
 	}
 
 	acceptedTransactionIDs := consensushashing.TransactionIDs(acceptedTransactions)
+	if f.cfg.TxStem {
+		return f.stemTransactions(acceptedTransactionIDs)
+	}
 	return f.EnqueueTransactionIDsForPropagation(acceptedTransactionIDs)
 }
 
@@ -41,41 +53,101 @@ func (f *FlowContext) OnTransactionAddedToMempool() {
 	}
 }
 
-// EnqueueTransactionIDsForPropagation add the given transactions IDs to a set of IDs to
-// propagate. The IDs will be broadcast to all peers within a single transaction Inv message.
-// The broadcast itself may happen only during a subsequent call to this method
+// EnqueueTransactionIDsForPropagation adds the given transaction IDs to the announcement
+// queues of all the ready peers. Every queue is sent as transaction Inv messages after a
+// random delay, so that the order in which peers learn about a transaction doesn't reveal
+// where it came from. Transactions that are still in their stem phase are skipped.
 func (f *FlowContext) EnqueueTransactionIDsForPropagation(transactionIDs []*externalapi.DomainTransactionID) error {
-	f.transactionIDPropagationLock.Lock()
-	defer f.transactionIDPropagationLock.Unlock()
+	transactionIDs = f.filterStemTransactions(transactionIDs)
+	if len(transactionIDs) == 0 {
+		return nil
+	}
 
-	f.transactionIDsToPropagate = append(f.transactionIDsToPropagate, transactionIDs...)
+	f.transactionAnnouncementLock.Lock()
+	defer f.transactionAnnouncementLock.Unlock()
 
-	return f.maybePropagateTransactions()
+	now := time.Now()
+	for _, queue := range f.transactionAnnouncementQueues {
+		queue.transactionIDs = append(queue.transactionIDs, transactionIDs...)
+		if queue.timer != nil {
+			continue
+		}
+		queue := queue
+		queue.timer = time.AfterFunc(f.transactionAnnouncementDelay(queue.peer, now), func() {
+			f.announceQueuedTransactions(queue)
+		})
+	}
+	return nil
 }
 
-func (f *FlowContext) maybePropagateTransactions() error {
-	if time.Since(f.lastTransactionIDPropagationTime) < TransactionIDPropagationInterval &&
-		len(f.transactionIDsToPropagate) < appmessage.MaxInvPerTxInvMsg {
-		return nil
+// transactionAnnouncementDelay returns how long to wait before announcing transactions to the given peer.
+// Outbound peers get their own, shorter, delays. Inbound peers all share the same announcement times, so
+// that an observer doesn't learn about transactions any sooner by opening many connections to this node.
+// It must be called with transactionAnnouncementLock held.
+func (f *FlowContext) transactionAnnouncementDelay(peer *peerpkg.Peer, now time.Time) time.Duration {
+	if peer.IsOutbound() {
+		return poissonDelay(f.cfg.TxRelayOutboundInterval)
+	}
+	if !now.Before(f.nextInboundTransactionAnnouncement) {
+		f.nextInboundTransactionAnnouncement = now.Add(poissonDelay(f.cfg.TxRelayInboundInterval))
 	}
+	return f.nextInboundTransactionAnnouncement.Sub(now)
+}
 
-	for len(f.transactionIDsToPropagate) > 0 {
-		transactionIDsToBroadcast := f.transactionIDsToPropagate
-		if len(transactionIDsToBroadcast) > appmessage.MaxInvPerTxInvMsg {
-			transactionIDsToBroadcast = f.transactionIDsToPropagate[:len(transactionIDsToBroadcast)]
-		}
-		log.Debugf("Transaction propagation: broadcasting %d transactions", len(transactionIDsToBroadcast))
+// poissonDelay returns a random delay between events of a Poisson process with the given average delay
+func poissonDelay(averageDelay time.Duration) time.Duration {
+	return time.Duration(rand.ExpFloat64() * float64(averageDelay))
+}
+
+func (f *FlowContext) announceQueuedTransactions(queue *transactionAnnouncementQueue) {
+	f.transactionAnnouncementLock.Lock()
+	transactionIDs := queue.transactionIDs
+	queue.transactionIDs = nil
+	queue.timer = nil
+	f.transactionAnnouncementLock.Unlock()
 
-		inv := appmessage.NewMsgInvTransaction(transactionIDsToBroadcast)
-		err := f.Broadcast(inv)
+	log.Debugf("Transaction propagation: announcing %d transactions to %s", len(transactionIDs), queue.peer)
+	err := f.announceTransactions(queue.peer, transactionIDs)
+	if err != nil {
+		log.Warnf("Could not announce transactions to %s: %s", queue.peer, err)
+	}
+}
+
+// announceTransactions sends the given transaction IDs to the given peer,
+// split into as many transaction Inv messages as needed
+func (f *FlowContext) announceTransactions(peer *peerpkg.Peer, transactionIDs []*externalapi.DomainTransactionID) error {
+	for len(transactionIDs) > 0 {
+		transactionIDsToAnnounce := transactionIDs
+		if len(transactionIDsToAnnounce) > appmessage.MaxInvPerTxInvMsg {
+			transactionIDsToAnnounce = transactionIDs[:appmessage.MaxInvPerTxInvMsg]
+		}
+		inv := appmessage.NewMsgInvTransaction(transactionIDsToAnnounce)
+		err := f.netAdapter.P2PBroadcast([]*netadapter.NetConnection{peer.Connection()}, inv)
 		if err != nil {
 			return err
 		}
-
-		f.transactionIDsToPropagate = f.transactionIDsToPropagate[len(transactionIDsToBroadcast):]
+		transactionIDs = transactionIDs[len(transactionIDsToAnnounce):]
 	}
+	return nil
+}
 
-	f.lastTransactionIDPropagationTime = time.Now()
+func (f *FlowContext) addTransactionAnnouncementQueue(peer *peerpkg.Peer) {
+	f.transactionAnnouncementLock.Lock()
+	defer f.transactionAnnouncementLock.Unlock()
 
-	return nil
+	f.transactionAnnouncementQueues[*peer.ID()] = &transactionAnnouncementQueue{peer: peer}
+}
+
+func (f *FlowContext) removeTransactionAnnouncementQueue(peer *peerpkg.Peer) {
+	f.transactionAnnouncementLock.Lock()
+	defer f.transactionAnnouncementLock.Unlock()
+
+	queue, ok := f.transactionAnnouncementQueues[*peer.ID()]
+	if !ok {
+		return
+	}
+	if queue.timer != nil {
+		queue.timer.Stop()
+	}
+	delete(f.transactionAnnouncementQueues, *peer.ID())
 }
@@ -0,0 +1,57 @@
+package flowcontext
+
+import (
+	"testing"
+	"time"
+
+	peerpkg "github.com/Hoosat-Oy/HTND/app/protocol/peer"
+	"github.com/Hoosat-Oy/HTND/domain/consensus/model/externalapi"
+	"github.com/Hoosat-Oy/HTND/infrastructure/config"
+)
+
+func TestPoissonDelay(t *testing.T) {
+	const averageDelay = time.Second
+	const samples = 10_000
+
+	var total time.Duration
+	for i := 0; i < samples; i++ {
+		delay := poissonDelay(averageDelay)
+		if delay < 0 {
+			t.Fatalf("got a negative delay %s", delay)
+		}
+		total += delay
+	}
+	average := total / samples
+	if average < 900*time.Millisecond || average > 1100*time.Millisecond {
+		t.Fatalf("expected an average delay of about %s, got %s", averageDelay, average)
+	}
+
+	if delay := poissonDelay(0); delay != 0 {
+		t.Fatalf("expected no delay for an average delay of 0, got %s", delay)
+	}
+}
+
+func TestStemTransactionsAreHidden(t *testing.T) {
+	flowContext := New(config.DefaultConfig(), nil, nil, nil, nil)
+	stemPeer := peerpkg.New(nil)
+	otherPeer := peerpkg.New(nil)
+
+	stemTransactionID := externalapi.NewDomainTransactionIDFromByteArray(&[externalapi.DomainHashSize]byte{1})
+	otherTransactionID := externalapi.NewDomainTransactionIDFromByteArray(&[externalapi.DomainHashSize]byte{2})
+	flowContext.stemTransactionPeers[*stemTransactionID] = stemPeer
+
+	if flowContext.ShouldHideTransaction(stemTransactionID, stemPeer) {
+		t.Fatalf("a stem transaction was hidden from its stem peer")
+	}
+	if !flowContext.ShouldHideTransaction(stemTransactionID, otherPeer) {
+		t.Fatalf("a stem transaction wasn't hidden from a peer other than its stem peer")
+	}
+	if flowContext.ShouldHideTransaction(otherTransactionID, otherPeer) {
+		t.Fatalf("a transaction that isn't in its stem phase was hidden")
+	}
+
+	filtered := flowContext.filterStemTransactions([]*externalapi.DomainTransactionID{stemTransactionID, otherTransactionID})
+	if len(filtered) != 1 || !filtered[0].Equal(otherTransactionID) {
+		t.Fatalf("expected only the transaction that isn't in its stem phase to be propagated, got %v", filtered)
+	}
+}
@@ -1,10 +1,12 @@
 package addressexchange
 
 import (
+	"fmt"
+
 	"github.com/Hoosat-Oy/HTND/app/appmessage"
 	"github.com/Hoosat-Oy/HTND/app/protocol/common"
+	"github.com/Hoosat-Oy/HTND/app/protocol/flowcontext"
 	peerpkg "github.com/Hoosat-Oy/HTND/app/protocol/peer"
-	"github.com/Hoosat-Oy/HTND/app/protocol/protocolerrors"
 	"github.com/Hoosat-Oy/HTND/infrastructure/network/addressmanager"
 	"github.com/Hoosat-Oy/HTND/infrastructure/network/netadapter/router"
 )
@@ -12,6 +14,7 @@ import (
 // ReceiveAddressesContext is the interface for the context needed for the ReceiveAddresses flow.
 type ReceiveAddressesContext interface {
 	AddressManager() *addressmanager.AddressManager
+	AddBanScore(peer *peerpkg.Peer, persistent uint32, transient uint32, reason string) error
 }
 
 // ReceiveAddresses asks a peer for more addresses if needed.
@@ -31,9 +34,17 @@ func ReceiveAddresses(context ReceiveAddressesContext, incomingRoute *router.Rou
 	}
 
 	msgAddresses := message.(*appmessage.MsgAddresses)
-	if len(msgAddresses.AddressList) > addressmanager.GetAddressesMax {
-		return protocolerrors.Errorf(true, "address count exceeded %d", addressmanager.GetAddressesMax)
+	addressList := msgAddresses.AddressList
+	if len(addressList) > addressmanager.GetAddressesMax {
+		err := context.AddBanScore(peer, 0, flowcontext.BanScoreAddressSpam,
+			fmt.Sprintf("address count exceeded %d", addressmanager.GetAddressesMax))
+		if err != nil {
+			return err
+		}
+		addressList = addressList[:addressmanager.GetAddressesMax]
 	}
 
-	return context.AddressManager().AddAddresses(msgAddresses.AddressList...)
+	// The addresses are bucketed by the network group of the peer that sent them,
+	// so that a single peer can't flood the address manager
+	return context.AddressManager().AddAddressesFromSource(peer.Connection().NetAddress(), addressList...)
 }
@@ -2,6 +2,7 @@ package blockrelay
 
 import (
 	"github.com/Hoosat-Oy/HTND/app/appmessage"
+	peerpkg "github.com/Hoosat-Oy/HTND/app/protocol/peer"
 	"github.com/Hoosat-Oy/HTND/app/protocol/protocolerrors"
 	"github.com/Hoosat-Oy/HTND/domain"
 	"github.com/Hoosat-Oy/HTND/infrastructure/network/netadapter/router"
@@ -16,7 +17,7 @@ type HandleIBDBlockRequestsContext interface {
 // HandleIBDBlockRequests listens to appmessage.MsgRequestRelayBlocks messages and sends
 // their corresponding blocks to the requesting peer.
 func HandleIBDBlockRequests(context HandleIBDBlockRequestsContext, incomingRoute *router.Route,
-	outgoingRoute *router.Route) error {
+	outgoingRoute *router.Route, peer *peerpkg.Peer) error {
 
 	for {
 		message, err := incomingRoute.Dequeue()
@@ -40,6 +41,7 @@ func HandleIBDBlockRequests(context HandleIBDBlockRequestsContext, incomingRoute
 
 			blockMessage := appmessage.DomainBlockToMsgBlock(block)
 			ibdBlockMessage := appmessage.NewMsgIBDBlock(blockMessage)
+			peer.WaitForUploadQuota()
 			err = outgoingRoute.Enqueue(ibdBlockMessage)
 			if err != nil {
 				return err
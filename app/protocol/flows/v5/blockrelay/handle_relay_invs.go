@@ -1,6 +1,8 @@
 package blockrelay
 
 import (
+	"fmt"
+
 	"github.com/Hoosat-Oy/HTND/app/appmessage"
 	"github.com/Hoosat-Oy/HTND/app/protocol/common"
 	"github.com/Hoosat-Oy/HTND/app/protocol/flowcontext"
@@ -38,6 +40,7 @@ type RelayInvsContext interface {
 	IsIBDRunning() bool
 	IsRecoverableError(err error) bool
 	IsNearlySynced() (bool, error)
+	AddBanScore(peer *peerpkg.Peer, persistent uint32, transient uint32, reason string) error
 }
 
 type invRelayBlock struct {
@@ -86,8 +89,12 @@ func (flow *handleRelayInvsFlow) start() error {
 		}
 		if blockInfo.Exists && blockInfo.BlockStatus != externalapi.StatusHeaderOnly {
 			if blockInfo.BlockStatus == externalapi.StatusInvalid {
-				return protocolerrors.Errorf(true, "sent inv of an invalid block %s",
-					inv.Hash)
+				err := flow.AddBanScore(flow.peer, flowcontext.BanScoreInvalidBlock, 0,
+					fmt.Sprintf("sent inv of an invalid block %s", inv.Hash))
+				if err != nil {
+					return err
+				}
+				continue
 			}
 			log.Debugf("Block %s already exists. continuing...", inv.Hash)
 			continue
@@ -200,6 +207,15 @@ func (flow *handleRelayInvsFlow) start() error {
 				log.Infof("Ignoring duplicate block %s", inv.Hash)
 				continue
 			}
+
+			if errors.As(err, &ruleerrors.RuleError{}) {
+				err := flow.AddBanScore(flow.peer, flowcontext.BanScoreInvalidBlock, 0,
+					fmt.Sprintf("got invalid block %s from relay: %s", inv.Hash, err))
+				if err != nil {
+					return err
+				}
+				continue
+			}
 			return err
 		}
 		if len(missingParents) > 0 {
@@ -251,6 +267,7 @@ func (flow *handleRelayInvsFlow) start() error {
 			}
 		}
 		log.Infof("Accepted block %s via relay", inv.Hash)
+		flow.peer.MarkNovelBlock()
 		err = flow.OnNewBlock(block)
 		if err != nil {
 			return err
@@ -303,18 +320,25 @@ func (flow *handleRelayInvsFlow) requestBlock(requestHash *externalapi.DomainHas
 		return nil, false, err
 	}
 
-	msgBlock, err := flow.readMsgBlock()
-	if err != nil {
-		return nil, false, err
-	}
+	for {
+		msgBlock, err := flow.readMsgBlock()
+		if err != nil {
+			return nil, false, err
+		}
 
-	block := appmessage.MsgBlockToDomainBlock(msgBlock)
-	blockHash := consensushashing.BlockHash(block)
-	if !blockHash.Equal(requestHash) {
-		return nil, false, protocolerrors.Errorf(true, "got unrequested block %s", blockHash)
-	}
+		block := appmessage.MsgBlockToDomainBlock(msgBlock)
+		blockHash := consensushashing.BlockHash(block)
+		if blockHash.Equal(requestHash) {
+			return block, false, nil
+		}
 
-	return block, false, nil
+		// The unrequested block is dropped, and we keep waiting for the requested one
+		err = flow.AddBanScore(flow.peer, 0, flowcontext.BanScoreUnrequestedBlock,
+			fmt.Sprintf("got unrequested block %s", blockHash))
+		if err != nil {
+			return nil, false, err
+		}
+	}
 }
 
 // readMsgBlock returns the next msgBlock in msgChan, and populates invsQueue with any inv messages that meanwhile arrive.
@@ -357,7 +381,7 @@ func (flow *handleRelayInvsFlow) processBlock(block *externalapi.DomainBlock) ([
 		if !errors.Is(err, ruleerrors.ErrDuplicateBlock) {
 			log.Warnf("Rejected block %s from %s: %s", blockHash, flow.peer, err)
 		}
-		return nil, protocolerrors.Wrapf(true, err, "got invalid block %s from relay", blockHash)
+		return nil, err
 	}
 	return nil, nil
 }
@@ -96,6 +96,7 @@ func (flow *handleRequestHeadersFlow) start() error {
 			}
 
 			blockHeadersMessage := appmessage.NewBlockHeadersMessage(blockHeaders)
+			flow.peer.WaitForUploadQuota()
 			err = flow.outgoingRoute.Enqueue(blockHeadersMessage)
 			if err != nil {
 				return err
@@ -6,6 +6,7 @@ import (
 
 	"github.com/Hoosat-Oy/HTND/app/appmessage"
 	"github.com/Hoosat-Oy/HTND/app/protocol/common"
+	"github.com/Hoosat-Oy/HTND/app/protocol/flowcontext"
 	peerpkg "github.com/Hoosat-Oy/HTND/app/protocol/peer"
 	"github.com/Hoosat-Oy/HTND/app/protocol/protocolerrors"
 	"github.com/Hoosat-Oy/HTND/domain"
@@ -29,6 +30,7 @@ type IBDContext interface {
 	TrySetIBDRunning(ibdPeer *peerpkg.Peer) bool
 	UnsetIBDRunning()
 	IsRecoverableError(err error) bool
+	AddBanScore(peer *peerpkg.Peer, persistent uint32, transient uint32, reason string) error
 }
 
 type handleIBDFlow struct {
@@ -528,7 +530,13 @@ func (flow *handleIBDFlow) processHeader(consensus externalapi.Consensus, msgBlo
 			log.Debugf("Skipping block header %s as it is a duplicate", blockHash)
 		} else {
 			log.Infof("Rejected block header %s from %s during IBD: %s", blockHash, flow.peer, err)
-			return protocolerrors.Wrapf(true, err, "got invalid block header %s during IBD", blockHash)
+			banErr := flow.AddBanScore(flow.peer, flowcontext.BanScoreInvalidBlockHeader, 0,
+				fmt.Sprintf("got invalid block header %s during IBD", blockHash))
+			if banErr != nil {
+				return banErr
+			}
+			// IBD can't go on without the rejected header, so the peer is disconnected even if it's not banned
+			return protocolerrors.Wrapf(false, err, "got invalid block header %s during IBD", blockHash)
 		}
 	}
 
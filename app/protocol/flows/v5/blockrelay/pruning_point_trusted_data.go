@@ -0,0 +1,99 @@
+package blockrelay
+
+import (
+	"github.com/Hoosat-Oy/HTND/app/appmessage"
+	"github.com/Hoosat-Oy/HTND/domain/consensus/model/externalapi"
+	"github.com/Hoosat-Oy/HTND/domain/dagconfig"
+)
+
+// PruningPointAndItsAnticoneTrustedData is the trusted data of the pruning point and its anticone.
+// Every DAA window block and GHOSTDAG data entry appears once in TrustedData, and the blocks
+// refer to them by their indices.
+type PruningPointAndItsAnticoneTrustedData struct {
+	PointAndItsAnticone []*externalapi.DomainHash
+	TrustedData         *appmessage.MsgTrustedData
+	DAAWindowIndices    map[externalapi.DomainHash][]uint64
+	GHOSTDAGDataIndices map[externalapi.DomainHash][]uint64
+}
+
+// BlockWithTrustedData returns the message of the given block of the pruning point anticone,
+// with the indices of its trusted data.
+func (data *PruningPointAndItsAnticoneTrustedData) BlockWithTrustedData(
+	block *externalapi.DomainBlock, blockHash *externalapi.DomainHash) *appmessage.MsgBlockWithTrustedDataV4 {
+
+	return appmessage.DomainBlockWithTrustedDataToBlockWithTrustedDataV4(
+		block, data.DAAWindowIndices[*blockHash], data.GHOSTDAGDataIndices[*blockHash])
+}
+
+// BuildPruningPointAndItsAnticoneTrustedData collects the trusted data of the pruning point and its
+// anticone, which a node that syncs from the pruning point needs in order to validate them.
+func BuildPruningPointAndItsAnticoneTrustedData(consensus externalapi.Consensus, params *dagconfig.Params) (
+	*PruningPointAndItsAnticoneTrustedData, error) {
+
+	pointAndItsAnticone, err := consensus.PruningPointAndItsAnticone()
+	if err != nil {
+		return nil, err
+	}
+
+	windowSize := params.DifficultyAdjustmentWindowSize
+	daaWindowBlocks := make([]*externalapi.TrustedDataDataDAAHeader, 0, windowSize)
+	daaWindowHashesToIndex := make(map[externalapi.DomainHash]int, windowSize)
+	trustedDataDAABlockIndexes := make(map[externalapi.DomainHash][]uint64)
+
+	ghostdagData := make([]*externalapi.BlockGHOSTDAGDataHashPair, 0)
+	ghostdagDataHashToIndex := make(map[externalapi.DomainHash]int)
+	trustedDataGHOSTDAGDataIndexes := make(map[externalapi.DomainHash][]uint64)
+	for _, blockHash := range pointAndItsAnticone {
+		blockDAAWindowHashes, err := consensus.BlockDAAWindowHashes(blockHash)
+		if err != nil {
+			return nil, err
+		}
+
+		trustedDataDAABlockIndexes[*blockHash] = make([]uint64, 0, windowSize)
+		for i, daaBlockHash := range blockDAAWindowHashes {
+			index, exists := daaWindowHashesToIndex[*daaBlockHash]
+			if !exists {
+				trustedDataDataDAAHeader, err := consensus.TrustedDataDataDAAHeader(blockHash, daaBlockHash, uint64(i))
+				if err != nil {
+					return nil, err
+				}
+				daaWindowBlocks = append(daaWindowBlocks, trustedDataDataDAAHeader)
+				index = len(daaWindowBlocks) - 1
+				daaWindowHashesToIndex[*daaBlockHash] = index
+			}
+
+			trustedDataDAABlockIndexes[*blockHash] = append(trustedDataDAABlockIndexes[*blockHash], uint64(index))
+		}
+
+		ghostdagDataBlockHashes, err := consensus.TrustedBlockAssociatedGHOSTDAGDataBlockHashes(blockHash)
+		if err != nil {
+			return nil, err
+		}
+
+		trustedDataGHOSTDAGDataIndexes[*blockHash] = make([]uint64, 0, params.K)
+		for _, ghostdagDataBlockHash := range ghostdagDataBlockHashes {
+			index, exists := ghostdagDataHashToIndex[*ghostdagDataBlockHash]
+			if !exists {
+				data, err := consensus.TrustedGHOSTDAGData(ghostdagDataBlockHash)
+				if err != nil {
+					return nil, err
+				}
+				ghostdagData = append(ghostdagData, &externalapi.BlockGHOSTDAGDataHashPair{
+					Hash:         ghostdagDataBlockHash,
+					GHOSTDAGData: data,
+				})
+				index = len(ghostdagData) - 1
+				ghostdagDataHashToIndex[*ghostdagDataBlockHash] = index
+			}
+
+			trustedDataGHOSTDAGDataIndexes[*blockHash] = append(trustedDataGHOSTDAGDataIndexes[*blockHash], uint64(index))
+		}
+	}
+
+	return &PruningPointAndItsAnticoneTrustedData{
+		PointAndItsAnticone: pointAndItsAnticone,
+		TrustedData:         appmessage.DomainTrustedDataToTrustedData(daaWindowBlocks, ghostdagData),
+		DAAWindowIndices:    trustedDataDAABlockIndexes,
+		GHOSTDAGDataIndices: trustedDataGHOSTDAGDataIndexes,
+	}, nil
+}
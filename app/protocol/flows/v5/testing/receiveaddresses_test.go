@@ -7,19 +7,32 @@ import (
 	"github.com/Hoosat-Oy/HTND/app/protocol/flows/v5/addressexchange"
 
 	"github.com/Hoosat-Oy/HTND/app/appmessage"
+	"github.com/Hoosat-Oy/HTND/app/protocol/flowcontext"
 	peerpkg "github.com/Hoosat-Oy/HTND/app/protocol/peer"
+	"github.com/Hoosat-Oy/HTND/app/protocol/protocolerrors"
 	"github.com/Hoosat-Oy/HTND/domain/consensus"
 	"github.com/Hoosat-Oy/HTND/domain/consensus/utils/testutils"
 	"github.com/Hoosat-Oy/HTND/infrastructure/network/addressmanager"
 	"github.com/Hoosat-Oy/HTND/infrastructure/network/netadapter/router"
 )
 
-type fakeReceiveAddressesContext struct{}
+type fakeReceiveAddressesContext struct {
+	banThreshold uint32
+}
 
 func (f fakeReceiveAddressesContext) AddressManager() *addressmanager.AddressManager {
 	return nil
 }
 
+func (f fakeReceiveAddressesContext) AddBanScore(peer *peerpkg.Peer, persistent uint32, transient uint32, reason string) error {
+	score := peer.IncreaseBanScore(persistent, transient)
+	if score >= f.banThreshold {
+		return protocolerrors.Errorf(true, "ban score of %d reached the ban threshold of %d: %s",
+			score, f.banThreshold, reason)
+	}
+	return nil
+}
+
 func TestReceiveAddressesErrors(t *testing.T) {
 	testutils.ForAllNets(t, true, func(t *testing.T, consensusConfig *consensus.Config) {
 		incomingRoute := router.NewRoute("incoming")
@@ -27,7 +40,9 @@ func TestReceiveAddressesErrors(t *testing.T) {
 		peer := peerpkg.New(nil)
 		errChan := make(chan error)
 		go func() {
-			errChan <- addressexchange.ReceiveAddresses(fakeReceiveAddressesContext{}, incomingRoute, outgoingRoute, peer)
+			// The threshold is set so that a single violation is enough to ban the peer
+			context := fakeReceiveAddressesContext{banThreshold: flowcontext.BanScoreAddressSpam}
+			errChan <- addressexchange.ReceiveAddresses(context, incomingRoute, outgoingRoute, peer)
 		}()
 
 		_, err := outgoingRoute.DequeueWithTimeout(time.Second)
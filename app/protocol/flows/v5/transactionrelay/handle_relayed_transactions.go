@@ -1,9 +1,12 @@
 package transactionrelay
 
 import (
+	"fmt"
+
 	"github.com/Hoosat-Oy/HTND/app/appmessage"
 	"github.com/Hoosat-Oy/HTND/app/protocol/common"
 	"github.com/Hoosat-Oy/HTND/app/protocol/flowcontext"
+	peerpkg "github.com/Hoosat-Oy/HTND/app/protocol/peer"
 	"github.com/Hoosat-Oy/HTND/app/protocol/protocolerrors"
 	"github.com/Hoosat-Oy/HTND/domain"
 	"github.com/Hoosat-Oy/HTND/domain/consensus/model/externalapi"
@@ -23,21 +26,27 @@ type TransactionsRelayContext interface {
 	OnTransactionAddedToMempool()
 	EnqueueTransactionIDsForPropagation(transactionIDs []*externalapi.DomainTransactionID) error
 	IsNearlySynced() (bool, error)
+	AddBanScore(peer *peerpkg.Peer, persistent uint32, transient uint32, reason string) error
+	ShouldHideTransaction(transactionID *externalapi.DomainTransactionID, peer *peerpkg.Peer) bool
 }
 
 type handleRelayedTransactionsFlow struct {
 	TransactionsRelayContext
 	incomingRoute, outgoingRoute *router.Route
+	peer                         *peerpkg.Peer
 	invsQueue                    []*appmessage.MsgInvTransaction
 }
 
 // HandleRelayedTransactions listens to appmessage.MsgInvTransaction messages, requests their corresponding transactions if they
 // are missing, adds them to the mempool and propagates them to the rest of the network.
-func HandleRelayedTransactions(context TransactionsRelayContext, incomingRoute *router.Route, outgoingRoute *router.Route) error {
+func HandleRelayedTransactions(context TransactionsRelayContext, incomingRoute *router.Route, outgoingRoute *router.Route,
+	peer *peerpkg.Peer) error {
+
 	flow := &handleRelayedTransactionsFlow{
 		TransactionsRelayContext: context,
 		incomingRoute:            incomingRoute,
 		outgoingRoute:            outgoingRoute,
+		peer:                     peer,
 		invsQueue:                make([]*appmessage.MsgInvTransaction, 0),
 	}
 	return flow.start()
@@ -191,18 +200,23 @@ func (flow *handleRelayedTransactionsFlow) receiveTransactions(requestedTransact
 				return errors.Wrapf(err, "failed to process transaction %s", txID)
 			}
 
-			shouldBan := false
+			isInvalid := false
 			if txRuleErr := (&mempool.TxRuleError{}); errors.As(ruleErr.Err, txRuleErr) {
 				if txRuleErr.RejectCode == mempool.RejectInvalid {
-					shouldBan = true
+					isInvalid = true
 				}
 			}
 
-			if !shouldBan {
+			if !isInvalid {
 				continue
 			}
 
-			return protocolerrors.Errorf(true, "rejected transaction %s: %s", txID, ruleErr)
+			err := flow.AddBanScore(flow.peer, 0, flowcontext.BanScoreInvalidTransaction,
+				fmt.Sprintf("rejected transaction %s: %s", txID, ruleErr))
+			if err != nil {
+				return err
+			}
+			continue
 		}
 		err = flow.broadcastAcceptedTransactions(consensushashing.TransactionIDs(acceptedTransactions))
 		if err != nil {
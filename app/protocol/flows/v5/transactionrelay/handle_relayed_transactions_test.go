@@ -7,6 +7,7 @@ import (
 
 	"github.com/Hoosat-Oy/HTND/app/protocol/flowcontext"
 	"github.com/Hoosat-Oy/HTND/app/protocol/flows/v5/transactionrelay"
+	peerpkg "github.com/Hoosat-Oy/HTND/app/protocol/peer"
 
 	"github.com/Hoosat-Oy/HTND/app/protocol/protocolerrors"
 	"github.com/Hoosat-Oy/HTND/domain"
@@ -52,6 +53,14 @@ func (m *mocTransactionsRelayContext) IsNearlySynced() (bool, error) {
 	return true, nil
 }
 
+func (m *mocTransactionsRelayContext) AddBanScore(peer *peerpkg.Peer, persistent uint32, transient uint32, reason string) error {
+	return nil
+}
+
+func (m *mocTransactionsRelayContext) ShouldHideTransaction(transactionID *externalapi.DomainTransactionID, peer *peerpkg.Peer) bool {
+	return false
+}
+
 // TestHandleRelayedTransactionsNotFound tests the flow of  HandleRelayedTransactions when the peer doesn't
 // have the requested transactions in the mempool.
 func TestHandleRelayedTransactionsNotFound(t *testing.T) {
@@ -129,7 +138,7 @@ func TestHandleRelayedTransactionsNotFound(t *testing.T) {
 			}
 		})
 
-		err = transactionrelay.HandleRelayedTransactions(context, incomingRoute, peerIncomingRoute)
+		err = transactionrelay.HandleRelayedTransactions(context, incomingRoute, peerIncomingRoute, peerpkg.New(nil))
 		// Since we inserted an unexpected message type to stop the infinity loop,
 		// we expect the error will be infected from this specific message and also the
 		// error will count as a protocol message.
@@ -189,7 +198,7 @@ func TestOnClosedIncomingRoute(t *testing.T) {
 			t.Fatalf("Unexpected error from incomingRoute.Enqueue: %v", err)
 		}
 		incomingRoute.Close()
-		err = transactionrelay.HandleRelayedTransactions(context, incomingRoute, outgoingRoute)
+		err = transactionrelay.HandleRelayedTransactions(context, incomingRoute, outgoingRoute, peerpkg.New(nil))
 		if err == nil || !errors.Is(err, router.ErrRouteClosed) {
 			t.Fatalf("Unexpected error: expected: %v, got : %v", router.ErrRouteClosed, err)
 		}
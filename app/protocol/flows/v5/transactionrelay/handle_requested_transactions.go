@@ -2,22 +2,27 @@ package transactionrelay
 
 import (
 	"github.com/Hoosat-Oy/HTND/app/appmessage"
+	peerpkg "github.com/Hoosat-Oy/HTND/app/protocol/peer"
 	"github.com/Hoosat-Oy/HTND/infrastructure/network/netadapter/router"
 )
 
 type handleRequestedTransactionsFlow struct {
 	TransactionsRelayContext
 	incomingRoute, outgoingRoute *router.Route
+	peer                         *peerpkg.Peer
 }
 
 // HandleRequestedTransactions listens to appmessage.MsgRequestTransactions messages, responding with the requested
 // transactions if those are in the mempool.
-// Missing transactions would be ignored
-func HandleRequestedTransactions(context TransactionsRelayContext, incomingRoute *router.Route, outgoingRoute *router.Route) error {
+// Missing transactions, and transactions that are in their stem phase through another peer, are reported as not found
+func HandleRequestedTransactions(context TransactionsRelayContext, incomingRoute *router.Route,
+	outgoingRoute *router.Route, peer *peerpkg.Peer) error {
+
 	flow := &handleRequestedTransactionsFlow{
 		TransactionsRelayContext: context,
 		incomingRoute:            incomingRoute,
 		outgoingRoute:            outgoingRoute,
+		peer:                     peer,
 	}
 	return flow.start()
 }
@@ -31,6 +36,9 @@ func (flow *handleRequestedTransactionsFlow) start() error {
 
 		for _, transactionID := range msgRequestTransactions.IDs {
 			tx, _, ok := flow.Domain().MiningManager().GetTransaction(transactionID, true, false)
+			if ok && flow.ShouldHideTransaction(transactionID, flow.peer) {
+				ok = false
+			}
 
 			if !ok {
 				msgTransactionNotFound := appmessage.NewMsgTransactionNotFound(transactionID)
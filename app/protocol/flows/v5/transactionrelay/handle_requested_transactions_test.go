@@ -83,7 +83,7 @@ func TestHandleRequestedTransactionsNotFound(t *testing.T) {
 			incomingRoute.Close()
 		})
 
-		err = transactionrelay.HandleRequestedTransactions(context, incomingRoute, outgoingRoute)
+		err = transactionrelay.HandleRequestedTransactions(context, incomingRoute, outgoingRoute, nil)
 		// Make sure the error is due to the closed route.
 		if err == nil || !errors.Is(err, router.ErrRouteClosed) {
 			t.Fatalf("Unexpected error: expected: %v, got : %v", router.ErrRouteClosed, err)
@@ -0,0 +1,176 @@
+package blockrelay
+
+import (
+	"math/rand"
+
+	"github.com/Hoosat-Oy/HTND/app/appmessage"
+	"github.com/Hoosat-Oy/HTND/app/protocol/protocolerrors"
+	"github.com/Hoosat-Oy/HTND/domain/consensus/model/externalapi"
+	"github.com/Hoosat-Oy/HTND/domain/consensus/utils/consensushashing"
+	"github.com/Hoosat-Oy/HTND/domain/consensus/utils/merkle"
+)
+
+// domainBlockToCompactBlock returns the compact block of block. The coinbase transaction,
+// which the receiver can't have in its mempool, is sent in full.
+func domainBlockToCompactBlock(block *externalapi.DomainBlock) *appmessage.MsgCompactBlock {
+	nonce := rand.Uint64()
+	key := newShortIDKey(consensushashing.BlockHash(block), nonce)
+
+	var prefilledTransactions []*appmessage.PrefilledTransaction
+	shortIDs := make([]uint64, 0, len(block.Transactions))
+	for i, transaction := range block.Transactions {
+		if i == 0 {
+			prefilledTransactions = append(prefilledTransactions, &appmessage.PrefilledTransaction{
+				Index: uint32(i),
+				Tx:    appmessage.DomainTransactionToMsgTx(transaction),
+			})
+			continue
+		}
+		shortIDs = append(shortIDs, key.shortID(consensushashing.TransactionID(transaction)))
+	}
+
+	return appmessage.NewMsgCompactBlock(appmessage.DomainBlockHeaderToBlockHeader(block.Header),
+		nonce, shortIDs, prefilledTransactions)
+}
+
+// partialBlock is a block that is being rebuilt from a compact block
+type partialBlock struct {
+	header       externalapi.BlockHeader
+	hash         *externalapi.DomainHash
+	transactions []*externalapi.DomainTransaction
+	prefilled    []bool
+	shortIDs     []uint64
+	key          shortIDKey
+}
+
+// newPartialBlock checks that compactBlock is well formed, and places its prefilled
+// transactions in their positions in the block
+func newPartialBlock(compactBlock *appmessage.MsgCompactBlock) (*partialBlock, error) {
+	header := appmessage.BlockHeaderToDomainBlockHeader(&compactBlock.Header)
+	hash := consensushashing.HeaderHash(header)
+	transactionCount := len(compactBlock.ShortIDs) + len(compactBlock.PrefilledTransactions)
+
+	pb := &partialBlock{
+		header:       header,
+		hash:         hash,
+		transactions: make([]*externalapi.DomainTransaction, transactionCount),
+		prefilled:    make([]bool, transactionCount),
+		shortIDs:     make([]uint64, transactionCount),
+		key:          newShortIDKey(hash, compactBlock.ShortIDNonce),
+	}
+	for i, prefilledTransaction := range compactBlock.PrefilledTransactions {
+		index := int(prefilledTransaction.Index)
+		if index >= transactionCount {
+			return nil, protocolerrors.Errorf(true, "compact block %s has a prefilled transaction at index %d "+
+				"while it has %d transactions", hash, index, transactionCount)
+		}
+		if i > 0 && index <= int(compactBlock.PrefilledTransactions[i-1].Index) {
+			return nil, protocolerrors.Errorf(true, "the prefilled transactions of compact block %s "+
+				"are not sorted by their indexes", hash)
+		}
+		pb.transactions[index] = appmessage.MsgTxToDomainTransaction(prefilledTransaction.Tx)
+		pb.prefilled[index] = true
+	}
+
+	shortIDIndex := 0
+	for i := range pb.shortIDs {
+		if pb.prefilled[i] {
+			continue
+		}
+		pb.shortIDs[i] = compactBlock.ShortIDs[shortIDIndex]
+		shortIDIndex++
+	}
+	return pb, nil
+}
+
+// fillFromMempool fills the transactions of the block from mempoolTransactions. Short IDs
+// that match more than one mempool transaction are left missing.
+func (pb *partialBlock) fillFromMempool(mempoolTransactions []*externalapi.DomainTransaction) {
+	neededShortIDs := make(map[uint64]struct{}, len(pb.shortIDs))
+	for i, shortID := range pb.shortIDs {
+		if !pb.prefilled[i] {
+			neededShortIDs[shortID] = struct{}{}
+		}
+	}
+
+	candidates := make(map[uint64]*externalapi.DomainTransaction, len(neededShortIDs))
+	for _, transaction := range mempoolTransactions {
+		shortID := pb.key.shortID(consensushashing.TransactionID(transaction))
+		if _, ok := neededShortIDs[shortID]; !ok {
+			continue
+		}
+		if _, ok := candidates[shortID]; ok {
+			// Ambiguous short IDs are requested from the peer
+			candidates[shortID] = nil
+			continue
+		}
+		candidates[shortID] = transaction
+	}
+
+	for i, shortID := range pb.shortIDs {
+		if pb.prefilled[i] {
+			continue
+		}
+		if transaction := candidates[shortID]; transaction != nil {
+			pb.transactions[i] = blockTransactionFromMempoolTransaction(transaction)
+		}
+	}
+}
+
+// blockTransactionFromMempoolTransaction returns a copy of mempoolTransaction without the
+// fee, mass and UTXO entries that the mempool populated from the virtual UTXO set. Consensus
+// doesn't populate fields that are already set, so the block has to be validated against
+// its own past UTXO set rather than against the ones of the virtual.
+func blockTransactionFromMempoolTransaction(mempoolTransaction *externalapi.DomainTransaction) *externalapi.DomainTransaction {
+	transaction := mempoolTransaction.Clone()
+	transaction.Fee = 0
+	transaction.Mass = 0
+	for _, input := range transaction.Inputs {
+		input.UTXOEntry = nil
+	}
+	return transaction
+}
+
+// missingIndexes returns the indexes of the transactions that weren't filled yet
+func (pb *partialBlock) missingIndexes() []uint32 {
+	var missingIndexes []uint32
+	for i, transaction := range pb.transactions {
+		if transaction == nil {
+			missingIndexes = append(missingIndexes, uint32(i))
+		}
+	}
+	return missingIndexes
+}
+
+// nonPrefilledIndexes returns the indexes of all the transactions that weren't sent in full
+func (pb *partialBlock) nonPrefilledIndexes() []uint32 {
+	var indexes []uint32
+	for i, isPrefilled := range pb.prefilled {
+		if !isPrefilled {
+			indexes = append(indexes, uint32(i))
+		}
+	}
+	return indexes
+}
+
+// fill sets the transactions at the given indexes
+func (pb *partialBlock) fill(indexes []uint32, transactions []*appmessage.MsgTx) error {
+	if len(transactions) != len(indexes) {
+		return protocolerrors.Errorf(true, "got %d transactions of compact block %s while %d were requested",
+			len(transactions), pb.hash, len(indexes))
+	}
+	for i, index := range indexes {
+		pb.transactions[index] = appmessage.MsgTxToDomainTransaction(transactions[i])
+	}
+	return nil
+}
+
+// block returns the rebuilt block, and whether its transactions match the merkle root of
+// its header. A mismatch means that a short ID matched the wrong mempool transaction.
+func (pb *partialBlock) block() (*externalapi.DomainBlock, bool) {
+	block := &externalapi.DomainBlock{
+		Header:       pb.header,
+		Transactions: pb.transactions,
+	}
+	return block, merkle.CalculateHashMerkleRoot(pb.transactions).Equal(pb.header.HashMerkleRoot())
+}
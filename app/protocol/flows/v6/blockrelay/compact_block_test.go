@@ -0,0 +1,198 @@
+package blockrelay
+
+import (
+	"encoding/binary"
+	"math/big"
+	"testing"
+
+	"github.com/Hoosat-Oy/HTND/app/appmessage"
+	"github.com/Hoosat-Oy/HTND/domain/consensus/model/externalapi"
+	"github.com/Hoosat-Oy/HTND/domain/consensus/utils/blockheader"
+	"github.com/Hoosat-Oy/HTND/domain/consensus/utils/consensushashing"
+	"github.com/Hoosat-Oy/HTND/domain/consensus/utils/merkle"
+	"github.com/Hoosat-Oy/HTND/domain/consensus/utils/subnetworks"
+	"github.com/Hoosat-Oy/HTND/domain/consensus/utils/utxo"
+)
+
+func TestSipHash24(t *testing.T) {
+	// The test vector from the appendix of the SipHash paper
+	key := make([]byte, 16)
+	for i := range key {
+		key[i] = byte(i)
+	}
+	data := make([]byte, 15)
+	for i := range data {
+		data[i] = byte(i)
+	}
+
+	hash := sipHash24(binary.LittleEndian.Uint64(key[:8]), binary.LittleEndian.Uint64(key[8:]), data)
+	if hash != 0xa129ca6149be45e5 {
+		t.Fatalf("unexpected SipHash-2-4 result %x", hash)
+	}
+}
+
+func testTransaction(subnetworkID externalapi.DomainSubnetworkID, lockTime uint64) *externalapi.DomainTransaction {
+	return &externalapi.DomainTransaction{
+		Version: 0,
+		Inputs:  []*externalapi.DomainTransactionInput{},
+		Outputs: []*externalapi.DomainTransactionOutput{{
+			Value:           lockTime,
+			ScriptPublicKey: &externalapi.ScriptPublicKey{Script: []byte{0x51}, Version: 0},
+		}},
+		LockTime:     lockTime,
+		SubnetworkID: subnetworkID,
+		Payload:      []byte{},
+	}
+}
+
+func testBlock(transactionCount int) *externalapi.DomainBlock {
+	transactions := []*externalapi.DomainTransaction{testTransaction(subnetworks.SubnetworkIDCoinbase, 0)}
+	for i := 1; i < transactionCount; i++ {
+		transactions = append(transactions, testTransaction(subnetworks.SubnetworkIDNative, uint64(i)))
+	}
+	header := blockheader.NewImmutableBlockHeader(0, []externalapi.BlockLevelParents{},
+		merkle.CalculateHashMerkleRoot(transactions), &externalapi.DomainHash{}, &externalapi.DomainHash{},
+		1234, 0x207fffff, 5, 10, 10, big.NewInt(100), &externalapi.DomainHash{})
+	return &externalapi.DomainBlock{Header: header, Transactions: transactions}
+}
+
+func TestCompactBlockRebuild(t *testing.T) {
+	block := testBlock(10)
+	compactBlock := domainBlockToCompactBlock(block)
+	if len(compactBlock.PrefilledTransactions) != 1 || compactBlock.PrefilledTransactions[0].Index != 0 {
+		t.Fatalf("expected only the coinbase to be prefilled, got %d prefilled transactions",
+			len(compactBlock.PrefilledTransactions))
+	}
+	if len(compactBlock.ShortIDs) != len(block.Transactions)-1 {
+		t.Fatalf("expected %d short IDs, got %d", len(block.Transactions)-1, len(compactBlock.ShortIDs))
+	}
+
+	partialBlock, err := newPartialBlock(compactBlock)
+	if err != nil {
+		t.Fatalf("newPartialBlock: %s", err)
+	}
+	if !partialBlock.hash.Equal(consensushashing.BlockHash(block)) {
+		t.Fatalf("the partial block hash is %s while the block hash is %s",
+			partialBlock.hash, consensushashing.BlockHash(block))
+	}
+
+	// The mempool is missing transactions 3 and 7, and contains an unrelated transaction
+	var mempool []*externalapi.DomainTransaction
+	for i, transaction := range block.Transactions[1:] {
+		if i+1 == 3 || i+1 == 7 {
+			continue
+		}
+		mempool = append(mempool, transaction)
+	}
+	mempool = append(mempool, testTransaction(subnetworks.SubnetworkIDNative, 1000))
+	partialBlock.fillFromMempool(mempool)
+
+	missingIndexes := partialBlock.missingIndexes()
+	if len(missingIndexes) != 2 || missingIndexes[0] != 3 || missingIndexes[1] != 7 {
+		t.Fatalf("expected indexes 3 and 7 to be missing, got %v", missingIndexes)
+	}
+
+	err = partialBlock.fill(missingIndexes, []*appmessage.MsgTx{appmessage.DomainTransactionToMsgTx(block.Transactions[3])})
+	if err == nil {
+		t.Fatalf("expected an error when filling fewer transactions than requested")
+	}
+	err = partialBlock.fill(missingIndexes, []*appmessage.MsgTx{
+		appmessage.DomainTransactionToMsgTx(block.Transactions[3]),
+		appmessage.DomainTransactionToMsgTx(block.Transactions[7]),
+	})
+	if err != nil {
+		t.Fatalf("fill: %s", err)
+	}
+
+	rebuiltBlock, merkleRootMatches := partialBlock.block()
+	if !merkleRootMatches {
+		t.Fatalf("the merkle root of the rebuilt block doesn't match its header")
+	}
+	if !consensushashing.BlockHash(rebuiltBlock).Equal(consensushashing.BlockHash(block)) {
+		t.Fatalf("the rebuilt block has a different hash")
+	}
+	for i, transaction := range rebuiltBlock.Transactions {
+		if !consensushashing.TransactionID(transaction).Equal(consensushashing.TransactionID(block.Transactions[i])) {
+			t.Fatalf("transaction %d of the rebuilt block is different", i)
+		}
+	}
+}
+
+func TestCompactBlockWrongTransaction(t *testing.T) {
+	block := testBlock(3)
+	partialBlock, err := newPartialBlock(domainBlockToCompactBlock(block))
+	if err != nil {
+		t.Fatalf("newPartialBlock: %s", err)
+	}
+	err = partialBlock.fill(partialBlock.nonPrefilledIndexes(), []*appmessage.MsgTx{
+		appmessage.DomainTransactionToMsgTx(block.Transactions[2]),
+		appmessage.DomainTransactionToMsgTx(block.Transactions[1]),
+	})
+	if err != nil {
+		t.Fatalf("fill: %s", err)
+	}
+	if _, merkleRootMatches := partialBlock.block(); merkleRootMatches {
+		t.Fatalf("expected the merkle root not to match when transactions are swapped")
+	}
+}
+
+func TestCompactBlockInvalidPrefilledIndex(t *testing.T) {
+	compactBlock := domainBlockToCompactBlock(testBlock(3))
+	compactBlock.PrefilledTransactions[0].Index = 3
+	if _, err := newPartialBlock(compactBlock); err == nil {
+		t.Fatalf("expected an error for a prefilled transaction out of the block's range")
+	}
+
+	compactBlock = domainBlockToCompactBlock(testBlock(3))
+	compactBlock.PrefilledTransactions = append(compactBlock.PrefilledTransactions,
+		&appmessage.PrefilledTransaction{Index: 0, Tx: compactBlock.PrefilledTransactions[0].Tx})
+	compactBlock.ShortIDs = compactBlock.ShortIDs[:1]
+	if _, err := newPartialBlock(compactBlock); err == nil {
+		t.Fatalf("expected an error for unsorted prefilled transactions")
+	}
+}
+
+func TestCompactBlockRebuildClearsMempoolFields(t *testing.T) {
+	block := testBlock(2)
+	blockTransaction := block.Transactions[1]
+	blockTransaction.Inputs = []*externalapi.DomainTransactionInput{{
+		PreviousOutpoint: externalapi.DomainOutpoint{TransactionID: externalapi.DomainTransactionID{}, Index: 0},
+		SignatureScript:  []byte{},
+		Sequence:         0,
+	}}
+	block.Header = blockheader.NewImmutableBlockHeader(0, []externalapi.BlockLevelParents{},
+		merkle.CalculateHashMerkleRoot(block.Transactions), &externalapi.DomainHash{}, &externalapi.DomainHash{},
+		1234, 0x207fffff, 5, 10, 10, big.NewInt(100), &externalapi.DomainHash{})
+
+	// The mempool holds the transaction populated from the virtual UTXO set
+	mempoolTransaction := blockTransaction.Clone()
+	mempoolTransaction.Fee = 10
+	mempoolTransaction.Mass = 1000
+	mempoolTransaction.Inputs[0].UTXOEntry = utxo.NewUTXOEntry(100,
+		&externalapi.ScriptPublicKey{Script: []byte{0x51}, Version: 0}, false, 1234)
+
+	partialBlock, err := newPartialBlock(domainBlockToCompactBlock(block))
+	if err != nil {
+		t.Fatalf("newPartialBlock: %s", err)
+	}
+	partialBlock.fillFromMempool([]*externalapi.DomainTransaction{mempoolTransaction})
+	if missingIndexes := partialBlock.missingIndexes(); len(missingIndexes) != 0 {
+		t.Fatalf("expected no missing transactions, got %v", missingIndexes)
+	}
+
+	rebuiltBlock, merkleRootMatches := partialBlock.block()
+	if !merkleRootMatches {
+		t.Fatalf("the merkle root of the rebuilt block doesn't match its header")
+	}
+	rebuiltTransaction := rebuiltBlock.Transactions[1]
+	if rebuiltTransaction.Fee != 0 || rebuiltTransaction.Mass != 0 {
+		t.Fatalf("expected the fee and mass of the rebuilt transaction to be cleared, got fee %d and mass %d",
+			rebuiltTransaction.Fee, rebuiltTransaction.Mass)
+	}
+	if rebuiltTransaction.Inputs[0].UTXOEntry != nil {
+		t.Fatalf("expected the UTXO entry of the rebuilt transaction input to be cleared")
+	}
+	if mempoolTransaction.Inputs[0].UTXOEntry == nil || mempoolTransaction.Fee != 10 {
+		t.Fatalf("the mempool transaction was modified")
+	}
+}
@@ -0,0 +1,91 @@
+package blockrelay
+
+import (
+	"github.com/Hoosat-Oy/HTND/app/appmessage"
+	peerpkg "github.com/Hoosat-Oy/HTND/app/protocol/peer"
+	"github.com/Hoosat-Oy/HTND/app/protocol/protocolerrors"
+	"github.com/Hoosat-Oy/HTND/domain"
+	"github.com/Hoosat-Oy/HTND/infrastructure/network/netadapter/router"
+	"github.com/pkg/errors"
+)
+
+// RelayBlockRequestsContext is the interface for the context needed for the HandleRelayBlockRequests flow.
+type RelayBlockRequestsContext interface {
+	Domain() domain.Domain
+}
+
+// HandleRelayBlockRequests listens to appmessage.MsgRequestRelayBlocks messages and sends
+// the compact blocks of their corresponding blocks to the requesting peer. It also sends the
+// transactions that the peer couldn't find in its mempool, when it asks for them with
+// appmessage.MsgRequestBlockTransactions.
+func HandleRelayBlockRequests(context RelayBlockRequestsContext, incomingRoute *router.Route,
+	outgoingRoute *router.Route, peer *peerpkg.Peer) error {
+
+	for {
+		message, err := incomingRoute.Dequeue()
+		if err != nil {
+			return err
+		}
+
+		switch message := message.(type) {
+		case *appmessage.MsgRequestRelayBlocks:
+			err = sendCompactBlocks(context, outgoingRoute, message)
+		case *appmessage.MsgRequestBlockTransactions:
+			err = sendBlockTransactions(context, outgoingRoute, message)
+		default:
+			err = protocolerrors.Errorf(true, "unexpected message %s", message.Command())
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
+
+func sendCompactBlocks(context RelayBlockRequestsContext, outgoingRoute *router.Route,
+	message *appmessage.MsgRequestRelayBlocks) error {
+
+	log.Debugf("Got request for relay blocks with hashes %s", message.Hashes)
+	for _, hash := range message.Hashes {
+		// Fetch the block from the database.
+		block, found, err := context.Domain().Consensus().GetBlock(hash)
+		if err != nil {
+			return errors.Wrapf(err, "unable to fetch requested block hash %s", hash)
+		}
+
+		if !found {
+			return protocolerrors.Errorf(false, "Relay block %s not found", hash)
+		}
+
+		err = outgoingRoute.Enqueue(domainBlockToCompactBlock(block))
+		if err != nil {
+			return err
+		}
+		log.Debugf("Relayed compact block with hash %s", hash)
+	}
+	return nil
+}
+
+func sendBlockTransactions(context RelayBlockRequestsContext, outgoingRoute *router.Route,
+	message *appmessage.MsgRequestBlockTransactions) error {
+
+	block, found, err := context.Domain().Consensus().GetBlock(message.BlockHash)
+	if err != nil {
+		return errors.Wrapf(err, "unable to fetch block %s", message.BlockHash)
+	}
+	if !found {
+		return protocolerrors.Errorf(false, "Block %s not found while its transactions were requested",
+			message.BlockHash)
+	}
+
+	transactions := make([]*appmessage.MsgTx, len(message.Indexes))
+	for i, index := range message.Indexes {
+		if int(index) >= len(block.Transactions) {
+			return protocolerrors.Errorf(true, "requested transaction %d of block %s, which has only %d "+
+				"transactions", index, message.BlockHash, len(block.Transactions))
+		}
+		transactions[i] = appmessage.DomainTransactionToMsgTx(block.Transactions[index])
+	}
+
+	log.Debugf("Sending %d transactions of block %s", len(transactions), message.BlockHash)
+	return outgoingRoute.Enqueue(appmessage.NewMsgBlockTransactions(message.BlockHash, transactions))
+}
@@ -0,0 +1,84 @@
+package blockrelay
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"math/bits"
+
+	"github.com/Hoosat-Oy/HTND/domain/consensus/model/externalapi"
+)
+
+// shortIDMask keeps the 48 bits of a short ID. That's enough to make collisions between
+// the transactions of a block and a mempool of a few thousand transactions very rare,
+// and when they do happen they only cost a round trip.
+const shortIDMask = 1<<48 - 1
+
+// shortIDKey is the SipHash key that the short IDs of a compact block are calculated with.
+// It's derived from the block hash and a nonce that the sender picks, so that an attacker
+// can't craft transactions that collide in the short IDs of every block.
+type shortIDKey struct {
+	k0, k1 uint64
+}
+
+func newShortIDKey(blockHash *externalapi.DomainHash, nonce uint64) shortIDKey {
+	data := make([]byte, externalapi.DomainHashSize+8)
+	copy(data, blockHash.ByteSlice())
+	binary.LittleEndian.PutUint64(data[externalapi.DomainHashSize:], nonce)
+	hash := sha256.Sum256(data)
+	return shortIDKey{
+		k0: binary.LittleEndian.Uint64(hash[0:8]),
+		k1: binary.LittleEndian.Uint64(hash[8:16]),
+	}
+}
+
+func (key shortIDKey) shortID(transactionID *externalapi.DomainTransactionID) uint64 {
+	return sipHash24(key.k0, key.k1, transactionID.ByteSlice()) & shortIDMask
+}
+
+// sipHash24 implements SipHash-2-4 as described in https://www.aumasson.jp/siphash/siphash.pdf
+func sipHash24(k0, k1 uint64, data []byte) uint64 {
+	v0 := k0 ^ 0x736f6d6570736575
+	v1 := k1 ^ 0x646f72616e646f6d
+	v2 := k0 ^ 0x6c7967656e657261
+	v3 := k1 ^ 0x7465646279746573
+
+	round := func() {
+		v0 += v1
+		v1 = bits.RotateLeft64(v1, 13)
+		v1 ^= v0
+		v0 = bits.RotateLeft64(v0, 32)
+		v2 += v3
+		v3 = bits.RotateLeft64(v3, 16)
+		v3 ^= v2
+		v0 += v3
+		v3 = bits.RotateLeft64(v3, 21)
+		v3 ^= v0
+		v2 += v1
+		v1 = bits.RotateLeft64(v1, 17)
+		v1 ^= v2
+		v2 = bits.RotateLeft64(v2, 32)
+	}
+	compress := func(m uint64) {
+		v3 ^= m
+		round()
+		round()
+		v0 ^= m
+	}
+
+	length := len(data)
+	for ; len(data) >= 8; data = data[8:] {
+		compress(binary.LittleEndian.Uint64(data))
+	}
+	last := uint64(length) << 56
+	for i, b := range data {
+		last |= uint64(b) << (8 * i)
+	}
+	compress(last)
+
+	v2 ^= 0xff
+	round()
+	round()
+	round()
+	round()
+	return v0 ^ v1 ^ v2 ^ v3
+}
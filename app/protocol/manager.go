@@ -37,6 +37,7 @@ func NewManager(cfg *config.Config, domain domain.Domain, netAdapter *netadapter
 	}
 
 	netAdapter.SetP2PRouterInitializer(manager.routerInitializer)
+	connectionManager.SetPeerInfoProvider(manager.context)
 	return &manager, nil
 }
 
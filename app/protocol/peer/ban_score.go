@@ -0,0 +1,60 @@
+package peer
+
+import (
+	"math"
+	"sync"
+	"time"
+)
+
+const (
+	// banScoreHalflife is the time it takes the transient part of a ban score
+	// to decay to half of its value
+	banScoreHalflife = time.Minute
+
+	// banScoreLifetime is the time after which the transient part of a ban
+	// score is considered to have fully decayed
+	banScoreLifetime = 30 * time.Minute
+)
+
+// banScore is a misbehavior score made of a persistent part, which never
+// decays, and of a transient part, which decays exponentially over time.
+// Severe violations increase the persistent part, while violations that are
+// acceptable in small amounts, such as spam, increase the transient part.
+type banScore struct {
+	lock       sync.Mutex
+	persistent uint32
+	transient  float64
+	lastUpdate time.Time
+}
+
+func decayFactor(elapsed time.Duration) float64 {
+	if elapsed >= banScoreLifetime {
+		return 0
+	}
+	if elapsed <= 0 {
+		return 1
+	}
+	return math.Exp(-elapsed.Seconds() * math.Ln2 / banScoreHalflife.Seconds())
+}
+
+// score returns the ban score at the given time
+func (bs *banScore) score(now time.Time) uint32 {
+	bs.lock.Lock()
+	defer bs.lock.Unlock()
+
+	return bs.persistent + uint32(bs.transient*decayFactor(now.Sub(bs.lastUpdate)))
+}
+
+// increase adds the given amounts to the persistent and transient parts of
+// the score at the given time, and returns the resulting score
+func (bs *banScore) increase(persistent uint32, transient uint32, now time.Time) uint32 {
+	bs.lock.Lock()
+	defer bs.lock.Unlock()
+
+	bs.persistent += persistent
+	if transient > 0 {
+		bs.transient = bs.transient*decayFactor(now.Sub(bs.lastUpdate)) + float64(transient)
+		bs.lastUpdate = now
+	}
+	return bs.persistent + uint32(bs.transient*decayFactor(now.Sub(bs.lastUpdate)))
+}
@@ -0,0 +1,41 @@
+package peer
+
+import (
+	"testing"
+	"time"
+)
+
+func TestBanScore(t *testing.T) {
+	start := time.Now()
+	var score banScore
+
+	if score.score(start) != 0 {
+		t.Fatalf("A new ban score is expected to be 0, but got %d", score.score(start))
+	}
+
+	if result := score.increase(10, 0, start); result != 10 {
+		t.Fatalf("Expected a score of 10 after a persistent increase, but got %d", result)
+	}
+	if result := score.increase(0, 40, start); result != 50 {
+		t.Fatalf("Expected a score of 50 after a transient increase, but got %d", result)
+	}
+
+	// After a single halflife the transient part is halved, while the persistent part stays
+	if result := score.score(start.Add(banScoreHalflife)); result != 30 {
+		t.Fatalf("Expected a score of 30 after a halflife, but got %d", result)
+	}
+
+	// Transient increases stack on top of the decayed transient part
+	if result := score.increase(0, 40, start.Add(banScoreHalflife)); result != 70 {
+		t.Fatalf("Expected a score of 70 after another transient increase, but got %d", result)
+	}
+
+	// Once the lifetime passes, only the persistent part remains
+	later := start.Add(banScoreHalflife + banScoreLifetime)
+	if result := score.score(later); result != 10 {
+		t.Fatalf("Expected only the persistent score of 10 to remain, but got %d", result)
+	}
+	if result := score.increase(5, 0, later); result != 15 {
+		t.Fatalf("Expected a score of 15 after another persistent increase, but got %d", result)
+	}
+}
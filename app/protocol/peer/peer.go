@@ -33,6 +33,11 @@ type Peer struct {
 	lastPingDuration time.Duration // Time for last ping to return
 
 	ibdRequestChannel chan *externalapi.DomainBlock // A channel used to communicate IBD requests between flows
+
+	banScore banScore
+
+	novelBlockLock     sync.RWMutex
+	lastNovelBlockTime time.Time // Time this peer last relayed a block we didn't have
 }
 
 // New returns a new Peer
@@ -151,3 +156,38 @@ func (p *Peer) LastPingDuration() time.Duration {
 func (p *Peer) IBDRequestChannel() chan *externalapi.DomainBlock {
 	return p.ibdRequestChannel
 }
+
+// BanScore returns the current misbehavior score of this peer
+func (p *Peer) BanScore() uint32 {
+	return p.banScore.score(time.Now())
+}
+
+// IncreaseBanScore increases the misbehavior score of this peer. The persistent
+// amount never decays, while the transient amount decays over time. It returns
+// the resulting score.
+func (p *Peer) IncreaseBanScore(persistent uint32, transient uint32) uint32 {
+	return p.banScore.increase(persistent, transient, time.Now())
+}
+
+// WaitForUploadQuota blocks until the upload rate limits allow sending more IBD data
+// to this peer.
+func (p *Peer) WaitForUploadQuota() {
+	p.connection.WaitForUploadQuota()
+}
+
+// MarkNovelBlock records that this peer has just relayed a block we didn't have
+func (p *Peer) MarkNovelBlock() {
+	p.novelBlockLock.Lock()
+	defer p.novelBlockLock.Unlock()
+
+	p.lastNovelBlockTime = time.Now()
+}
+
+// LastNovelBlockTime returns the time this peer last relayed a block we didn't
+// have, or the zero time if it never did
+func (p *Peer) LastNovelBlockTime() time.Time {
+	p.novelBlockLock.RLock()
+	defer p.novelBlockLock.RUnlock()
+
+	return p.lastNovelBlockTime
+}
@@ -7,6 +7,7 @@ import (
 	"github.com/Hoosat-Oy/HTND/app/protocol/common"
 	"github.com/Hoosat-Oy/HTND/app/protocol/flows/ready"
 	v5 "github.com/Hoosat-Oy/HTND/app/protocol/flows/v5"
+	v6 "github.com/Hoosat-Oy/HTND/app/protocol/flows/v6"
 
 	"github.com/Hoosat-Oy/HTND/app/appmessage"
 	"github.com/Hoosat-Oy/HTND/app/protocol/flows/handshake"
@@ -79,6 +80,8 @@ func (m *Manager) routerInitializer(router *routerpkg.Router, netConnection *net
 		switch peer.ProtocolVersion() {
 		case 5:
 			flows = v5.Register(m, router, errChan, &isStopping)
+		case 6:
+			flows = v6.Register(m, router, errChan, &isStopping)
 		default:
 			panic(errors.Errorf("no way to handle protocol version %d", peer.ProtocolVersion()))
 		}
@@ -108,16 +111,20 @@ func (m *Manager) routerInitializer(router *routerpkg.Router, netConnection *net
 func (m *Manager) handleError(err error, netConnection *netadapter.NetConnection, outgoingRoute *routerpkg.Route) {
 	if protocolErr := (protocolerrors.ProtocolError{}); errors.As(err, &protocolErr) {
 		if m.context.Config().EnableBanning && protocolErr.ShouldBan {
-			log.Warnf("Banning %s (reason: %s)", netConnection, protocolErr.Cause)
+			if m.context.IsWhitelisted(netConnection) {
+				log.Infof("Not banning whitelisted peer %s (reason: %s)", netConnection, protocolErr.Cause)
+			} else {
+				log.Warnf("Banning %s (reason: %s)", netConnection, protocolErr.Cause)
 
-			err := m.context.ConnectionManager().Ban(netConnection)
-			if err != nil && !errors.Is(err, connmanager.ErrCannotBanPermanent) {
-				panic(err)
-			}
+				err := m.context.ConnectionManager().Ban(netConnection, protocolErr.Cause.Error())
+				if err != nil && !errors.Is(err, connmanager.ErrCannotBanPermanent) {
+					panic(err)
+				}
 
-			err = outgoingRoute.Enqueue(appmessage.NewMsgReject(protocolErr.Error()))
-			if err != nil && !errors.Is(err, routerpkg.ErrRouteClosed) {
-				panic(err)
+				err = outgoingRoute.Enqueue(appmessage.NewMsgReject(protocolErr.Error()))
+				if err != nil && !errors.Is(err, routerpkg.ErrRouteClosed) {
+					panic(err)
+				}
 			}
 		}
 		log.Infof("Disconnecting from %s (reason: %s)", netConnection, protocolErr.Cause)
@@ -8,6 +8,7 @@ import (
 	"github.com/Hoosat-Oy/HTND/domain/consensus/model/externalapi"
 	"github.com/Hoosat-Oy/HTND/domain/utxoindex"
 	"github.com/Hoosat-Oy/HTND/infrastructure/config"
+	"github.com/Hoosat-Oy/HTND/infrastructure/db/database"
 	"github.com/Hoosat-Oy/HTND/infrastructure/logger"
 	"github.com/Hoosat-Oy/HTND/infrastructure/network/addressmanager"
 	"github.com/Hoosat-Oy/HTND/infrastructure/network/connmanager"
@@ -29,6 +30,8 @@ func NewManager(
 	connectionManager *connmanager.ConnectionManager,
 	addressManager *addressmanager.AddressManager,
 	utxoIndex *utxoindex.UTXOIndex,
+	db database.Database,
+	dbPath string,
 	consensusEventsChan chan externalapi.ConsensusEvent,
 	shutDownChan chan<- struct{}) *Manager {
 
@@ -41,6 +44,8 @@ func NewManager(
 			connectionManager,
 			addressManager,
 			utxoIndex,
+			db,
+			dbPath,
 			shutDownChan,
 		),
 	}
@@ -195,10 +200,7 @@ func (m *Manager) notifyPruningPointUTXOSetOverride() error {
 	onEnd := logger.LogAndMeasureExecutionTime(log, "RPCManager.notifyPruningPointUTXOSetOverride")
 	defer onEnd()
 
-	err := m.context.UTXOIndex.Reset()
-	if err != nil {
-		return err
-	}
+	m.context.UTXOIndex.Rebuild()
 
 	return m.context.NotificationManager.NotifyPruningPointUTXOSetOverride()
 }
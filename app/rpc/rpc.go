@@ -43,6 +43,13 @@ var handlers = map[appmessage.MessageCommand]handler{
 	appmessage.CmdNotifyVirtualSelectedParentBlueScoreChangedRequestMessage: rpchandlers.HandleNotifyVirtualSelectedParentBlueScoreChanged,
 	appmessage.CmdBanRequestMessage:                                         rpchandlers.HandleBan,
 	appmessage.CmdUnbanRequestMessage:                                       rpchandlers.HandleUnban,
+	appmessage.CmdGetBansRequestMessage:                                     rpchandlers.HandleGetBans,
+	appmessage.CmdGetWhitelistRequestMessage:                                rpchandlers.HandleGetWhitelist,
+	appmessage.CmdAddToWhitelistRequestMessage:                              rpchandlers.HandleAddToWhitelist,
+	appmessage.CmdRemoveFromWhitelistRequestMessage:                         rpchandlers.HandleRemoveFromWhitelist,
+	appmessage.CmdGetCacheStatsRequestMessage:                               rpchandlers.HandleGetCacheStats,
+	appmessage.CmdGetDatabaseStatsRequestMessage:                            rpchandlers.HandleGetDatabaseStats,
+	appmessage.CmdCompactDatabaseRequestMessage:                             rpchandlers.HandleCompactDatabase,
 	appmessage.CmdGetInfoRequestMessage:                                     rpchandlers.HandleGetInfo,
 	appmessage.CmdNotifyPruningPointUTXOSetOverrideRequestMessage:           rpchandlers.HandleNotifyPruningPointUTXOSetOverrideRequest,
 	appmessage.CmdStopNotifyingPruningPointUTXOSetOverrideRequestMessage:    rpchandlers.HandleStopNotifyingPruningPointUTXOSetOverrideRequest,
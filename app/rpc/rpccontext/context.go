@@ -1,10 +1,12 @@
 package rpccontext
 
 import (
+	"github.com/Hoosat-Oy/HTND/app/dbmaintenance"
 	"github.com/Hoosat-Oy/HTND/app/protocol"
 	"github.com/Hoosat-Oy/HTND/domain"
 	"github.com/Hoosat-Oy/HTND/domain/utxoindex"
 	"github.com/Hoosat-Oy/HTND/infrastructure/config"
+	"github.com/Hoosat-Oy/HTND/infrastructure/db/database"
 	"github.com/Hoosat-Oy/HTND/infrastructure/network/addressmanager"
 	"github.com/Hoosat-Oy/HTND/infrastructure/network/connmanager"
 	"github.com/Hoosat-Oy/HTND/infrastructure/network/netadapter"
@@ -19,9 +21,13 @@ type Context struct {
 	ConnectionManager *connmanager.ConnectionManager
 	AddressManager    *addressmanager.AddressManager
 	UTXOIndex         *utxoindex.UTXOIndex
+	Database          database.Database
+	DatabasePath      string
 	ShutDownChan      chan<- struct{}
 
-	NotificationManager *NotificationManager
+	NotificationManager    *NotificationManager
+	DatabaseCompactor      *dbmaintenance.Compactor
+	DatabaseStatsCollector *dbmaintenance.StatsCollector
 }
 
 // NewContext creates a new RPC context
@@ -32,6 +38,8 @@ func NewContext(cfg *config.Config,
 	connectionManager *connmanager.ConnectionManager,
 	addressManager *addressmanager.AddressManager,
 	utxoIndex *utxoindex.UTXOIndex,
+	db database.Database,
+	dbPath string,
 	shutDownChan chan<- struct{}) *Context {
 
 	context := &Context{
@@ -42,9 +50,13 @@ func NewContext(cfg *config.Config,
 		ConnectionManager: connectionManager,
 		AddressManager:    addressManager,
 		UTXOIndex:         utxoIndex,
+		Database:          db,
+		DatabasePath:      dbPath,
 		ShutDownChan:      shutDownChan,
 	}
 	context.NotificationManager = NewNotificationManager(cfg.ActiveNetParams)
+	context.DatabaseCompactor = dbmaintenance.NewCompactor(db)
+	context.DatabaseStatsCollector = dbmaintenance.NewStatsCollector(db)
 
 	return context
 }
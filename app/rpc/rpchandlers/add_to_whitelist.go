@@ -0,0 +1,36 @@
+package rpchandlers
+
+import (
+	"github.com/Hoosat-Oy/HTND/app/appmessage"
+	"github.com/Hoosat-Oy/HTND/app/rpc/rpccontext"
+	"github.com/Hoosat-Oy/HTND/infrastructure/config"
+	"github.com/Hoosat-Oy/HTND/infrastructure/network/netadapter/router"
+)
+
+// HandleAddToWhitelist handles the respectively named RPC command
+func HandleAddToWhitelist(context *rpccontext.Context, _ *router.Router, request appmessage.Message) (appmessage.Message, error) {
+	if context.Config.SafeRPC {
+		log.Warn("AddToWhitelist RPC command called while node in safe RPC mode -- ignoring.")
+		response := appmessage.NewAddToWhitelistResponseMessage()
+		response.Error =
+			appmessage.RPCErrorf("AddToWhitelist RPC command called while node in safe RPC mode")
+		return response, nil
+	}
+
+	addToWhitelistRequest := request.(*appmessage.AddToWhitelistRequestMessage)
+	subnet, err := config.ParseWhitelist(addToWhitelistRequest.Subnet)
+	if err != nil {
+		errorMessage := appmessage.NewAddToWhitelistResponseMessage()
+		errorMessage.Error = appmessage.RPCErrorf("Could not parse subnet: %s", err)
+		return errorMessage, nil
+	}
+
+	err = context.AddressManager.AddToWhitelist(subnet, addToWhitelistRequest.Reason)
+	if err != nil {
+		errorMessage := appmessage.NewAddToWhitelistResponseMessage()
+		errorMessage.Error = appmessage.RPCErrorf("Could not whitelist subnet: %s", err)
+		return errorMessage, nil
+	}
+	log.Infof("Whitelisted %s (reason: %s)", subnet, addToWhitelistRequest.Reason)
+	return appmessage.NewAddToWhitelistResponseMessage(), nil
+}
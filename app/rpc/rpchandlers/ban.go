@@ -1,7 +1,9 @@
 package rpchandlers
 
 import (
+	"math"
 	"net"
+	"time"
 
 	"github.com/Hoosat-Oy/HTND/app/appmessage"
 	"github.com/Hoosat-Oy/HTND/app/rpc/rpccontext"
@@ -30,7 +32,24 @@ func HandleBan(context *rpccontext.Context, _ *router.Router, request appmessage
 		return errorMessage, nil
 	}
 
-	err := context.ConnectionManager.BanByIP(ip)
+	// maxBanDurationSeconds is the largest duration that still fits in a time.Duration
+	const maxBanDurationSeconds = math.MaxInt64 / int64(time.Second)
+	if banRequest.DurationSeconds > maxBanDurationSeconds {
+		errorMessage := &appmessage.BanResponseMessage{}
+		errorMessage.Error = appmessage.RPCErrorf("Ban duration must be at most %d seconds, "+
+			"or negative to ban forever", maxBanDurationSeconds)
+		return errorMessage, nil
+	}
+
+	var duration time.Duration
+	switch {
+	case banRequest.DurationSeconds == 0:
+		duration = context.Config.BanDuration
+	case banRequest.DurationSeconds > 0:
+		duration = time.Duration(banRequest.DurationSeconds) * time.Second
+	}
+
+	err := context.ConnectionManager.BanByIP(ip, duration, banRequest.Reason)
 	if err != nil {
 		errorMessage := &appmessage.BanResponseMessage{}
 		errorMessage.Error = appmessage.RPCErrorf("Could not ban IP: %s", err)
@@ -0,0 +1,25 @@
+package rpchandlers
+
+import (
+	"github.com/Hoosat-Oy/HTND/app/appmessage"
+	"github.com/Hoosat-Oy/HTND/app/rpc/rpccontext"
+	"github.com/Hoosat-Oy/HTND/infrastructure/network/netadapter/router"
+)
+
+// HandleCompactDatabase handles the respectively named RPC command
+func HandleCompactDatabase(context *rpccontext.Context, _ *router.Router, _ appmessage.Message) (appmessage.Message, error) {
+	if context.Config.SafeRPC {
+		log.Warn("CompactDatabase RPC command called while node in safe RPC mode -- ignoring.")
+		response := &appmessage.CompactDatabaseResponseMessage{}
+		response.Error =
+			appmessage.RPCErrorf("CompactDatabase RPC command called while node in safe RPC mode")
+		return response, nil
+	}
+
+	started := context.DatabaseCompactor.Start()
+	if started {
+		log.Infof("Started compacting the database")
+	}
+	compaction := databaseCompactionState(context.DatabaseCompactor.State())
+	return appmessage.NewCompactDatabaseResponseMessage(started, compaction), nil
+}
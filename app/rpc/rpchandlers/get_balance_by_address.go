@@ -4,6 +4,7 @@ import (
 	"github.com/Hoosat-Oy/HTND/app/appmessage"
 	"github.com/Hoosat-Oy/HTND/app/rpc/rpccontext"
 	"github.com/Hoosat-Oy/HTND/domain/consensus/utils/txscript"
+	"github.com/Hoosat-Oy/HTND/domain/utxoindex"
 	"github.com/Hoosat-Oy/HTND/infrastructure/network/netadapter/router"
 	"github.com/Hoosat-Oy/HTND/util"
 	"github.com/pkg/errors"
@@ -44,14 +45,12 @@ func getBalanceByAddress(context *rpccontext.Context, addressString string) (uin
 	if err != nil {
 		return 0, appmessage.RPCErrorf("Could not create a scriptPublicKey for address '%s': %s", addressString, err)
 	}
-	utxoOutpointEntryPairs, err := context.UTXOIndex.UTXOs(scriptPublicKey)
+	aggregate, err := context.UTXOIndex.Aggregate(scriptPublicKey)
 	if err != nil {
+		if errors.As(err, new(*utxoindex.IndexBuildingError)) {
+			return 0, appmessage.RPCErrorf("%s", err)
+		}
 		return 0, err
 	}
-
-	balance := uint64(0)
-	for _, utxoOutpointEntryPair := range utxoOutpointEntryPairs {
-		balance += utxoOutpointEntryPair.Amount()
-	}
-	return balance, nil
+	return aggregate.Balance, nil
 }
@@ -0,0 +1,28 @@
+package rpchandlers
+
+import (
+	"github.com/Hoosat-Oy/HTND/app/appmessage"
+	"github.com/Hoosat-Oy/HTND/app/rpc/rpccontext"
+	"github.com/Hoosat-Oy/HTND/infrastructure/network/netadapter/router"
+)
+
+// HandleGetBans handles the respectively named RPC command
+func HandleGetBans(context *rpccontext.Context, _ *router.Router, _ appmessage.Message) (appmessage.Message, error) {
+	bans, err := context.AddressManager.Bans()
+	if err != nil {
+		return nil, err
+	}
+
+	banEntries := make([]*appmessage.BanEntry, len(bans))
+	for i, ban := range bans {
+		banEntries[i] = &appmessage.BanEntry{
+			IP:       ban.Address.IP.String(),
+			Reason:   ban.Reason,
+			BannedAt: ban.BannedAt.UnixMilliseconds(),
+		}
+		if !ban.ExpiresAt.IsZero() {
+			banEntries[i].ExpiresAt = ban.ExpiresAt.UnixMilliseconds()
+		}
+	}
+	return appmessage.NewGetBansResponseMessage(banEntries), nil
+}
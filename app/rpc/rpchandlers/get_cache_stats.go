@@ -0,0 +1,23 @@
+package rpchandlers
+
+import (
+	"github.com/Hoosat-Oy/HTND/app/appmessage"
+	"github.com/Hoosat-Oy/HTND/app/rpc/rpccontext"
+	"github.com/Hoosat-Oy/HTND/infrastructure/network/netadapter/router"
+)
+
+// HandleGetCacheStats handles the respectively named RPC command
+func HandleGetCacheStats(context *rpccontext.Context, _ *router.Router, _ appmessage.Message) (appmessage.Message, error) {
+	cacheStats := context.Domain.Consensus().CacheStats()
+	caches := make([]*appmessage.CacheStats, len(cacheStats))
+	for i, stats := range cacheStats {
+		caches[i] = &appmessage.CacheStats{
+			Name:     stats.Name,
+			Size:     uint64(stats.Size),
+			Capacity: uint64(stats.Capacity),
+			Hits:     stats.Hits,
+			Misses:   stats.Misses,
+		}
+	}
+	return appmessage.NewGetCacheStatsResponseMessage(caches, context.Config.CachePreset, context.Config.CacheMem), nil
+}
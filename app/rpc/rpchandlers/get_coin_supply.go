@@ -4,7 +4,9 @@ import (
 	"github.com/Hoosat-Oy/HTND/app/appmessage"
 	"github.com/Hoosat-Oy/HTND/app/rpc/rpccontext"
 	"github.com/Hoosat-Oy/HTND/domain/consensus/utils/constants"
+	"github.com/Hoosat-Oy/HTND/domain/utxoindex"
 	"github.com/Hoosat-Oy/HTND/infrastructure/network/netadapter/router"
+	"github.com/pkg/errors"
 )
 
 // HandleGetCoinSupply handles the respectively named RPC command
@@ -17,6 +19,11 @@ func HandleGetCoinSupply(context *rpccontext.Context, _ *router.Router, _ appmes
 
 	circulatingSompiSupply, err := context.UTXOIndex.GetCirculatingSompiSupply()
 	if err != nil {
+		if errors.As(err, new(*utxoindex.IndexBuildingError)) {
+			errorMessage := &appmessage.GetCoinSupplyResponseMessage{}
+			errorMessage.Error = appmessage.RPCErrorf("%s", err)
+			return errorMessage, nil
+		}
 		return nil, err
 	}
 
@@ -12,6 +12,15 @@ func HandleGetConnectedPeerInfo(context *rpccontext.Context, _ *router.Router, _
 	ibdPeer := context.ProtocolManager.IBDPeer()
 	infos := make([]*appmessage.GetConnectedPeerInfoMessage, 0, len(peers))
 	for _, peer := range peers {
+		bandwidthStats := peer.Connection().BandwidthStats()
+		messageBandwidth := make([]*appmessage.PeerMessageBandwidth, len(bandwidthStats.Commands))
+		for i, commandStats := range bandwidthStats.Commands {
+			messageBandwidth[i] = &appmessage.PeerMessageBandwidth{
+				Command:       commandStats.Command,
+				BytesSent:     commandStats.BytesSent,
+				BytesReceived: commandStats.BytesReceived,
+			}
+		}
 		info := &appmessage.GetConnectedPeerInfoMessage{
 			ID:                        peer.ID().String(),
 			Address:                   peer.Address(),
@@ -22,6 +31,10 @@ func HandleGetConnectedPeerInfo(context *rpccontext.Context, _ *router.Router, _
 			AdvertisedProtocolVersion: peer.AdvertisedProtocolVersion(),
 			TimeConnected:             peer.TimeConnected().Milliseconds(),
 			IsIBDPeer:                 peer == ibdPeer,
+			BanScore:                  peer.BanScore(),
+			BytesSent:                 bandwidthStats.BytesSent,
+			BytesReceived:             bandwidthStats.BytesReceived,
+			MessageBandwidth:          messageBandwidth,
 		}
 		infos = append(infos, info)
 	}
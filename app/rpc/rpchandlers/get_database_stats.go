@@ -0,0 +1,63 @@
+package rpchandlers
+
+import (
+	"github.com/Hoosat-Oy/HTND/app/appmessage"
+	"github.com/Hoosat-Oy/HTND/app/dbmaintenance"
+	"github.com/Hoosat-Oy/HTND/app/rpc/rpccontext"
+	"github.com/Hoosat-Oy/HTND/infrastructure/network/netadapter/router"
+)
+
+// HandleGetDatabaseStats handles the respectively named RPC command
+func HandleGetDatabaseStats(context *rpccontext.Context, _ *router.Router, request appmessage.Message) (appmessage.Message, error) {
+	getDatabaseStatsRequest := request.(*appmessage.GetDatabaseStatsRequestMessage)
+	if getDatabaseStatsRequest.Collect {
+		// Collecting the stats reads every key of the database
+		if context.Config.SafeRPC {
+			log.Warn("GetDatabaseStats RPC command called with collect while node in safe RPC mode -- ignoring.")
+			errorMessage := &appmessage.GetDatabaseStatsResponseMessage{}
+			errorMessage.Error =
+				appmessage.RPCErrorf("GetDatabaseStats RPC command called with collect while node in safe RPC mode")
+			return errorMessage, nil
+		}
+		started := context.DatabaseStatsCollector.Start()
+		if started {
+			log.Infof("Started collecting the database stats")
+		}
+	}
+
+	diskSize, err := dbmaintenance.DiskSize(context.DatabasePath)
+	if err != nil {
+		errorMessage := &appmessage.GetDatabaseStatsResponseMessage{}
+		errorMessage.Error = appmessage.RPCErrorf("Could not measure the size of the database: %s", err)
+		return errorMessage, nil
+	}
+
+	collectionState := context.DatabaseStatsCollector.State()
+	buckets := make([]*appmessage.DatabaseBucketStats, len(collectionState.Buckets))
+	for i, bucketStats := range collectionState.Buckets {
+		buckets[i] = &appmessage.DatabaseBucketStats{
+			Owner:    bucketStats.Owner,
+			Store:    bucketStats.Store,
+			Bucket:   bucketStats.Bucket,
+			KeyCount: bucketStats.KeyCount,
+			Size:     bucketStats.Size,
+		}
+	}
+	collection := &appmessage.DatabaseStatsCollectionState{
+		IsCollecting: collectionState.IsCollecting,
+		LastError:    collectionState.LastError,
+	}
+	if !collectionState.CollectedAt.IsZero() {
+		collection.CollectedAt = collectionState.CollectedAt.UnixMilliseconds()
+	}
+	compaction := databaseCompactionState(context.DatabaseCompactor.State())
+	return appmessage.NewGetDatabaseStatsResponseMessage(buckets, diskSize, compaction, collection), nil
+}
+
+func databaseCompactionState(state dbmaintenance.CompactionState) *appmessage.DatabaseCompactionState {
+	return &appmessage.DatabaseCompactionState{
+		IsCompacting: state.IsCompacting,
+		Progress:     state.Progress,
+		LastError:    state.LastError,
+	}
+}
@@ -1,11 +1,19 @@
 package rpchandlers
 
 import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
 	"github.com/Hoosat-Oy/HTND/app/appmessage"
 	"github.com/Hoosat-Oy/HTND/app/rpc/rpccontext"
+	"github.com/Hoosat-Oy/HTND/domain/consensus/model/externalapi"
 	"github.com/Hoosat-Oy/HTND/domain/consensus/utils/txscript"
+	"github.com/Hoosat-Oy/HTND/domain/utxoindex"
 	"github.com/Hoosat-Oy/HTND/infrastructure/network/netadapter/router"
 	"github.com/Hoosat-Oy/HTND/util"
+	"github.com/pkg/errors"
 )
 
 // HandleGetUTXOsByAddresses handles the respectively named RPC command
@@ -18,8 +26,26 @@ func HandleGetUTXOsByAddresses(context *rpccontext.Context, _ *router.Router, re
 
 	getUTXOsByAddressesRequest := request.(*appmessage.GetUTXOsByAddressesRequestMessage)
 
+	fromAddressIndex, fromOutpoint, err := parseUTXOsByAddressesCursor(getUTXOsByAddressesRequest.Cursor)
+	if err != nil {
+		errorMessage := &appmessage.GetUTXOsByAddressesResponseMessage{}
+		errorMessage.Error = appmessage.RPCErrorf("Could not parse cursor '%s': %s", getUTXOsByAddressesRequest.Cursor, err)
+		return errorMessage, nil
+	}
+	if fromOutpoint != nil && fromAddressIndex >= len(getUTXOsByAddressesRequest.Addresses) {
+		errorMessage := &appmessage.GetUTXOsByAddressesResponseMessage{}
+		errorMessage.Error = appmessage.RPCErrorf("Cursor '%s' doesn't match the requested addresses", getUTXOsByAddressesRequest.Cursor)
+		return errorMessage, nil
+	}
+	remaining := math.MaxInt
+	if getUTXOsByAddressesRequest.Limit > 0 {
+		remaining = int(getUTXOsByAddressesRequest.Limit)
+	}
+
 	allEntries := make([]*appmessage.UTXOsByAddressesEntry, 0)
-	for _, addressString := range getUTXOsByAddressesRequest.Addresses {
+	nextCursor := ""
+	for addressIndex := fromAddressIndex; addressIndex < len(getUTXOsByAddressesRequest.Addresses); addressIndex++ {
+		addressString := getUTXOsByAddressesRequest.Addresses[addressIndex]
 		address, err := util.DecodeAddress(addressString, context.Config.ActiveNetParams.Prefix)
 		if err != nil {
 			errorMessage := &appmessage.GetUTXOsByAddressesResponseMessage{}
@@ -32,14 +58,58 @@ func HandleGetUTXOsByAddresses(context *rpccontext.Context, _ *router.Router, re
 			errorMessage.Error = appmessage.RPCErrorf("Could not create a scriptPublicKey for address '%s': %s", addressString, err)
 			return errorMessage, nil
 		}
-		utxoOutpointEntryPairs, err := context.UTXOIndex.UTXOs(scriptPublicKey)
+		var addressFromOutpoint *externalapi.DomainOutpoint
+		if addressIndex == fromAddressIndex {
+			addressFromOutpoint = fromOutpoint
+		}
+		pairs, err := context.UTXOIndex.UTXOsPage(scriptPublicKey, addressFromOutpoint, remaining)
 		if err != nil {
+			if errors.As(err, new(*utxoindex.IndexBuildingError)) {
+				errorMessage := &appmessage.GetUTXOsByAddressesResponseMessage{}
+				errorMessage.Error = appmessage.RPCErrorf("%s", err)
+				return errorMessage, nil
+			}
 			return nil, err
 		}
-		entries := rpccontext.ConvertUTXOOutpointEntryPairsToUTXOsByAddressesEntries(addressString, utxoOutpointEntryPairs)
-		allEntries = append(allEntries, entries...)
+		allEntries = append(allEntries, rpccontext.ConvertOutpointAndUTXOEntryPairsToUTXOsByAddressesEntries(addressString, pairs)...)
+
+		remaining -= len(pairs)
+		if remaining == 0 {
+			nextCursor = utxosByAddressesCursor(addressIndex, pairs[len(pairs)-1].Outpoint)
+			break
+		}
 	}
 
-	response := appmessage.NewGetUTXOsByAddressesResponseMessage(allEntries)
+	response := appmessage.NewGetUTXOsByAddressesResponseMessage(allEntries, nextCursor)
 	return response, nil
 }
+
+// utxosByAddressesCursor returns the cursor of the page that follows the given
+// outpoint of the address in the given index of the requested addresses
+func utxosByAddressesCursor(addressIndex int, outpoint *externalapi.DomainOutpoint) string {
+	return fmt.Sprintf("%d:%s:%d", addressIndex, outpoint.TransactionID, outpoint.Index)
+}
+
+func parseUTXOsByAddressesCursor(cursor string) (addressIndex int, outpoint *externalapi.DomainOutpoint, err error) {
+	if cursor == "" {
+		return 0, nil, nil
+	}
+
+	parts := strings.Split(cursor, ":")
+	if len(parts) != 3 {
+		return 0, nil, errors.Errorf("expected 3 parts separated by ':' but got %d", len(parts))
+	}
+	addressIndex, err = strconv.Atoi(parts[0])
+	if err != nil || addressIndex < 0 {
+		return 0, nil, errors.Errorf("invalid address index '%s'", parts[0])
+	}
+	transactionID, err := externalapi.NewDomainTransactionIDFromString(parts[1])
+	if err != nil {
+		return 0, nil, err
+	}
+	index, err := strconv.ParseUint(parts[2], 10, 32)
+	if err != nil {
+		return 0, nil, errors.Errorf("invalid output index '%s'", parts[2])
+	}
+	return addressIndex, externalapi.NewDomainOutpoint(transactionID, uint32(index)), nil
+}
@@ -0,0 +1,25 @@
+package rpchandlers
+
+import (
+	"github.com/Hoosat-Oy/HTND/app/appmessage"
+	"github.com/Hoosat-Oy/HTND/app/rpc/rpccontext"
+	"github.com/Hoosat-Oy/HTND/infrastructure/network/netadapter/router"
+)
+
+// HandleGetWhitelist handles the respectively named RPC command
+func HandleGetWhitelist(context *rpccontext.Context, _ *router.Router, _ appmessage.Message) (appmessage.Message, error) {
+	whitelist := context.AddressManager.Whitelist()
+
+	entries := make([]*appmessage.WhitelistEntry, len(whitelist))
+	for i, entry := range whitelist {
+		entries[i] = &appmessage.WhitelistEntry{
+			Subnet:       entry.Subnet.String(),
+			Reason:       entry.Reason,
+			IsFromConfig: entry.IsFromConfig,
+		}
+		if !entry.AddedAt.IsZero() {
+			entries[i].AddedAt = entry.AddedAt.UnixMilliseconds()
+		}
+	}
+	return appmessage.NewGetWhitelistResponseMessage(entries), nil
+}
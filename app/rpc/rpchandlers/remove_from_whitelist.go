@@ -0,0 +1,36 @@
+package rpchandlers
+
+import (
+	"github.com/Hoosat-Oy/HTND/app/appmessage"
+	"github.com/Hoosat-Oy/HTND/app/rpc/rpccontext"
+	"github.com/Hoosat-Oy/HTND/infrastructure/config"
+	"github.com/Hoosat-Oy/HTND/infrastructure/network/netadapter/router"
+)
+
+// HandleRemoveFromWhitelist handles the respectively named RPC command
+func HandleRemoveFromWhitelist(context *rpccontext.Context, _ *router.Router, request appmessage.Message) (appmessage.Message, error) {
+	if context.Config.SafeRPC {
+		log.Warn("RemoveFromWhitelist RPC command called while node in safe RPC mode -- ignoring.")
+		response := appmessage.NewRemoveFromWhitelistResponseMessage()
+		response.Error =
+			appmessage.RPCErrorf("RemoveFromWhitelist RPC command called while node in safe RPC mode")
+		return response, nil
+	}
+
+	removeFromWhitelistRequest := request.(*appmessage.RemoveFromWhitelistRequestMessage)
+	subnet, err := config.ParseWhitelist(removeFromWhitelistRequest.Subnet)
+	if err != nil {
+		errorMessage := appmessage.NewRemoveFromWhitelistResponseMessage()
+		errorMessage.Error = appmessage.RPCErrorf("Could not parse subnet: %s", err)
+		return errorMessage, nil
+	}
+
+	err = context.AddressManager.RemoveFromWhitelist(subnet)
+	if err != nil {
+		errorMessage := appmessage.NewRemoveFromWhitelistResponseMessage()
+		errorMessage.Error = appmessage.RPCErrorf("Could not remove subnet from the whitelist: %s", err)
+		return errorMessage, nil
+	}
+	log.Infof("Removed %s from the whitelist", subnet)
+	return appmessage.NewRemoveFromWhitelistResponseMessage(), nil
+}
@@ -0,0 +1,194 @@
+package snapshot
+
+import (
+	"github.com/Hoosat-Oy/HTND/app/appmessage"
+	"github.com/Hoosat-Oy/HTND/app/messagefile"
+	"github.com/Hoosat-Oy/HTND/app/protocol/flows/v5/blockrelay"
+	"github.com/Hoosat-Oy/HTND/domain/consensus/model/externalapi"
+	"github.com/Hoosat-Oy/HTND/domain/dagconfig"
+	"github.com/Hoosat-Oy/HTND/infrastructure/logger"
+	"github.com/pkg/errors"
+)
+
+// snapshotFormat identifies snapshot files
+var snapshotFormat = &messagefile.Format{
+	Name:    "snapshot",
+	Magic:   []byte("htnsnapshot"),
+	Version: 1,
+}
+
+// headersChunkSize is the amount of headers in a single record. It has to be
+// at least MergeSetSizeLimit + 1, like the batches of headers sent during IBD.
+const headersChunkSize = 1 << 10
+
+// utxoSetChunkSize is the amount of UTXOs in a single record
+const utxoSetChunkSize = 1000
+
+// Export writes a snapshot of the pruning point of the given consensus to the
+// given path. The snapshot holds the same data that a syncer sends during IBD
+// with a pruning point proof: the proof, the past pruning points, the pruning
+// point and its anticone with their trusted data, the headers above the
+// pruning point and the pruning point UTXO set.
+//
+// NOTE: No blocks may be added to the consensus while this is called.
+func Export(consensus externalapi.Consensus, params *dagconfig.Params, path string) error {
+	onEnd := logger.LogAndMeasureExecutionTime(log, "Export")
+	defer onEnd()
+
+	pruningPoint, err := consensus.PruningPoint()
+	if err != nil {
+		return err
+	}
+	if pruningPoint.Equal(params.GenesisHash) {
+		return errors.New("the pruning point is still the genesis, so there's nothing to snapshot")
+	}
+	log.Infof("Exporting a snapshot of the pruning point %s to %s", pruningPoint, path)
+
+	w, err := messagefile.Create(path, snapshotFormat, params.Name)
+	if err != nil {
+		return err
+	}
+	err = exportSnapshot(consensus, params, pruningPoint, w)
+	if err == nil {
+		err = w.WriteEnd()
+	}
+	if err == nil {
+		err = w.Finish()
+	}
+	if err != nil {
+		w.Abort()
+		return err
+	}
+
+	log.Infof("Exported the snapshot to %s", path)
+	return nil
+}
+
+func exportSnapshot(consensus externalapi.Consensus, params *dagconfig.Params,
+	pruningPoint *externalapi.DomainHash, w *messagefile.Writer) error {
+
+	log.Infof("Exporting the pruning point proof")
+	pruningPointProof, err := consensus.BuildPruningPointProof()
+	if err != nil {
+		return err
+	}
+	err = w.WriteMessage(appmessage.DomainPruningPointProofToMsgPruningPointProof(pruningPointProof))
+	if err != nil {
+		return err
+	}
+
+	log.Infof("Exporting the past pruning points and the pruning point anticone")
+	err = exportPruningPointsAndPruningPointAnticone(consensus, params, w)
+	if err != nil {
+		return err
+	}
+
+	log.Infof("Exporting the headers above the pruning point")
+	err = exportPruningPointFutureHeaders(consensus, pruningPoint, w)
+	if err != nil {
+		return err
+	}
+
+	log.Infof("Exporting the pruning point UTXO set")
+	return exportPruningPointUTXOSet(consensus, pruningPoint, w)
+}
+
+func exportPruningPointsAndPruningPointAnticone(consensus externalapi.Consensus, params *dagconfig.Params, w *messagefile.Writer) error {
+	pruningPointHeaders, err := consensus.PruningPointHeaders()
+	if err != nil {
+		return err
+	}
+	msgPruningPointHeaders := make([]*appmessage.MsgBlockHeader, len(pruningPointHeaders))
+	for i, header := range pruningPointHeaders {
+		msgPruningPointHeaders[i] = appmessage.DomainBlockHeaderToBlockHeader(header)
+	}
+	err = w.WriteMessage(appmessage.NewMsgPruningPoints(msgPruningPointHeaders))
+	if err != nil {
+		return err
+	}
+
+	trustedData, err := blockrelay.BuildPruningPointAndItsAnticoneTrustedData(consensus, params)
+	if err != nil {
+		return err
+	}
+	err = w.WriteMessage(trustedData.TrustedData)
+	if err != nil {
+		return err
+	}
+	for _, blockHash := range trustedData.PointAndItsAnticone {
+		block, found, err := consensus.GetBlock(blockHash)
+		if err != nil {
+			return err
+		}
+		if !found {
+			return errors.Errorf("pruning point anticone block %s not found", blockHash)
+		}
+		err = w.WriteMessage(trustedData.BlockWithTrustedData(block, blockHash))
+		if err != nil {
+			return err
+		}
+	}
+	return w.WriteMessage(appmessage.NewMsgDoneBlocksWithTrustedData())
+}
+
+func exportPruningPointFutureHeaders(consensus externalapi.Consensus, pruningPoint *externalapi.DomainHash, w *messagefile.Writer) error {
+	headersSelectedTip, err := consensus.GetHeadersSelectedTip()
+	if err != nil {
+		return err
+	}
+
+	headerCount := 0
+	lowHash := pruningPoint
+	for !lowHash.Equal(headersSelectedTip) {
+		blockHashes, _, err := consensus.GetHashesBetween(lowHash, headersSelectedTip, headersChunkSize)
+		if err != nil {
+			return err
+		}
+
+		blockHeaders := make([]*appmessage.MsgBlockHeader, len(blockHashes))
+		for i, blockHash := range blockHashes {
+			blockHeader, err := consensus.GetBlockHeader(blockHash)
+			if err != nil {
+				return err
+			}
+			blockHeaders[i] = appmessage.DomainBlockHeaderToBlockHeader(blockHeader)
+		}
+		err = w.WriteMessage(appmessage.NewBlockHeadersMessage(blockHeaders))
+		if err != nil {
+			return err
+		}
+
+		headerCount += len(blockHashes)
+		log.Debugf("Exported %d headers", headerCount)
+		lowHash = blockHashes[len(blockHashes)-1]
+	}
+	log.Infof("Exported %d headers", headerCount)
+
+	return w.WriteMessage(appmessage.NewMsgDoneHeaders())
+}
+
+func exportPruningPointUTXOSet(consensus externalapi.Consensus, pruningPoint *externalapi.DomainHash, w *messagefile.Writer) error {
+	utxoCount := 0
+	var fromOutpoint *externalapi.DomainOutpoint
+	for {
+		pruningPointUTXOs, err := consensus.GetPruningPointUTXOs(pruningPoint, fromOutpoint, utxoSetChunkSize)
+		if err != nil {
+			return err
+		}
+		if len(pruningPointUTXOs) > 0 {
+			err = w.WriteMessage(appmessage.NewMsgPruningPointUTXOSetChunk(
+				appmessage.DomainOutpointAndUTXOEntryPairsToOutpointAndUTXOEntryPairs(pruningPointUTXOs)))
+			if err != nil {
+				return err
+			}
+			utxoCount += len(pruningPointUTXOs)
+			fromOutpoint = pruningPointUTXOs[len(pruningPointUTXOs)-1].Outpoint
+		}
+		if len(pruningPointUTXOs) < utxoSetChunkSize {
+			break
+		}
+	}
+	log.Infof("Exported %d UTXOs", utxoCount)
+
+	return w.WriteMessage(appmessage.NewMsgDonePruningPointUTXOSetChunks())
+}
@@ -0,0 +1,366 @@
+package snapshot
+
+import (
+	"math/big"
+
+	"github.com/Hoosat-Oy/HTND/app/appmessage"
+	"github.com/Hoosat-Oy/HTND/app/messagefile"
+	"github.com/Hoosat-Oy/HTND/domain"
+	"github.com/Hoosat-Oy/HTND/domain/consensus/model/externalapi"
+	"github.com/Hoosat-Oy/HTND/domain/consensus/utils/consensushashing"
+	"github.com/Hoosat-Oy/HTND/domain/dagconfig"
+	"github.com/Hoosat-Oy/HTND/infrastructure/logger"
+	"github.com/pkg/errors"
+)
+
+// importLogInterval is the amount of records between progress logs
+const importLogInterval = 100
+
+// Import bootstraps the consensus of the given domain from the snapshot in the
+// given path. The snapshot is validated like the data received during IBD with
+// a pruning point proof: it's inserted into a staging consensus, which replaces
+// the current consensus only once the pruning point UTXO set matches its
+// commitment. The blocks above the pruning point are then synced from peers.
+//
+// Importing a snapshot whose pruning point is already in the DAG does nothing,
+// so a node that keeps --import-snapshot in its configuration restarts normally.
+//
+// NOTE: No blocks may be added to the consensus while this is called.
+func Import(domain domain.Domain, params *dagconfig.Params, path string) error {
+	onEnd := logger.LogAndMeasureExecutionTime(log, "Import")
+	defer onEnd()
+
+	r, err := messagefile.Open(path, snapshotFormat, params.Name)
+	if err != nil {
+		return errors.Wrapf(err, "failed opening the snapshot %s", path)
+	}
+	defer r.Close()
+
+	pruningPointProof, err := readPruningPointProof(r)
+	if err != nil {
+		return err
+	}
+	pruningPoint := consensushashing.HeaderHash(pruningPointProof.Headers[0][len(pruningPointProof.Headers[0])-1])
+	pruningPointInfo, err := domain.Consensus().GetBlockInfo(pruningPoint)
+	if err != nil {
+		return err
+	}
+	if pruningPointInfo.Exists {
+		log.Infof("The pruning point %s of the snapshot %s is already in the DAG. Skipping the import",
+			pruningPoint, path)
+		return nil
+	}
+
+	log.Infof("Verifying the checksum of the snapshot %s", path)
+	err = messagefile.VerifyChecksum(path, snapshotFormat)
+	if err != nil {
+		return err
+	}
+
+	log.Infof("Importing the snapshot of the pruning point %s", pruningPoint)
+	err = domain.InitStagingConsensusWithoutGenesis()
+	if err != nil {
+		return err
+	}
+	err = importSnapshot(domain, params, r, pruningPointProof, pruningPoint)
+	if err != nil {
+		log.Infof("Importing the snapshot was unsuccessful. Deleting the staging consensus. (%s)", err)
+		deleteStagingConsensusErr := domain.DeleteStagingConsensus()
+		if deleteStagingConsensusErr != nil {
+			return deleteStagingConsensusErr
+		}
+		return errors.Wrapf(err, "failed importing the snapshot %s", path)
+	}
+
+	err = domain.CommitStagingConsensus()
+	if err != nil {
+		return err
+	}
+	log.Infof("Imported the snapshot of the pruning point %s", pruningPoint)
+	return nil
+}
+
+func readPruningPointProof(r *messagefile.Reader) (*externalapi.PruningPointProof, error) {
+	message, err := r.ReadMessage()
+	if err != nil {
+		return nil, err
+	}
+	msgPruningPointProof, ok := message.(*appmessage.MsgPruningPointProof)
+	if !ok {
+		return nil, unexpectedMessageError(message, appmessage.CmdPruningPointProof)
+	}
+	if len(msgPruningPointProof.Headers) == 0 || len(msgPruningPointProof.Headers[0]) == 0 {
+		return nil, errors.New("the pruning point proof of the snapshot is empty")
+	}
+	return appmessage.MsgPruningPointProofToDomainPruningPointProof(msgPruningPointProof), nil
+}
+
+func importSnapshot(domain domain.Domain, params *dagconfig.Params, r *messagefile.Reader,
+	pruningPointProof *externalapi.PruningPointProof, pruningPoint *externalapi.DomainHash) error {
+
+	if pruningPoint.Equal(params.GenesisHash) {
+		return errors.New("the genesis pruning point violates finality")
+	}
+
+	log.Infof("Validating the pruning point proof")
+	err := domain.Consensus().ValidatePruningPointProof(pruningPointProof)
+	if err != nil {
+		return errors.Wrap(err, "pruning point proof validation failed")
+	}
+	err = domain.StagingConsensus().ApplyPruningPointProof(pruningPointProof)
+	if err != nil {
+		return err
+	}
+
+	log.Infof("Importing the past pruning points and the pruning point anticone")
+	err = importPruningPoints(domain, r, pruningPoint)
+	if err != nil {
+		return err
+	}
+	err = importPruningPointAndItsAnticone(domain.StagingConsensus(), r, pruningPoint)
+	if err != nil {
+		return err
+	}
+
+	log.Infof("Importing the headers above the pruning point")
+	err = importPruningPointFutureHeaders(domain.StagingConsensus(), r)
+	if err != nil {
+		return err
+	}
+	err = validateSnapshotIsAheadOfConsensus(domain)
+	if err != nil {
+		return err
+	}
+
+	isValid, err := domain.StagingConsensus().IsValidPruningPoint(pruningPoint)
+	if err != nil {
+		return err
+	}
+	if !isValid {
+		return errors.Errorf("invalid pruning point %s", pruningPoint)
+	}
+
+	log.Infof("Importing the pruning point UTXO set")
+	err = importPruningPointUTXOSet(domain.StagingConsensus(), r, pruningPoint)
+	if err != nil {
+		return err
+	}
+
+	message, err := r.ReadMessage()
+	if err != nil {
+		return err
+	}
+	if message != nil {
+		return errors.Errorf("unexpected %s message at the end of the snapshot", message.Command())
+	}
+	return nil
+}
+
+func importPruningPoints(domain domain.Domain, r *messagefile.Reader, pruningPoint *externalapi.DomainHash) error {
+	message, err := r.ReadMessage()
+	if err != nil {
+		return err
+	}
+	msgPruningPoints, ok := message.(*appmessage.MsgPruningPoints)
+	if !ok {
+		return unexpectedMessageError(message, appmessage.CmdPruningPoints)
+	}
+	if len(msgPruningPoints.Headers) == 0 {
+		return errors.New("the snapshot has no past pruning points")
+	}
+
+	headers := make([]externalapi.BlockHeader, len(msgPruningPoints.Headers))
+	for i, header := range msgPruningPoints.Headers {
+		headers[i] = appmessage.BlockHeaderToDomainBlockHeader(header)
+	}
+
+	arePruningPointsViolatingFinality, err := domain.Consensus().ArePruningPointsViolatingFinality(headers)
+	if err != nil {
+		return err
+	}
+	if arePruningPointsViolatingFinality {
+		return errors.New("the pruning points of the snapshot are violating finality")
+	}
+
+	lastPruningPoint := consensushashing.HeaderHash(headers[len(headers)-1])
+	if !lastPruningPoint.Equal(pruningPoint) {
+		return errors.New("the proof pruning point is not equal to the last pruning point in the list")
+	}
+
+	return domain.StagingConsensus().ImportPruningPoints(headers)
+}
+
+func importPruningPointAndItsAnticone(consensus externalapi.Consensus, r *messagefile.Reader, pruningPoint *externalapi.DomainHash) error {
+	message, err := r.ReadMessage()
+	if err != nil {
+		return err
+	}
+	msgTrustedData, ok := message.(*appmessage.MsgTrustedData)
+	if !ok {
+		return unexpectedMessageError(message, appmessage.CmdTrustedData)
+	}
+
+	blockCount := 0
+	for {
+		message, err := r.ReadMessage()
+		if err != nil {
+			return err
+		}
+		if _, ok := message.(*appmessage.MsgDoneBlocksWithTrustedData); ok {
+			break
+		}
+		msgBlockWithTrustedData, ok := message.(*appmessage.MsgBlockWithTrustedDataV4)
+		if !ok {
+			return unexpectedMessageError(message, appmessage.CmdBlockWithTrustedDataV4)
+		}
+
+		blockWithTrustedData, err := appmessage.BlockWithTrustedDataV4ToDomainBlockWithTrustedData(
+			msgBlockWithTrustedData, msgTrustedData)
+		if err != nil {
+			return err
+		}
+		if blockCount == 0 && !consensushashing.BlockHash(blockWithTrustedData.Block).Equal(pruningPoint) {
+			return errors.New("the first block with trusted data is not the pruning point")
+		}
+
+		err = consensus.ValidateAndInsertBlockWithTrustedData(blockWithTrustedData, false)
+		if err != nil {
+			return errors.Wrap(err, "failed validating block with trusted data")
+		}
+		blockCount++
+	}
+	if blockCount == 0 {
+		return errors.New("the snapshot doesn't include the pruning point")
+	}
+
+	log.Infof("Imported the pruning point and its anticone. Total blocks: %d", blockCount)
+	return nil
+}
+
+func importPruningPointFutureHeaders(consensus externalapi.Consensus, r *messagefile.Reader) error {
+	headerCount := 0
+	recordCount := 0
+	for {
+		message, err := r.ReadMessage()
+		if err != nil {
+			return err
+		}
+		if _, ok := message.(*appmessage.MsgDoneHeaders); ok {
+			break
+		}
+		blockHeadersMessage, ok := message.(*appmessage.BlockHeadersMessage)
+		if !ok {
+			return unexpectedMessageError(message, appmessage.CmdBlockHeaders)
+		}
+
+		for _, msgBlockHeader := range blockHeadersMessage.BlockHeaders {
+			block := &externalapi.DomainBlock{
+				Header:       appmessage.BlockHeaderToDomainBlockHeader(msgBlockHeader),
+				Transactions: nil,
+			}
+			blockHash := consensushashing.BlockHash(block)
+			blockInfo, err := consensus.GetBlockInfo(blockHash)
+			if err != nil {
+				return err
+			}
+			if blockInfo.Exists {
+				continue
+			}
+
+			err = consensus.ValidateAndInsertBlock(block, false, new(externalapi.DomainHash))
+			if err != nil {
+				return errors.Wrapf(err, "failed validating block header %s", blockHash)
+			}
+		}
+
+		headerCount += len(blockHeadersMessage.BlockHeaders)
+		recordCount++
+		if recordCount%importLogInterval == 0 {
+			log.Infof("Imported %d headers so far", headerCount)
+		}
+	}
+
+	log.Infof("Imported the headers above the pruning point. Total headers: %d", headerCount)
+	return nil
+}
+
+// validateSnapshotIsAheadOfConsensus makes sure that replacing the current
+// consensus with the snapshot doesn't move the node backwards
+func validateSnapshotIsAheadOfConsensus(domain domain.Domain) error {
+	snapshotBlueWork, err := headersSelectedTipBlueWork(domain.StagingConsensus())
+	if err != nil {
+		return err
+	}
+	currentBlueWork, err := headersSelectedTipBlueWork(domain.Consensus())
+	if err != nil {
+		return err
+	}
+	if snapshotBlueWork.Cmp(currentBlueWork) <= 0 {
+		return errors.New("the DAG of the node has more blue work than the snapshot")
+	}
+	return nil
+}
+
+func headersSelectedTipBlueWork(consensus externalapi.Consensus) (*big.Int, error) {
+	headersSelectedTip, err := consensus.GetHeadersSelectedTip()
+	if err != nil {
+		return nil, err
+	}
+	headersSelectedTipInfo, err := consensus.GetBlockInfo(headersSelectedTip)
+	if err != nil {
+		return nil, err
+	}
+	return headersSelectedTipInfo.BlueWork, nil
+}
+
+func importPruningPointUTXOSet(consensus externalapi.Consensus, r *messagefile.Reader, pruningPoint *externalapi.DomainHash) (err error) {
+	defer func() {
+		clearErr := consensus.ClearImportedPruningPointData()
+		if clearErr != nil && err == nil {
+			err = clearErr
+		}
+	}()
+
+	utxoCount := 0
+	chunkCount := 0
+	for {
+		message, err := r.ReadMessage()
+		if err != nil {
+			return err
+		}
+		if _, ok := message.(*appmessage.MsgDonePruningPointUTXOSetChunks); ok {
+			break
+		}
+		msgPruningPointUTXOSetChunk, ok := message.(*appmessage.MsgPruningPointUTXOSetChunk)
+		if !ok {
+			return unexpectedMessageError(message, appmessage.CmdPruningPointUTXOSetChunk)
+		}
+
+		err = consensus.AppendImportedPruningPointUTXOs(appmessage.OutpointAndUTXOEntryPairsToDomainOutpointAndUTXOEntryPairs(
+			msgPruningPointUTXOSetChunk.OutpointAndUTXOEntryPairs))
+		if err != nil {
+			return err
+		}
+
+		utxoCount += len(msgPruningPointUTXOSetChunk.OutpointAndUTXOEntryPairs)
+		chunkCount++
+		if chunkCount%importLogInterval == 0 {
+			log.Infof("Imported %d UTXO set chunks so far, totaling in %d UTXOs", chunkCount, utxoCount)
+		}
+	}
+	log.Infof("Imported the UTXO set. Total UTXOs: %d", utxoCount)
+
+	err = consensus.ValidateAndInsertImportedPruningPoint(pruningPoint)
+	if err != nil {
+		return errors.Wrap(err, "error with pruning point UTXO set")
+	}
+	return nil
+}
+
+func unexpectedMessageError(message appmessage.Message, expectedCommand appmessage.MessageCommand) error {
+	if message == nil {
+		return errors.Errorf("the snapshot ended while expecting a %s message", expectedCommand)
+	}
+	return errors.Errorf("unexpected %s message in the snapshot, expected a %s message",
+		message.Command(), expectedCommand)
+}
@@ -0,0 +1,125 @@
+package snapshot
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/Hoosat-Oy/HTND/domain"
+	"github.com/Hoosat-Oy/HTND/domain/consensus"
+	"github.com/Hoosat-Oy/HTND/domain/consensus/model/externalapi"
+	"github.com/Hoosat-Oy/HTND/domain/dagconfig"
+	"github.com/Hoosat-Oy/HTND/domain/miningmanager/mempool"
+	"github.com/Hoosat-Oy/HTND/infrastructure/db/database/ldb"
+)
+
+func TestExportAndImport(t *testing.T) {
+	// The dev fee validation only recognizes mainnet addresses, so the test DAG,
+	// whose blocks pay the dev fee, is built with the mainnet params
+	consensusConfig := &consensus.Config{Params: dagconfig.MainnetParams}
+	consensusConfig.SkipProofOfWork = true
+
+	// This is done to reduce the pruning depth to 6 blocks
+	finalityDepth := 5
+	consensusConfig.FinalityDuration = time.Duration(finalityDepth) * consensusConfig.TargetTimePerBlock
+	consensusConfig.K = 0
+	consensusConfig.PruningProofM = 1
+
+	factory := consensus.NewFactory()
+	syncer, teardownSyncer, err := factory.NewTestConsensus(consensusConfig, "TestExportAndImportSyncer")
+	if err != nil {
+		t.Fatalf("Error setting up consensus: %+v", err)
+	}
+	defer teardownSyncer(false)
+
+	tipHash := consensusConfig.GenesisHash
+	for i := 0; i < 20; i++ {
+		tipHash, _, err = syncer.AddBlock([]*externalapi.DomainHash{tipHash}, nil, nil)
+		if err != nil {
+			t.Fatalf("AddBlock: %+v", err)
+		}
+	}
+	pruningPoint, err := syncer.PruningPoint()
+	if err != nil {
+		t.Fatalf("PruningPoint: %+v", err)
+	}
+	if pruningPoint.Equal(consensusConfig.GenesisHash) {
+		t.Fatalf("the pruning point didn't move from genesis")
+	}
+
+	path := filepath.Join(t.TempDir(), "snapshot")
+	err = Export(syncer, &consensusConfig.Params, path)
+	if err != nil {
+		t.Fatalf("Export: %+v", err)
+	}
+
+	db, err := ldb.NewLevelDB(t.TempDir(), 8)
+	if err != nil {
+		t.Fatalf("NewLevelDB: %+v", err)
+	}
+	defer db.Close()
+	synceeDomain, err := domain.New(consensusConfig, mempool.DefaultConfig(&consensusConfig.Params), db)
+	if err != nil {
+		t.Fatalf("New: %+v", err)
+	}
+
+	err = Import(synceeDomain, &consensusConfig.Params, path)
+	if err != nil {
+		t.Fatalf("Import: %+v", err)
+	}
+	syncee := synceeDomain.Consensus()
+	synceePruningPoint, err := syncee.PruningPoint()
+	if err != nil {
+		t.Fatalf("PruningPoint: %+v", err)
+	}
+	if !synceePruningPoint.Equal(pruningPoint) {
+		t.Fatalf("expected the pruning point %s after the import, got %s", pruningPoint, synceePruningPoint)
+	}
+
+	// Sync the blocks above the pruning point, like a node that bootstraps
+	// from a snapshot does with its peers
+	headersSelectedTip, err := syncee.GetHeadersSelectedTip()
+	if err != nil {
+		t.Fatalf("GetHeadersSelectedTip: %+v", err)
+	}
+	if !headersSelectedTip.Equal(tipHash) {
+		t.Fatalf("expected the headers selected tip %s after the import, got %s", tipHash, headersSelectedTip)
+	}
+	missingBlockHashes, err := syncee.GetMissingBlockBodyHashes(headersSelectedTip)
+	if err != nil {
+		t.Fatalf("GetMissingBlockBodyHashes: %+v", err)
+	}
+	for _, blockHash := range missingBlockHashes {
+		block, _, err := syncer.GetBlock(blockHash)
+		if err != nil {
+			t.Fatalf("GetBlock: %+v", err)
+		}
+		err = syncee.ValidateAndInsertBlock(block, true, new(externalapi.DomainHash))
+		if err != nil {
+			t.Fatalf("ValidateAndInsertBlock: %+v", err)
+		}
+	}
+
+	coinbaseData := &externalapi.DomainCoinbaseData{
+		ScriptPublicKey: &externalapi.ScriptPublicKey{},
+		ExtraData:       []byte{},
+	}
+	syncerTemplate, err := syncer.BuildBlock(coinbaseData, nil)
+	if err != nil {
+		t.Fatalf("BuildBlock: %+v", err)
+	}
+	synceeTemplate, err := syncee.BuildBlock(coinbaseData, nil)
+	if err != nil {
+		t.Fatalf("BuildBlock: %+v", err)
+	}
+	if !synceeTemplate.Header.UTXOCommitment().Equal(syncerTemplate.Header.UTXOCommitment()) {
+		t.Fatalf("expected the virtual UTXO commitment %s after the import, got %s",
+			syncerTemplate.Header.UTXOCommitment(), synceeTemplate.Header.UTXOCommitment())
+	}
+
+	// Importing the same snapshot again does nothing
+	err = Import(synceeDomain, &consensusConfig.Params, path)
+	if err != nil {
+		t.Fatalf("Import: %+v", err)
+	}
+}
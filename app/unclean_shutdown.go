@@ -0,0 +1,51 @@
+package app
+
+import (
+	"github.com/Hoosat-Oy/HTND/infrastructure/db/database"
+)
+
+// uncleanShutdownKey is set in the database while htnd is running and removed
+// once all of its components have stopped. Finding it on startup means that the
+// previous run was killed or lost power while it was writing to the database.
+var uncleanShutdownKey = database.MakeBucket(nil).Key([]byte("unclean-shutdown"))
+
+// markDatabaseInUse sets the unclean shutdown key and returns whether it had
+// already been set, i.e. whether the previous run didn't shut down cleanly
+func markDatabaseInUse(db database.Database) (hadUncleanShutdown bool, err error) {
+	hadUncleanShutdown, err = db.Has(uncleanShutdownKey)
+	if err != nil {
+		return false, err
+	}
+
+	// The key is set in a transaction so that it's synced to disk when --dbsync is used
+	dbTx, err := db.Begin()
+	if err != nil {
+		return false, err
+	}
+	defer dbTx.RollbackUnlessClosed()
+
+	err = dbTx.Put(uncleanShutdownKey, []byte{0})
+	if err != nil {
+		return false, err
+	}
+	err = dbTx.Commit()
+	if err != nil {
+		return false, err
+	}
+	return hadUncleanShutdown, nil
+}
+
+// markDatabaseClosedCleanly removes the unclean shutdown key
+func markDatabaseClosedCleanly(db database.Database) error {
+	dbTx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+	defer dbTx.RollbackUnlessClosed()
+
+	err = dbTx.Delete(uncleanShutdownKey)
+	if err != nil {
+		return err
+	}
+	return dbTx.Commit()
+}
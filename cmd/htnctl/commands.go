@@ -41,6 +41,13 @@ var commandTypes = []reflect.Type{
 
 	reflect.TypeOf(protowire.HoosatdMessage_BanRequest{}),
 	reflect.TypeOf(protowire.HoosatdMessage_UnbanRequest{}),
+	reflect.TypeOf(protowire.HoosatdMessage_GetBansRequest{}),
+	reflect.TypeOf(protowire.HoosatdMessage_GetWhitelistRequest{}),
+	reflect.TypeOf(protowire.HoosatdMessage_AddToWhitelistRequest{}),
+	reflect.TypeOf(protowire.HoosatdMessage_RemoveFromWhitelistRequest{}),
+	reflect.TypeOf(protowire.HoosatdMessage_GetCacheStatsRequest{}),
+	reflect.TypeOf(protowire.HoosatdMessage_GetDatabaseStatsRequest{}),
+	reflect.TypeOf(protowire.HoosatdMessage_CompactDatabaseRequest{}),
 }
 
 type commandDescription struct {
@@ -0,0 +1,106 @@
+package main
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/Hoosat-Oy/HTND/domain/consensus/database"
+	"github.com/Hoosat-Oy/HTND/domain/consensus/model"
+	"github.com/Hoosat-Oy/HTND/domain/consensus/model/externalapi"
+	"github.com/pkg/errors"
+)
+
+func block(conf *blockConfig) error {
+	blockHash, err := externalapi.NewDomainHashFromString(conf.Hash)
+	if err != nil {
+		return errors.Wrapf(err, "invalid block hash '%s'", conf.Hash)
+	}
+
+	db, err := openReadOnlyDatabase(&conf.databaseFlags)
+	if err != nil {
+		return err
+	}
+	defer closeDatabase(db)
+
+	stores, err := newConsensusStores(db)
+	if err != nil {
+		return err
+	}
+	stagingArea := model.NewStagingArea()
+
+	hasHeader, err := stores.blockHeaderStore.HasBlockHeader(stores.dbContext, stagingArea, blockHash)
+	if err != nil {
+		return err
+	}
+	if !hasHeader {
+		return errors.Errorf("block %s is not in the database", blockHash)
+	}
+
+	header, err := stores.blockHeaderStore.BlockHeader(stores.dbContext, stagingArea, blockHash)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Block %s\n", blockHash)
+	fmt.Printf("Header:\n")
+	fmt.Printf("  Version:                 %d\n", header.Version())
+	fmt.Printf("  Timestamp:               %s\n", time.UnixMilli(header.TimeInMilliseconds()).UTC())
+	fmt.Printf("  Bits:                    %x\n", header.Bits())
+	fmt.Printf("  Nonce:                   %d\n", header.Nonce())
+	fmt.Printf("  DAA score:               %d\n", header.DAAScore())
+	fmt.Printf("  Blue score:              %d\n", header.BlueScore())
+	fmt.Printf("  Blue work:               %x\n", header.BlueWork())
+	fmt.Printf("  Hash merkle root:        %s\n", header.HashMerkleRoot())
+	fmt.Printf("  Accepted ID merkle root: %s\n", header.AcceptedIDMerkleRoot())
+	fmt.Printf("  UTXO commitment:         %s\n", header.UTXOCommitment())
+	fmt.Printf("  Pruning point:           %s\n", header.PruningPoint())
+	for level, levelParents := range header.Parents() {
+		fmt.Printf("  Parents at level %d:     %s\n", level, levelParents)
+	}
+
+	hasBody, err := stores.blockStore.HasBlock(stores.dbContext, stagingArea, blockHash)
+	if err != nil {
+		return err
+	}
+	if hasBody {
+		body, err := stores.blockStore.Block(stores.dbContext, stagingArea, blockHash)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Body: %d transactions\n", len(body.Transactions))
+	} else {
+		fmt.Printf("Body: not stored\n")
+	}
+
+	status, err := stores.blockStatusStore.Get(stores.dbContext, stagingArea, blockHash)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Status: %s\n", status)
+
+	ghostdagData, err := stores.ghostdagDataStore.Get(stores.dbContext, stagingArea, blockHash, false)
+	if database.IsNotFoundError(err) {
+		fmt.Printf("GHOSTDAG data: not stored\n")
+	} else if err != nil {
+		return err
+	} else {
+		fmt.Printf("GHOSTDAG data:\n")
+		fmt.Printf("  Blue score:      %d\n", ghostdagData.BlueScore())
+		fmt.Printf("  Blue work:       %x\n", ghostdagData.BlueWork())
+		fmt.Printf("  Selected parent: %s\n", ghostdagData.SelectedParent())
+		fmt.Printf("  Mergeset blues:  %s\n", ghostdagData.MergeSetBlues())
+		fmt.Printf("  Mergeset reds:   %s\n", ghostdagData.MergeSetReds())
+	}
+
+	blockRelations, err := stores.blockRelationStore.BlockRelation(stores.dbContext, stagingArea, blockHash)
+	if database.IsNotFoundError(err) {
+		fmt.Printf("Relations: not stored\n")
+	} else if err != nil {
+		return err
+	} else {
+		fmt.Printf("Relations:\n")
+		fmt.Printf("  Parents:  %s\n", blockRelations.Parents)
+		fmt.Printf("  Children: %s\n", blockRelations.Children)
+	}
+
+	return nil
+}
@@ -0,0 +1,108 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+
+	"github.com/Hoosat-Oy/HTND/infrastructure/config"
+	"github.com/jessevdk/go-flags"
+	"github.com/pkg/errors"
+)
+
+const (
+	statsSubCmd            = "stats"
+	blockSubCmd            = "block"
+	verifyUTXOSetSubCmd    = "verify-utxo-set"
+	rebuildUTXOIndexSubCmd = "rebuild-utxoindex"
+)
+
+// defaultDataDirname is the name of the database directory inside the network
+// directory of the htnd app directory. It has to match the one htnd uses.
+const defaultDataDirname = "datadir2"
+
+type databaseFlags struct {
+	AppDir string `short:"b" long:"appdir" description:"The app directory of the htnd node whose database is opened (default: the default htnd app directory)"`
+	config.NetworkFlags
+}
+
+// databasePath returns the path of the database directory of the selected network
+func (flags *databaseFlags) databasePath() string {
+	appDir := flags.AppDir
+	if appDir == "" {
+		appDir = config.DefaultAppDir
+	}
+	return filepath.Join(appDir, flags.NetParams().Name, defaultDataDirname)
+}
+
+type statsConfig struct {
+	databaseFlags
+}
+
+type blockConfig struct {
+	Hash string `long:"hash" description:"The hash of the block to print" required:"true"`
+	databaseFlags
+}
+
+type verifyUTXOSetConfig struct {
+	databaseFlags
+}
+
+type rebuildUTXOIndexConfig struct {
+	databaseFlags
+}
+
+func parseCommandLine() (subCommand string, config interface{}) {
+	parser := flags.NewParser(&struct{}{}, flags.PrintErrors|flags.HelpFlag)
+	parser.Usage = "htndbtool [COMMAND] [OPTIONS]\n\nThe node must not be running while htndbtool uses its database."
+
+	statsConf := &statsConfig{}
+	parser.AddCommand(statsSubCmd, "Prints the amount and size of entries in every database bucket",
+		"Prints the amount of entries and their total size in bytes for every bucket of every "+
+			"datastructure store in the database", statsConf)
+
+	blockConf := &blockConfig{}
+	parser.AddCommand(blockSubCmd, "Prints the stored data of a block",
+		"Prints the header, status, GHOSTDAG data and relations of a block as they're stored in the database",
+		blockConf)
+
+	verifyUTXOSetConf := &verifyUTXOSetConfig{}
+	parser.AddCommand(verifyUTXOSetSubCmd, "Verifies the UTXO sets against their commitments",
+		"Verifies the pruning point UTXO set against the UTXO commitment of the pruning point, and the "+
+			"virtual UTXO set against the virtual multiset", verifyUTXOSetConf)
+
+	rebuildUTXOIndexConf := &rebuildUTXOIndexConfig{}
+	parser.AddCommand(rebuildUTXOIndexSubCmd, "Rebuilds the UTXO index from the virtual UTXO set",
+		"Deletes the UTXO index and rebuilds it from the virtual UTXO set, without resyncing the node. "+
+			"This is the only command that writes into the database", rebuildUTXOIndexConf)
+
+	_, err := parser.Parse()
+	if err != nil {
+		var flagsErr *flags.Error
+		if ok := errors.As(err, &flagsErr); ok && flagsErr.Type == flags.ErrHelp {
+			os.Exit(0)
+		} else {
+			os.Exit(1)
+		}
+		return "", nil
+	}
+
+	switch parser.Command.Active.Name {
+	case statsSubCmd:
+		err = statsConf.ResolveNetwork(parser)
+		config = statsConf
+	case blockSubCmd:
+		err = blockConf.ResolveNetwork(parser)
+		config = blockConf
+	case verifyUTXOSetSubCmd:
+		err = verifyUTXOSetConf.ResolveNetwork(parser)
+		config = verifyUTXOSetConf
+	case rebuildUTXOIndexSubCmd:
+		err = rebuildUTXOIndexConf.ResolveNetwork(parser)
+		config = rebuildUTXOIndexConf
+	}
+	if err != nil {
+		printErrorAndExit(err)
+	}
+
+	return parser.Command.Active.Name, config
+}
@@ -0,0 +1,103 @@
+package main
+
+import (
+	"fmt"
+
+	consensusdatabase "github.com/Hoosat-Oy/HTND/domain/consensus/database"
+	"github.com/Hoosat-Oy/HTND/domain/consensus/datastructures/blockheaderstore"
+	"github.com/Hoosat-Oy/HTND/domain/consensus/datastructures/blockrelationstore"
+	"github.com/Hoosat-Oy/HTND/domain/consensus/datastructures/blockstatusstore"
+	"github.com/Hoosat-Oy/HTND/domain/consensus/datastructures/blockstore"
+	"github.com/Hoosat-Oy/HTND/domain/consensus/datastructures/consensusstatestore"
+	"github.com/Hoosat-Oy/HTND/domain/consensus/datastructures/ghostdagdatastore"
+	"github.com/Hoosat-Oy/HTND/domain/consensus/datastructures/multisetstore"
+	"github.com/Hoosat-Oy/HTND/domain/consensus/datastructures/pruningstore"
+	"github.com/Hoosat-Oy/HTND/domain/consensus/model"
+	"github.com/Hoosat-Oy/HTND/domain/prefixmanager"
+	"github.com/Hoosat-Oy/HTND/domain/prefixmanager/prefix"
+	"github.com/Hoosat-Oy/HTND/infrastructure/db/database"
+	"github.com/Hoosat-Oy/HTND/infrastructure/db/database/backend"
+	"github.com/pkg/errors"
+)
+
+// databaseCacheSizeMiB is the leveldb cache size. htndbtool reads most of
+// the data once, so it has little use for a large cache.
+const databaseCacheSizeMiB = 64
+
+// storeCacheSize is the cache size of the datastructure stores
+const storeCacheSize = 10
+
+func openReadOnlyDatabase(flags *databaseFlags) (database.Database, error) {
+	databasePath := flags.databasePath()
+	db, err := backend.OpenReadOnly(databasePath, databaseCacheSizeMiB)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed opening the database in %s -- make sure that htnd "+
+			"isn't running", databasePath)
+	}
+	return db, nil
+}
+
+func activePrefix(db database.Database) (*prefix.Prefix, error) {
+	activePrefix, exists, err := prefixmanager.ActivePrefix(db)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, errors.New("the database doesn't have an active consensus")
+	}
+	return activePrefix, nil
+}
+
+// consensusStores are the datastructure stores of the active consensus
+// that htndbtool reads
+type consensusStores struct {
+	dbContext           model.DBManager
+	blockStore          model.BlockStore
+	blockHeaderStore    model.BlockHeaderStore
+	blockStatusStore    model.BlockStatusStore
+	blockRelationStore  model.BlockRelationStore
+	ghostdagDataStore   model.GHOSTDAGDataStore
+	multisetStore       model.MultisetStore
+	pruningStore        model.PruningStore
+	consensusStateStore model.ConsensusStateStore
+}
+
+func newConsensusStores(db database.Database) (*consensusStores, error) {
+	activePrefix, err := activePrefix(db)
+	if err != nil {
+		return nil, err
+	}
+
+	dbContext := consensusdatabase.New(db)
+	prefixBucket := consensusdatabase.MakeBucket(activePrefix.Serialize())
+	// The block relations and GHOSTDAG data of level 0 are the ones the consensus uses
+	levelZeroBucket := prefixBucket.Bucket([]byte{0})
+
+	blockStore, err := blockstore.New(dbContext, prefixBucket, storeCacheSize, false)
+	if err != nil {
+		return nil, err
+	}
+	blockHeaderStore, err := blockheaderstore.New(dbContext, prefixBucket, storeCacheSize, false)
+	if err != nil {
+		return nil, err
+	}
+
+	return &consensusStores{
+		dbContext:           dbContext,
+		blockStore:          blockStore,
+		blockHeaderStore:    blockHeaderStore,
+		blockStatusStore:    blockstatusstore.New(prefixBucket, storeCacheSize, false),
+		blockRelationStore:  blockrelationstore.New(levelZeroBucket, storeCacheSize, false),
+		ghostdagDataStore:   ghostdagdatastore.New(levelZeroBucket, storeCacheSize, false),
+		multisetStore:       multisetstore.New(prefixBucket, storeCacheSize, false),
+		pruningStore:        pruningstore.New(prefixBucket, storeCacheSize, false),
+		consensusStateStore: consensusstatestore.New(prefixBucket, storeCacheSize, false),
+	}, nil
+}
+
+func closeDatabase(db database.Database) {
+	err := db.Close()
+	if err != nil {
+		fmt.Printf("Failed closing the database: %s\n", err)
+	}
+}
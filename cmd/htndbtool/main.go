@@ -0,0 +1,34 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/pkg/errors"
+)
+
+func main() {
+	subCmd, config := parseCommandLine()
+	var err error
+	switch subCmd {
+	case statsSubCmd:
+		err = stats(config.(*statsConfig))
+	case blockSubCmd:
+		err = block(config.(*blockConfig))
+	case verifyUTXOSetSubCmd:
+		err = verifyUTXOSet(config.(*verifyUTXOSetConfig))
+	case rebuildUTXOIndexSubCmd:
+		err = rebuildUTXOIndex(config.(*rebuildUTXOIndexConfig))
+	default:
+		err = errors.Errorf("Unknown sub-command '%s'\n", subCmd)
+	}
+
+	if err != nil {
+		printErrorAndExit(err)
+	}
+}
+
+func printErrorAndExit(err error) {
+	fmt.Fprintf(os.Stderr, "%s\n", err)
+	os.Exit(1)
+}
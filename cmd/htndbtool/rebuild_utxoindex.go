@@ -0,0 +1,59 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/Hoosat-Oy/HTND/domain"
+	"github.com/Hoosat-Oy/HTND/domain/consensus"
+	"github.com/Hoosat-Oy/HTND/domain/miningmanager/mempool"
+	"github.com/Hoosat-Oy/HTND/domain/utxoindex"
+	"github.com/Hoosat-Oy/HTND/infrastructure/db/database/backend"
+	"github.com/Hoosat-Oy/HTND/infrastructure/logger"
+	"github.com/pkg/errors"
+)
+
+func rebuildUTXOIndex(conf *rebuildUTXOIndexConfig) error {
+	// The UTXO index reports its progress in its log
+	logger.InitLogStdout(logger.LevelInfo)
+	logger.SetLogLevels(logger.LevelInfo)
+	defer logger.BackendLog.Close()
+
+	databasePath := conf.databasePath()
+	// The database type is detected first, so that a missing database isn't
+	// created by the open below
+	dbType, err := backend.Detect(databasePath)
+	if err != nil {
+		return err
+	}
+	if dbType == "" {
+		return errors.Errorf("there is no database in %s", databasePath)
+	}
+	db, err := backend.Open(dbType, databasePath, databaseCacheSizeMiB, false)
+	if err != nil {
+		return errors.Wrapf(err, "failed opening the database in %s -- make sure that htnd "+
+			"isn't running", databasePath)
+	}
+	defer closeDatabase(db)
+
+	consensusConfig := &consensus.Config{Params: *conf.NetParams()}
+	domain, err := domain.New(consensusConfig, mempool.DefaultConfig(&consensusConfig.Params), db)
+	if err != nil {
+		return err
+	}
+
+	// utxoindex.New only rebuilds an index that isn't synced with the
+	// virtual, and does it in the background, so the reset is done
+	// explicitly
+	utxoIndex, err := utxoindex.New(domain, db)
+	if err != nil {
+		return err
+	}
+	defer utxoIndex.Close()
+	err = utxoIndex.Reset()
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("The UTXO index was rebuilt\n")
+	return nil
+}
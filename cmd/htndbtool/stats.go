@@ -0,0 +1,32 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/Hoosat-Oy/HTND/app/dbmaintenance"
+)
+
+func stats(conf *statsConfig) error {
+	db, err := openReadOnlyDatabase(&conf.databaseFlags)
+	if err != nil {
+		return err
+	}
+	defer closeDatabase(db)
+
+	allStats, err := dbmaintenance.CollectStats(db)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("%-18s %-26s %-48s %12s %16s\n", "OWNER", "STORE", "BUCKET", "ENTRIES", "BYTES")
+	var totalEntries, totalSize uint64
+	for _, bucketStats := range allStats {
+		fmt.Printf("%-18s %-26s %-48s %12d %16d\n",
+			bucketStats.Owner, bucketStats.Store, bucketStats.Bucket, bucketStats.KeyCount, bucketStats.Size)
+		totalEntries += bucketStats.KeyCount
+		totalSize += bucketStats.Size
+	}
+	fmt.Printf("%-18s %-26s %-48s %12d %16d\n", "total", "", "", totalEntries, totalSize)
+
+	return nil
+}
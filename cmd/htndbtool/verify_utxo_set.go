@@ -0,0 +1,92 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/Hoosat-Oy/HTND/domain/consensus/model"
+	"github.com/Hoosat-Oy/HTND/domain/consensus/model/externalapi"
+	"github.com/Hoosat-Oy/HTND/domain/consensus/utils/multiset"
+	"github.com/Hoosat-Oy/HTND/domain/consensus/utils/utxo"
+	"github.com/pkg/errors"
+)
+
+func verifyUTXOSet(conf *verifyUTXOSetConfig) error {
+	db, err := openReadOnlyDatabase(&conf.databaseFlags)
+	if err != nil {
+		return err
+	}
+	defer closeDatabase(db)
+
+	stores, err := newConsensusStores(db)
+	if err != nil {
+		return err
+	}
+	stagingArea := model.NewStagingArea()
+
+	pruningPoint, err := stores.pruningStore.PruningPoint(stores.dbContext, stagingArea)
+	if err != nil {
+		return err
+	}
+	pruningPointHeader, err := stores.blockHeaderStore.BlockHeader(stores.dbContext, stagingArea, pruningPoint)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Verifying the UTXO set of the pruning point %s\n", pruningPoint)
+	pruningPointUTXOSetIterator, err := stores.pruningStore.PruningPointUTXOIterator(stores.dbContext)
+	if err != nil {
+		return err
+	}
+	pruningPointUTXOCount, pruningPointUTXOSetMultiset, err := utxoSetMultiset(pruningPointUTXOSetIterator)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("The pruning point UTXO set has %d UTXOs\n", pruningPointUTXOCount)
+	if !pruningPointUTXOSetMultiset.Hash().Equal(pruningPointHeader.UTXOCommitment()) {
+		return errors.Errorf("the pruning point UTXO set has multiset %s, but the UTXO commitment of "+
+			"the pruning point is %s", pruningPointUTXOSetMultiset.Hash(), pruningPointHeader.UTXOCommitment())
+	}
+
+	fmt.Printf("Verifying the virtual UTXO set\n")
+	virtualMultiset, err := stores.multisetStore.Get(stores.dbContext, stagingArea, model.VirtualBlockHash)
+	if err != nil {
+		return err
+	}
+	virtualUTXOSetIterator, err := stores.consensusStateStore.VirtualUTXOSetIterator(stores.dbContext, stagingArea)
+	if err != nil {
+		return err
+	}
+	virtualUTXOCount, virtualUTXOSetMultiset, err := utxoSetMultiset(virtualUTXOSetIterator)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("The virtual UTXO set has %d UTXOs\n", virtualUTXOCount)
+	if !virtualUTXOSetMultiset.Hash().Equal(virtualMultiset.Hash()) {
+		return errors.Errorf("the virtual UTXO set has multiset %s, but the stored virtual multiset is %s",
+			virtualUTXOSetMultiset.Hash(), virtualMultiset.Hash())
+	}
+
+	fmt.Printf("The UTXO sets match their commitments\n")
+	return nil
+}
+
+// utxoSetMultiset returns the amount of UTXOs the given iterator goes over
+// and their multiset. It closes the iterator.
+func utxoSetMultiset(utxoSetIterator externalapi.ReadOnlyUTXOSetIterator) (uint64, model.Multiset, error) {
+	defer utxoSetIterator.Close()
+
+	utxoCount := uint64(0)
+	utxoSetMultiset := multiset.New()
+	for ok := utxoSetIterator.First(); ok; ok = utxoSetIterator.Next() {
+		outpoint, entry, err := utxoSetIterator.Get()
+		if err != nil {
+			return 0, nil, err
+		}
+		serializedUTXO, err := utxo.SerializeUTXO(entry, outpoint)
+		if err != nil {
+			return 0, nil, err
+		}
+		utxoSetMultiset.Add(serializedUTXO)
+		utxoCount++
+	}
+	return utxoCount, utxoSetMultiset, nil
+}
@@ -0,0 +1,49 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/Hoosat-Oy/HTND/cmd/htnwallet/keys"
+	"github.com/pkg/errors"
+)
+
+func backup(conf *backupConfig) error {
+	if !conf.Yes {
+		_, err := os.Stat(conf.BackupFile)
+		if err == nil {
+			return errors.Errorf("The file %s already exists. Use --yes to override it", conf.BackupFile)
+		}
+		if !os.IsNotExist(err) {
+			return err
+		}
+	}
+
+	keysFile, err := keys.ReadKeysFile(conf.NetParams(), conf.KeysFile)
+	if err != nil {
+		return err
+	}
+
+	if len(conf.Password) == 0 {
+		conf.Password = keys.GetPassword("Password:")
+	}
+	walletBackup, err := keysFile.Backup(conf.NetParams(), conf.Password)
+	if err != nil {
+		return err
+	}
+
+	if len(conf.BackupPassword) == 0 {
+		conf.BackupPassword, err = keys.GetNewPassword("Enter password for the backup file:")
+		if err != nil {
+			return err
+		}
+	}
+
+	err = keys.WriteBackupFile(conf.BackupFile, walletBackup, conf.BackupPassword, keys.DefaultKDFParams())
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Wrote an encrypted backup of the wallet into %s\n", conf.BackupFile)
+	return nil
+}
@@ -0,0 +1,53 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/Hoosat-Oy/HTND/cmd/htnwallet/keys"
+)
+
+func changePassword(conf *changePasswordConfig) error {
+	keysFile, err := keys.ReadKeysFile(conf.NetParams(), conf.KeysFile)
+	if err != nil {
+		return err
+	}
+
+	// The lock makes sure no wallet daemon is running with this keys file, since it
+	// would overwrite the re-encrypted mnemonics the next time it saves the file
+	err = keysFile.TryLock()
+	if err != nil {
+		return err
+	}
+
+	if len(conf.Password) == 0 {
+		conf.Password = keys.GetPassword("Current password:")
+	}
+	if len(conf.NewPassword) == 0 {
+		conf.NewPassword, err = keys.GetNewPassword("Enter new password for the key file:")
+		if err != nil {
+			return err
+		}
+	}
+
+	var kdfParams *keys.KDFParams
+	if conf.KDFTime != 0 || conf.KDFMemory != 0 || conf.KDFThreads != 0 {
+		kdfParams = keys.DefaultKDFParams()
+		if conf.KDFTime != 0 {
+			kdfParams.Time = conf.KDFTime
+		}
+		if conf.KDFMemory != 0 {
+			kdfParams.Memory = conf.KDFMemory
+		}
+		if conf.KDFThreads != 0 {
+			kdfParams.Threads = conf.KDFThreads
+		}
+	}
+
+	err = keysFile.ChangePassword(conf.Password, conf.NewPassword, kdfParams)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Changed the password of %s\n", keysFile.Path())
+	return nil
+}
@@ -4,6 +4,8 @@ import (
 	"fmt"
 	"os"
 	"time"
+
+	"github.com/Hoosat-Oy/HTND/cmd/htnwallet/keys"
 )
 
 const daemonTimeout = 2 * time.Minute
@@ -12,3 +14,12 @@ func printErrorAndExit(err error) {
 	fmt.Fprintf(os.Stderr, "%s\n", err)
 	os.Exit(1)
 }
+
+// walletAddressPath returns the derivation path of the address with the given cosigner index,
+// key chain and index. It's the same path the wallet daemon uses for the address.
+func walletAddressPath(keysFile *keys.File, cosignerIndex uint32, keychain uint32, index uint32) string {
+	if len(keysFile.ExtendedPublicKeys) > 1 {
+		return fmt.Sprintf("m/%d/%d/%d", cosignerIndex, keychain, index)
+	}
+	return fmt.Sprintf("m/%d/%d", keychain, index)
+}
@@ -13,6 +13,7 @@ const (
 	createSubCmd                    = "create"
 	balanceSubCmd                   = "balance"
 	sendSubCmd                      = "send"
+	sendManySubCmd                  = "send-many"
 	sweepSubCmd                     = "sweep"
 	createUnsignedTransactionSubCmd = "create-unsigned-transaction"
 	signSubCmd                      = "sign"
@@ -24,6 +25,16 @@ const (
 	startDaemonSubCmd               = "start-daemon"
 	versionSubCmd                   = "version"
 	getDaemonVersionSubCmd          = "get-daemon-version"
+	signMessageSubCmd               = "sign-message"
+	verifyMessageSubCmd             = "verify-message"
+	changePasswordSubCmd            = "change-password"
+	backupSubCmd                    = "backup"
+	restoreSubCmd                   = "restore"
+	labelAddressSubCmd              = "label-address"
+	createVaultSubCmd               = "create-vault"
+	scriptHashAddressSubCmd         = "script-hash-address"
+	spendScriptHashSubCmd           = "spend-script-hash"
+	externalSignerSubCmd            = "external-signer"
 )
 
 const (
@@ -64,6 +75,19 @@ type sendConfig struct {
 	IsSendAll                bool     `long:"send-all" description:"Send all the Hoosat in the wallet (mutually exclusive with --send-amount). If --from-address was used, will send all only from the specified addresses."`
 	UseExistingChangeAddress bool     `long:"use-existing-change-address" short:"u" description:"Will use an existing change address (in case no change address was ever used, it will use a new one)"`
 	Verbose                  bool     `long:"show-serialized" short:"s" description:"Show a list of hex encoded sent transactions"`
+	externalSignerFlags
+	config.NetworkFlags
+}
+
+type sendManyConfig struct {
+	KeysFile                 string   `long:"keys-file" short:"f" description:"Keys file location (default: ~/.htnwallet/keys.json (*nix), %USERPROFILE%\\AppData\\Local\\Hoosatwallet\\key.json (Windows))"`
+	Password                 string   `long:"password" short:"p" description:"Wallet password"`
+	DaemonAddress            string   `long:"daemonaddress" short:"d" description:"Wallet daemon server to connect to"`
+	RecipientsFile           string   `long:"recipients-file" short:"r" description:"A CSV file of 'address,amount' lines, or a JSON array of {address, amount} objects, listing the recipients and the amounts in Hoosat (e.g. 1234.12345678) to send them" required:"true"`
+	FromAddresses            []string `long:"from-address" short:"a" description:"Specific public address to send Hoosat from. Repeat multiple times (adding -a before each) to accept several addresses" required:"false"`
+	UseExistingChangeAddress bool     `long:"use-existing-change-address" short:"u" description:"Will use an existing change address (in case no change address was ever used, it will use a new one)"`
+	Verbose                  bool     `long:"show-serialized" short:"s" description:"Show a list of hex encoded sent transactions"`
+	externalSignerFlags
 	config.NetworkFlags
 }
 
@@ -88,6 +112,7 @@ type signConfig struct {
 	Password        string `long:"password" short:"p" description:"Wallet password"`
 	Transaction     string `long:"transaction" short:"t" description:"The unsigned transaction(s) to sign on (encoded in hex)"`
 	TransactionFile string `long:"transaction-file" short:"F" description:"The file containing the unsigned transaction(s) to sign on (encoded in hex)"`
+	externalSignerFlags
 	config.NetworkFlags
 }
 
@@ -132,6 +157,95 @@ type dumpUnencryptedDataConfig struct {
 	config.NetworkFlags
 }
 
+type signMessageConfig struct {
+	KeysFile    string `long:"keys-file" short:"f" description:"Keys file location (default: ~/.htnwallet/keys.json (*nix), %USERPROFILE%\\AppData\\Local\\Hoosatwallet\\key.json (Windows))"`
+	Password    string `long:"password" short:"p" description:"Wallet password"`
+	Address     string `long:"address" short:"a" description:"The wallet address whose key signs the message" required:"true"`
+	Message     string `long:"message" short:"m" description:"The message to sign"`
+	MessageFile string `long:"message-file" short:"F" description:"The file containing the message to sign"`
+	config.NetworkFlags
+}
+
+type verifyMessageConfig struct {
+	Address     string `long:"address" short:"a" description:"The address that supposedly signed the message" required:"true"`
+	Signature   string `long:"signature" short:"s" description:"The signature to verify (encoded in hex)" required:"true"`
+	Message     string `long:"message" short:"m" description:"The signed message"`
+	MessageFile string `long:"message-file" short:"F" description:"The file containing the signed message"`
+	config.NetworkFlags
+}
+
+type changePasswordConfig struct {
+	KeysFile    string `long:"keys-file" short:"f" description:"Keys file location (default: ~/.htnwallet/keys.json (*nix), %USERPROFILE%\\AppData\\Local\\Hoosatwallet\\key.json (Windows))"`
+	Password    string `long:"password" short:"p" description:"Current wallet password"`
+	NewPassword string `long:"new-password" short:"n" description:"New wallet password"`
+	KDFTime     uint32 `long:"kdf-time" description:"Argon2id number of passes over the memory, at most 64 (default: 1)"`
+	KDFMemory   uint32 `long:"kdf-memory" description:"Argon2id memory size in KiB, at most 4194304 (default: 65536)"`
+	KDFThreads  uint8  `long:"kdf-threads" description:"Argon2id number of threads (default: 8)"`
+	config.NetworkFlags
+}
+
+type backupConfig struct {
+	KeysFile       string `long:"keys-file" short:"f" description:"Keys file location (default: ~/.htnwallet/keys.json (*nix), %USERPROFILE%\\AppData\\Local\\Hoosatwallet\\key.json (Windows))"`
+	Password       string `long:"password" short:"p" description:"Wallet password"`
+	BackupFile     string `long:"backup-file" short:"b" description:"The file to write the encrypted backup into" required:"true"`
+	BackupPassword string `long:"backup-password" short:"P" description:"The password to encrypt the backup with"`
+	Yes            bool   `long:"yes" short:"y" description:"Assume \"yes\" to all questions"`
+	config.NetworkFlags
+}
+
+type restoreConfig struct {
+	KeysFile       string `long:"keys-file" short:"f" description:"Keys file location (default: ~/.htnwallet/keys.json (*nix), %USERPROFILE%\\AppData\\Local\\Hoosatwallet\\key.json (Windows))"`
+	Password       string `long:"password" short:"p" description:"Password for the restored wallet"`
+	BackupFile     string `long:"backup-file" short:"b" description:"The encrypted backup file to restore the wallet from. If not given, the wallet is restored from a mnemonic"`
+	BackupPassword string `long:"backup-password" short:"P" description:"The password the backup was encrypted with"`
+	ECDSA          bool   `long:"ecdsa" description:"Restore an ECDSA wallet (only used when restoring from a mnemonic)"`
+	Yes            bool   `long:"yes" short:"y" description:"Assume \"yes\" to all questions"`
+	RPCServer      string `long:"rpcserver" short:"s" description:"RPC server to rescan the used addresses of the wallet with. The node must run with --utxoindex"`
+	GapLimit       uint32 `long:"gap-limit" description:"Number of consecutive unused addresses after which the rescan stops" default:"20"`
+	config.NetworkFlags
+}
+
+type labelAddressConfig struct {
+	KeysFile string `long:"keys-file" short:"f" description:"Keys file location (default: ~/.htnwallet/keys.json (*nix), %USERPROFILE%\\AppData\\Local\\Hoosatwallet\\key.json (Windows))"`
+	Address  string `long:"address" short:"a" description:"The address to label" required:"true"`
+	Label    string `long:"label" short:"l" description:"The label of the address. An empty label removes the current one"`
+	config.NetworkFlags
+}
+
+type createVaultConfig struct {
+	DaemonAddress     string `long:"daemonaddress" short:"d" description:"Wallet daemon server to connect to"`
+	TimeLockedAddress string `long:"time-locked-address" short:"t" description:"The public key address that can spend the vault after the lock DAA score (default: a new wallet address)"`
+	RecoveryAddress   string `long:"recovery-address" short:"r" description:"The public key address that can spend the vault at any time (default: a new wallet address)"`
+	LockDAAScore      uint64 `long:"lock-daa-score" short:"l" description:"The DAA score after which the time-locked address can spend the vault" required:"true"`
+	config.NetworkFlags
+}
+
+type scriptHashAddressConfig struct {
+	DaemonAddress string `long:"daemonaddress" short:"d" description:"Wallet daemon server to connect to"`
+	RedeemScript  string `long:"redeem-script" short:"s" description:"The redeem script (encoded in hex)" required:"true"`
+	config.NetworkFlags
+}
+
+type spendScriptHashConfig struct {
+	KeysFile       string `long:"keys-file" short:"f" description:"Keys file location (default: ~/.htnwallet/keys.json (*nix), %USERPROFILE%\\AppData\\Local\\Hoosatwallet\\key.json (Windows))"`
+	Password       string `long:"password" short:"p" description:"Wallet password"`
+	DaemonAddress  string `long:"daemonaddress" short:"d" description:"Wallet daemon server to connect to"`
+	RedeemScript   string `long:"redeem-script" short:"s" description:"The redeem script locking the funds (encoded in hex)" required:"true"`
+	SigningAddress string `long:"signing-address" short:"a" description:"The wallet address whose key signs the transaction" required:"true"`
+	ToAddress      string `long:"to-address" short:"t" description:"The public address to send the funds to (default: a new change address)"`
+	LockTime       uint64 `long:"lock-time" description:"The transaction lock time. Ignored for vault redeem scripts, whose lock time is derived from the signing key"`
+	Verbose        bool   `long:"show-serialized" description:"Show the hex encoded sent transaction"`
+	externalSignerFlags
+	config.NetworkFlags
+}
+
+type externalSignerConfig struct {
+	KeysFile string `long:"keys-file" short:"f" description:"Keys file location (default: ~/.htnwallet/keys.json (*nix), %USERPROFILE%\\AppData\\Local\\Hoosatwallet\\key.json (Windows))"`
+	Password string `long:"password" short:"p" description:"Wallet password"`
+	Socket   string `long:"socket" short:"s" description:"Listen for sign requests on this Unix socket, instead of serving the requests written to stdin"`
+	config.NetworkFlags
+}
+
 type versionConfig struct {
 }
 
@@ -156,6 +270,11 @@ func parseCommandLine() (subCommand string, config interface{}) {
 	parser.AddCommand(sendSubCmd, "Sends a Hoosat transaction to a public address",
 		"Sends a Hoosat transaction to a public address", sendConf)
 
+	sendManyConf := &sendManyConfig{DaemonAddress: defaultListen}
+	parser.AddCommand(sendManySubCmd, "Sends Hoosat to many public addresses at once",
+		"Sends Hoosat to all the recipients listed in a CSV or JSON file, packing the payments into as few "+
+			"transactions as the transaction mass limits allow", sendManyConf)
+
 	sweepConf := &sweepConfig{DaemonAddress: defaultListen}
 	parser.AddCommand(sweepSubCmd, "Sends all funds associated with the given schnorr private key to a new address of the current wallet",
 		"Sends all funds associated with the given schnorr private key to a newly created external (i.e. not a change) address of the "+
@@ -191,6 +310,53 @@ func parseCommandLine() (subCommand string, config interface{}) {
 		"Prints the unencrypted wallet data including its private keys. Anyone that sees it can access "+
 			"the funds. Use only on safe environment.", dumpUnencryptedDataConf)
 
+	signMessageConf := &signMessageConfig{}
+	parser.AddCommand(signMessageSubCmd, "Sign a message with the key of one of the wallet addresses",
+		"Sign a message with the key of one of the wallet addresses, to prove its ownership. "+
+			"The signature can be verified with the `verify-message` command", signMessageConf)
+
+	verifyMessageConf := &verifyMessageConfig{}
+	parser.AddCommand(verifyMessageSubCmd, "Verify a message signature created by `sign-message`",
+		"Verify that a message was signed with the key of the given address", verifyMessageConf)
+
+	changePasswordConf := &changePasswordConfig{}
+	parser.AddCommand(changePasswordSubCmd, "Change the password of the wallet",
+		"Re-encrypt the wallet mnemonics with a new password, optionally with new key derivation parameters", changePasswordConf)
+
+	backupConf := &backupConfig{}
+	parser.AddCommand(backupSubCmd, "Write an encrypted backup of the wallet",
+		"Write a password encrypted backup of the wallet, including its mnemonics, address indexes and labels, "+
+			"which can be restored with the `restore` command", backupConf)
+
+	restoreConf := &restoreConfig{}
+	parser.AddCommand(restoreSubCmd, "Restore a wallet from a backup file or a mnemonic",
+		"Restore a wallet from a backup file created by the `backup` command, or a single signer wallet from its mnemonic. "+
+			"If `--rpcserver` is given, the used addresses of the wallet are rescanned up to the gap limit", restoreConf)
+
+	labelAddressConf := &labelAddressConfig{}
+	parser.AddCommand(labelAddressSubCmd, "Set a label for an address",
+		"Set a label for an address, which is kept in the keys file and in its backups", labelAddressConf)
+
+	createVaultConf := &createVaultConfig{DaemonAddress: defaultListen}
+	parser.AddCommand(createVaultSubCmd, "Create a time-locked vault address",
+		"Create a pay-to-script-hash address whose funds are spendable by one key after a DAA score, "+
+			"or by another key at any time", createVaultConf)
+
+	scriptHashAddressConf := &scriptHashAddressConfig{DaemonAddress: defaultListen}
+	parser.AddCommand(scriptHashAddressSubCmd, "Shows the pay-to-script-hash address of a redeem script",
+		"Shows the pay-to-script-hash address of a redeem script. Funds can be locked by the script by "+
+			"sending them to this address", scriptHashAddressConf)
+
+	spendScriptHashConf := &spendScriptHashConfig{DaemonAddress: defaultListen}
+	parser.AddCommand(spendScriptHashSubCmd, "Spends the funds locked by a redeem script",
+		"Spends the funds of the pay-to-script-hash address of a redeem script with the key of one of the "+
+			"wallet addresses", spendScriptHashConf)
+
+	externalSignerConf := &externalSignerConfig{}
+	parser.AddCommand(externalSignerSubCmd, "Runs a reference external signer that signs with the keys file mnemonics",
+		"Runs a reference software signer that signs with the mnemonics of the keys file, over the external signer "+
+			"protocol used by --external-signer and --external-signer-socket", externalSignerConf)
+
 	startDaemonConf := &startDaemonConfig{
 		RPCServer: defaultRPCServer,
 		Listen:    defaultListen,
@@ -237,6 +403,13 @@ func parseCommandLine() (subCommand string, config interface{}) {
 			printErrorAndExit(err)
 		}
 		config = sendConf
+	case sendManySubCmd:
+		combineNetworkFlags(&sendManyConf.NetworkFlags, &cfg.NetworkFlags)
+		err := sendManyConf.ResolveNetwork(parser)
+		if err != nil {
+			printErrorAndExit(err)
+		}
+		config = sendManyConf
 	case sweepSubCmd:
 		combineNetworkFlags(&sweepConf.NetworkFlags, &cfg.NetworkFlags)
 		err := sweepConf.ResolveNetwork(parser)
@@ -297,6 +470,76 @@ func parseCommandLine() (subCommand string, config interface{}) {
 			printErrorAndExit(err)
 		}
 		config = dumpUnencryptedDataConf
+	case signMessageSubCmd:
+		combineNetworkFlags(&signMessageConf.NetworkFlags, &cfg.NetworkFlags)
+		err := signMessageConf.ResolveNetwork(parser)
+		if err != nil {
+			printErrorAndExit(err)
+		}
+		config = signMessageConf
+	case verifyMessageSubCmd:
+		combineNetworkFlags(&verifyMessageConf.NetworkFlags, &cfg.NetworkFlags)
+		err := verifyMessageConf.ResolveNetwork(parser)
+		if err != nil {
+			printErrorAndExit(err)
+		}
+		config = verifyMessageConf
+	case changePasswordSubCmd:
+		combineNetworkFlags(&changePasswordConf.NetworkFlags, &cfg.NetworkFlags)
+		err := changePasswordConf.ResolveNetwork(parser)
+		if err != nil {
+			printErrorAndExit(err)
+		}
+		config = changePasswordConf
+	case backupSubCmd:
+		combineNetworkFlags(&backupConf.NetworkFlags, &cfg.NetworkFlags)
+		err := backupConf.ResolveNetwork(parser)
+		if err != nil {
+			printErrorAndExit(err)
+		}
+		config = backupConf
+	case restoreSubCmd:
+		combineNetworkFlags(&restoreConf.NetworkFlags, &cfg.NetworkFlags)
+		err := restoreConf.ResolveNetwork(parser)
+		if err != nil {
+			printErrorAndExit(err)
+		}
+		config = restoreConf
+	case labelAddressSubCmd:
+		combineNetworkFlags(&labelAddressConf.NetworkFlags, &cfg.NetworkFlags)
+		err := labelAddressConf.ResolveNetwork(parser)
+		if err != nil {
+			printErrorAndExit(err)
+		}
+		config = labelAddressConf
+	case createVaultSubCmd:
+		combineNetworkFlags(&createVaultConf.NetworkFlags, &cfg.NetworkFlags)
+		err := createVaultConf.ResolveNetwork(parser)
+		if err != nil {
+			printErrorAndExit(err)
+		}
+		config = createVaultConf
+	case scriptHashAddressSubCmd:
+		combineNetworkFlags(&scriptHashAddressConf.NetworkFlags, &cfg.NetworkFlags)
+		err := scriptHashAddressConf.ResolveNetwork(parser)
+		if err != nil {
+			printErrorAndExit(err)
+		}
+		config = scriptHashAddressConf
+	case spendScriptHashSubCmd:
+		combineNetworkFlags(&spendScriptHashConf.NetworkFlags, &cfg.NetworkFlags)
+		err := spendScriptHashConf.ResolveNetwork(parser)
+		if err != nil {
+			printErrorAndExit(err)
+		}
+		config = spendScriptHashConf
+	case externalSignerSubCmd:
+		combineNetworkFlags(&externalSignerConf.NetworkFlags, &cfg.NetworkFlags)
+		err := externalSignerConf.ResolveNetwork(parser)
+		if err != nil {
+			printErrorAndExit(err)
+		}
+		config = externalSignerConf
 	case startDaemonSubCmd:
 		combineNetworkFlags(&startDaemonConf.NetworkFlags, &cfg.NetworkFlags)
 		err := startDaemonConf.ResolveNetwork(parser)
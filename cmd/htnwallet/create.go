@@ -66,6 +66,7 @@ func create(conf *createConfig) error {
 
 	file := keys.File{
 		Version:            keys.LastVersion,
+		KDF:                keys.DefaultKDFParams(),
 		EncryptedMnemonics: encryptedMnemonics,
 		ExtendedPublicKeys: extendedPublicKeys,
 		MinimumSignatures:  conf.MinimumSignatures,
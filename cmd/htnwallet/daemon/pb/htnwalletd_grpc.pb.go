@@ -19,16 +19,22 @@ import (
 const _ = grpc.SupportPackageIsVersion7
 
 const (
-	Htnwalletd_GetBalance_FullMethodName                 = "/htnwalletd.htnwalletd/GetBalance"
-	Htnwalletd_GetExternalSpendableUTXOs_FullMethodName  = "/htnwalletd.htnwalletd/GetExternalSpendableUTXOs"
-	Htnwalletd_CreateUnsignedTransactions_FullMethodName = "/htnwalletd.htnwalletd/CreateUnsignedTransactions"
-	Htnwalletd_ShowAddresses_FullMethodName              = "/htnwalletd.htnwalletd/ShowAddresses"
-	Htnwalletd_NewAddress_FullMethodName                 = "/htnwalletd.htnwalletd/NewAddress"
-	Htnwalletd_Shutdown_FullMethodName                   = "/htnwalletd.htnwalletd/Shutdown"
-	Htnwalletd_Broadcast_FullMethodName                  = "/htnwalletd.htnwalletd/Broadcast"
-	Htnwalletd_Send_FullMethodName                       = "/htnwalletd.htnwalletd/Send"
-	Htnwalletd_Sign_FullMethodName                       = "/htnwalletd.htnwalletd/Sign"
-	Htnwalletd_GetVersion_FullMethodName                 = "/htnwalletd.htnwalletd/GetVersion"
+	Htnwalletd_GetBalance_FullMethodName                               = "/htnwalletd.htnwalletd/GetBalance"
+	Htnwalletd_GetExternalSpendableUTXOs_FullMethodName                = "/htnwalletd.htnwalletd/GetExternalSpendableUTXOs"
+	Htnwalletd_CreateUnsignedTransactions_FullMethodName               = "/htnwalletd.htnwalletd/CreateUnsignedTransactions"
+	Htnwalletd_ShowAddresses_FullMethodName                            = "/htnwalletd.htnwalletd/ShowAddresses"
+	Htnwalletd_NewAddress_FullMethodName                               = "/htnwalletd.htnwalletd/NewAddress"
+	Htnwalletd_Shutdown_FullMethodName                                 = "/htnwalletd.htnwalletd/Shutdown"
+	Htnwalletd_Broadcast_FullMethodName                                = "/htnwalletd.htnwalletd/Broadcast"
+	Htnwalletd_Send_FullMethodName                                     = "/htnwalletd.htnwalletd/Send"
+	Htnwalletd_CreateUnsignedSendManyTransactions_FullMethodName       = "/htnwalletd.htnwalletd/CreateUnsignedSendManyTransactions"
+	Htnwalletd_SendMany_FullMethodName                                 = "/htnwalletd.htnwalletd/SendMany"
+	Htnwalletd_CreateVaultAddress_FullMethodName                       = "/htnwalletd.htnwalletd/CreateVaultAddress"
+	Htnwalletd_GetScriptHashAddress_FullMethodName                     = "/htnwalletd.htnwalletd/GetScriptHashAddress"
+	Htnwalletd_CreateUnsignedScriptHashSpendTransaction_FullMethodName = "/htnwalletd.htnwalletd/CreateUnsignedScriptHashSpendTransaction"
+	Htnwalletd_SpendScriptHash_FullMethodName                          = "/htnwalletd.htnwalletd/SpendScriptHash"
+	Htnwalletd_Sign_FullMethodName                                     = "/htnwalletd.htnwalletd/Sign"
+	Htnwalletd_GetVersion_FullMethodName                               = "/htnwalletd.htnwalletd/GetVersion"
 )
 
 // HtnwalletdClient is the client API for Htnwalletd service.
@@ -44,6 +50,14 @@ type HtnwalletdClient interface {
 	Broadcast(ctx context.Context, in *BroadcastRequest, opts ...grpc.CallOption) (*BroadcastResponse, error)
 	// Since SendRequest contains a password - this command should only be used on a trusted or secure connection
 	Send(ctx context.Context, in *SendRequest, opts ...grpc.CallOption) (*SendResponse, error)
+	CreateUnsignedSendManyTransactions(ctx context.Context, in *CreateUnsignedSendManyTransactionsRequest, opts ...grpc.CallOption) (*CreateUnsignedSendManyTransactionsResponse, error)
+	// Since SendManyRequest contains a password - this command should only be used on a trusted or secure connection
+	SendMany(ctx context.Context, in *SendManyRequest, opts ...grpc.CallOption) (*SendManyResponse, error)
+	CreateVaultAddress(ctx context.Context, in *CreateVaultAddressRequest, opts ...grpc.CallOption) (*CreateVaultAddressResponse, error)
+	GetScriptHashAddress(ctx context.Context, in *GetScriptHashAddressRequest, opts ...grpc.CallOption) (*GetScriptHashAddressResponse, error)
+	CreateUnsignedScriptHashSpendTransaction(ctx context.Context, in *CreateUnsignedScriptHashSpendTransactionRequest, opts ...grpc.CallOption) (*CreateUnsignedScriptHashSpendTransactionResponse, error)
+	// Since SpendScriptHashRequest contains a password - this command should only be used on a trusted or secure connection
+	SpendScriptHash(ctx context.Context, in *SpendScriptHashRequest, opts ...grpc.CallOption) (*SpendScriptHashResponse, error)
 	// Since SignRequest contains a password - this command should only be used on a trusted or secure connection
 	Sign(ctx context.Context, in *SignRequest, opts ...grpc.CallOption) (*SignResponse, error)
 	GetVersion(ctx context.Context, in *GetVersionRequest, opts ...grpc.CallOption) (*GetVersionResponse, error)
@@ -129,6 +143,60 @@ func (c *htnwalletdClient) Send(ctx context.Context, in *SendRequest, opts ...gr
 	return out, nil
 }
 
+func (c *htnwalletdClient) CreateUnsignedSendManyTransactions(ctx context.Context, in *CreateUnsignedSendManyTransactionsRequest, opts ...grpc.CallOption) (*CreateUnsignedSendManyTransactionsResponse, error) {
+	out := new(CreateUnsignedSendManyTransactionsResponse)
+	err := c.cc.Invoke(ctx, Htnwalletd_CreateUnsignedSendManyTransactions_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *htnwalletdClient) SendMany(ctx context.Context, in *SendManyRequest, opts ...grpc.CallOption) (*SendManyResponse, error) {
+	out := new(SendManyResponse)
+	err := c.cc.Invoke(ctx, Htnwalletd_SendMany_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *htnwalletdClient) CreateVaultAddress(ctx context.Context, in *CreateVaultAddressRequest, opts ...grpc.CallOption) (*CreateVaultAddressResponse, error) {
+	out := new(CreateVaultAddressResponse)
+	err := c.cc.Invoke(ctx, Htnwalletd_CreateVaultAddress_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *htnwalletdClient) GetScriptHashAddress(ctx context.Context, in *GetScriptHashAddressRequest, opts ...grpc.CallOption) (*GetScriptHashAddressResponse, error) {
+	out := new(GetScriptHashAddressResponse)
+	err := c.cc.Invoke(ctx, Htnwalletd_GetScriptHashAddress_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *htnwalletdClient) CreateUnsignedScriptHashSpendTransaction(ctx context.Context, in *CreateUnsignedScriptHashSpendTransactionRequest, opts ...grpc.CallOption) (*CreateUnsignedScriptHashSpendTransactionResponse, error) {
+	out := new(CreateUnsignedScriptHashSpendTransactionResponse)
+	err := c.cc.Invoke(ctx, Htnwalletd_CreateUnsignedScriptHashSpendTransaction_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *htnwalletdClient) SpendScriptHash(ctx context.Context, in *SpendScriptHashRequest, opts ...grpc.CallOption) (*SpendScriptHashResponse, error) {
+	out := new(SpendScriptHashResponse)
+	err := c.cc.Invoke(ctx, Htnwalletd_SpendScriptHash_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *htnwalletdClient) Sign(ctx context.Context, in *SignRequest, opts ...grpc.CallOption) (*SignResponse, error) {
 	out := new(SignResponse)
 	err := c.cc.Invoke(ctx, Htnwalletd_Sign_FullMethodName, in, out, opts...)
@@ -160,6 +228,14 @@ type HtnwalletdServer interface {
 	Broadcast(context.Context, *BroadcastRequest) (*BroadcastResponse, error)
 	// Since SendRequest contains a password - this command should only be used on a trusted or secure connection
 	Send(context.Context, *SendRequest) (*SendResponse, error)
+	CreateUnsignedSendManyTransactions(context.Context, *CreateUnsignedSendManyTransactionsRequest) (*CreateUnsignedSendManyTransactionsResponse, error)
+	// Since SendManyRequest contains a password - this command should only be used on a trusted or secure connection
+	SendMany(context.Context, *SendManyRequest) (*SendManyResponse, error)
+	CreateVaultAddress(context.Context, *CreateVaultAddressRequest) (*CreateVaultAddressResponse, error)
+	GetScriptHashAddress(context.Context, *GetScriptHashAddressRequest) (*GetScriptHashAddressResponse, error)
+	CreateUnsignedScriptHashSpendTransaction(context.Context, *CreateUnsignedScriptHashSpendTransactionRequest) (*CreateUnsignedScriptHashSpendTransactionResponse, error)
+	// Since SpendScriptHashRequest contains a password - this command should only be used on a trusted or secure connection
+	SpendScriptHash(context.Context, *SpendScriptHashRequest) (*SpendScriptHashResponse, error)
 	// Since SignRequest contains a password - this command should only be used on a trusted or secure connection
 	Sign(context.Context, *SignRequest) (*SignResponse, error)
 	GetVersion(context.Context, *GetVersionRequest) (*GetVersionResponse, error)
@@ -194,6 +270,24 @@ func (UnimplementedHtnwalletdServer) Broadcast(context.Context, *BroadcastReques
 func (UnimplementedHtnwalletdServer) Send(context.Context, *SendRequest) (*SendResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method Send not implemented")
 }
+func (UnimplementedHtnwalletdServer) CreateUnsignedSendManyTransactions(context.Context, *CreateUnsignedSendManyTransactionsRequest) (*CreateUnsignedSendManyTransactionsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateUnsignedSendManyTransactions not implemented")
+}
+func (UnimplementedHtnwalletdServer) SendMany(context.Context, *SendManyRequest) (*SendManyResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SendMany not implemented")
+}
+func (UnimplementedHtnwalletdServer) CreateVaultAddress(context.Context, *CreateVaultAddressRequest) (*CreateVaultAddressResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateVaultAddress not implemented")
+}
+func (UnimplementedHtnwalletdServer) GetScriptHashAddress(context.Context, *GetScriptHashAddressRequest) (*GetScriptHashAddressResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetScriptHashAddress not implemented")
+}
+func (UnimplementedHtnwalletdServer) CreateUnsignedScriptHashSpendTransaction(context.Context, *CreateUnsignedScriptHashSpendTransactionRequest) (*CreateUnsignedScriptHashSpendTransactionResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateUnsignedScriptHashSpendTransaction not implemented")
+}
+func (UnimplementedHtnwalletdServer) SpendScriptHash(context.Context, *SpendScriptHashRequest) (*SpendScriptHashResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SpendScriptHash not implemented")
+}
 func (UnimplementedHtnwalletdServer) Sign(context.Context, *SignRequest) (*SignResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method Sign not implemented")
 }
@@ -357,6 +451,114 @@ func _Htnwalletd_Send_Handler(srv interface{}, ctx context.Context, dec func(int
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Htnwalletd_CreateUnsignedSendManyTransactions_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateUnsignedSendManyTransactionsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(HtnwalletdServer).CreateUnsignedSendManyTransactions(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Htnwalletd_CreateUnsignedSendManyTransactions_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(HtnwalletdServer).CreateUnsignedSendManyTransactions(ctx, req.(*CreateUnsignedSendManyTransactionsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Htnwalletd_SendMany_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SendManyRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(HtnwalletdServer).SendMany(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Htnwalletd_SendMany_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(HtnwalletdServer).SendMany(ctx, req.(*SendManyRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Htnwalletd_CreateVaultAddress_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateVaultAddressRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(HtnwalletdServer).CreateVaultAddress(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Htnwalletd_CreateVaultAddress_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(HtnwalletdServer).CreateVaultAddress(ctx, req.(*CreateVaultAddressRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Htnwalletd_GetScriptHashAddress_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetScriptHashAddressRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(HtnwalletdServer).GetScriptHashAddress(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Htnwalletd_GetScriptHashAddress_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(HtnwalletdServer).GetScriptHashAddress(ctx, req.(*GetScriptHashAddressRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Htnwalletd_CreateUnsignedScriptHashSpendTransaction_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateUnsignedScriptHashSpendTransactionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(HtnwalletdServer).CreateUnsignedScriptHashSpendTransaction(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Htnwalletd_CreateUnsignedScriptHashSpendTransaction_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(HtnwalletdServer).CreateUnsignedScriptHashSpendTransaction(ctx, req.(*CreateUnsignedScriptHashSpendTransactionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Htnwalletd_SpendScriptHash_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SpendScriptHashRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(HtnwalletdServer).SpendScriptHash(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Htnwalletd_SpendScriptHash_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(HtnwalletdServer).SpendScriptHash(ctx, req.(*SpendScriptHashRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _Htnwalletd_Sign_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(SignRequest)
 	if err := dec(in); err != nil {
@@ -432,6 +634,30 @@ var Htnwalletd_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "Send",
 			Handler:    _Htnwalletd_Send_Handler,
 		},
+		{
+			MethodName: "CreateUnsignedSendManyTransactions",
+			Handler:    _Htnwalletd_CreateUnsignedSendManyTransactions_Handler,
+		},
+		{
+			MethodName: "SendMany",
+			Handler:    _Htnwalletd_SendMany_Handler,
+		},
+		{
+			MethodName: "CreateVaultAddress",
+			Handler:    _Htnwalletd_CreateVaultAddress_Handler,
+		},
+		{
+			MethodName: "GetScriptHashAddress",
+			Handler:    _Htnwalletd_GetScriptHashAddress_Handler,
+		},
+		{
+			MethodName: "CreateUnsignedScriptHashSpendTransaction",
+			Handler:    _Htnwalletd_CreateUnsignedScriptHashSpendTransaction_Handler,
+		},
+		{
+			MethodName: "SpendScriptHash",
+			Handler:    _Htnwalletd_SpendScriptHash_Handler,
+		},
 		{
 			MethodName: "Sign",
 			Handler:    _Htnwalletd_Sign_Handler,
@@ -79,6 +79,15 @@ func (s *server) NewAddress(_ context.Context, request *pb.NewAddressRequest) (*
 		return nil, errors.Errorf("wallet daemon is not synced yet, %s", s.formatSyncStateReport())
 	}
 
+	address, err := s.newExternalAddress()
+	if err != nil {
+		return nil, err
+	}
+
+	return &pb.NewAddressResponse{Address: address.String()}, nil
+}
+
+func (s *server) newExternalAddress() (util.Address, error) {
 	err := s.keysFile.SetLastUsedExternalIndex(s.keysFile.LastUsedExternalIndex() + 1)
 	if err != nil {
 		return nil, err
@@ -95,12 +104,39 @@ func (s *server) NewAddress(_ context.Context, request *pb.NewAddressRequest) (*
 		keyChain:      libhtnwallet.ExternalKeychain,
 	}
 	path := s.walletAddressPath(walletAddr)
-	address, err := libhtnwallet.Address(s.params, s.keysFile.ExtendedPublicKeys, s.keysFile.MinimumSignatures, path, s.keysFile.ECDSA)
-	if err != nil {
-		return nil, err
+	return libhtnwallet.Address(s.params, s.keysFile.ExtendedPublicKeys, s.keysFile.MinimumSignatures, path, s.keysFile.ECDSA)
+}
+
+// findWalletAddress returns the wallet address of the given address string. Addresses without a balance are
+// not kept in s.addressSet, so all the addresses up to the last used indexes of the wallet are searched as well.
+func (s *server) findWalletAddress(addressString string) (*walletAddress, error) {
+	if walletAddr, ok := s.addressSet[addressString]; ok {
+		return walletAddr, nil
 	}
 
-	return &pb.NewAddressResponse{Address: address.String()}, nil
+	for _, keyChain := range keyChains {
+		lastUsedIndex := s.keysFile.LastUsedExternalIndex()
+		if keyChain == libhtnwallet.InternalKeychain {
+			lastUsedIndex = s.keysFile.LastUsedInternalIndex()
+		}
+
+		for index := uint32(0); index <= lastUsedIndex; index++ {
+			walletAddr := &walletAddress{
+				index:         index,
+				cosignerIndex: s.keysFile.CosignerIndex,
+				keyChain:      keyChain,
+			}
+			walletAddrString, err := s.walletAddressString(walletAddr)
+			if err != nil {
+				return nil, err
+			}
+			if walletAddrString == addressString {
+				return walletAddr, nil
+			}
+		}
+	}
+
+	return nil, errors.Errorf("%s is not an address of this wallet", addressString)
 }
 
 func (s *server) walletAddressString(wAddr *walletAddress) (string, error) {
@@ -6,6 +6,7 @@ import (
 
 	"github.com/Hoosat-Oy/HTND/cmd/htnwallet/daemon/pb"
 	"github.com/Hoosat-Oy/HTND/cmd/htnwallet/libhtnwallet"
+	"github.com/Hoosat-Oy/HTND/domain/consensus/model/externalapi"
 	"github.com/Hoosat-Oy/HTND/domain/consensus/utils/constants"
 	"github.com/Hoosat-Oy/HTND/util"
 	"github.com/pkg/errors"
@@ -45,16 +46,12 @@ func (s *server) createUnsignedTransactions(address string, amount uint64, isSen
 		return nil, err
 	}
 
-	var fromAddresses []*walletAddress
-	for _, from := range fromAddressesString {
-		fromAddress, exists := s.addressSet[from]
-		if !exists {
-			return nil, fmt.Errorf("Specified from address %s does not exists", from)
-		}
-		fromAddresses = append(fromAddresses, fromAddress)
+	fromAddresses, err := s.walletAddressesFromStrings(fromAddressesString)
+	if err != nil {
+		return nil, err
 	}
 
-	selectedUTXOs, spendValue, changeSompi, err := s.selectUTXOs(amount, isSendAll, feePerInput, fromAddresses)
+	selectedUTXOs, spendValue, changeSompi, err := s.selectUTXOs(amount, isSendAll, feePerInput, fromAddresses, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -68,10 +65,11 @@ func (s *server) createUnsignedTransactions(address string, amount uint64, isSen
 		return nil, err
 	}
 
-	payments := []*libhtnwallet.Payment{{
+	payment := &libhtnwallet.Payment{
 		Address: toAddress,
 		Amount:  spendValue,
-	}}
+	}
+	payments := []*libhtnwallet.Payment{payment}
 	if changeSompi > 0 {
 		payments = append(payments, &libhtnwallet.Payment{
 			Address: changeAddress,
@@ -85,14 +83,30 @@ func (s *server) createUnsignedTransactions(address string, amount uint64, isSen
 		return nil, err
 	}
 
-	unsignedTransactions, err := s.maybeAutoCompoundTransaction(unsignedTransaction, toAddress, changeAddress, changeWalletAddress)
+	unsignedTransactions, err := s.maybeAutoCompoundTransaction(unsignedTransaction, []*libhtnwallet.Payment{payment}, 0,
+		changeAddress, changeWalletAddress)
 	if err != nil {
 		return nil, err
 	}
 	return unsignedTransactions, nil
 }
 
-func (s *server) selectUTXOs(spendAmount uint64, isSendAll bool, feePerInput uint64, fromAddresses []*walletAddress) (
+func (s *server) walletAddressesFromStrings(addressStrings []string) ([]*walletAddress, error) {
+	var addresses []*walletAddress
+	for _, addressString := range addressStrings {
+		address, exists := s.addressSet[addressString]
+		if !exists {
+			return nil, fmt.Errorf("Specified from address %s does not exists", addressString)
+		}
+		addresses = append(addresses, address)
+	}
+	return addresses, nil
+}
+
+// selectUTXOs selects UTXOs to fund a transaction spending spendAmount. UTXOs whose outpoints are in
+// excludedOutpoints are never selected.
+func (s *server) selectUTXOs(spendAmount uint64, isSendAll bool, feePerInput uint64, fromAddresses []*walletAddress,
+	excludedOutpoints map[externalapi.DomainOutpoint]struct{}) (
 	selectedUTXOs []*libhtnwallet.UTXO, totalReceived uint64, changeSompi uint64, err error) {
 
 	selectedUTXOs = []*libhtnwallet.UTXO{}
@@ -109,6 +123,10 @@ func (s *server) selectUTXOs(spendAmount uint64, isSendAll bool, feePerInput uin
 			continue
 		}
 
+		if _, ok := excludedOutpoints[*utxo.Outpoint]; ok {
+			continue
+		}
+
 		if broadcastTime, ok := s.usedOutpoints[*utxo.Outpoint]; ok {
 			if s.usedOutpointHasExpired(broadcastTime) {
 				delete(s.usedOutpoints, *utxo.Outpoint)
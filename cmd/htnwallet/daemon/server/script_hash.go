@@ -0,0 +1,265 @@
+package server
+
+import (
+	"context"
+	"sort"
+
+	"github.com/Hoosat-Oy/HTND/app/appmessage"
+	"github.com/Hoosat-Oy/HTND/cmd/htnwallet/daemon/pb"
+	"github.com/Hoosat-Oy/HTND/cmd/htnwallet/libhtnwallet"
+	"github.com/Hoosat-Oy/HTND/cmd/htnwallet/libhtnwallet/serialization"
+	"github.com/Hoosat-Oy/HTND/domain/consensus/utils/constants"
+	"github.com/Hoosat-Oy/HTND/domain/miningmanager/mempool"
+	"github.com/Hoosat-Oy/HTND/util"
+	"github.com/pkg/errors"
+)
+
+func (s *server) CreateVaultAddress(_ context.Context, request *pb.CreateVaultAddressRequest) (
+	*pb.CreateVaultAddressResponse, error) {
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	timeLockedAddress, err := s.vaultKeyAddress(request.TimeLockedAddress)
+	if err != nil {
+		return nil, err
+	}
+	recoveryAddress, err := s.vaultKeyAddress(request.RecoveryAddress)
+	if err != nil {
+		return nil, err
+	}
+
+	redeemScript, err := libhtnwallet.VaultRedeemScript(timeLockedAddress, recoveryAddress, request.LockDaaScore)
+	if err != nil {
+		return nil, err
+	}
+	address, err := libhtnwallet.ScriptHashAddress(s.params, redeemScript)
+	if err != nil {
+		return nil, err
+	}
+
+	return &pb.CreateVaultAddressResponse{
+		Address:           address.String(),
+		RedeemScript:      redeemScript,
+		TimeLockedAddress: timeLockedAddress.String(),
+		RecoveryAddress:   recoveryAddress.String(),
+	}, nil
+}
+
+// vaultKeyAddress decodes the given vault key address, or returns a new wallet address if it's empty
+func (s *server) vaultKeyAddress(addressString string) (util.Address, error) {
+	if addressString != "" {
+		return util.DecodeAddress(addressString, s.params.Prefix)
+	}
+
+	if s.isMultisig() {
+		return nil, errors.Errorf("the addresses of a multisig wallet can't be used as vault keys")
+	}
+	if !s.isSynced() {
+		return nil, errors.Errorf("wallet daemon is not synced yet, %s", s.formatSyncStateReport())
+	}
+	return s.newExternalAddress()
+}
+
+func (s *server) GetScriptHashAddress(_ context.Context, request *pb.GetScriptHashAddressRequest) (
+	*pb.GetScriptHashAddressResponse, error) {
+
+	if len(request.RedeemScript) == 0 {
+		return nil, errors.Errorf("redeem script is empty")
+	}
+
+	address, err := libhtnwallet.ScriptHashAddress(s.params, request.RedeemScript)
+	if err != nil {
+		return nil, err
+	}
+
+	return &pb.GetScriptHashAddressResponse{Address: address.String()}, nil
+}
+
+func (s *server) CreateUnsignedScriptHashSpendTransaction(_ context.Context,
+	request *pb.CreateUnsignedScriptHashSpendTransactionRequest) (*pb.CreateUnsignedScriptHashSpendTransactionResponse, error) {
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	unsignedTransaction, err := s.createUnsignedScriptHashSpendTransaction(request.RedeemScript, request.SigningAddress,
+		request.ToAddress, request.LockTime)
+	if err != nil {
+		return nil, err
+	}
+
+	return &pb.CreateUnsignedScriptHashSpendTransactionResponse{UnsignedTransaction: unsignedTransaction}, nil
+}
+
+func (s *server) SpendScriptHash(_ context.Context, request *pb.SpendScriptHashRequest) (*pb.SpendScriptHashResponse, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	unsignedTransaction, err := s.createUnsignedScriptHashSpendTransaction(request.RedeemScript, request.SigningAddress,
+		request.ToAddress, request.LockTime)
+	if err != nil {
+		return nil, err
+	}
+
+	signedTransactions, err := s.signTransactions([][]byte{unsignedTransaction}, request.Password)
+	if err != nil {
+		return nil, err
+	}
+
+	txIDs, err := s.broadcast(signedTransactions, false)
+	if err != nil {
+		return nil, err
+	}
+
+	return &pb.SpendScriptHashResponse{TxID: txIDs[0], SignedTransaction: signedTransactions[0]}, nil
+}
+
+// createUnsignedScriptHashSpendTransaction creates a transaction that spends the spendable outputs locked by
+// the given redeem script into a single output, and that is signed by the key of signingAddressString.
+// If the redeem script is a vault script, the lock time and the branch of the script to execute are derived
+// from the signing key. Otherwise, the signature script is made of the signature and the redeem script alone.
+// If the outputs are too many to fit into a single standard transaction, only part of them are spent.
+func (s *server) createUnsignedScriptHashSpendTransaction(redeemScript []byte, signingAddressString string,
+	toAddressString string, lockTime uint64) ([]byte, error) {
+
+	if s.isMultisig() {
+		return nil, errors.Errorf("spending pay-to-script-hash outputs is only supported by single signer wallets")
+	}
+	if len(redeemScript) == 0 {
+		return nil, errors.Errorf("redeem script is empty")
+	}
+
+	signingAddress, err := util.DecodeAddress(signingAddressString, s.params.Prefix)
+	if err != nil {
+		return nil, err
+	}
+	signingWalletAddress, err := s.findWalletAddress(signingAddressString)
+	if err != nil {
+		return nil, err
+	}
+
+	isVault, redeemScriptArguments, vaultLockTime, err := libhtnwallet.VaultSpendParameters(redeemScript, signingAddress)
+	if err != nil {
+		return nil, err
+	}
+	if isVault {
+		lockTime = vaultLockTime
+	}
+
+	scriptHashAddress, err := libhtnwallet.ScriptHashAddress(s.params, redeemScript)
+	if err != nil {
+		return nil, err
+	}
+
+	dagInfo, err := s.rpcClient.GetBlockDAGInfo()
+	if err != nil {
+		return nil, err
+	}
+	if lockTime != 0 && lockTime < constants.LockTimeThreshold && dagInfo.VirtualDAAScore <= lockTime {
+		return nil, errors.Errorf("the transaction is locked until DAA score %d, while the current DAA score is %d",
+			lockTime, dagInfo.VirtualDAAScore)
+	}
+
+	utxosResponse, err := s.rpcClient.GetUTXOsByAddresses([]string{scriptHashAddress.String()})
+	if err != nil {
+		return nil, err
+	}
+	selectedUTXOs, err := s.selectScriptHashUTXOs(utxosResponse.Entries, s.walletAddressPath(signingWalletAddress),
+		dagInfo.VirtualDAAScore)
+	if err != nil {
+		return nil, err
+	}
+	if len(selectedUTXOs) == 0 {
+		return nil, errors.Errorf("Could not find any spendable UTXOs in %s", scriptHashAddress)
+	}
+
+	var toAddress util.Address
+	if toAddressString == "" {
+		toAddress, _, err = s.changeAddress(false, nil)
+	} else {
+		toAddress, err = util.DecodeAddress(toAddressString, s.params.Prefix)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// Drop the smallest UTXOs until the transaction fits into the standard transaction mass
+	for {
+		totalValue := uint64(0)
+		for _, utxo := range selectedUTXOs {
+			totalValue += utxo.UTXOEntry.Amount()
+		}
+		fee := feePerInput * uint64(len(selectedUTXOs))
+		if totalValue <= fee {
+			return nil, errors.Errorf("the UTXOs of %s are too small to pay for the transaction fee", scriptHashAddress)
+		}
+
+		unsignedTransaction, err := libhtnwallet.CreateUnsignedScriptHashTransaction(s.keysFile.ExtendedPublicKeys[0],
+			redeemScript, redeemScriptArguments, lockTime,
+			[]*libhtnwallet.Payment{{Address: toAddress, Amount: totalValue - fee}}, selectedUTXOs)
+		if err != nil {
+			return nil, err
+		}
+
+		deserializedTransaction, err := serialization.DeserializePartiallySignedTransaction(unsignedTransaction)
+		if err != nil {
+			return nil, err
+		}
+		mass, err := s.estimateMassAfterSignatures(deserializedTransaction)
+		if err != nil {
+			return nil, err
+		}
+		if mass <= mempool.MaximumStandardTransactionMass {
+			return unsignedTransaction, nil
+		}
+		if len(selectedUTXOs) == 1 {
+			return nil, errors.Errorf("a transaction spending a single UTXO of %s has mass %d, which exceeds "+
+				"the maximum standard transaction mass", scriptHashAddress, mass)
+		}
+
+		numUTXOs := uint64(len(selectedUTXOs)) * mempool.MaximumStandardTransactionMass / mass
+		if numUTXOs == 0 {
+			numUTXOs = 1
+		}
+		selectedUTXOs = selectedUTXOs[:numUTXOs]
+	}
+}
+
+// selectScriptHashUTXOs returns the spendable UTXOs out of the given entries, sorted from the largest to the smallest
+func (s *server) selectScriptHashUTXOs(entries []*appmessage.UTXOsByAddressesEntry, derivationPath string,
+	virtualDAAScore uint64) ([]*libhtnwallet.UTXO, error) {
+
+	var selectedUTXOs []*libhtnwallet.UTXO
+	for _, entry := range entries {
+		if !isExternalUTXOSpendable(entry, virtualDAAScore, s.coinbaseMaturity) {
+			continue
+		}
+
+		outpoint, err := appmessage.RPCOutpointToDomainOutpoint(entry.Outpoint)
+		if err != nil {
+			return nil, err
+		}
+		if broadcastTime, ok := s.usedOutpoints[*outpoint]; ok {
+			if s.usedOutpointHasExpired(broadcastTime) {
+				delete(s.usedOutpoints, *outpoint)
+			} else {
+				continue
+			}
+		}
+
+		utxoEntry, err := appmessage.RPCUTXOEntryToUTXOEntry(entry.UTXOEntry)
+		if err != nil {
+			return nil, err
+		}
+		selectedUTXOs = append(selectedUTXOs, &libhtnwallet.UTXO{
+			Outpoint:       outpoint,
+			UTXOEntry:      utxoEntry,
+			DerivationPath: derivationPath,
+		})
+	}
+
+	sort.Slice(selectedUTXOs, func(i, j int) bool {
+		return selectedUTXOs[i].UTXOEntry.Amount() > selectedUTXOs[j].UTXOEntry.Amount()
+	})
+	return selectedUTXOs, nil
+}
@@ -0,0 +1,220 @@
+package server
+
+import (
+	"context"
+
+	"github.com/Hoosat-Oy/HTND/cmd/htnwallet/daemon/pb"
+	"github.com/Hoosat-Oy/HTND/cmd/htnwallet/libhtnwallet"
+	"github.com/Hoosat-Oy/HTND/domain/consensus/model/externalapi"
+	"github.com/Hoosat-Oy/HTND/domain/consensus/utils/txscript"
+	"github.com/Hoosat-Oy/HTND/domain/miningmanager/mempool"
+	"github.com/Hoosat-Oy/HTND/util"
+	"github.com/Hoosat-Oy/HTND/util/txmass"
+	"github.com/pkg/errors"
+)
+
+// maxSendManyOutputsMass is the maximum mass the outputs of a single send-many transaction may have.
+// The rest of the standard transaction mass is left for the transaction's inputs, so that the transaction
+// can still be split and merged by maybeAutoCompoundTransaction if it ends up spending many UTXOs.
+const maxSendManyOutputsMass = mempool.MaximumStandardTransactionMass * 3 / 4
+
+// paymentsBatch is a group of payments that are paid together in a single transaction
+type paymentsBatch struct {
+	payments []*libhtnwallet.Payment
+
+	// outputsFee is the fee required for the mass of the batch's outputs, on top of feePerInput for each input
+	outputsFee uint64
+}
+
+func (s *server) CreateUnsignedSendManyTransactions(_ context.Context, request *pb.CreateUnsignedSendManyTransactionsRequest) (
+	*pb.CreateUnsignedSendManyTransactionsResponse, error,
+) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	unsignedTransactions, paymentTransactionIndexes, err := s.createUnsignedSendManyTransactions(request.Payments,
+		request.From, request.UseExistingChangeAddress)
+	if err != nil {
+		return nil, err
+	}
+
+	return &pb.CreateUnsignedSendManyTransactionsResponse{
+		UnsignedTransactions:      unsignedTransactions,
+		PaymentTransactionIndexes: paymentTransactionIndexes,
+	}, nil
+}
+
+func (s *server) SendMany(_ context.Context, request *pb.SendManyRequest) (*pb.SendManyResponse, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	unsignedTransactions, paymentTransactionIndexes, err := s.createUnsignedSendManyTransactions(request.Payments,
+		request.From, request.UseExistingChangeAddress)
+	if err != nil {
+		return nil, err
+	}
+
+	signedTransactions, err := s.signTransactions(unsignedTransactions, request.Password)
+	if err != nil {
+		return nil, err
+	}
+
+	txIDs, err := s.broadcast(signedTransactions, false)
+	if err != nil {
+		return nil, err
+	}
+
+	paymentTxIDs := make([]string, len(paymentTransactionIndexes))
+	for i, transactionIndex := range paymentTransactionIndexes {
+		paymentTxIDs[i] = txIDs[transactionIndex]
+	}
+
+	return &pb.SendManyResponse{TxIDs: txIDs, SignedTransactions: signedTransactions, PaymentTxIDs: paymentTxIDs}, nil
+}
+
+// createUnsignedSendManyTransactions creates the unsigned transactions paying all the given payments.
+// The payments are packed into as few transactions as the standard transaction mass allows, and each
+// such transaction is split and merged if needed, same as in createUnsignedTransactions.
+// For each payment, the index of the transaction that pays it is returned in paymentTransactionIndexes.
+func (s *server) createUnsignedSendManyTransactions(requestPayments []*pb.Payment, fromAddressesString []string,
+	useExistingChangeAddress bool) (unsignedTransactions [][]byte, paymentTransactionIndexes []uint32, err error) {
+
+	if !s.isSynced() {
+		return nil, nil, errors.Errorf("wallet daemon is not synced yet, %s", s.formatSyncStateReport())
+	}
+	if len(requestPayments) == 0 {
+		return nil, nil, errors.Errorf("no payments were specified")
+	}
+
+	// make sure all addresses are correct before proceeding to a
+	// potentially long UTXO refreshment operation
+	payments := make([]*libhtnwallet.Payment, len(requestPayments))
+	for i, requestPayment := range requestPayments {
+		address, err := util.DecodeAddress(requestPayment.Address, s.params.Prefix)
+		if err != nil {
+			return nil, nil, errors.Wrapf(err, "invalid address in payment #%d", i+1)
+		}
+		if requestPayment.Amount == 0 {
+			return nil, nil, errors.Errorf("payment #%d to %s has a zero amount", i+1, requestPayment.Address)
+		}
+		payments[i] = &libhtnwallet.Payment{
+			Address: address,
+			Amount:  requestPayment.Amount,
+		}
+	}
+
+	fromAddresses, err := s.walletAddressesFromStrings(fromAddressesString)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	changeAddress, changeWalletAddress, err := s.changeAddress(useExistingChangeAddress, fromAddresses)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	batches, err := s.batchPayments(payments, changeAddress)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// UTXOs selected for one batch must not be selected again for the following ones
+	selectedOutpoints := make(map[externalapi.DomainOutpoint]struct{})
+	paymentTransactionIndexes = make([]uint32, 0, len(payments))
+	for _, batch := range batches {
+		batchAmount := uint64(0)
+		for _, payment := range batch.payments {
+			batchAmount += payment.Amount
+		}
+
+		selectedUTXOs, _, changeSompi, err := s.selectUTXOs(batchAmount+batch.outputsFee, false, feePerInput,
+			fromAddresses, selectedOutpoints)
+		if err != nil {
+			return nil, nil, err
+		}
+		if len(selectedUTXOs) == 0 {
+			return nil, nil, errors.Errorf("couldn't find funds to spend")
+		}
+		for _, selectedUTXO := range selectedUTXOs {
+			selectedOutpoints[*selectedUTXO.Outpoint] = struct{}{}
+		}
+
+		transactionPayments := make([]*libhtnwallet.Payment, len(batch.payments), len(batch.payments)+1)
+		copy(transactionPayments, batch.payments)
+		if changeSompi > 0 {
+			transactionPayments = append(transactionPayments, &libhtnwallet.Payment{
+				Address: changeAddress,
+				Amount:  changeSompi,
+			})
+		}
+		unsignedTransaction, err := libhtnwallet.CreateUnsignedTransaction(s.keysFile.ExtendedPublicKeys,
+			s.keysFile.MinimumSignatures, transactionPayments, selectedUTXOs)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		batchTransactions, err := s.maybeAutoCompoundTransaction(unsignedTransaction, batch.payments, batch.outputsFee,
+			changeAddress, changeWalletAddress)
+		if err != nil {
+			return nil, nil, err
+		}
+		unsignedTransactions = append(unsignedTransactions, batchTransactions...)
+
+		// If the batch transaction was split, the payments are made by the final merge transaction,
+		// which is always the last one
+		payingTransactionIndex := uint32(len(unsignedTransactions) - 1)
+		for range batch.payments {
+			paymentTransactionIndexes = append(paymentTransactionIndexes, payingTransactionIndex)
+		}
+	}
+
+	return unsignedTransactions, paymentTransactionIndexes, nil
+}
+
+// batchPayments groups the given payments, in order, into as few batches as possible, such that the outputs
+// of every batch, together with a change output, do not exceed maxSendManyOutputsMass
+func (s *server) batchPayments(payments []*libhtnwallet.Payment, changeAddress util.Address) ([]*paymentsBatch, error) {
+	changeOutputMass, err := s.paymentOutputMass(&libhtnwallet.Payment{Address: changeAddress})
+	if err != nil {
+		return nil, err
+	}
+
+	var batches []*paymentsBatch
+	currentBatch := &paymentsBatch{}
+	currentBatchMass := changeOutputMass
+	for _, payment := range payments {
+		outputMass, err := s.paymentOutputMass(payment)
+		if err != nil {
+			return nil, err
+		}
+		if len(currentBatch.payments) > 0 && currentBatchMass+outputMass > maxSendManyOutputsMass {
+			batches = append(batches, currentBatch)
+			currentBatch = &paymentsBatch{}
+			currentBatchMass = changeOutputMass
+		}
+		currentBatch.payments = append(currentBatch.payments, payment)
+		currentBatch.outputsFee += outputMass
+		currentBatchMass += outputMass
+	}
+	batches = append(batches, currentBatch)
+
+	return batches, nil
+}
+
+// paymentOutputMass returns the mass the output paying the given payment adds to a transaction
+func (s *server) paymentOutputMass(payment *libhtnwallet.Payment) (uint64, error) {
+	scriptPublicKey, err := txscript.PayToAddrScript(payment.Address)
+	if err != nil {
+		return 0, err
+	}
+	output := &externalapi.DomainTransactionOutput{
+		Value:           payment.Amount,
+		ScriptPublicKey: scriptPublicKey,
+	}
+
+	massForSize := txmass.TransactionOutputEstimatedSerializedSize(output) * s.txMassCalculator.MassPerTxByte()
+	scriptPublicKeySize := 2 + uint64(len(scriptPublicKey.Script)) // output.ScriptPublicKey.Version (uint16) + script
+	massForScriptPublicKey := scriptPublicKeySize * s.txMassCalculator.MassPerScriptPubKeyByte()
+
+	return massForSize + massForScriptPublicKey, nil
+}
@@ -0,0 +1,82 @@
+package server
+
+import (
+	"testing"
+
+	"github.com/Hoosat-Oy/HTND/cmd/htnwallet/libhtnwallet"
+	"github.com/Hoosat-Oy/HTND/domain/dagconfig"
+	"github.com/Hoosat-Oy/HTND/util"
+	"github.com/Hoosat-Oy/HTND/util/txmass"
+)
+
+func TestBatchPayments(t *testing.T) {
+	params := &dagconfig.MainnetParams
+	serverInstance := &server{
+		params:           params,
+		txMassCalculator: txmass.NewCalculator(params.MassPerTxByte, params.MassPerScriptPubKeyByte, params.MassPerSigOp),
+	}
+
+	changeAddress, err := util.NewAddressPublicKey(make([]byte, 32), params.Prefix)
+	if err != nil {
+		t.Fatalf("NewAddressPublicKey: %+v", err)
+	}
+
+	const paymentCount = 500
+	payments := make([]*libhtnwallet.Payment, paymentCount)
+	for i := range payments {
+		publicKey := make([]byte, 32)
+		publicKey[0] = byte(i)
+		publicKey[1] = byte(i >> 8)
+		address, err := util.NewAddressPublicKey(publicKey, params.Prefix)
+		if err != nil {
+			t.Fatalf("NewAddressPublicKey: %+v", err)
+		}
+		payments[i] = &libhtnwallet.Payment{Address: address, Amount: uint64(i + 1)}
+	}
+
+	outputMass, err := serverInstance.paymentOutputMass(payments[0])
+	if err != nil {
+		t.Fatalf("paymentOutputMass: %+v", err)
+	}
+	changeOutputMass, err := serverInstance.paymentOutputMass(&libhtnwallet.Payment{Address: changeAddress})
+	if err != nil {
+		t.Fatalf("paymentOutputMass: %+v", err)
+	}
+	paymentsPerBatch := int((maxSendManyOutputsMass - changeOutputMass) / outputMass)
+	expectedBatchCount := paymentCount / paymentsPerBatch
+	if paymentCount%paymentsPerBatch > 0 {
+		expectedBatchCount++
+	}
+
+	batches, err := serverInstance.batchPayments(payments, changeAddress)
+	if err != nil {
+		t.Fatalf("batchPayments: %+v", err)
+	}
+	if len(batches) != expectedBatchCount {
+		t.Fatalf("Expected %d batches but got %d", expectedBatchCount, len(batches))
+	}
+
+	paymentIndex := 0
+	for i, batch := range batches {
+		if i < len(batches)-1 && len(batch.payments) != paymentsPerBatch {
+			t.Errorf("Expected batch #%d to have %d payments but got %d", i, paymentsPerBatch, len(batch.payments))
+		}
+		if batch.outputsFee != uint64(len(batch.payments))*outputMass {
+			t.Errorf("Expected batch #%d outputs fee to be %d but got %d",
+				i, uint64(len(batch.payments))*outputMass, batch.outputsFee)
+		}
+		if batch.outputsFee+changeOutputMass > maxSendManyOutputsMass {
+			t.Errorf("Batch #%d outputs mass %d is above the maximum of %d",
+				i, batch.outputsFee+changeOutputMass, maxSendManyOutputsMass)
+		}
+		for _, payment := range batch.payments {
+			if payment != payments[paymentIndex] {
+				t.Fatalf("Payment #%d is out of order", paymentIndex)
+			}
+			paymentIndex++
+		}
+	}
+	if paymentIndex != paymentCount {
+		t.Errorf("Expected all %d payments to be batched but got %d", paymentCount, paymentIndex)
+	}
+}
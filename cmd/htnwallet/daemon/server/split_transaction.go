@@ -18,16 +18,17 @@ import (
 // transaction.
 // If it is - the transaction is split into multiple transactions, each with a portion of the inputs and a single output
 // into a change address.
-// An additional `mergeTransaction` is generated - which merges the outputs of the above splits into a single output
-// paying to the original transaction's payee.
-func (s *server) maybeAutoCompoundTransaction(transactionBytes []byte, toAddress util.Address,
+// An additional `mergeTransaction` is generated - which merges the outputs of the above splits into outputs
+// paying to the original transaction's payees.
+// outputsFee is the fee, on top of feePerInput for each input, required to pay for the payments' outputs.
+func (s *server) maybeAutoCompoundTransaction(transactionBytes []byte, payments []*libhtnwallet.Payment, outputsFee uint64,
 	changeAddress util.Address, changeWalletAddress *walletAddress) ([][]byte, error) {
 	transaction, err := serialization.DeserializePartiallySignedTransaction(transactionBytes)
 	if err != nil {
 		return nil, err
 	}
 
-	splitTransactions, err := s.maybeSplitAndMergeTransaction(transaction, toAddress, changeAddress, changeWalletAddress)
+	splitTransactions, err := s.maybeSplitAndMergeTransaction(transaction, payments, outputsFee, changeAddress, changeWalletAddress)
 	if err != nil {
 		return nil, err
 	}
@@ -44,21 +45,25 @@ func (s *server) maybeAutoCompoundTransaction(transactionBytes []byte, toAddress
 func (s *server) mergeTransaction(
 	splitTransactions []*serialization.PartiallySignedTransaction,
 	originalTransaction *serialization.PartiallySignedTransaction,
-	toAddress util.Address,
+	payments []*libhtnwallet.Payment,
+	outputsFee uint64,
 	changeAddress util.Address,
 	changeWalletAddress *walletAddress,
 ) (*serialization.PartiallySignedTransaction, error) {
 	numOutputs := len(originalTransaction.Tx.Outputs)
-	if numOutputs > 2 || numOutputs == 0 {
-		// This is a sanity check to make sure originalTransaction has either 1 or 2 outputs:
-		// 1. For the payment itself
+	if numOutputs > len(payments)+1 || numOutputs < len(payments) || numOutputs == 0 {
+		// This is a sanity check to make sure originalTransaction has either len(payments) or len(payments)+1 outputs:
+		// 1. For the payments themselves
 		// 2. (optional) for change
-		return nil, errors.Errorf("original transaction has %d outputs, while 1 or 2 are expected",
-			len(originalTransaction.Tx.Outputs))
+		return nil, errors.Errorf("original transaction has %d outputs, while %d or %d are expected",
+			len(originalTransaction.Tx.Outputs), len(payments), len(payments)+1)
 	}
 
 	totalValue := uint64(0)
-	sentValue := originalTransaction.Tx.Outputs[0].Value
+	sentValue := outputsFee
+	for _, payment := range payments {
+		sentValue += payment.Amount
+	}
 	utxos := make([]*libhtnwallet.UTXO, len(splitTransactions))
 	for i, splitTransaction := range splitTransactions {
 		output := splitTransaction.Tx.Outputs[0]
@@ -85,19 +90,17 @@ func (s *server) mergeTransaction(
 		totalValue += totalValueAdded
 	}
 
-	payments := []*libhtnwallet.Payment{{
-		Address: toAddress,
-		Amount:  sentValue,
-	}}
+	mergePayments := make([]*libhtnwallet.Payment, len(payments), len(payments)+1)
+	copy(mergePayments, payments)
 	if totalValue > sentValue {
-		payments = append(payments, &libhtnwallet.Payment{
+		mergePayments = append(mergePayments, &libhtnwallet.Payment{
 			Address: changeAddress,
 			Amount:  totalValue - sentValue,
 		})
 	}
 
 	mergeTransactionBytes, err := libhtnwallet.CreateUnsignedTransaction(s.keysFile.ExtendedPublicKeys,
-		s.keysFile.MinimumSignatures, payments, utxos)
+		s.keysFile.MinimumSignatures, mergePayments, utxos)
 	if err != nil {
 		return nil, err
 	}
@@ -105,8 +108,9 @@ func (s *server) mergeTransaction(
 	return serialization.DeserializePartiallySignedTransaction(mergeTransactionBytes)
 }
 
-func (s *server) maybeSplitAndMergeTransaction(transaction *serialization.PartiallySignedTransaction, toAddress util.Address,
-	changeAddress util.Address, changeWalletAddress *walletAddress) ([]*serialization.PartiallySignedTransaction, error) {
+func (s *server) maybeSplitAndMergeTransaction(transaction *serialization.PartiallySignedTransaction,
+	payments []*libhtnwallet.Payment, outputsFee uint64, changeAddress util.Address, changeWalletAddress *walletAddress) (
+	[]*serialization.PartiallySignedTransaction, error) {
 
 	transactionMass, err := s.estimateMassAfterSignatures(transaction)
 	if err != nil {
@@ -134,12 +138,14 @@ func (s *server) maybeSplitAndMergeTransaction(transaction *serialization.Partia
 	}
 
 	if len(splitTransactions) > 1 {
-		mergeTransaction, err := s.mergeTransaction(splitTransactions, transaction, toAddress, changeAddress, changeWalletAddress)
+		mergeTransaction, err := s.mergeTransaction(splitTransactions, transaction, payments, outputsFee, changeAddress,
+			changeWalletAddress)
 		if err != nil {
 			return nil, err
 		}
 		// Recursion will be 2-3 iterations deep even in the rarest` cases, so considered safe..
-		splitMergeTransaction, err := s.maybeSplitAndMergeTransaction(mergeTransaction, toAddress, changeAddress, changeWalletAddress)
+		splitMergeTransaction, err := s.maybeSplitAndMergeTransaction(mergeTransaction, payments, outputsFee, changeAddress,
+			changeWalletAddress)
 		if err != nil {
 			return nil, err
 		}
@@ -230,6 +236,7 @@ func (s *server) estimateMassAfterSignatures(transaction *serialization.Partiall
 		signatureSize = secp256k1.SerializedSchnorrSignatureSize
 	}
 
+	var err error
 	for i, input := range transaction.PartiallySignedInputs {
 		for j, pubKeyPair := range input.PubKeySignaturePairs {
 			if uint32(j) >= s.keysFile.MinimumSignatures {
@@ -237,7 +244,10 @@ func (s *server) estimateMassAfterSignatures(transaction *serialization.Partiall
 			}
 			pubKeyPair.Signature = make([]byte, signatureSize+1) // +1 for SigHashType
 		}
-		transaction.Tx.Inputs[i].SigOpCount = byte(len(input.PubKeySignaturePairs))
+		transaction.Tx.Inputs[i].SigOpCount, err = libhtnwallet.PartiallySignedInputSigOpCount(input)
+		if err != nil {
+			return 0, err
+		}
 	}
 
 	transactionWithSignatures, err := libhtnwallet.ExtractTransactionDeserialized(transaction, s.keysFile.ECDSA)
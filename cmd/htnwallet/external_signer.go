@@ -0,0 +1,110 @@
+package main
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"os/signal"
+	"strings"
+
+	"github.com/Hoosat-Oy/HTND/cmd/htnwallet/keys"
+	"github.com/Hoosat-Oy/HTND/cmd/htnwallet/libhtnwallet"
+	"github.com/Hoosat-Oy/HTND/domain/dagconfig"
+	"github.com/pkg/errors"
+)
+
+// externalSignerFlags are the flags of the commands that can sign transactions with an external signer
+type externalSignerFlags struct {
+	ExternalSigner       string `long:"external-signer" description:"A command to sign with instead of the keys file mnemonics. The command is run for each transaction, and speaks the external signer protocol over its stdin/stdout"`
+	ExternalSignerSocket string `long:"external-signer-socket" description:"The path of a Unix socket of an external signer to sign with instead of the keys file mnemonics"`
+}
+
+func (esf *externalSignerFlags) isSet() bool {
+	return esf.ExternalSigner != "" || esf.ExternalSignerSocket != ""
+}
+
+func (esf *externalSignerFlags) externalSigner() (libhtnwallet.ExternalSigner, error) {
+	if esf.ExternalSigner != "" && esf.ExternalSignerSocket != "" {
+		return nil, errors.Errorf("Both --external-signer and --external-signer-socket cannot be passed at the same time")
+	}
+	if esf.ExternalSignerSocket != "" {
+		return libhtnwallet.NewExternalSignerSocket(esf.ExternalSignerSocket), nil
+	}
+
+	commandFields := strings.Fields(esf.ExternalSigner)
+	if len(commandFields) == 0 {
+		return nil, errors.Errorf("--external-signer must not be empty")
+	}
+	return libhtnwallet.NewExternalSignerProcess(commandFields[0], commandFields[1:]...), nil
+}
+
+// newTransactionSigner returns a function that signs partially signed transactions. If an external
+// signer is set the transactions are signed by it, and otherwise they're signed by the keys file
+// mnemonics, which are decrypted with the given password (or with one the user is prompted for).
+func newTransactionSigner(params *dagconfig.Params, keysFile *keys.File, password string,
+	flags *externalSignerFlags) (func(partiallySignedTransaction []byte) ([]byte, error), error) {
+
+	if flags.isSet() {
+		externalSigner, err := flags.externalSigner()
+		if err != nil {
+			return nil, err
+		}
+		return func(partiallySignedTransaction []byte) ([]byte, error) {
+			return libhtnwallet.SignWithExternalSigner(params, externalSigner, partiallySignedTransaction, keysFile.ECDSA)
+		}, nil
+	}
+
+	if len(password) == 0 {
+		password = keys.GetPassword("Password:")
+	}
+	mnemonics, err := keysFile.DecryptMnemonics(password)
+	if err != nil {
+		return nil, err
+	}
+	return func(partiallySignedTransaction []byte) ([]byte, error) {
+		return libhtnwallet.Sign(params, mnemonics, partiallySignedTransaction, keysFile.ECDSA)
+	}, nil
+}
+
+// externalSigner runs a reference software signer, that signs with the mnemonics of a keys file
+// over the external signer protocol
+func externalSigner(conf *externalSignerConfig) error {
+	if conf.Socket == "" && len(conf.Password) == 0 {
+		return errors.Errorf("--password is required when serving over stdin/stdout, since stdin is " +
+			"used by the protocol")
+	}
+
+	keysFile, err := keys.ReadKeysFile(conf.NetParams(), conf.KeysFile)
+	if err != nil {
+		return err
+	}
+
+	if len(conf.Password) == 0 {
+		conf.Password = keys.GetPassword("Password:")
+	}
+	mnemonics, err := keysFile.DecryptMnemonics(conf.Password)
+	if err != nil {
+		return err
+	}
+
+	signer := libhtnwallet.NewSoftwareSigner(conf.NetParams(), mnemonics, keysFile.ECDSA)
+	if conf.Socket == "" {
+		return libhtnwallet.ServeExternalSigner(signer, os.Stdin, os.Stdout)
+	}
+
+	listener, err := net.Listen("unix", conf.Socket)
+	if err != nil {
+		return err
+	}
+
+	interrupt := make(chan os.Signal, 1)
+	signal.Notify(interrupt, os.Interrupt)
+	go func() {
+		<-interrupt
+		// Closing the listener also removes the socket file
+		listener.Close()
+	}()
+
+	fmt.Fprintf(os.Stderr, "Listening for sign requests on %s\n", conf.Socket)
+	return libhtnwallet.ServeExternalSignerListener(signer, listener)
+}
@@ -0,0 +1,246 @@
+package keys
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"os"
+
+	"github.com/Hoosat-Oy/HTND/domain/dagconfig"
+	"github.com/pkg/errors"
+	"github.com/tyler-smith/go-bip39"
+)
+
+// LastBackupVersion is the most up to date backup file format version
+const LastBackupVersion = 1
+
+// Backup holds everything that is needed in order to fully restore a wallet, including its secrets
+type Backup struct {
+	Network               string
+	Mnemonics             []string
+	ExtendedPublicKeys    []string
+	MinimumSignatures     uint32
+	CosignerIndex         uint32
+	LastUsedExternalIndex uint32
+	LastUsedInternalIndex uint32
+	ECDSA                 bool
+	Labels                map[string]string
+}
+
+type backupJSON struct {
+	Network               string            `json:"network"`
+	Mnemonics             []string          `json:"mnemonics"`
+	ExtendedPublicKeys    []string          `json:"publicKeys"`
+	MinimumSignatures     uint32            `json:"minimumSignatures"`
+	CosignerIndex         uint32            `json:"cosignerIndex"`
+	LastUsedExternalIndex uint32            `json:"lastUsedExternalIndex"`
+	LastUsedInternalIndex uint32            `json:"lastUsedInternalIndex"`
+	ECDSA                 bool              `json:"ecdsa"`
+	Labels                map[string]string `json:"labels,omitempty"`
+}
+
+// backupFileJSON is the on-disk format of a backup file. Only the network name and the
+// KDF parameters are stored in plain text, and the rest of the backup is encrypted.
+type backupFileJSON struct {
+	Version uint32         `json:"version"`
+	Network string         `json:"network"`
+	KDF     *kdfParamsJSON `json:"kdf"`
+	Salt    string         `json:"salt"`
+	Cipher  string         `json:"cipher"`
+}
+
+// Backup decrypts the mnemonics of the file and returns a Backup of the wallet
+func (d *File) Backup(params *dagconfig.Params, password string) (*Backup, error) {
+	mnemonics, err := d.DecryptMnemonics(password)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Backup{
+		Network:               params.Name,
+		Mnemonics:             mnemonics,
+		ExtendedPublicKeys:    d.ExtendedPublicKeys,
+		MinimumSignatures:     d.MinimumSignatures,
+		CosignerIndex:         d.CosignerIndex,
+		LastUsedExternalIndex: d.lastUsedExternalIndex,
+		LastUsedInternalIndex: d.lastUsedInternalIndex,
+		ECDSA:                 d.ECDSA,
+		Labels:                d.Labels(),
+	}, nil
+}
+
+// NewFileFromBackup creates a new File out of the given backup, with its mnemonics encrypted
+// with the given password and KDF parameters
+func NewFileFromBackup(backup *Backup, password string, kdfParams *KDFParams) (*File, error) {
+	err := kdfParams.Validate()
+	if err != nil {
+		return nil, err
+	}
+
+	encryptedMnemonics := make([]*EncryptedMnemonic, len(backup.Mnemonics))
+	for i, mnemonic := range backup.Mnemonics {
+		encryptedMnemonics[i], err = encryptMnemonic(mnemonic, []byte(password), kdfParams)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return &File{
+		Version:               LastVersion,
+		KDF:                   kdfParams,
+		EncryptedMnemonics:    encryptedMnemonics,
+		ExtendedPublicKeys:    backup.ExtendedPublicKeys,
+		MinimumSignatures:     backup.MinimumSignatures,
+		CosignerIndex:         backup.CosignerIndex,
+		lastUsedExternalIndex: backup.LastUsedExternalIndex,
+		lastUsedInternalIndex: backup.LastUsedInternalIndex,
+		ECDSA:                 backup.ECDSA,
+		labels:                backup.Labels,
+	}, nil
+}
+
+// WriteBackupFile encrypts the given backup with the given password and KDF parameters, and
+// writes it to path
+func WriteBackupFile(path string, backup *Backup, password string, kdfParams *KDFParams) error {
+	err := kdfParams.Validate()
+	if err != nil {
+		return err
+	}
+
+	plaintext, err := json.Marshal(&backupJSON{
+		Network:               backup.Network,
+		Mnemonics:             backup.Mnemonics,
+		ExtendedPublicKeys:    backup.ExtendedPublicKeys,
+		MinimumSignatures:     backup.MinimumSignatures,
+		CosignerIndex:         backup.CosignerIndex,
+		LastUsedExternalIndex: backup.LastUsedExternalIndex,
+		LastUsedInternalIndex: backup.LastUsedInternalIndex,
+		ECDSA:                 backup.ECDSA,
+		Labels:                backup.Labels,
+	})
+	if err != nil {
+		return err
+	}
+
+	salt, err := generateSalt()
+	if err != nil {
+		return err
+	}
+
+	aead, err := getAEAD(kdfParams, []byte(password), salt)
+	if err != nil {
+		return err
+	}
+
+	nonce := make([]byte, aead.NonceSize(), aead.NonceSize()+len(plaintext)+aead.Overhead())
+	if _, err := rand.Read(nonce); err != nil {
+		return err
+	}
+	// The plain text header is authenticated as additional data, so it can't be tampered with
+	header := backupHeader(backup.Network, kdfParams)
+	cipher := aead.Seal(nonce, nonce, plaintext, header)
+
+	err = createFileDirectoryIfDoesntExist(path)
+	if err != nil {
+		return err
+	}
+
+	return writeFileAtomically(path, &backupFileJSON{
+		Version: LastBackupVersion,
+		Network: backup.Network,
+		KDF:     kdfParams.toJSON(),
+		Salt:    hex.EncodeToString(salt),
+		Cipher:  hex.EncodeToString(cipher),
+	})
+}
+
+// ReadBackupFile reads and decrypts the backup file at path
+func ReadBackupFile(path string, password string) (*Backup, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	decoder := json.NewDecoder(file)
+	decoder.DisallowUnknownFields()
+	fileJSON := &backupFileJSON{}
+	err = decoder.Decode(fileJSON)
+	if err != nil {
+		return nil, errors.Wrapf(err, "%s is not a valid backup file", path)
+	}
+
+	if fileJSON.Version != LastBackupVersion {
+		return nil, errors.Errorf("unsupported backup file version %d", fileJSON.Version)
+	}
+	if fileJSON.KDF == nil {
+		return nil, errors.Errorf("backup file is missing its KDF parameters")
+	}
+	kdfParams, err := fileJSON.KDF.toKDFParams()
+	if err != nil {
+		return nil, err
+	}
+
+	salt, err := hex.DecodeString(fileJSON.Salt)
+	if err != nil {
+		return nil, err
+	}
+	cipher, err := hex.DecodeString(fileJSON.Cipher)
+	if err != nil {
+		return nil, err
+	}
+
+	aead, err := getAEAD(kdfParams, []byte(password), salt)
+	if err != nil {
+		return nil, err
+	}
+	if len(cipher) < aead.NonceSize() {
+		return nil, errors.New("ciphertext too short")
+	}
+	nonce, ciphertext := cipher[:aead.NonceSize()], cipher[aead.NonceSize():]
+	plaintext, err := aead.Open(nil, nonce, ciphertext, backupHeader(fileJSON.Network, kdfParams))
+	if err != nil {
+		return nil, err
+	}
+
+	decoded := &backupJSON{}
+	err = json.Unmarshal(plaintext, decoded)
+	if err != nil {
+		return nil, err
+	}
+
+	backup := &Backup{
+		Network:               decoded.Network,
+		Mnemonics:             decoded.Mnemonics,
+		ExtendedPublicKeys:    decoded.ExtendedPublicKeys,
+		MinimumSignatures:     decoded.MinimumSignatures,
+		CosignerIndex:         decoded.CosignerIndex,
+		LastUsedExternalIndex: decoded.LastUsedExternalIndex,
+		LastUsedInternalIndex: decoded.LastUsedInternalIndex,
+		ECDSA:                 decoded.ECDSA,
+		Labels:                decoded.Labels,
+	}
+	for _, mnemonic := range backup.Mnemonics {
+		if !bip39.IsMnemonicValid(mnemonic) {
+			return nil, errors.Errorf("backup file contains an invalid mnemonic")
+		}
+	}
+
+	return backup, nil
+}
+
+func backupHeader(network string, kdfParams *KDFParams) []byte {
+	header, err := json.Marshal(struct {
+		Version uint32         `json:"version"`
+		Network string         `json:"network"`
+		KDF     *kdfParamsJSON `json:"kdf"`
+	}{
+		Version: LastBackupVersion,
+		Network: network,
+		KDF:     kdfParams.toJSON(),
+	})
+	if err != nil {
+		panic(errors.Wrap(err, "this should never happen: failed to marshal backup header"))
+	}
+	return header
+}
@@ -3,7 +3,6 @@ package keys
 import (
 	"bufio"
 	"crypto/rand"
-	"crypto/subtle"
 	"fmt"
 	"os"
 
@@ -52,13 +51,11 @@ func encryptedMnemonicExtendedPublicKeyPairs(params *dagconfig.Params, mnemonics
 	encryptedPrivateKeys []*EncryptedMnemonic, extendedPublicKeys []string, err error) {
 	password := []byte(cmdLinePassword)
 	if len(password) == 0 {
-
-		password = []byte(GetPassword("Enter password for the key file:"))
-		confirmPassword := []byte(GetPassword("Confirm password:"))
-
-		if subtle.ConstantTimeCompare(password, confirmPassword) != 1 {
-			return nil, nil, errors.New("Passwords are not identical")
+		newPassword, err := GetNewPassword("Enter password for the key file:")
+		if err != nil {
+			return nil, nil, err
 		}
+		password = []byte(newPassword)
 	}
 
 	encryptedPrivateKeys = make([]*EncryptedMnemonic, 0, len(mnemonics))
@@ -72,7 +69,7 @@ func encryptedMnemonicExtendedPublicKeyPairs(params *dagconfig.Params, mnemonics
 
 		extendedPublicKeys = append(extendedPublicKeys, extendedPublicKey)
 
-		encryptedPrivateKey, err := encryptMnemonic(mnemonic, password)
+		encryptedPrivateKey, err := encryptMnemonic(mnemonic, password, DefaultKDFParams())
 		if err != nil {
 			return nil, nil, err
 		}
@@ -92,7 +89,7 @@ func generateSalt() ([]byte, error) {
 	return salt, nil
 }
 
-func encryptMnemonic(mnemonic string, password []byte) (*EncryptedMnemonic, error) {
+func encryptMnemonic(mnemonic string, password []byte, kdfParams *KDFParams) (*EncryptedMnemonic, error) {
 	mnemonicBytes := []byte(mnemonic)
 
 	salt, err := generateSalt()
@@ -100,7 +97,7 @@ func encryptMnemonic(mnemonic string, password []byte) (*EncryptedMnemonic, erro
 		return nil, err
 	}
 
-	aead, err := getAEAD(defaultNumThreads, password, salt)
+	aead, err := getAEAD(kdfParams, password, salt)
 	if err != nil {
 		return nil, err
 	}
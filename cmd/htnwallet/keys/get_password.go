@@ -1,11 +1,13 @@
 package keys
 
 import (
+	"crypto/subtle"
 	"fmt"
 	"os"
 	"os/signal"
 	"syscall"
 
+	"github.com/pkg/errors"
 	"golang.org/x/term"
 )
 
@@ -40,3 +42,14 @@ func GetPassword(prompt string) string {
 
 	return string(p)
 }
+
+// GetNewPassword asks the user to enter a new password twice, and returns it if both entries are identical
+func GetNewPassword(prompt string) (string, error) {
+	password := []byte(GetPassword(prompt))
+	confirmPassword := []byte(GetPassword("Confirm password:"))
+
+	if subtle.ConstantTimeCompare(password, confirmPassword) != 1 {
+		return "", errors.New("Passwords are not identical")
+	}
+	return string(password), nil
+}
@@ -26,8 +26,59 @@ var (
 	defaultAppDir = util.AppDir("htnwallet", false)
 )
 
-// LastVersion is the most up to date file format version
-const LastVersion = 1
+// LastVersion is the most up to date file format version.
+// Version 2 stores the parameters of the key derivation function in the file,
+// so that they can be increased later on.
+const LastVersion = 2
+
+// KDFParams are the argon2id parameters used to derive the mnemonics encryption key from the wallet password
+type KDFParams struct {
+	Time    uint32
+	Memory  uint32 // In KiB
+	Threads uint8
+}
+
+// DefaultKDFParams returns the parameters used to encrypt new mnemonics.
+// These are also the fixed parameters of version 1 files.
+func DefaultKDFParams() *KDFParams {
+	return &KDFParams{
+		Time:    1,
+		Memory:  64 * 1024,
+		Threads: defaultNumThreads,
+	}
+}
+
+// The upper bounds of the KDF parameters. The parameters are read from keys
+// and backup files, so they're bounded to keep a corrupted or crafted file
+// from making the wallet allocate huge amounts of memory or run for hours.
+// Threads is a uint8, so it's bounded by 255.
+const (
+	maxKDFTime   = 64
+	maxKDFMemory = 4 * 1024 * 1024 // 4GiB in KiB
+)
+
+// Validate makes sure the KDF parameters are sane
+func (p *KDFParams) Validate() error {
+	defaultParams := DefaultKDFParams()
+	if p.Time < defaultParams.Time {
+		return errors.Errorf("KDF time must be at least %d", defaultParams.Time)
+	}
+	if p.Time > maxKDFTime {
+		return errors.Errorf("KDF time must be at most %d", maxKDFTime)
+	}
+	if p.Memory < defaultParams.Memory {
+		return errors.Errorf("KDF memory must be at least %d KiB", defaultParams.Memory)
+	}
+	if p.Memory > maxKDFMemory {
+		return errors.Errorf("KDF memory must be at most %d KiB", maxKDFMemory)
+	}
+	if p.Threads == 0 {
+		return errors.Errorf("KDF threads must be at least 1")
+	}
+	return nil
+}
+
+const kdfAlgorithmArgon2id = "argon2id"
 
 func defaultKeysFile(netParams *dagconfig.Params) string {
 	return filepath.Join(defaultAppDir, netParams.Name, "keys.json")
@@ -38,9 +89,42 @@ type encryptedPrivateKeyJSON struct {
 	Salt   string `json:"salt"`
 }
 
+type kdfParamsJSON struct {
+	Algorithm string `json:"algorithm"`
+	Time      uint32 `json:"time"`
+	Memory    uint32 `json:"memory"`
+	Threads   uint8  `json:"threads"`
+}
+
+func (p *KDFParams) toJSON() *kdfParamsJSON {
+	return &kdfParamsJSON{
+		Algorithm: kdfAlgorithmArgon2id,
+		Time:      p.Time,
+		Memory:    p.Memory,
+		Threads:   p.Threads,
+	}
+}
+
+func (p *kdfParamsJSON) toKDFParams() (*KDFParams, error) {
+	if p.Algorithm != kdfAlgorithmArgon2id {
+		return nil, errors.Errorf("unsupported KDF algorithm '%s'", p.Algorithm)
+	}
+	kdfParams := &KDFParams{
+		Time:    p.Time,
+		Memory:  p.Memory,
+		Threads: p.Threads,
+	}
+	err := kdfParams.Validate()
+	if err != nil {
+		return nil, err
+	}
+	return kdfParams, nil
+}
+
 type keysFileJSON struct {
 	Version               uint32                     `json:"version"`
 	NumThreads            uint8                      `json:"numThreads,omitempty"` // This field is ignored for versions different from 0. See more details at the function `numThreads`.
+	KDF                   *kdfParamsJSON             `json:"kdf,omitempty"`        // This field is only used from version 2
 	EncryptedPrivateKeys  []*encryptedPrivateKeyJSON `json:"encryptedMnemonics"`
 	ExtendedPublicKeys    []string                   `json:"publicKeys"`
 	MinimumSignatures     uint32                     `json:"minimumSignatures"`
@@ -48,6 +132,7 @@ type keysFileJSON struct {
 	LastUsedExternalIndex uint32                     `json:"lastUsedExternalIndex"`
 	LastUsedInternalIndex uint32                     `json:"lastUsedInternalIndex"`
 	ECDSA                 bool                       `json:"ecdsa"`
+	Labels                map[string]string          `json:"labels,omitempty"`
 }
 
 // EncryptedMnemonic represents an encrypted mnemonic
@@ -59,7 +144,8 @@ type EncryptedMnemonic struct {
 // File holds all the data related to the wallet keys
 type File struct {
 	Version               uint32
-	NumThreads            uint8 // This field is ignored for versions different than 0
+	NumThreads            uint8      // This field is ignored for versions different than 0
+	KDF                   *KDFParams // This field is only used from version 2. If nil, DefaultKDFParams are used
 	EncryptedMnemonics    []*EncryptedMnemonic
 	ExtendedPublicKeys    []string
 	MinimumSignatures     uint32
@@ -67,6 +153,7 @@ type File struct {
 	lastUsedExternalIndex uint32
 	lastUsedInternalIndex uint32
 	ECDSA                 bool
+	labels                map[string]string
 	path                  string
 }
 
@@ -79,9 +166,15 @@ func (d *File) toJSON() *keysFileJSON {
 		}
 	}
 
+	var kdfJSON *kdfParamsJSON
+	if d.Version >= 2 {
+		kdfJSON = d.versionKDFParams().toJSON()
+	}
+
 	return &keysFileJSON{
 		Version:               d.Version,
 		NumThreads:            d.NumThreads,
+		KDF:                   kdfJSON,
 		EncryptedPrivateKeys:  encryptedPrivateKeysJSON,
 		ExtendedPublicKeys:    d.ExtendedPublicKeys,
 		MinimumSignatures:     d.MinimumSignatures,
@@ -89,6 +182,7 @@ func (d *File) toJSON() *keysFileJSON {
 		CosignerIndex:         d.CosignerIndex,
 		LastUsedExternalIndex: d.lastUsedExternalIndex,
 		LastUsedInternalIndex: d.lastUsedInternalIndex,
+		Labels:                d.labels,
 	}
 }
 
@@ -102,6 +196,7 @@ func NewFileFromMnemonic(params *dagconfig.Params, mnemonic string, password str
 	return &File{
 		Version:            LastVersion,
 		NumThreads:         defaultNumThreads,
+		KDF:                DefaultKDFParams(),
 		EncryptedMnemonics: encryptedMnemonics,
 		ExtendedPublicKeys: extendedPublicKeys,
 		MinimumSignatures:  1,
@@ -118,6 +213,18 @@ func (d *File) fromJSON(fileJSON *keysFileJSON) error {
 	d.CosignerIndex = fileJSON.CosignerIndex
 	d.lastUsedExternalIndex = fileJSON.LastUsedExternalIndex
 	d.lastUsedInternalIndex = fileJSON.LastUsedInternalIndex
+	d.labels = fileJSON.Labels
+
+	if d.Version >= 2 {
+		if fileJSON.KDF == nil {
+			return errors.Errorf("keys file of version %d is missing its KDF parameters", d.Version)
+		}
+		var err error
+		d.KDF, err = fileJSON.KDF.toKDFParams()
+		if err != nil {
+			return err
+		}
+	}
 
 	d.EncryptedMnemonics = make([]*EncryptedMnemonic, len(fileJSON.EncryptedPrivateKeys))
 	for i, encryptedPrivateKeyJSON := range fileJSON.EncryptedPrivateKeys {
@@ -206,15 +313,43 @@ func (d *File) LastUsedInternalIndex() uint32 {
 	return d.lastUsedInternalIndex
 }
 
+// Label returns the label of the given address, or an empty string if it has none
+func (d *File) Label(address string) string {
+	return d.labels[address]
+}
+
+// Labels returns all the address labels of the wallet, keyed by address
+func (d *File) Labels() map[string]string {
+	labels := make(map[string]string, len(d.labels))
+	for address, label := range d.labels {
+		labels[address] = label
+	}
+	return labels
+}
+
+// SetLabel sets the label of the given address, and saves the file.
+// An empty label removes the address label.
+func (d *File) SetLabel(address string, label string) error {
+	if label == "" {
+		delete(d.labels, address)
+	} else {
+		if d.labels == nil {
+			d.labels = make(map[string]string)
+		}
+		d.labels[address] = label
+	}
+	return d.Save()
+}
+
 // DecryptMnemonics asks the user to enter the password for the private keys and
 // returns the decrypted private keys.
 func (d *File) DecryptMnemonics(password string) ([]string, error) {
 	passwordBytes := []byte(password)
 
-	var numThreads uint8
+	var kdfParams *KDFParams
 	if len(d.EncryptedMnemonics) > 0 {
 		var err error
-		numThreads, err = d.numThreads(passwordBytes)
+		kdfParams, err = d.kdfParams(passwordBytes)
 		if err != nil {
 			return nil, err
 		}
@@ -223,7 +358,7 @@ func (d *File) DecryptMnemonics(password string) ([]string, error) {
 	privateKeys := make([]string, len(d.EncryptedMnemonics))
 	for i, encryptedPrivateKey := range d.EncryptedMnemonics {
 		var err error
-		privateKeys[i], err = decryptMnemonic(numThreads, encryptedPrivateKey, passwordBytes)
+		privateKeys[i], err = decryptMnemonic(kdfParams, encryptedPrivateKey, passwordBytes)
 		if err != nil {
 			return nil, err
 		}
@@ -232,6 +367,41 @@ func (d *File) DecryptMnemonics(password string) ([]string, error) {
 	return privateKeys, nil
 }
 
+// ChangePassword re-encrypts the mnemonics, which are currently encrypted with oldPassword, with
+// newPassword, using the given KDF parameters. If kdfParams is nil, the current KDF parameters
+// of the file are kept, unless they're weaker than DefaultKDFParams.
+// The file is upgraded to LastVersion and saved.
+func (d *File) ChangePassword(oldPassword, newPassword string, kdfParams *KDFParams) error {
+	mnemonics, err := d.DecryptMnemonics(oldPassword)
+	if err != nil {
+		return err
+	}
+
+	if kdfParams == nil {
+		kdfParams = d.versionKDFParams()
+		if kdfParams.Validate() != nil {
+			kdfParams = DefaultKDFParams()
+		}
+	}
+	err = kdfParams.Validate()
+	if err != nil {
+		return err
+	}
+
+	encryptedMnemonics := make([]*EncryptedMnemonic, len(mnemonics))
+	for i, mnemonic := range mnemonics {
+		encryptedMnemonics[i], err = encryptMnemonic(mnemonic, []byte(newPassword), kdfParams)
+		if err != nil {
+			return err
+		}
+	}
+
+	d.EncryptedMnemonics = encryptedMnemonics
+	d.Version = LastVersion
+	d.KDF = kdfParams
+	return d.Save()
+}
+
 // ReadKeysFile returns the data related to the keys file
 func ReadKeysFile(netParams *dagconfig.Params, path string) (*File, error) {
 	if path == "" {
@@ -302,23 +472,69 @@ func (d *File) Save() error {
 		return err
 	}
 
-	file, err := os.OpenFile(d.path, os.O_WRONLY|os.O_CREATE, 0600)
+	return writeFileAtomically(d.path, d.toJSON())
+}
+
+// writeFileAtomically encodes the given value as JSON into a temporary file and then renames it
+// to path, so that a crash in the middle of writing never leaves a partially written file behind.
+func writeFileAtomically(path string, value interface{}) error {
+	tempPath := path + ".tmp"
+	file, err := os.OpenFile(tempPath, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0600)
 	if err != nil {
 		return err
 	}
-	defer file.Close()
 
 	encoder := json.NewEncoder(file)
-	err = encoder.Encode(d.toJSON())
+	err = encoder.Encode(value)
 	if err != nil {
+		file.Close()
 		return err
 	}
 
-	return nil
+	err = file.Sync()
+	if err != nil {
+		file.Close()
+		return err
+	}
+
+	err = file.Close()
+	if err != nil {
+		return err
+	}
+
+	return os.Rename(tempPath, path)
 }
 
 const defaultNumThreads = 8
 
+// kdfParams returns the KDF parameters the mnemonics of the file are encrypted with
+func (d *File) kdfParams(password []byte) (*KDFParams, error) {
+	if d.Version != 0 {
+		return d.versionKDFParams(), nil
+	}
+
+	numThreads, err := d.numThreads(password)
+	if err != nil {
+		return nil, err
+	}
+	kdfParams := DefaultKDFParams()
+	kdfParams.Threads = numThreads
+	return kdfParams, nil
+}
+
+// versionKDFParams returns the KDF parameters of the file without trying to detect them,
+// which is required only for version 0 files.
+func (d *File) versionKDFParams() *KDFParams {
+	if d.Version >= 2 && d.KDF != nil {
+		return d.KDF
+	}
+	kdfParams := DefaultKDFParams()
+	if d.Version == 0 && d.NumThreads != 0 {
+		kdfParams.Threads = d.NumThreads
+	}
+	return kdfParams
+}
+
 func (d *File) numThreads(password []byte) (uint8, error) {
 	// There's a bug in v0 wallets where the number of threads
 	// was determined by the number of logical CPUs at the machine,
@@ -350,7 +566,7 @@ func (d *File) detectNumThreads(password []byte, encryptedMnemonic *EncryptedMne
 	if d.NumThreads == 0 {
 		firstGuessNumThreads = uint8(runtime.NumCPU())
 	}
-	_, err := decryptMnemonic(firstGuessNumThreads, encryptedMnemonic, password)
+	_, err := decryptMnemonic(v0KDFParams(firstGuessNumThreads), encryptedMnemonic, password)
 	if err != nil {
 		if !strings.Contains(err.Error(), "message authentication failed") {
 			return 0, err
@@ -364,7 +580,7 @@ func (d *File) detectNumThreads(password []byte, encryptedMnemonic *EncryptedMne
 			continue
 		}
 
-		_, err := decryptMnemonic(numThreadsGuess, encryptedMnemonic, password)
+		_, err := decryptMnemonic(v0KDFParams(numThreadsGuess), encryptedMnemonic, password)
 		if err != nil {
 			const maxTries = 255
 			if numThreadsGuess == maxTries || !strings.Contains(err.Error(), "message authentication failed") {
@@ -376,13 +592,19 @@ func (d *File) detectNumThreads(password []byte, encryptedMnemonic *EncryptedMne
 	}
 }
 
-func getAEAD(threads uint8, password, salt []byte) (cipher.AEAD, error) {
-	key := argon2.IDKey(password, salt, 1, 64*1024, threads, 32)
+func v0KDFParams(numThreads uint8) *KDFParams {
+	kdfParams := DefaultKDFParams()
+	kdfParams.Threads = numThreads
+	return kdfParams
+}
+
+func getAEAD(kdfParams *KDFParams, password, salt []byte) (cipher.AEAD, error) {
+	key := argon2.IDKey(password, salt, kdfParams.Time, kdfParams.Memory, kdfParams.Threads, 32)
 	return chacha20poly1305.NewX(key)
 }
 
-func decryptMnemonic(numThreads uint8, encryptedPrivateKey *EncryptedMnemonic, password []byte) (string, error) {
-	aead, err := getAEAD(numThreads, password, encryptedPrivateKey.salt)
+func decryptMnemonic(kdfParams *KDFParams, encryptedPrivateKey *EncryptedMnemonic, password []byte) (string, error) {
+	aead, err := getAEAD(kdfParams, password, encryptedPrivateKey.salt)
 	if err != nil {
 		return "", err
 	}
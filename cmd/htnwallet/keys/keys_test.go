@@ -0,0 +1,189 @@
+package keys
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"testing"
+
+	"github.com/Hoosat-Oy/HTND/cmd/htnwallet/libhtnwallet"
+	"github.com/Hoosat-Oy/HTND/domain/dagconfig"
+)
+
+func newTestFile(t *testing.T, params *dagconfig.Params, password string) (*File, string) {
+	mnemonic, err := libhtnwallet.CreateMnemonic()
+	if err != nil {
+		t.Fatalf("CreateMnemonic: %+v", err)
+	}
+
+	file, err := NewFileFromMnemonic(params, mnemonic, password)
+	if err != nil {
+		t.Fatalf("NewFileFromMnemonic: %+v", err)
+	}
+
+	err = file.SetPath(params, filepath.Join(t.TempDir(), "keys.json"), false)
+	if err != nil {
+		t.Fatalf("SetPath: %+v", err)
+	}
+	err = file.Save()
+	if err != nil {
+		t.Fatalf("Save: %+v", err)
+	}
+
+	return file, mnemonic
+}
+
+func TestChangePassword(t *testing.T) {
+	params := &dagconfig.MainnetParams
+	file, mnemonic := newTestFile(t, params, "old password")
+
+	kdfParams := &KDFParams{Time: 2, Memory: 128 * 1024, Threads: 4}
+	err := file.ChangePassword("wrong password", "new password", kdfParams)
+	if err == nil {
+		t.Fatalf("ChangePassword unexpectedly succeeded with a wrong password")
+	}
+
+	err = file.ChangePassword("old password", "new password", kdfParams)
+	if err != nil {
+		t.Fatalf("ChangePassword: %+v", err)
+	}
+
+	readFile, err := ReadKeysFile(params, file.Path())
+	if err != nil {
+		t.Fatalf("ReadKeysFile: %+v", err)
+	}
+	if readFile.Version != LastVersion {
+		t.Fatalf("Unexpected version. Want: %d, got: %d", LastVersion, readFile.Version)
+	}
+	if !reflect.DeepEqual(readFile.KDF, kdfParams) {
+		t.Fatalf("Unexpected KDF parameters. Want: %+v, got: %+v", kdfParams, readFile.KDF)
+	}
+
+	_, err = readFile.DecryptMnemonics("old password")
+	if err == nil {
+		t.Fatalf("DecryptMnemonics unexpectedly succeeded with the old password")
+	}
+	mnemonics, err := readFile.DecryptMnemonics("new password")
+	if err != nil {
+		t.Fatalf("DecryptMnemonics: %+v", err)
+	}
+	if len(mnemonics) != 1 || mnemonics[0] != mnemonic {
+		t.Fatalf("Unexpected decrypted mnemonics. Want: [%s], got: %s", mnemonic, mnemonics)
+	}
+}
+
+func TestBackup(t *testing.T) {
+	params := &dagconfig.TestnetParams
+	file, mnemonic := newTestFile(t, params, "password")
+
+	err := file.SetLastUsedExternalIndex(7)
+	if err != nil {
+		t.Fatalf("SetLastUsedExternalIndex: %+v", err)
+	}
+	err = file.SetLabel("hoosattest:qq0d6h0prjm5mpdld5pncst3adu0yam6xch4tr69k2", "savings")
+	if err != nil {
+		t.Fatalf("SetLabel: %+v", err)
+	}
+
+	walletBackup, err := file.Backup(params, "password")
+	if err != nil {
+		t.Fatalf("Backup: %+v", err)
+	}
+
+	backupPath := filepath.Join(t.TempDir(), "backup.json")
+	err = WriteBackupFile(backupPath, walletBackup, "backup password", DefaultKDFParams())
+	if err != nil {
+		t.Fatalf("WriteBackupFile: %+v", err)
+	}
+
+	_, err = ReadBackupFile(backupPath, "password")
+	if err == nil {
+		t.Fatalf("ReadBackupFile unexpectedly succeeded with a wrong password")
+	}
+
+	readBackup, err := ReadBackupFile(backupPath, "backup password")
+	if err != nil {
+		t.Fatalf("ReadBackupFile: %+v", err)
+	}
+	if !reflect.DeepEqual(readBackup, walletBackup) {
+		t.Fatalf("Unexpected backup. Want: %+v, got: %+v", walletBackup, readBackup)
+	}
+
+	restoredFile, err := NewFileFromBackup(readBackup, "restored password", DefaultKDFParams())
+	if err != nil {
+		t.Fatalf("NewFileFromBackup: %+v", err)
+	}
+	if restoredFile.LastUsedExternalIndex() != 7 {
+		t.Fatalf("Unexpected last used external index. Want: 7, got: %d", restoredFile.LastUsedExternalIndex())
+	}
+	if !reflect.DeepEqual(restoredFile.Labels(), file.Labels()) {
+		t.Fatalf("Unexpected labels. Want: %+v, got: %+v", file.Labels(), restoredFile.Labels())
+	}
+	mnemonics, err := restoredFile.DecryptMnemonics("restored password")
+	if err != nil {
+		t.Fatalf("DecryptMnemonics: %+v", err)
+	}
+	if len(mnemonics) != 1 || mnemonics[0] != mnemonic {
+		t.Fatalf("Unexpected decrypted mnemonics. Want: [%s], got: %s", mnemonic, mnemonics)
+	}
+}
+
+func TestKDFParamsValidate(t *testing.T) {
+	tests := []struct {
+		name      string
+		kdfParams *KDFParams
+		isValid   bool
+	}{
+		{name: "default", kdfParams: DefaultKDFParams(), isValid: true},
+		{name: "maximum", kdfParams: &KDFParams{Time: maxKDFTime, Memory: maxKDFMemory, Threads: 255}, isValid: true},
+		{name: "time too low", kdfParams: &KDFParams{Time: 0, Memory: 64 * 1024, Threads: 1}},
+		{name: "time too high", kdfParams: &KDFParams{Time: maxKDFTime + 1, Memory: 64 * 1024, Threads: 1}},
+		{name: "memory too low", kdfParams: &KDFParams{Time: 1, Memory: 1024, Threads: 1}},
+		{name: "memory too high", kdfParams: &KDFParams{Time: 1, Memory: maxKDFMemory + 1, Threads: 1}},
+		{name: "no threads", kdfParams: &KDFParams{Time: 1, Memory: 64 * 1024, Threads: 0}},
+	}
+	for _, test := range tests {
+		err := test.kdfParams.Validate()
+		if test.isValid && err != nil {
+			t.Fatalf("%s: Validate: %+v", test.name, err)
+		}
+		if !test.isValid && err == nil {
+			t.Fatalf("%s: Validate unexpectedly succeeded", test.name)
+		}
+	}
+}
+
+func TestReadKeysFileWithOutOfRangeKDFParams(t *testing.T) {
+	params := &dagconfig.TestnetParams
+	file, _ := newTestFile(t, params, "password")
+
+	tests := []struct {
+		name string
+		kdf  string
+	}{
+		{name: "time", kdf: `{"algorithm":"argon2id","time":4294967295,"memory":65536,"threads":8}`},
+		{name: "memory", kdf: `{"algorithm":"argon2id","time":1,"memory":4294967295,"threads":8}`},
+		{name: "threads", kdf: `{"algorithm":"argon2id","time":1,"memory":65536,"threads":65535}`},
+	}
+	for _, test := range tests {
+		content, err := os.ReadFile(file.Path())
+		if err != nil {
+			t.Fatalf("ReadFile: %+v", err)
+		}
+		kdfRegexp := regexp.MustCompile(`"kdf":\{[^}]*\}`)
+		if !kdfRegexp.Match(content) {
+			t.Fatalf("The keys file has no KDF parameters")
+		}
+		path := filepath.Join(t.TempDir(), "keys.json")
+		err = os.WriteFile(path, kdfRegexp.ReplaceAll(content, []byte(`"kdf":`+test.kdf)), 0600)
+		if err != nil {
+			t.Fatalf("WriteFile: %+v", err)
+		}
+
+		_, err = ReadKeysFile(params, path)
+		if err == nil {
+			t.Fatalf("%s: ReadKeysFile unexpectedly succeeded with out of range KDF parameters", test.name)
+		}
+	}
+}
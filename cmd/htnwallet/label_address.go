@@ -0,0 +1,39 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/Hoosat-Oy/HTND/cmd/htnwallet/keys"
+	"github.com/Hoosat-Oy/HTND/util"
+)
+
+func labelAddress(conf *labelAddressConfig) error {
+	_, err := util.DecodeAddress(conf.Address, conf.NetParams().Prefix)
+	if err != nil {
+		return err
+	}
+
+	keysFile, err := keys.ReadKeysFile(conf.NetParams(), conf.KeysFile)
+	if err != nil {
+		return err
+	}
+
+	// The lock makes sure no wallet daemon is running with this keys file, since it
+	// would overwrite the label the next time it saves the file
+	err = keysFile.TryLock()
+	if err != nil {
+		return err
+	}
+
+	err = keysFile.SetLabel(conf.Address, conf.Label)
+	if err != nil {
+		return err
+	}
+
+	if conf.Label == "" {
+		fmt.Printf("Removed the label of %s\n", conf.Address)
+	} else {
+		fmt.Printf("Labeled %s as '%s'\n", conf.Address, conf.Label)
+	}
+	return nil
+}
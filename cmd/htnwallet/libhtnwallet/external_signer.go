@@ -0,0 +1,381 @@
+package libhtnwallet
+
+import (
+	"encoding/hex"
+
+	"github.com/Hoosat-Oy/HTND/cmd/htnwallet/libhtnwallet/bip32"
+	"github.com/Hoosat-Oy/HTND/cmd/htnwallet/libhtnwallet/serialization"
+	"github.com/Hoosat-Oy/HTND/domain/consensus/model/externalapi"
+	"github.com/Hoosat-Oy/HTND/domain/consensus/utils/consensushashing"
+	"github.com/Hoosat-Oy/HTND/domain/consensus/utils/subnetworks"
+	"github.com/Hoosat-Oy/HTND/domain/consensus/utils/txscript"
+	"github.com/Hoosat-Oy/HTND/domain/consensus/utils/utxo"
+	"github.com/Hoosat-Oy/HTND/domain/dagconfig"
+	"github.com/kaspanet/go-secp256k1"
+	"github.com/pkg/errors"
+)
+
+// ExternalSignerProtocolVersion is the version of the external signer protocol
+// that is spoken by this version of libhtnwallet
+const ExternalSignerProtocolVersion = 1
+
+// ExternalSigner is a signer that holds the wallet keys outside of the wallet process,
+// such as a hardware wallet, an HSM or a process running on an air-gapped machine
+type ExternalSigner interface {
+	SignTransaction(request *ExternalSignRequest) (*ExternalSignResponse, error)
+}
+
+// ExternalSignRequest asks an external signer to sign a transaction. The transaction is
+// fully described, so that the signer can show it to the user and recalculate the
+// signature hashes by itself instead of trusting the wallet.
+type ExternalSignRequest struct {
+	Version     uint32                     `json:"version"`
+	Network     string                     `json:"network"`
+	ECDSA       bool                       `json:"ecdsa"`
+	Transaction *ExternalSignerTransaction `json:"transaction"`
+}
+
+// ExternalSignerTransaction is the description of a transaction that is sent to an external signer
+type ExternalSignerTransaction struct {
+	Version      uint16                  `json:"version"`
+	Inputs       []*ExternalSignerInput  `json:"inputs"`
+	Outputs      []*ExternalSignerOutput `json:"outputs"`
+	LockTime     uint64                  `json:"lockTime"`
+	SubnetworkID string                  `json:"subnetworkId"`
+	Gas          uint64                  `json:"gas"`
+	Payload      string                  `json:"payload"`
+	Fee          uint64                  `json:"fee"`
+}
+
+// ExternalSignerInput is the description of a transaction input that is sent to an external signer.
+// SigHash is the hash that each of the keys in PublicKeys that hasn't signed yet is expected to sign.
+type ExternalSignerInput struct {
+	PreviousTransactionID  string                       `json:"previousTransactionId"`
+	PreviousIndex          uint32                       `json:"previousIndex"`
+	Sequence               uint64                       `json:"sequence"`
+	SigOpCount             byte                         `json:"sigOpCount"`
+	Amount                 uint64                       `json:"amount"`
+	ScriptPublicKey        string                       `json:"scriptPublicKey"`
+	ScriptPublicKeyVersion uint16                       `json:"scriptPublicKeyVersion"`
+	DerivationPath         string                       `json:"derivationPath"`
+	MinimumSignatures      uint32                       `json:"minimumSignatures"`
+	PublicKeys             []*ExternalSignerPublicKey   `json:"publicKeys"`
+	SigHashType            consensushashing.SigHashType `json:"sigHashType"`
+	SigHash                string                       `json:"sigHash"`
+}
+
+// ExternalSignerPublicKey is one of the extended public keys that can sign an input. The key
+// is already derived with the derivation path of the input.
+type ExternalSignerPublicKey struct {
+	ExtendedPublicKey string `json:"extendedPublicKey"`
+	IsSigned          bool   `json:"isSigned"`
+}
+
+// ExternalSignerOutput is the description of a transaction output that is sent to an external signer.
+// Address is empty if the output script is not of a standard type.
+type ExternalSignerOutput struct {
+	Amount                 uint64 `json:"amount"`
+	ScriptPublicKey        string `json:"scriptPublicKey"`
+	ScriptPublicKeyVersion uint16 `json:"scriptPublicKeyVersion"`
+	Address                string `json:"address"`
+}
+
+// ExternalSignResponse is the response of an external signer to an ExternalSignRequest.
+// If Error is not empty the signer refused or failed to sign the transaction.
+type ExternalSignResponse struct {
+	Version    uint32                     `json:"version"`
+	Signatures []*ExternalSignerSignature `json:"signatures"`
+	Error      string                     `json:"error,omitempty"`
+}
+
+// ExternalSignerSignature is a signature over the SigHash of input InputIndex, made by the private
+// key of ExtendedPublicKey. The signature is serialized without the sighash type.
+type ExternalSignerSignature struct {
+	InputIndex        uint32 `json:"inputIndex"`
+	ExtendedPublicKey string `json:"extendedPublicKey"`
+	Signature         string `json:"signature"`
+}
+
+// SignWithExternalSigner signs the transaction using the given external signer. The signatures
+// returned by the signer are verified before they're added to the transaction.
+func SignWithExternalSigner(params *dagconfig.Params, signer ExternalSigner, serializedPSTx []byte, ecdsa bool) ([]byte, error) {
+	partiallySignedTransaction, err := serialization.DeserializePartiallySignedTransaction(serializedPSTx)
+	if err != nil {
+		return nil, err
+	}
+
+	if isTransactionFullySigned(partiallySignedTransaction) {
+		return serializedPSTx, nil
+	}
+
+	request, err := NewExternalSignRequest(params, partiallySignedTransaction, ecdsa)
+	if err != nil {
+		return nil, err
+	}
+
+	response, err := signer.SignTransaction(request)
+	if err != nil {
+		return nil, err
+	}
+	if response.Error != "" {
+		return nil, errors.Errorf("external signer failed to sign the transaction: %s", response.Error)
+	}
+	if response.Version != ExternalSignerProtocolVersion {
+		return nil, errors.Errorf("external signer responded with protocol version %d, while version %d is expected",
+			response.Version, ExternalSignerProtocolVersion)
+	}
+	if len(response.Signatures) == 0 {
+		return nil, errors.Errorf("external signer didn't return any signature")
+	}
+
+	for _, signature := range response.Signatures {
+		err := addExternalSignature(partiallySignedTransaction, request, signature, ecdsa)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return serialization.SerializePartiallySignedTransaction(partiallySignedTransaction)
+}
+
+// NewExternalSignRequest creates an ExternalSignRequest that describes the given partially signed transaction
+func NewExternalSignRequest(params *dagconfig.Params, partiallySignedTransaction *serialization.PartiallySignedTransaction,
+	ecdsa bool) (*ExternalSignRequest, error) {
+
+	err := populateUTXOEntries(partiallySignedTransaction)
+	if err != nil {
+		return nil, err
+	}
+
+	tx := partiallySignedTransaction.Tx
+	transaction := &ExternalSignerTransaction{
+		Version:      tx.Version,
+		Inputs:       make([]*ExternalSignerInput, len(tx.Inputs)),
+		Outputs:      make([]*ExternalSignerOutput, len(tx.Outputs)),
+		LockTime:     tx.LockTime,
+		SubnetworkID: tx.SubnetworkID.String(),
+		Gas:          tx.Gas,
+		Payload:      hex.EncodeToString(tx.Payload),
+		Fee:          tx.Fee,
+	}
+
+	sighashReusedValues := &consensushashing.SighashReusedValues{}
+	for i, partiallySignedInput := range partiallySignedTransaction.PartiallySignedInputs {
+		input := tx.Inputs[i]
+		sigHash, err := calculateSignatureHash(tx, i, consensushashing.SigHashAll, sighashReusedValues, ecdsa)
+		if err != nil {
+			return nil, err
+		}
+
+		publicKeys := make([]*ExternalSignerPublicKey, len(partiallySignedInput.PubKeySignaturePairs))
+		for j, pair := range partiallySignedInput.PubKeySignaturePairs {
+			publicKeys[j] = &ExternalSignerPublicKey{
+				ExtendedPublicKey: pair.ExtendedPublicKey,
+				IsSigned:          pair.Signature != nil,
+			}
+		}
+
+		transaction.Inputs[i] = &ExternalSignerInput{
+			PreviousTransactionID:  input.PreviousOutpoint.TransactionID.String(),
+			PreviousIndex:          input.PreviousOutpoint.Index,
+			Sequence:               input.Sequence,
+			SigOpCount:             input.SigOpCount,
+			Amount:                 partiallySignedInput.PrevOutput.Value,
+			ScriptPublicKey:        hex.EncodeToString(partiallySignedInput.PrevOutput.ScriptPublicKey.Script),
+			ScriptPublicKeyVersion: partiallySignedInput.PrevOutput.ScriptPublicKey.Version,
+			DerivationPath:         partiallySignedInput.DerivationPath,
+			MinimumSignatures:      partiallySignedInput.MinimumSignatures,
+			PublicKeys:             publicKeys,
+			SigHashType:            consensushashing.SigHashAll,
+			SigHash:                sigHash.String(),
+		}
+	}
+
+	for i, output := range tx.Outputs {
+		var address string
+		_, outputAddress, err := txscript.ExtractScriptPubKeyAddress(output.ScriptPublicKey, params)
+		if err == nil && outputAddress != nil {
+			address = outputAddress.String()
+		}
+
+		transaction.Outputs[i] = &ExternalSignerOutput{
+			Amount:                 output.Value,
+			ScriptPublicKey:        hex.EncodeToString(output.ScriptPublicKey.Script),
+			ScriptPublicKeyVersion: output.ScriptPublicKey.Version,
+			Address:                address,
+		}
+	}
+
+	return &ExternalSignRequest{
+		Version:     ExternalSignerProtocolVersion,
+		Network:     params.Name,
+		ECDSA:       ecdsa,
+		Transaction: transaction,
+	}, nil
+}
+
+// DomainTransaction rebuilds the described transaction, with its inputs' UTXO entries populated
+// so that signature hashes can be calculated over it
+func (est *ExternalSignerTransaction) DomainTransaction() (*externalapi.DomainTransaction, error) {
+	subnetworkID, err := subnetworks.FromString(est.SubnetworkID)
+	if err != nil {
+		return nil, err
+	}
+
+	payload, err := hex.DecodeString(est.Payload)
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid transaction payload")
+	}
+
+	tx := &externalapi.DomainTransaction{
+		Version:      est.Version,
+		Inputs:       make([]*externalapi.DomainTransactionInput, len(est.Inputs)),
+		Outputs:      make([]*externalapi.DomainTransactionOutput, len(est.Outputs)),
+		LockTime:     est.LockTime,
+		SubnetworkID: *subnetworkID,
+		Gas:          est.Gas,
+		Payload:      payload,
+		Fee:          est.Fee,
+	}
+
+	for i, input := range est.Inputs {
+		previousTransactionID, err := externalapi.NewDomainTransactionIDFromString(input.PreviousTransactionID)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid previous transaction ID of input %d", i)
+		}
+		script, err := hex.DecodeString(input.ScriptPublicKey)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid script public key of input %d", i)
+		}
+
+		tx.Inputs[i] = &externalapi.DomainTransactionInput{
+			PreviousOutpoint: externalapi.DomainOutpoint{
+				TransactionID: *previousTransactionID,
+				Index:         input.PreviousIndex,
+			},
+			Sequence:   input.Sequence,
+			SigOpCount: input.SigOpCount,
+			UTXOEntry: utxo.NewUTXOEntry(
+				input.Amount,
+				&externalapi.ScriptPublicKey{Script: script, Version: input.ScriptPublicKeyVersion},
+				false, // This is a fake value, because it's irrelevant for the signature
+				0,     // This is a fake value, because it's irrelevant for the signature
+			),
+		}
+	}
+
+	for i, output := range est.Outputs {
+		script, err := hex.DecodeString(output.ScriptPublicKey)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid script public key of output %d", i)
+		}
+
+		tx.Outputs[i] = &externalapi.DomainTransactionOutput{
+			Value:           output.Amount,
+			ScriptPublicKey: &externalapi.ScriptPublicKey{Script: script, Version: output.ScriptPublicKeyVersion},
+		}
+	}
+
+	return tx, nil
+}
+
+func populateUTXOEntries(partiallySignedTransaction *serialization.PartiallySignedTransaction) error {
+	for i, partiallySignedInput := range partiallySignedTransaction.PartiallySignedInputs {
+		prevOut := partiallySignedInput.PrevOutput
+		partiallySignedTransaction.Tx.Inputs[i].UTXOEntry = utxo.NewUTXOEntry(
+			prevOut.Value,
+			prevOut.ScriptPublicKey,
+			false, // This is a fake value, because it's irrelevant for the signature
+			0,     // This is a fake value, because it's irrelevant for the signature
+		)
+		sigOpCount, err := PartiallySignedInputSigOpCount(partiallySignedInput)
+		if err != nil {
+			return err
+		}
+		partiallySignedTransaction.Tx.Inputs[i].SigOpCount = sigOpCount
+	}
+	return nil
+}
+
+func calculateSignatureHash(tx *externalapi.DomainTransaction, inputIndex int, hashType consensushashing.SigHashType,
+	sighashReusedValues *consensushashing.SighashReusedValues, ecdsa bool) (*externalapi.DomainHash, error) {
+
+	if ecdsa {
+		return consensushashing.CalculateSignatureHashECDSA(tx, inputIndex, hashType, sighashReusedValues)
+	}
+	return consensushashing.CalculateSignatureHashSchnorr(tx, inputIndex, hashType, sighashReusedValues)
+}
+
+func addExternalSignature(partiallySignedTransaction *serialization.PartiallySignedTransaction,
+	request *ExternalSignRequest, signature *ExternalSignerSignature, ecdsa bool) error {
+
+	if int(signature.InputIndex) >= len(partiallySignedTransaction.PartiallySignedInputs) {
+		return errors.Errorf("external signer returned a signature for input %d, while the transaction has only %d inputs",
+			signature.InputIndex, len(partiallySignedTransaction.PartiallySignedInputs))
+	}
+	partiallySignedInput := partiallySignedTransaction.PartiallySignedInputs[signature.InputIndex]
+	requestInput := request.Transaction.Inputs[signature.InputIndex]
+
+	var pair *serialization.PubKeySignaturePair
+	for _, candidate := range partiallySignedInput.PubKeySignaturePairs {
+		if candidate.ExtendedPublicKey == signature.ExtendedPublicKey {
+			pair = candidate
+			break
+		}
+	}
+	if pair == nil {
+		return errors.Errorf("external signer returned a signature of input %d for the unknown public key %s",
+			signature.InputIndex, signature.ExtendedPublicKey)
+	}
+
+	serializedSignature, err := hex.DecodeString(signature.Signature)
+	if err != nil {
+		return errors.Wrapf(err, "external signer returned a malformed signature for input %d", signature.InputIndex)
+	}
+
+	sigHash, err := externalapi.NewDomainHashFromString(requestInput.SigHash)
+	if err != nil {
+		return err
+	}
+
+	extendedKey, err := bip32.DeserializeExtendedKey(pair.ExtendedPublicKey)
+	if err != nil {
+		return err
+	}
+	publicKey, err := extendedKey.PublicKey()
+	if err != nil {
+		return err
+	}
+
+	isValid, err := verifySignature(publicKey, sigHash, serializedSignature, ecdsa)
+	if err != nil {
+		return errors.Wrapf(err, "external signer returned a malformed signature for input %d", signature.InputIndex)
+	}
+	if !isValid {
+		return errors.Errorf("external signer returned an invalid signature for input %d", signature.InputIndex)
+	}
+
+	pair.Signature = append(serializedSignature, byte(requestInput.SigHashType))
+	return nil
+}
+
+func verifySignature(publicKey *secp256k1.ECDSAPublicKey, hash *externalapi.DomainHash, signature []byte, ecdsa bool) (bool, error) {
+	secpHash := secp256k1.Hash(*hash.ByteArray())
+	if ecdsa {
+		ecdsaSignature, err := secp256k1.DeserializeECDSASignatureFromSlice(signature)
+		if err != nil {
+			return false, err
+		}
+		return publicKey.ECDSAVerify(&secpHash, ecdsaSignature), nil
+	}
+
+	schnorrPublicKey, err := publicKey.ToSchnorr()
+	if err != nil {
+		return false, err
+	}
+	schnorrSignature, err := secp256k1.DeserializeSchnorrSignatureFromSlice(signature)
+	if err != nil {
+		return false, err
+	}
+	return schnorrPublicKey.SchnorrVerify(&secpHash, schnorrSignature), nil
+}
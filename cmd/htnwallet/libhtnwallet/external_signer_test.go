@@ -0,0 +1,306 @@
+package libhtnwallet_test
+
+import (
+	"encoding/hex"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/Hoosat-Oy/HTND/cmd/htnwallet/libhtnwallet"
+	"github.com/Hoosat-Oy/HTND/cmd/htnwallet/libhtnwallet/serialization"
+	"github.com/Hoosat-Oy/HTND/domain/consensus"
+	"github.com/Hoosat-Oy/HTND/domain/consensus/model/externalapi"
+	"github.com/Hoosat-Oy/HTND/domain/consensus/utils/consensushashing"
+	"github.com/Hoosat-Oy/HTND/domain/consensus/utils/testutils"
+	"github.com/Hoosat-Oy/HTND/domain/consensus/utils/txscript"
+	"github.com/Hoosat-Oy/HTND/domain/consensus/utils/utxo"
+	"github.com/Hoosat-Oy/HTND/domain/dagconfig"
+)
+
+const (
+	signerProcessMnemonicEnv = "HTNWALLET_TEST_SIGNER_MNEMONIC"
+	signerProcessNetworkEnv  = "HTNWALLET_TEST_SIGNER_NETWORK"
+	signerProcessECDSAEnv    = "HTNWALLET_TEST_SIGNER_ECDSA"
+)
+
+// TestExternalSignerProcess isn't a real test. It's the reference signer process that is
+// run by TestExternalSigner, which serves a single request over stdin/stdout.
+func TestExternalSignerProcess(t *testing.T) {
+	mnemonic := os.Getenv(signerProcessMnemonicEnv)
+	if mnemonic == "" {
+		return
+	}
+
+	var params *dagconfig.Params
+	for _, netParams := range []*dagconfig.Params{&dagconfig.MainnetParams, &dagconfig.TestnetParams,
+		&dagconfig.DevnetParams, &dagconfig.SimnetParams} {
+
+		if netParams.Name == os.Getenv(signerProcessNetworkEnv) {
+			params = netParams
+		}
+	}
+	ecdsa, err := strconv.ParseBool(os.Getenv(signerProcessECDSAEnv))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "ParseBool: %+v", err)
+		os.Exit(1)
+	}
+
+	signer := libhtnwallet.NewSoftwareSigner(params, []string{mnemonic}, ecdsa)
+	err = libhtnwallet.ServeExternalSigner(signer, os.Stdin, os.Stdout)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "ServeExternalSigner: %+v", err)
+		os.Exit(1)
+	}
+	os.Exit(0)
+}
+
+func TestExternalSigner(t *testing.T) {
+	testutils.ForAllNets(t, true, func(t *testing.T, consensusConfig *consensus.Config) {
+		params := &consensusConfig.Params
+		forSchnorrAndECDSA(t, func(t *testing.T, ecdsa bool) {
+			consensusConfig.BlockCoinbaseMaturity = 0
+			tc, teardown, err := consensus.NewFactory().NewTestConsensus(consensusConfig, "TestExternalSigner")
+			if err != nil {
+				t.Fatalf("Error setting up tc: %+v", err)
+			}
+			defer teardown(false)
+
+			const numKeys = 3
+			mnemonics := make([]string, numKeys)
+			publicKeys := make([]string, numKeys)
+			for i := 0; i < numKeys; i++ {
+				mnemonics[i], err = libhtnwallet.CreateMnemonic()
+				if err != nil {
+					t.Fatalf("CreateMnemonic: %+v", err)
+				}
+
+				publicKeys[i], err = libhtnwallet.MasterPublicKeyFromMnemonic(params, mnemonics[i], true)
+				if err != nil {
+					t.Fatalf("MasterPublicKeyFromMnemonic: %+v", err)
+				}
+			}
+
+			const minimumSignatures = 2
+			const path = "m/1/2/3"
+			address, err := libhtnwallet.Address(params, publicKeys, minimumSignatures, path, ecdsa)
+			if err != nil {
+				t.Fatalf("Address: %+v", err)
+			}
+			scriptPublicKey, err := txscript.PayToAddrScript(address)
+			if err != nil {
+				t.Fatalf("PayToAddrScript: %+v", err)
+			}
+
+			fundingBlockHash, _, err := tc.AddBlock([]*externalapi.DomainHash{consensusConfig.GenesisHash},
+				&externalapi.DomainCoinbaseData{ScriptPublicKey: scriptPublicKey}, nil)
+			if err != nil {
+				t.Fatalf("AddBlock: %+v", err)
+			}
+			block1Hash, _, err := tc.AddBlock([]*externalapi.DomainHash{fundingBlockHash}, nil, nil)
+			if err != nil {
+				t.Fatalf("AddBlock: %+v", err)
+			}
+			block1, _, err := tc.GetBlock(block1Hash)
+			if err != nil {
+				t.Fatalf("GetBlock: %+v", err)
+			}
+
+			block1TxOut := block1.Transactions[0].Outputs[0]
+			selectedUTXOs := []*libhtnwallet.UTXO{
+				{
+					Outpoint: &externalapi.DomainOutpoint{
+						TransactionID: *consensushashing.TransactionID(block1.Transactions[0]),
+						Index:         0,
+					},
+					UTXOEntry:      utxo.NewUTXOEntry(block1TxOut.Value, block1TxOut.ScriptPublicKey, true, 0),
+					DerivationPath: path,
+				},
+			}
+			unsignedTransaction, err := libhtnwallet.CreateUnsignedTransaction(publicKeys, minimumSignatures,
+				[]*libhtnwallet.Payment{{
+					Address: address,
+					Amount:  10,
+				}}, selectedUTXOs)
+			if err != nil {
+				t.Fatalf("CreateUnsignedTransactions: %+v", err)
+			}
+
+			// A signer that doesn't hold any of the transaction keys refuses to sign it
+			otherMnemonic, err := libhtnwallet.CreateMnemonic()
+			if err != nil {
+				t.Fatalf("CreateMnemonic: %+v", err)
+			}
+			_, err = libhtnwallet.SignWithExternalSigner(params,
+				libhtnwallet.NewSoftwareSigner(params, []string{otherMnemonic}, ecdsa), unsignedTransaction, ecdsa)
+			if err == nil || !strings.Contains(err.Error(), "doesn't match any of the transaction public keys") {
+				t.Fatalf("Unexpected error from a signer without the transaction keys: %+v", err)
+			}
+
+			// Invalid signatures are rejected by the wallet
+			_, err = libhtnwallet.SignWithExternalSigner(params,
+				&corruptingSigner{libhtnwallet.NewSoftwareSigner(params, mnemonics[:1], ecdsa)}, unsignedTransaction, ecdsa)
+			if err == nil || !strings.Contains(err.Error(), "invalid signature") {
+				t.Fatalf("Unexpected error from a signer that returns invalid signatures: %+v", err)
+			}
+
+			// The first signature is made by a signer that listens on a Unix socket
+			socketPath := filepath.Join(t.TempDir(), "signer.sock")
+			listener, err := net.Listen("unix", socketPath)
+			if err != nil {
+				t.Fatalf("Listen: %+v", err)
+			}
+			defer listener.Close()
+			go func() {
+				err := libhtnwallet.ServeExternalSignerListener(
+					libhtnwallet.NewSoftwareSigner(params, mnemonics[:1], ecdsa), listener)
+				if err != nil {
+					t.Errorf("ServeExternalSignerListener: %+v", err)
+				}
+			}()
+
+			signedTxStep1, err := libhtnwallet.SignWithExternalSigner(params,
+				libhtnwallet.NewExternalSignerSocket(socketPath), unsignedTransaction, ecdsa)
+			if err != nil {
+				t.Fatalf("SignWithExternalSigner: %+v", err)
+			}
+			isFullySigned, err := libhtnwallet.IsTransactionFullySigned(signedTxStep1)
+			if err != nil {
+				t.Fatalf("IsTransactionFullySigned: %+v", err)
+			}
+			if isFullySigned {
+				t.Fatalf("Transaction is not expected to be fully signed")
+			}
+
+			// The second signature is made by a signer process that talks over stdin/stdout
+			processSigner := &environmentSigner{
+				ExternalSigner: libhtnwallet.NewExternalSignerProcess(os.Args[0], "-test.run=^TestExternalSignerProcess$"),
+				environment: []string{
+					signerProcessMnemonicEnv + "=" + mnemonics[1],
+					signerProcessNetworkEnv + "=" + params.Name,
+					signerProcessECDSAEnv + "=" + strconv.FormatBool(ecdsa),
+				},
+			}
+			signedTxStep2, err := libhtnwallet.SignWithExternalSigner(params, processSigner, signedTxStep1, ecdsa)
+			if err != nil {
+				t.Fatalf("SignWithExternalSigner: %+v", err)
+			}
+			isFullySigned, err = libhtnwallet.IsTransactionFullySigned(signedTxStep2)
+			if err != nil {
+				t.Fatalf("IsTransactionFullySigned: %+v", err)
+			}
+			if !isFullySigned {
+				t.Fatalf("Transaction is expected to be fully signed")
+			}
+
+			tx, err := libhtnwallet.ExtractTransaction(signedTxStep2, ecdsa)
+			if err != nil {
+				t.Fatalf("ExtractTransaction: %+v", err)
+			}
+			addBlockWithTransaction(t, tc, block1Hash, tx)
+		})
+	})
+}
+
+func TestSoftwareSignerRecalculatesSigHashes(t *testing.T) {
+	params := &dagconfig.SimnetParams
+	mnemonic, err := libhtnwallet.CreateMnemonic()
+	if err != nil {
+		t.Fatalf("CreateMnemonic: %+v", err)
+	}
+	publicKey, err := libhtnwallet.MasterPublicKeyFromMnemonic(params, mnemonic, false)
+	if err != nil {
+		t.Fatalf("MasterPublicKeyFromMnemonic: %+v", err)
+	}
+
+	const path = "m/0/1"
+	address, err := libhtnwallet.Address(params, []string{publicKey}, 1, path, false)
+	if err != nil {
+		t.Fatalf("Address: %+v", err)
+	}
+	scriptPublicKey, err := txscript.PayToAddrScript(address)
+	if err != nil {
+		t.Fatalf("PayToAddrScript: %+v", err)
+	}
+
+	unsignedTransaction, err := libhtnwallet.CreateUnsignedTransaction([]string{publicKey}, 1,
+		[]*libhtnwallet.Payment{{
+			Address: address,
+			Amount:  10,
+		}}, []*libhtnwallet.UTXO{{
+			Outpoint:       &externalapi.DomainOutpoint{Index: 0},
+			UTXOEntry:      utxo.NewUTXOEntry(100, scriptPublicKey, false, 0),
+			DerivationPath: path,
+		}})
+	if err != nil {
+		t.Fatalf("CreateUnsignedTransaction: %+v", err)
+	}
+	partiallySignedTransaction, err := serialization.DeserializePartiallySignedTransaction(unsignedTransaction)
+	if err != nil {
+		t.Fatalf("DeserializePartiallySignedTransaction: %+v", err)
+	}
+	request, err := libhtnwallet.NewExternalSignRequest(params, partiallySignedTransaction, false)
+	if err != nil {
+		t.Fatalf("NewExternalSignRequest: %+v", err)
+	}
+
+	signer := libhtnwallet.NewSoftwareSigner(params, []string{mnemonic}, false)
+	response, err := signer.SignTransaction(request)
+	if err != nil {
+		t.Fatalf("SignTransaction: %+v", err)
+	}
+	if len(response.Signatures) != 1 {
+		t.Fatalf("Expected a single signature, but got %d", len(response.Signatures))
+	}
+
+	// A wallet that asks to sign a hash of another transaction than the one it
+	// describes is caught by the signer
+	request.Transaction.Outputs[0].Amount++
+	_, err = signer.SignTransaction(request)
+	if err == nil || !strings.Contains(err.Error(), "doesn't match the transaction") {
+		t.Fatalf("Unexpected error for a mismatching sighash: %+v", err)
+	}
+}
+
+// corruptingSigner flips a bit in each of the signatures of the wrapped signer
+type corruptingSigner struct {
+	libhtnwallet.ExternalSigner
+}
+
+func (cs *corruptingSigner) SignTransaction(request *libhtnwallet.ExternalSignRequest) (*libhtnwallet.ExternalSignResponse, error) {
+	response, err := cs.ExternalSigner.SignTransaction(request)
+	if err != nil {
+		return nil, err
+	}
+	for _, signature := range response.Signatures {
+		serializedSignature, err := hex.DecodeString(signature.Signature)
+		if err != nil {
+			return nil, err
+		}
+		serializedSignature[len(serializedSignature)/2] ^= 1
+		signature.Signature = hex.EncodeToString(serializedSignature)
+	}
+	return response, nil
+}
+
+// environmentSigner sets environment variables for the duration of each request of the wrapped
+// signer, so that they're inherited by signer processes
+type environmentSigner struct {
+	libhtnwallet.ExternalSigner
+	environment []string
+}
+
+func (es *environmentSigner) SignTransaction(request *libhtnwallet.ExternalSignRequest) (*libhtnwallet.ExternalSignResponse, error) {
+	for _, variable := range es.environment {
+		keyValue := strings.SplitN(variable, "=", 2)
+		err := os.Setenv(keyValue[0], keyValue[1])
+		if err != nil {
+			return nil, err
+		}
+		defer os.Unsetenv(keyValue[0])
+	}
+	return es.ExternalSigner.SignTransaction(request)
+}
@@ -0,0 +1,144 @@
+package libhtnwallet
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"io"
+	"net"
+	"os"
+	"os/exec"
+
+	"github.com/pkg/errors"
+)
+
+// The external signer protocol is a request/response protocol where each message is a
+// single line of JSON. The wallet writes an ExternalSignRequest, and the signer answers
+// with an ExternalSignResponse. The protocol can run over the stdin/stdout of a signer
+// process, or over a Unix socket the signer listens on.
+
+type externalSignerProcess struct {
+	command string
+	args    []string
+}
+
+// NewExternalSignerProcess returns an ExternalSigner that runs the given command for every
+// request, writes the request to its stdin and reads the response from its stdout. The
+// stderr of the process is passed through, so it can be used to interact with the user.
+func NewExternalSignerProcess(command string, args ...string) ExternalSigner {
+	return &externalSignerProcess{
+		command: command,
+		args:    args,
+	}
+}
+
+func (esp *externalSignerProcess) SignTransaction(request *ExternalSignRequest) (*ExternalSignResponse, error) {
+	stdin := &bytes.Buffer{}
+	err := json.NewEncoder(stdin).Encode(request)
+	if err != nil {
+		return nil, err
+	}
+
+	stdout := &bytes.Buffer{}
+	cmd := exec.Command(esp.command, esp.args...)
+	cmd.Stdin = stdin
+	cmd.Stdout = stdout
+	cmd.Stderr = os.Stderr
+	err = cmd.Run()
+	if err != nil {
+		return nil, errors.Wrapf(err, "external signer %s failed", esp.command)
+	}
+
+	return readExternalSignResponse(stdout)
+}
+
+type externalSignerSocket struct {
+	path string
+}
+
+// NewExternalSignerSocket returns an ExternalSigner that sends every request over a new
+// connection to the Unix socket at the given path
+func NewExternalSignerSocket(path string) ExternalSigner {
+	return &externalSignerSocket{path: path}
+}
+
+func (ess *externalSignerSocket) SignTransaction(request *ExternalSignRequest) (*ExternalSignResponse, error) {
+	connection, err := net.Dial("unix", ess.path)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to connect to the external signer at %s", ess.path)
+	}
+	defer connection.Close()
+
+	err = json.NewEncoder(connection).Encode(request)
+	if err != nil {
+		return nil, err
+	}
+
+	return readExternalSignResponse(connection)
+}
+
+func readExternalSignResponse(reader io.Reader) (*ExternalSignResponse, error) {
+	response := &ExternalSignResponse{}
+	err := json.NewDecoder(reader).Decode(response)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read the external signer response")
+	}
+	return response, nil
+}
+
+// ServeExternalSigner reads sign requests from reader and writes the responses of signer to
+// writer, until reader is exhausted. Errors returned by the signer are sent back in the
+// Error field of the response.
+func ServeExternalSigner(signer ExternalSigner, reader io.Reader, writer io.Writer) error {
+	scanner := bufio.NewScanner(reader)
+	// Requests of transactions with many inputs are much longer than the default maximum line length
+	const maxRequestSize = 64 * 1024 * 1024
+	scanner.Buffer(make([]byte, 0, 64*1024), maxRequestSize)
+
+	encoder := json.NewEncoder(writer)
+	for scanner.Scan() {
+		line := bytes.TrimSpace(scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+
+		var response *ExternalSignResponse
+		request := &ExternalSignRequest{}
+		err := json.Unmarshal(line, request)
+		if err == nil {
+			response, err = signer.SignTransaction(request)
+		}
+		if err != nil {
+			response = &ExternalSignResponse{
+				Version: ExternalSignerProtocolVersion,
+				Error:   err.Error(),
+			}
+		}
+
+		err = encoder.Encode(response)
+		if err != nil {
+			return err
+		}
+	}
+	return scanner.Err()
+}
+
+// ServeExternalSignerListener serves every connection accepted by listener with ServeExternalSigner.
+// It returns once the listener is closed.
+func ServeExternalSignerListener(signer ExternalSigner, listener net.Listener) error {
+	for {
+		connection, err := listener.Accept()
+		if err != nil {
+			if errors.Is(err, net.ErrClosed) {
+				return nil
+			}
+			return err
+		}
+
+		go func() {
+			defer connection.Close()
+			// A failure to talk to a single client shouldn't stop the signer
+			_ = ServeExternalSigner(signer, connection, connection)
+		}()
+	}
+}
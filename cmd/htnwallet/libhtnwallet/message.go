@@ -0,0 +1,117 @@
+package libhtnwallet
+
+import (
+	"github.com/Hoosat-Oy/HTND/cmd/htnwallet/libhtnwallet/bip32"
+	"github.com/Hoosat-Oy/HTND/domain/consensus/model/externalapi"
+	"github.com/Hoosat-Oy/HTND/domain/consensus/utils/hashes"
+	"github.com/Hoosat-Oy/HTND/domain/dagconfig"
+	"github.com/Hoosat-Oy/HTND/util"
+	"github.com/kaspanet/go-secp256k1"
+	"github.com/pkg/errors"
+)
+
+// MessageHash returns the domain separated hash of the given message, which is the hash
+// that is actually signed by SignMessage and verified by VerifyMessage
+func MessageHash(message []byte) *externalapi.DomainHash {
+	hashWriter := hashes.NewPersonalMessageSigningHashWriter()
+	hashWriter.InfallibleWrite(message)
+	return hashWriter.Finalize()
+}
+
+// SignMessage signs the given message with the private key derived from the given mnemonic and derivation path.
+// It's meant to be used with single signer wallets, to prove the ownership of one of their addresses.
+func SignMessage(params *dagconfig.Params, mnemonic string, derivationPath string, message []byte, ecdsa bool) ([]byte, error) {
+	extendedKey, err := extendedKeyFromMnemonicAndPath(mnemonic, defaultPath(false), params)
+	if err != nil {
+		return nil, err
+	}
+
+	derivedKey, err := extendedKey.DeriveFromPath(derivationPath)
+	if err != nil {
+		return nil, err
+	}
+
+	return signMessage(derivedKey, message, ecdsa)
+}
+
+// SignMessageWithPrivateKey signs the given message with the given serialized private key
+func SignMessageWithPrivateKey(privateKey []byte, message []byte, ecdsa bool) ([]byte, error) {
+	secpHash := secp256k1.Hash(*MessageHash(message).ByteArray())
+	if ecdsa {
+		ecdsaPrivateKey, err := secp256k1.DeserializeECDSAPrivateKeyFromSlice(privateKey)
+		if err != nil {
+			return nil, errors.Wrap(err, "Failed to deserialize private key")
+		}
+		return signMessageECDSA(ecdsaPrivateKey, &secpHash)
+	}
+
+	schnorrKeyPair, err := secp256k1.DeserializeSchnorrPrivateKeyFromSlice(privateKey)
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed to deserialize private key")
+	}
+	return signMessageSchnorr(schnorrKeyPair, &secpHash)
+}
+
+func signMessage(extendedKey *bip32.ExtendedKey, message []byte, ecdsa bool) ([]byte, error) {
+	secpHash := secp256k1.Hash(*MessageHash(message).ByteArray())
+	privateKey := extendedKey.PrivateKey()
+	if ecdsa {
+		return signMessageECDSA(privateKey, &secpHash)
+	}
+
+	schnorrKeyPair, err := privateKey.ToSchnorr()
+	if err != nil {
+		return nil, err
+	}
+	return signMessageSchnorr(schnorrKeyPair, &secpHash)
+}
+
+func signMessageSchnorr(keyPair *secp256k1.SchnorrKeyPair, hash *secp256k1.Hash) ([]byte, error) {
+	signature, err := keyPair.SchnorrSign(hash)
+	if err != nil {
+		return nil, err
+	}
+	return signature.Serialize()[:], nil
+}
+
+func signMessageECDSA(privateKey *secp256k1.ECDSAPrivateKey, hash *secp256k1.Hash) ([]byte, error) {
+	signature, err := privateKey.ECDSASign(hash)
+	if err != nil {
+		return nil, err
+	}
+	return signature.Serialize()[:], nil
+}
+
+// VerifyMessage checks whether signature is a valid signature of message, created by SignMessage with the
+// key of the given address. The signature scheme (schnorr or ECDSA) is deduced from the address type.
+// An error is returned if the address or the signature are malformed, or if the address type is not
+// a public key address.
+func VerifyMessage(address util.Address, message []byte, signature []byte) (bool, error) {
+	secpHash := secp256k1.Hash(*MessageHash(message).ByteArray())
+
+	switch address := address.(type) {
+	case *util.AddressPublicKey:
+		publicKey, err := secp256k1.DeserializeSchnorrPubKey(address.ScriptAddress())
+		if err != nil {
+			return false, errors.Wrap(err, "Failed to deserialize public key")
+		}
+		schnorrSignature, err := secp256k1.DeserializeSchnorrSignatureFromSlice(signature)
+		if err != nil {
+			return false, errors.Wrap(err, "Failed to deserialize signature")
+		}
+		return publicKey.SchnorrVerify(&secpHash, schnorrSignature), nil
+	case *util.AddressPublicKeyECDSA:
+		publicKey, err := secp256k1.DeserializeECDSAPubKey(address.ScriptAddress())
+		if err != nil {
+			return false, errors.Wrap(err, "Failed to deserialize public key")
+		}
+		ecdsaSignature, err := secp256k1.DeserializeECDSASignatureFromSlice(signature)
+		if err != nil {
+			return false, errors.Wrap(err, "Failed to deserialize signature")
+		}
+		return publicKey.ECDSAVerify(&secpHash, ecdsaSignature), nil
+	default:
+		return false, errors.Errorf("message verification is only supported for public key addresses, "+
+			"while %s is of type %T", address, address)
+	}
+}
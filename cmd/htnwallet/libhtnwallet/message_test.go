@@ -0,0 +1,114 @@
+package libhtnwallet_test
+
+import (
+	"testing"
+
+	"github.com/Hoosat-Oy/HTND/cmd/htnwallet/libhtnwallet"
+	"github.com/Hoosat-Oy/HTND/domain/dagconfig"
+	"github.com/Hoosat-Oy/HTND/util"
+)
+
+func TestSignAndVerifyMessage(t *testing.T) {
+	params := &dagconfig.MainnetParams
+	forSchnorrAndECDSA(t, func(t *testing.T, ecdsa bool) {
+		mnemonic, err := libhtnwallet.CreateMnemonic()
+		if err != nil {
+			t.Fatalf("CreateMnemonic: %+v", err)
+		}
+
+		publicKey, err := libhtnwallet.MasterPublicKeyFromMnemonic(params, mnemonic, false)
+		if err != nil {
+			t.Fatalf("MasterPublicKeyFromMnemonic: %+v", err)
+		}
+
+		const path = "m/0/1"
+		address, err := libhtnwallet.Address(params, []string{publicKey}, 1, path, ecdsa)
+		if err != nil {
+			t.Fatalf("Address: %+v", err)
+		}
+		otherAddress, err := libhtnwallet.Address(params, []string{publicKey}, 1, "m/0/2", ecdsa)
+		if err != nil {
+			t.Fatalf("Address: %+v", err)
+		}
+
+		message := []byte("I own this address")
+		signature, err := libhtnwallet.SignMessage(params, mnemonic, path, message, ecdsa)
+		if err != nil {
+			t.Fatalf("SignMessage: %+v", err)
+		}
+
+		valid, err := libhtnwallet.VerifyMessage(address, message, signature)
+		if err != nil {
+			t.Fatalf("VerifyMessage: %+v", err)
+		}
+		if !valid {
+			t.Fatalf("Expected the signature to be valid")
+		}
+
+		valid, err = libhtnwallet.VerifyMessage(address, []byte("I own this address!"), signature)
+		if err != nil {
+			t.Fatalf("VerifyMessage: %+v", err)
+		}
+		if valid {
+			t.Fatalf("Expected the signature of a different message to be invalid")
+		}
+
+		valid, err = libhtnwallet.VerifyMessage(otherAddress, message, signature)
+		if err != nil {
+			t.Fatalf("VerifyMessage: %+v", err)
+		}
+		if valid {
+			t.Fatalf("Expected the signature to be invalid for a different address")
+		}
+
+		_, err = libhtnwallet.VerifyMessage(address, message, signature[1:])
+		if err == nil {
+			t.Fatalf("Expected an error for a malformed signature")
+		}
+	})
+}
+
+func TestSignMessageWithPrivateKey(t *testing.T) {
+	params := &dagconfig.MainnetParams
+	forSchnorrAndECDSA(t, func(t *testing.T, ecdsa bool) {
+		privateKey, publicKey, err := libhtnwallet.CreateKeyPair(ecdsa)
+		if err != nil {
+			t.Fatalf("CreateKeyPair: %+v", err)
+		}
+
+		var address util.Address
+		if ecdsa {
+			address, err = util.NewAddressPublicKeyECDSA(publicKey, params.Prefix)
+		} else {
+			address, err = util.NewAddressPublicKey(publicKey, params.Prefix)
+		}
+		if err != nil {
+			t.Fatalf("NewAddress: %+v", err)
+		}
+
+		message := []byte("Hoosat")
+		signature, err := libhtnwallet.SignMessageWithPrivateKey(privateKey, message, ecdsa)
+		if err != nil {
+			t.Fatalf("SignMessageWithPrivateKey: %+v", err)
+		}
+
+		valid, err := libhtnwallet.VerifyMessage(address, message, signature)
+		if err != nil {
+			t.Fatalf("VerifyMessage: %+v", err)
+		}
+		if !valid {
+			t.Fatalf("Expected the signature to be valid")
+		}
+	})
+}
+
+func TestVerifyMessageScriptHashAddress(t *testing.T) {
+	address, err := util.NewAddressScriptHash([]byte{1, 2, 3}, dagconfig.MainnetParams.Prefix)
+	if err != nil {
+		t.Fatalf("NewAddressScriptHash: %+v", err)
+	}
+	_, err = libhtnwallet.VerifyMessage(address, []byte("Hoosat"), make([]byte, 64))
+	if err == nil {
+		t.Fatalf("Expected an error for a script hash address")
+	}
+}
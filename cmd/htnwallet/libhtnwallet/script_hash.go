@@ -0,0 +1,164 @@
+package libhtnwallet
+
+import (
+	"bytes"
+
+	"github.com/Hoosat-Oy/HTND/cmd/htnwallet/libhtnwallet/serialization"
+	"github.com/Hoosat-Oy/HTND/domain/consensus/utils/constants"
+	"github.com/Hoosat-Oy/HTND/domain/consensus/utils/txscript"
+	"github.com/Hoosat-Oy/HTND/domain/dagconfig"
+	"github.com/Hoosat-Oy/HTND/util"
+	"github.com/pkg/errors"
+)
+
+// ScriptHashAddress returns the pay-to-script-hash address of the given redeem script
+func ScriptHashAddress(params *dagconfig.Params, redeemScript []byte) (util.Address, error) {
+	return util.NewAddressScriptHash(redeemScript, params.Prefix)
+}
+
+// VaultRedeemScript returns a time-locked vault redeem script, which is spendable by the key of timeLockedAddress
+// once the DAA score reaches lockDAAScore, or by the key of recoveryAddress at any time.
+// Both addresses must be public key addresses.
+func VaultRedeemScript(timeLockedAddress, recoveryAddress util.Address, lockDAAScore uint64) ([]byte, error) {
+	if lockDAAScore == 0 || lockDAAScore >= constants.LockTimeThreshold {
+		return nil, errors.Errorf("lock DAA score must be between 1 and %d", uint64(constants.LockTimeThreshold-1))
+	}
+
+	timeLockedPubKey, isTimeLockedECDSA, err := addressPublicKey(timeLockedAddress)
+	if err != nil {
+		return nil, err
+	}
+	recoveryPubKey, isRecoveryECDSA, err := addressPublicKey(recoveryAddress)
+	if err != nil {
+		return nil, err
+	}
+
+	return txscript.VaultScript(&txscript.VaultDataPushes{
+		LockTime:          lockDAAScore,
+		TimeLockedPubKey:  timeLockedPubKey,
+		IsTimeLockedECDSA: isTimeLockedECDSA,
+		RecoveryPubKey:    recoveryPubKey,
+		IsRecoveryECDSA:   isRecoveryECDSA,
+	})
+}
+
+// VaultSpendParameters returns the redeem script arguments and the transaction lock time that are
+// required in order to spend an output locked by the given vault redeem script with the key of signerAddress.
+// If redeemScript is not a vault script, isVault is false.
+func VaultSpendParameters(redeemScript []byte, signerAddress util.Address) (
+	isVault bool, redeemScriptArguments []byte, lockTime uint64, err error) {
+
+	vault, err := txscript.ExtractVaultDataPushes(0, redeemScript)
+	if err != nil {
+		return false, nil, 0, err
+	}
+	if vault == nil {
+		return false, nil, 0, nil
+	}
+
+	signerPubKey, isSignerECDSA, err := addressPublicKey(signerAddress)
+	if err != nil {
+		return false, nil, 0, err
+	}
+
+	// The arguments select the OP_IF branch of the vault script
+	switch {
+	case isSignerECDSA == vault.IsTimeLockedECDSA && bytes.Equal(signerPubKey, vault.TimeLockedPubKey):
+		redeemScriptArguments, err = txscript.NewScriptBuilder().AddOp(txscript.OpTrue).Script()
+		lockTime = vault.LockTime
+	case isSignerECDSA == vault.IsRecoveryECDSA && bytes.Equal(signerPubKey, vault.RecoveryPubKey):
+		redeemScriptArguments, err = txscript.NewScriptBuilder().AddOp(txscript.OpFalse).Script()
+	default:
+		return false, nil, 0, errors.Errorf("%s is not one of the vault keys", signerAddress)
+	}
+	if err != nil {
+		return false, nil, 0, err
+	}
+
+	return true, redeemScriptArguments, lockTime, nil
+}
+
+func addressPublicKey(address util.Address) (publicKey []byte, isECDSA bool, err error) {
+	switch address := address.(type) {
+	case *util.AddressPublicKey:
+		return address.ScriptAddress(), false, nil
+	case *util.AddressPublicKeyECDSA:
+		return address.ScriptAddress(), true, nil
+	default:
+		return nil, false, errors.Errorf("%s is not a public key address", address)
+	}
+}
+
+// CreateUnsignedScriptHashTransaction creates an unsigned transaction that spends the given pay-to-script-hash
+// UTXOs, which are all locked by redeemScript, to the given payments. Each input is signed by the key of a single
+// signer wallet at the UTXO derivation path, and redeemScriptArguments are placed between the signature and the
+// redeem script in its signature script.
+func CreateUnsignedScriptHashTransaction(
+	extendedPublicKey string,
+	redeemScript []byte,
+	redeemScriptArguments []byte,
+	lockTime uint64,
+	payments []*Payment,
+	selectedUTXOs []*UTXO) ([]byte, error) {
+
+	scriptHashScript, err := txscript.PayToScriptHashScript(redeemScript)
+	if err != nil {
+		return nil, err
+	}
+	for _, utxo := range selectedUTXOs {
+		if !bytes.Equal(utxo.UTXOEntry.ScriptPublicKey().Script, scriptHashScript) {
+			return nil, errors.Errorf("UTXO %s is not locked by the given redeem script", utxo.Outpoint)
+		}
+	}
+
+	unsignedTransaction, err := createUnsignedTransaction([]string{extendedPublicKey}, 1, payments, selectedUTXOs)
+	if err != nil {
+		return nil, err
+	}
+
+	unsignedTransaction.Tx.LockTime = lockTime
+	for _, input := range unsignedTransaction.PartiallySignedInputs {
+		input.RedeemScript = redeemScript
+		input.RedeemScriptArguments = redeemScriptArguments
+	}
+
+	return serialization.SerializePartiallySignedTransaction(unsignedTransaction)
+}
+
+// PartiallySignedInputSigOpCount returns the number of signature operations the given input
+// will have once it's signed
+func PartiallySignedInputSigOpCount(input *serialization.PartiallySignedInput) (byte, error) {
+	if input.RedeemScript == nil {
+		return byte(len(input.PubKeySignaturePairs)), nil
+	}
+
+	signatureScript, err := txscript.PayToScriptHashSignatureScript(input.RedeemScript, input.RedeemScriptArguments)
+	if err != nil {
+		return 0, err
+	}
+	return byte(txscript.GetPreciseSigOpCount(signatureScript, input.PrevOutput.ScriptPublicKey, true)), nil
+}
+
+func scriptHashSignatureScript(input *serialization.PartiallySignedInput) ([]byte, error) {
+	scriptBuilder := txscript.NewScriptBuilder()
+	signatureCount := 0
+	for _, pair := range input.PubKeySignaturePairs {
+		if pair.Signature != nil {
+			scriptBuilder.AddData(pair.Signature)
+			signatureCount++
+		}
+	}
+	if uint32(signatureCount) < input.MinimumSignatures {
+		return nil, errors.Errorf("missing %d signatures", input.MinimumSignatures-uint32(signatureCount))
+	}
+
+	signatures, err := scriptBuilder.Script()
+	if err != nil {
+		return nil, err
+	}
+
+	arguments := make([]byte, len(signatures)+len(input.RedeemScriptArguments))
+	copy(arguments, signatures)
+	copy(arguments[len(signatures):], input.RedeemScriptArguments)
+	return txscript.PayToScriptHashSignatureScript(input.RedeemScript, arguments)
+}
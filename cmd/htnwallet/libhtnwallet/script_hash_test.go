@@ -0,0 +1,202 @@
+package libhtnwallet_test
+
+import (
+	"testing"
+
+	"github.com/Hoosat-Oy/HTND/cmd/htnwallet/libhtnwallet"
+	"github.com/Hoosat-Oy/HTND/domain/consensus"
+	"github.com/Hoosat-Oy/HTND/domain/consensus/model/externalapi"
+	"github.com/Hoosat-Oy/HTND/domain/consensus/model/testapi"
+	"github.com/Hoosat-Oy/HTND/domain/consensus/utils/consensushashing"
+	"github.com/Hoosat-Oy/HTND/domain/consensus/utils/testutils"
+	"github.com/Hoosat-Oy/HTND/domain/consensus/utils/txscript"
+	"github.com/Hoosat-Oy/HTND/domain/consensus/utils/utxo"
+	"github.com/Hoosat-Oy/HTND/domain/dagconfig"
+	"github.com/Hoosat-Oy/HTND/util"
+)
+
+func TestVault(t *testing.T) {
+	testutils.ForAllNets(t, true, func(t *testing.T, consensusConfig *consensus.Config) {
+		params := &consensusConfig.Params
+		forSchnorrAndECDSA(t, func(t *testing.T, ecdsa bool) {
+			consensusConfig.BlockCoinbaseMaturity = 0
+			// The chain built by the test passes the block version upgrade DAA score of some of the nets,
+			// after which blocks must include a dev fee output, so the upgrade is disabled
+			consensusConfig.POWScores = nil
+			tc, teardown, err := consensus.NewFactory().NewTestConsensus(consensusConfig, "TestVault")
+			if err != nil {
+				t.Fatalf("Error setting up tc: %+v", err)
+			}
+			defer teardown(false)
+
+			mnemonic, err := libhtnwallet.CreateMnemonic()
+			if err != nil {
+				t.Fatalf("CreateMnemonic: %+v", err)
+			}
+			publicKey, err := libhtnwallet.MasterPublicKeyFromMnemonic(params, mnemonic, false)
+			if err != nil {
+				t.Fatalf("MasterPublicKeyFromMnemonic: %+v", err)
+			}
+
+			const fundingPath = "m/0/1"
+			const timeLockedPath = "m/0/2"
+			const recoveryPath = "m/0/3"
+			addresses := make(map[string]util.Address)
+			for _, path := range []string{fundingPath, timeLockedPath, recoveryPath} {
+				addresses[path], err = libhtnwallet.Address(params, []string{publicKey}, 1, path, ecdsa)
+				if err != nil {
+					t.Fatalf("Address: %+v", err)
+				}
+			}
+
+			const lockDAAScore = 10
+			redeemScript, err := libhtnwallet.VaultRedeemScript(addresses[timeLockedPath], addresses[recoveryPath], lockDAAScore)
+			if err != nil {
+				t.Fatalf("VaultRedeemScript: %+v", err)
+			}
+			vaultAddress, err := libhtnwallet.ScriptHashAddress(params, redeemScript)
+			if err != nil {
+				t.Fatalf("ScriptHashAddress: %+v", err)
+			}
+
+			fundingScriptPublicKey, err := txscript.PayToAddrScript(addresses[fundingPath])
+			if err != nil {
+				t.Fatalf("PayToAddrScript: %+v", err)
+			}
+			fundingBlockHash, _, err := tc.AddBlock([]*externalapi.DomainHash{consensusConfig.GenesisHash},
+				&externalapi.DomainCoinbaseData{ScriptPublicKey: fundingScriptPublicKey}, nil)
+			if err != nil {
+				t.Fatalf("AddBlock: %+v", err)
+			}
+			block1Hash, _, err := tc.AddBlock([]*externalapi.DomainHash{fundingBlockHash}, nil, nil)
+			if err != nil {
+				t.Fatalf("AddBlock: %+v", err)
+			}
+			block1, _, err := tc.GetBlock(block1Hash)
+			if err != nil {
+				t.Fatalf("GetBlock: %+v", err)
+			}
+
+			// Lock two outputs in the vault, one to be spent by each of the vault keys
+			block1TxOut := block1.Transactions[0].Outputs[0]
+			const fee = 10000
+			vaultOutputAmount := (block1TxOut.Value - fee) / 2
+			unsignedFundingTransaction, err := libhtnwallet.CreateUnsignedTransaction([]string{publicKey}, 1,
+				[]*libhtnwallet.Payment{
+					{Address: vaultAddress, Amount: vaultOutputAmount},
+					{Address: vaultAddress, Amount: vaultOutputAmount},
+				},
+				[]*libhtnwallet.UTXO{{
+					Outpoint: &externalapi.DomainOutpoint{
+						TransactionID: *consensushashing.TransactionID(block1.Transactions[0]),
+						Index:         0,
+					},
+					UTXOEntry:      utxo.NewUTXOEntry(block1TxOut.Value, block1TxOut.ScriptPublicKey, true, 0),
+					DerivationPath: fundingPath,
+				}})
+			if err != nil {
+				t.Fatalf("CreateUnsignedTransaction: %+v", err)
+			}
+			fundingTransaction := signAndExtract(t, params, mnemonic, unsignedFundingTransaction, ecdsa)
+			vaultBlockHash := addBlockWithTransaction(t, tc, block1Hash, fundingTransaction)
+
+			vaultUTXO := func(index uint32, derivationPath string) []*libhtnwallet.UTXO {
+				output := fundingTransaction.Outputs[index]
+				return []*libhtnwallet.UTXO{{
+					Outpoint: &externalapi.DomainOutpoint{
+						TransactionID: *consensushashing.TransactionID(fundingTransaction),
+						Index:         index,
+					},
+					UTXOEntry:      utxo.NewUTXOEntry(output.Value, output.ScriptPublicKey, false, 0),
+					DerivationPath: derivationPath,
+				}}
+			}
+			payments := []*libhtnwallet.Payment{{Address: addresses[fundingPath], Amount: vaultOutputAmount - fee}}
+
+			_, _, _, err = libhtnwallet.VaultSpendParameters(redeemScript, addresses[fundingPath])
+			if err == nil {
+				t.Fatalf("VaultSpendParameters unexpectedly succeeded with a key that is not a vault key")
+			}
+
+			// The recovery key can spend the vault at any time
+			isVault, redeemScriptArguments, lockTime, err := libhtnwallet.VaultSpendParameters(redeemScript,
+				addresses[recoveryPath])
+			if err != nil {
+				t.Fatalf("VaultSpendParameters: %+v", err)
+			}
+			if !isVault || lockTime != 0 {
+				t.Fatalf("Unexpected recovery spend parameters: isVault: %t, lockTime: %d", isVault, lockTime)
+			}
+			unsignedRecoveryTransaction, err := libhtnwallet.CreateUnsignedScriptHashTransaction(publicKey, redeemScript,
+				redeemScriptArguments, lockTime, payments, vaultUTXO(0, recoveryPath))
+			if err != nil {
+				t.Fatalf("CreateUnsignedScriptHashTransaction: %+v", err)
+			}
+			recoveryTransaction := signAndExtract(t, params, mnemonic, unsignedRecoveryTransaction, ecdsa)
+			tipHash := addBlockWithTransaction(t, tc, vaultBlockHash, recoveryTransaction)
+
+			// The time-locked key can spend the vault only after lockDAAScore
+			isVault, redeemScriptArguments, lockTime, err = libhtnwallet.VaultSpendParameters(redeemScript,
+				addresses[timeLockedPath])
+			if err != nil {
+				t.Fatalf("VaultSpendParameters: %+v", err)
+			}
+			if !isVault || lockTime != lockDAAScore {
+				t.Fatalf("Unexpected time-locked spend parameters: isVault: %t, lockTime: %d", isVault, lockTime)
+			}
+			unsignedTimeLockedTransaction, err := libhtnwallet.CreateUnsignedScriptHashTransaction(publicKey, redeemScript,
+				redeemScriptArguments, lockTime, payments, vaultUTXO(1, timeLockedPath))
+			if err != nil {
+				t.Fatalf("CreateUnsignedScriptHashTransaction: %+v", err)
+			}
+			timeLockedTransaction := signAndExtract(t, params, mnemonic, unsignedTimeLockedTransaction, ecdsa)
+
+			_, _, err = tc.AddBlock([]*externalapi.DomainHash{tipHash}, nil,
+				[]*externalapi.DomainTransaction{timeLockedTransaction})
+			if err == nil {
+				t.Fatalf("A block with a time-locked vault spend was unexpectedly accepted before the lock DAA score")
+			}
+
+			for i := 0; i < lockDAAScore; i++ {
+				tipHash, _, err = tc.AddBlock([]*externalapi.DomainHash{tipHash}, nil, nil)
+				if err != nil {
+					t.Fatalf("AddBlock: %+v", err)
+				}
+			}
+			addBlockWithTransaction(t, tc, tipHash, timeLockedTransaction)
+		})
+	})
+}
+
+func signAndExtract(t *testing.T, params *dagconfig.Params, mnemonic string, unsignedTransaction []byte,
+	ecdsa bool) *externalapi.DomainTransaction {
+
+	signedTransaction, err := libhtnwallet.Sign(params, []string{mnemonic}, unsignedTransaction, ecdsa)
+	if err != nil {
+		t.Fatalf("Sign: %+v", err)
+	}
+	transaction, err := libhtnwallet.ExtractTransaction(signedTransaction, ecdsa)
+	if err != nil {
+		t.Fatalf("ExtractTransaction: %+v", err)
+	}
+	return transaction
+}
+
+func addBlockWithTransaction(t *testing.T, tc testapi.TestConsensus, parentHash *externalapi.DomainHash,
+	transaction *externalapi.DomainTransaction) *externalapi.DomainHash {
+
+	blockHash, virtualChangeSet, err := tc.AddBlock([]*externalapi.DomainHash{parentHash}, nil,
+		[]*externalapi.DomainTransaction{transaction})
+	if err != nil {
+		t.Fatalf("AddBlock: %+v", err)
+	}
+
+	addedUTXO := &externalapi.DomainOutpoint{
+		TransactionID: *consensushashing.TransactionID(transaction),
+		Index:         0,
+	}
+	if !virtualChangeSet.VirtualUTXODiff.ToAdd().Contains(addedUTXO) {
+		t.Fatalf("Transaction wasn't accepted in the DAG")
+	}
+	return blockHash
+}
@@ -26,6 +26,13 @@ type PartiallySignedInput struct {
 	MinimumSignatures    uint32
 	PubKeySignaturePairs []*PubKeySignaturePair
 	DerivationPath       string
+
+	// RedeemScript is set only for inputs that spend pay-to-script-hash outputs
+	// that are not the wallet's own multisig addresses. In that case the
+	// signature script is made of the signatures, followed by
+	// RedeemScriptArguments and by RedeemScript.
+	RedeemScript          []byte
+	RedeemScriptArguments []byte
 }
 
 // PubKeySignaturePair is a pair of public key and (potentially) its associated signature
@@ -54,6 +61,12 @@ func (psi PartiallySignedInput) Clone() *PartiallySignedInput {
 		PubKeySignaturePairs: make([]*PubKeySignaturePair, len(psi.PubKeySignaturePairs)),
 		DerivationPath:       psi.DerivationPath,
 	}
+	if psi.RedeemScript != nil {
+		clone.RedeemScript = append([]byte{}, psi.RedeemScript...)
+	}
+	if psi.RedeemScriptArguments != nil {
+		clone.RedeemScriptArguments = append([]byte{}, psi.RedeemScriptArguments...)
+	}
 	for i, pubKeySignaturePair := range psi.PubKeySignaturePairs {
 		clone.PubKeySignaturePairs[i] = pubKeySignaturePair.Clone()
 	}
@@ -148,10 +161,12 @@ func partiallySignedInputFromProto(protoPartiallySignedInput *protoserialization
 	}
 
 	return &PartiallySignedInput{
-		PrevOutput:           output,
-		MinimumSignatures:    protoPartiallySignedInput.MinimumSignatures,
-		PubKeySignaturePairs: pubKeySignaturePairs,
-		DerivationPath:       protoPartiallySignedInput.DerivationPath,
+		PrevOutput:            output,
+		MinimumSignatures:     protoPartiallySignedInput.MinimumSignatures,
+		PubKeySignaturePairs:  pubKeySignaturePairs,
+		DerivationPath:        protoPartiallySignedInput.DerivationPath,
+		RedeemScript:          protoPartiallySignedInput.RedeemScript,
+		RedeemScriptArguments: protoPartiallySignedInput.RedeemScriptArguments,
 	}, nil
 }
 
@@ -162,10 +177,12 @@ func partiallySignedInputToProto(partiallySignedInput *PartiallySignedInput) *pr
 	}
 
 	return &protoserialization.PartiallySignedInput{
-		PrevOutput:           transactionOutputToProto(partiallySignedInput.PrevOutput),
-		MinimumSignatures:    partiallySignedInput.MinimumSignatures,
-		PubKeySignaturePairs: protoPairs,
-		DerivationPath:       partiallySignedInput.DerivationPath,
+		PrevOutput:            transactionOutputToProto(partiallySignedInput.PrevOutput),
+		MinimumSignatures:     partiallySignedInput.MinimumSignatures,
+		PubKeySignaturePairs:  protoPairs,
+		DerivationPath:        partiallySignedInput.DerivationPath,
+		RedeemScript:          partiallySignedInput.RedeemScript,
+		RedeemScriptArguments: partiallySignedInput.RedeemScriptArguments,
 	}
 }
 
@@ -0,0 +1,121 @@
+package libhtnwallet
+
+import (
+	"encoding/hex"
+
+	"github.com/Hoosat-Oy/HTND/domain/consensus/utils/consensushashing"
+	"github.com/Hoosat-Oy/HTND/domain/dagconfig"
+	"github.com/kaspanet/go-secp256k1"
+	"github.com/pkg/errors"
+)
+
+// SoftwareSigner is a reference ExternalSigner that keeps the wallet mnemonics in memory.
+// It doesn't trust the signature hashes it's given, and recalculates them out of the
+// transaction description instead, the same way a hardware wallet is expected to.
+type SoftwareSigner struct {
+	params    *dagconfig.Params
+	mnemonics []string
+	ecdsa     bool
+}
+
+// NewSoftwareSigner returns a new SoftwareSigner that signs with the given mnemonics
+func NewSoftwareSigner(params *dagconfig.Params, mnemonics []string, ecdsa bool) *SoftwareSigner {
+	return &SoftwareSigner{
+		params:    params,
+		mnemonics: mnemonics,
+		ecdsa:     ecdsa,
+	}
+}
+
+// SignTransaction signs every input of the requested transaction that requires a signature
+// of one of the signer keys
+func (ss *SoftwareSigner) SignTransaction(request *ExternalSignRequest) (*ExternalSignResponse, error) {
+	if request.Version != ExternalSignerProtocolVersion {
+		return nil, errors.Errorf("unsupported external signer protocol version %d", request.Version)
+	}
+	if request.Network != ss.params.Name {
+		return nil, errors.Errorf("the transaction is for network %s, while the signer is for network %s",
+			request.Network, ss.params.Name)
+	}
+	if request.ECDSA != ss.ecdsa {
+		return nil, errors.Errorf("the signature scheme of the transaction doesn't match the signer's")
+	}
+	if request.Transaction == nil {
+		return nil, errors.Errorf("the request is missing its transaction")
+	}
+
+	tx, err := request.Transaction.DomainTransaction()
+	if err != nil {
+		return nil, err
+	}
+
+	sighashReusedValues := &consensushashing.SighashReusedValues{}
+	signatures := make([]*ExternalSignerSignature, 0)
+	for i, input := range request.Transaction.Inputs {
+		if input.SigHashType != consensushashing.SigHashAll {
+			return nil, errors.Errorf("input %d uses the unsupported sighash type %d", i, input.SigHashType)
+		}
+
+		sigHash, err := calculateSignatureHash(tx, i, input.SigHashType, sighashReusedValues, ss.ecdsa)
+		if err != nil {
+			return nil, err
+		}
+		if sigHash.String() != input.SigHash {
+			return nil, errors.Errorf("the signature hash of input %d doesn't match the transaction", i)
+		}
+		secpHash := secp256k1.Hash(*sigHash.ByteArray())
+
+		isMultisig := len(input.PublicKeys) > 1
+		for _, mnemonic := range ss.mnemonics {
+			extendedKey, err := extendedKeyFromMnemonicAndPath(mnemonic, defaultPath(isMultisig), ss.params)
+			if err != nil {
+				return nil, err
+			}
+
+			derivedKey, err := extendedKey.DeriveFromPath(input.DerivationPath)
+			if err != nil {
+				return nil, err
+			}
+
+			derivedPublicKey, err := derivedKey.Public()
+			if err != nil {
+				return nil, err
+			}
+
+			for _, publicKey := range input.PublicKeys {
+				if publicKey.IsSigned || publicKey.ExtendedPublicKey != derivedPublicKey.String() {
+					continue
+				}
+
+				var signature []byte
+				if ss.ecdsa {
+					signature, err = signMessageECDSA(derivedKey.PrivateKey(), &secpHash)
+				} else {
+					schnorrKeyPair, schnorrErr := derivedKey.PrivateKey().ToSchnorr()
+					if schnorrErr != nil {
+						return nil, schnorrErr
+					}
+					signature, err = signMessageSchnorr(schnorrKeyPair, &secpHash)
+				}
+				if err != nil {
+					return nil, err
+				}
+
+				signatures = append(signatures, &ExternalSignerSignature{
+					InputIndex:        uint32(i),
+					ExtendedPublicKey: publicKey.ExtendedPublicKey,
+					Signature:         hex.EncodeToString(signature),
+				})
+			}
+		}
+	}
+
+	if len(signatures) == 0 {
+		return nil, errors.Errorf("Public key doesn't match any of the transaction public keys")
+	}
+
+	return &ExternalSignResponse{
+		Version:    ExternalSignerProtocolVersion,
+		Signatures: signatures,
+	}, nil
+}
@@ -204,6 +204,15 @@ func ExtractTransactionDeserialized(partiallySignedTransaction *serialization.Pa
 	*externalapi.DomainTransaction, error) {
 
 	for i, input := range partiallySignedTransaction.PartiallySignedInputs {
+		if input.RedeemScript != nil {
+			sigScript, err := scriptHashSignatureScript(input)
+			if err != nil {
+				return nil, err
+			}
+			partiallySignedTransaction.Tx.Inputs[i].SignatureScript = sigScript
+			continue
+		}
+
 		isMultisig := len(input.PubKeySignaturePairs) > 1
 		scriptBuilder := txscript.NewScriptBuilder()
 		if isMultisig {
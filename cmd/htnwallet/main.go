@@ -14,6 +14,8 @@ func main() {
 		err = balance(config.(*balanceConfig))
 	case sendSubCmd:
 		err = send(config.(*sendConfig))
+	case sendManySubCmd:
+		err = sendMany(config.(*sendManyConfig))
 	case createUnsignedTransactionSubCmd:
 		err = createUnsignedTransaction(config.(*createUnsignedTransactionConfig))
 	case signSubCmd:
@@ -28,6 +30,26 @@ func main() {
 		err = newAddress(config.(*newAddressConfig))
 	case dumpUnencryptedDataSubCmd:
 		err = dumpUnencryptedData(config.(*dumpUnencryptedDataConfig))
+	case signMessageSubCmd:
+		err = signMessage(config.(*signMessageConfig))
+	case verifyMessageSubCmd:
+		err = verifyMessage(config.(*verifyMessageConfig))
+	case changePasswordSubCmd:
+		err = changePassword(config.(*changePasswordConfig))
+	case backupSubCmd:
+		err = backup(config.(*backupConfig))
+	case restoreSubCmd:
+		err = restore(config.(*restoreConfig))
+	case labelAddressSubCmd:
+		err = labelAddress(config.(*labelAddressConfig))
+	case createVaultSubCmd:
+		err = createVault(config.(*createVaultConfig))
+	case scriptHashAddressSubCmd:
+		err = scriptHashAddress(config.(*scriptHashAddressConfig))
+	case spendScriptHashSubCmd:
+		err = spendScriptHash(config.(*spendScriptHashConfig))
+	case externalSignerSubCmd:
+		err = externalSigner(config.(*externalSignerConfig))
 	case startDaemonSubCmd:
 		err = startDaemon(config.(*startDaemonConfig))
 	case sweepSubCmd:
@@ -0,0 +1,129 @@
+package main
+
+import (
+	"encoding/hex"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strings"
+
+	"github.com/Hoosat-Oy/HTND/cmd/htnwallet/keys"
+	"github.com/Hoosat-Oy/HTND/cmd/htnwallet/libhtnwallet"
+	"github.com/Hoosat-Oy/HTND/domain/dagconfig"
+	"github.com/Hoosat-Oy/HTND/util"
+	"github.com/pkg/errors"
+)
+
+func signMessage(conf *signMessageConfig) error {
+	message, err := readMessage(conf.Message, conf.MessageFile)
+	if err != nil {
+		return err
+	}
+
+	keysFile, err := keys.ReadKeysFile(conf.NetParams(), conf.KeysFile)
+	if err != nil {
+		return err
+	}
+
+	if len(keysFile.ExtendedPublicKeys) > 1 {
+		return errors.Errorf("Cannot use 'sign-message' command for multisig wallet")
+	}
+
+	derivationPath, err := walletAddressDerivationPath(conf.NetParams(), keysFile, conf.Address)
+	if err != nil {
+		return err
+	}
+
+	if len(conf.Password) == 0 {
+		conf.Password = keys.GetPassword("Password:")
+	}
+	mnemonics, err := keysFile.DecryptMnemonics(conf.Password)
+	if err != nil {
+		return err
+	}
+
+	signature, err := libhtnwallet.SignMessage(conf.NetParams(), mnemonics[0], derivationPath, message, keysFile.ECDSA)
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintln(os.Stderr, "Signed message with the key of address", conf.Address)
+	fmt.Println(hex.EncodeToString(signature))
+	return nil
+}
+
+func verifyMessage(conf *verifyMessageConfig) error {
+	message, err := readMessage(conf.Message, conf.MessageFile)
+	if err != nil {
+		return err
+	}
+
+	address, err := util.DecodeAddress(conf.Address, conf.NetParams().Prefix)
+	if err != nil {
+		return err
+	}
+
+	signature, err := hex.DecodeString(strings.TrimSpace(conf.Signature))
+	if err != nil {
+		return errors.Wrap(err, "The signature is not a valid hex string")
+	}
+
+	valid, err := libhtnwallet.VerifyMessage(address, message, signature)
+	if err != nil {
+		return err
+	}
+	if !valid {
+		return errors.Errorf("The signature is NOT valid for the given message and address")
+	}
+
+	fmt.Println("The signature is valid")
+	return nil
+}
+
+func readMessage(message string, messageFile string) ([]byte, error) {
+	if message == "" && messageFile == "" {
+		return nil, errors.Errorf("Either --message or --message-file is required")
+	}
+	if message != "" && messageFile != "" {
+		return nil, errors.Errorf("Both --message and --message-file cannot be passed at the same time")
+	}
+
+	if messageFile != "" {
+		messageBytes, err := ioutil.ReadFile(messageFile)
+		if err != nil {
+			return nil, errors.Wrapf(err, "Could not read message from %s", messageFile)
+		}
+		return messageBytes, nil
+	}
+	return []byte(message), nil
+}
+
+// walletAddressDerivationPath looks for the given address among all the addresses that were
+// generated by the given single signer wallet, and returns its derivation path
+func walletAddressDerivationPath(params *dagconfig.Params, keysFile *keys.File, addressString string) (string, error) {
+	keychains := []struct {
+		keychain      uint32
+		lastUsedIndex uint32
+	}{
+		{keychain: libhtnwallet.ExternalKeychain, lastUsedIndex: keysFile.LastUsedExternalIndex()},
+		{keychain: libhtnwallet.InternalKeychain, lastUsedIndex: keysFile.LastUsedInternalIndex()},
+	}
+
+	// Index 0 is included, since it's used by change addresses that reuse the
+	// first internal address
+	for _, keychain := range keychains {
+		for index := uint32(0); index <= keychain.lastUsedIndex; index++ {
+			path := walletAddressPath(keysFile, keysFile.CosignerIndex, keychain.keychain, index)
+			address, err := libhtnwallet.Address(params, keysFile.ExtendedPublicKeys, keysFile.MinimumSignatures,
+				path, keysFile.ECDSA)
+			if err != nil {
+				return "", err
+			}
+			if address.String() == addressString {
+				return path, nil
+			}
+		}
+	}
+
+	return "", errors.Errorf("Address %s does not belong to the wallet", addressString)
+}
@@ -0,0 +1,54 @@
+package main
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/Hoosat-Oy/HTND/cmd/htnwallet/keys"
+	"github.com/Hoosat-Oy/HTND/cmd/htnwallet/libhtnwallet"
+	"github.com/Hoosat-Oy/HTND/domain/dagconfig"
+)
+
+func TestWalletAddressDerivationPath(t *testing.T) {
+	params := &dagconfig.MainnetParams
+	mnemonic, err := libhtnwallet.CreateMnemonic()
+	if err != nil {
+		t.Fatalf("CreateMnemonic: %+v", err)
+	}
+	publicKey, err := libhtnwallet.MasterPublicKeyFromMnemonic(params, mnemonic, false)
+	if err != nil {
+		t.Fatalf("MasterPublicKeyFromMnemonic: %+v", err)
+	}
+	keysFile := &keys.File{
+		ExtendedPublicKeys: []string{publicKey},
+		MinimumSignatures:  1,
+	}
+
+	// The change address of a wallet that didn't generate any internal
+	// address yet has the internal index 0
+	changePath := fmt.Sprintf("m/%d/%d", libhtnwallet.InternalKeychain, 0)
+	changeAddress, err := libhtnwallet.Address(params, keysFile.ExtendedPublicKeys, keysFile.MinimumSignatures,
+		changePath, keysFile.ECDSA)
+	if err != nil {
+		t.Fatalf("Address: %+v", err)
+	}
+
+	path, err := walletAddressDerivationPath(params, keysFile, changeAddress.String())
+	if err != nil {
+		t.Fatalf("walletAddressDerivationPath: %+v", err)
+	}
+	if path != changePath {
+		t.Fatalf("Expected the derivation path %s, got %s", changePath, path)
+	}
+
+	otherPath := fmt.Sprintf("m/%d/%d", libhtnwallet.InternalKeychain, 1)
+	otherAddress, err := libhtnwallet.Address(params, keysFile.ExtendedPublicKeys, keysFile.MinimumSignatures,
+		otherPath, keysFile.ECDSA)
+	if err != nil {
+		t.Fatalf("Address: %+v", err)
+	}
+	_, err = walletAddressDerivationPath(params, keysFile, otherAddress.String())
+	if err == nil {
+		t.Fatalf("Expected an address beyond the last used index not to belong to the wallet")
+	}
+}
@@ -0,0 +1,190 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+
+	"github.com/Hoosat-Oy/HTND/cmd/htnwallet/keys"
+	"github.com/Hoosat-Oy/HTND/cmd/htnwallet/libhtnwallet"
+	"github.com/Hoosat-Oy/HTND/cmd/htnwallet/utils"
+	"github.com/Hoosat-Oy/HTND/domain/dagconfig"
+	"github.com/Hoosat-Oy/HTND/infrastructure/network/rpcclient"
+	"github.com/pkg/errors"
+	"github.com/tyler-smith/go-bip39"
+)
+
+func restore(conf *restoreConfig) error {
+	var walletBackup *keys.Backup
+	var err error
+	if conf.BackupFile != "" {
+		walletBackup, err = readBackup(conf)
+	} else {
+		walletBackup, err = backupFromMnemonic(conf)
+	}
+	if err != nil {
+		return err
+	}
+
+	if len(conf.Password) == 0 {
+		conf.Password, err = keys.GetNewPassword("Enter password for the key file:")
+		if err != nil {
+			return err
+		}
+	}
+
+	keysFile, err := keys.NewFileFromBackup(walletBackup, conf.Password, keys.DefaultKDFParams())
+	if err != nil {
+		return err
+	}
+
+	err = keysFile.SetPath(conf.NetParams(), conf.KeysFile, conf.Yes)
+	if err != nil {
+		return err
+	}
+
+	err = keysFile.TryLock()
+	if err != nil {
+		return err
+	}
+
+	err = keysFile.Save()
+	if err != nil {
+		return err
+	}
+
+	if conf.RPCServer != "" {
+		err = rescanUsedAddresses(conf.NetParams(), conf.RPCServer, keysFile, conf.GapLimit)
+		if err != nil {
+			return err
+		}
+	} else {
+		fmt.Println("No --rpcserver was given, so the used addresses were not rescanned. " +
+			"The wallet daemon will discover them while syncing.")
+	}
+
+	fmt.Printf("Restored the wallet into %s\n", keysFile.Path())
+	return nil
+}
+
+func readBackup(conf *restoreConfig) (*keys.Backup, error) {
+	if len(conf.BackupPassword) == 0 {
+		conf.BackupPassword = keys.GetPassword("Backup password:")
+	}
+
+	walletBackup, err := keys.ReadBackupFile(conf.BackupFile, conf.BackupPassword)
+	if err != nil {
+		return nil, err
+	}
+
+	if walletBackup.Network != conf.NetParams().Name {
+		return nil, errors.Errorf("The backup is of a %s wallet, while the selected network is %s",
+			walletBackup.Network, conf.NetParams().Name)
+	}
+	return walletBackup, nil
+}
+
+// backupFromMnemonic creates a backup of a single signer wallet from a mnemonic entered by the user.
+// Multisig wallets should be restored with `create --import` instead.
+func backupFromMnemonic(conf *restoreConfig) (*keys.Backup, error) {
+	fmt.Println("Enter mnemonic here:")
+	reader := bufio.NewReader(os.Stdin)
+	mnemonic, err := utils.ReadLine(reader)
+	if err != nil {
+		return nil, err
+	}
+
+	if !bip39.IsMnemonicValid(mnemonic) {
+		return nil, errors.Errorf("mnemonic is invalid")
+	}
+
+	extendedPublicKey, err := libhtnwallet.MasterPublicKeyFromMnemonic(conf.NetParams(), mnemonic, false)
+	if err != nil {
+		return nil, err
+	}
+
+	return &keys.Backup{
+		Network:            conf.NetParams().Name,
+		Mnemonics:          []string{mnemonic},
+		ExtendedPublicKeys: []string{extendedPublicKey},
+		MinimumSignatures:  1,
+		ECDSA:              conf.ECDSA,
+	}, nil
+}
+
+// rescanUsedAddresses scans the addresses of both key chains of the wallet, and raises the last used
+// indexes of the wallet to the highest index of an address that holds funds. The scan of each key chain
+// stops once gapLimit consecutive addresses without funds are found after its last used address.
+// Note that the node must run with --utxoindex.
+func rescanUsedAddresses(params *dagconfig.Params, rpcServer string, keysFile *keys.File, gapLimit uint32) error {
+	if gapLimit == 0 {
+		return errors.Errorf("--gap-limit must be positive")
+	}
+
+	rpcAddress, err := params.NormalizeRPCServerAddress(rpcServer)
+	if err != nil {
+		return err
+	}
+	rpcClient, err := rpcclient.NewRPCClient(rpcAddress)
+	if err != nil {
+		return errors.Wrapf(err, "Error connecting to RPC server %s", rpcAddress)
+	}
+	defer rpcClient.Close()
+
+	for _, keychain := range []uint32{libhtnwallet.ExternalKeychain, libhtnwallet.InternalKeychain} {
+		lastUsedIndex := uint32(0)
+		for start := uint32(1); start <= lastUsedIndex+gapLimit; {
+			end := lastUsedIndex + gapLimit
+
+			addressIndexes := make(map[string]uint32)
+			for index := start; index <= end; index++ {
+				for cosignerIndex := uint32(0); cosignerIndex < uint32(len(keysFile.ExtendedPublicKeys)); cosignerIndex++ {
+					path := walletAddressPath(keysFile, cosignerIndex, keychain, index)
+					address, err := libhtnwallet.Address(params, keysFile.ExtendedPublicKeys, keysFile.MinimumSignatures,
+						path, keysFile.ECDSA)
+					if err != nil {
+						return err
+					}
+					addressIndexes[address.String()] = index
+				}
+			}
+
+			addresses := make([]string, 0, len(addressIndexes))
+			for address := range addressIndexes {
+				addresses = append(addresses, address)
+			}
+			response, err := rpcClient.GetBalancesByAddresses(addresses)
+			if err != nil {
+				return err
+			}
+			for _, entry := range response.Entries {
+				index, ok := addressIndexes[entry.Address]
+				if !ok {
+					return errors.Errorf("Got result from address %s even though it wasn't requested", entry.Address)
+				}
+				if entry.Balance > 0 && index > lastUsedIndex {
+					lastUsedIndex = index
+				}
+			}
+
+			start = end + 1
+		}
+
+		if keychain == libhtnwallet.ExternalKeychain {
+			if lastUsedIndex > keysFile.LastUsedExternalIndex() {
+				err = keysFile.SetLastUsedExternalIndex(lastUsedIndex)
+			}
+			fmt.Printf("Last used external address index: %d\n", keysFile.LastUsedExternalIndex())
+		} else {
+			if lastUsedIndex > keysFile.LastUsedInternalIndex() {
+				err = keysFile.SetLastUsedInternalIndex(lastUsedIndex)
+			}
+			fmt.Printf("Last used internal address index: %d\n", keysFile.LastUsedInternalIndex())
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
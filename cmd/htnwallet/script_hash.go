@@ -0,0 +1,133 @@
+package main
+
+import (
+	"context"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/Hoosat-Oy/HTND/cmd/htnwallet/daemon/client"
+	"github.com/Hoosat-Oy/HTND/cmd/htnwallet/daemon/pb"
+	"github.com/Hoosat-Oy/HTND/cmd/htnwallet/keys"
+	"github.com/pkg/errors"
+)
+
+func createVault(conf *createVaultConfig) error {
+	daemonClient, tearDown, err := client.Connect(conf.DaemonAddress)
+	if err != nil {
+		return err
+	}
+	defer tearDown()
+
+	ctx, cancel := context.WithTimeout(context.Background(), daemonTimeout)
+	defer cancel()
+
+	response, err := daemonClient.CreateVaultAddress(ctx, &pb.CreateVaultAddressRequest{
+		TimeLockedAddress: conf.TimeLockedAddress,
+		RecoveryAddress:   conf.RecoveryAddress,
+		LockDaaScore:      conf.LockDAAScore,
+	})
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Vault address:\n%s\n\n", response.Address)
+	fmt.Printf("Spendable by %s after DAA score %d, and by %s at any time\n\n",
+		response.TimeLockedAddress, conf.LockDAAScore, response.RecoveryAddress)
+	fmt.Printf("Redeem script:\n%x\n\n", response.RedeemScript)
+	fmt.Println("Keep the redeem script, since it's required in order to spend the funds sent to the vault address")
+	return nil
+}
+
+func scriptHashAddress(conf *scriptHashAddressConfig) error {
+	redeemScript, err := hex.DecodeString(conf.RedeemScript)
+	if err != nil {
+		return errors.Wrap(err, "Failed to decode the redeem script")
+	}
+
+	daemonClient, tearDown, err := client.Connect(conf.DaemonAddress)
+	if err != nil {
+		return err
+	}
+	defer tearDown()
+
+	ctx, cancel := context.WithTimeout(context.Background(), daemonTimeout)
+	defer cancel()
+
+	response, err := daemonClient.GetScriptHashAddress(ctx, &pb.GetScriptHashAddressRequest{RedeemScript: redeemScript})
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Pay-to-script-hash address:\n%s\n", response.Address)
+	return nil
+}
+
+func spendScriptHash(conf *spendScriptHashConfig) error {
+	redeemScript, err := hex.DecodeString(conf.RedeemScript)
+	if err != nil {
+		return errors.Wrap(err, "Failed to decode the redeem script")
+	}
+
+	keysFile, err := keys.ReadKeysFile(conf.NetParams(), conf.KeysFile)
+	if err != nil {
+		return err
+	}
+
+	daemonClient, tearDown, err := client.Connect(conf.DaemonAddress)
+	if err != nil {
+		return err
+	}
+	defer tearDown()
+
+	ctx, cancel := context.WithTimeout(context.Background(), daemonTimeout)
+	defer cancel()
+
+	response, err := daemonClient.CreateUnsignedScriptHashSpendTransaction(ctx,
+		&pb.CreateUnsignedScriptHashSpendTransactionRequest{
+			RedeemScript:   redeemScript,
+			SigningAddress: conf.SigningAddress,
+			ToAddress:      conf.ToAddress,
+			LockTime:       conf.LockTime,
+		})
+	if err != nil {
+		return err
+	}
+
+	signTransaction, err := newTransactionSigner(conf.NetParams(), keysFile, conf.Password, &conf.externalSignerFlags)
+	if err != nil {
+		if strings.Contains(err.Error(), "message authentication failed") {
+			fmt.Fprintf(os.Stderr, "Password decryption failed. Sometimes this is a result of not "+
+				"specifying the same keys file used by the wallet daemon process.\n")
+		}
+		return err
+	}
+
+	signedTransaction, err := signTransaction(response.UnsignedTransaction)
+	if err != nil {
+		return err
+	}
+
+	// Since we waited for user input when getting the password, which could take unbound amount of time -
+	// create a new context for broadcast, to reset the timeout.
+	broadcastCtx, broadcastCancel := context.WithTimeout(context.Background(), daemonTimeout)
+	defer broadcastCancel()
+
+	broadcastResponse, err := daemonClient.Broadcast(broadcastCtx, &pb.BroadcastRequest{
+		Transactions: [][]byte{signedTransaction},
+	})
+	if err != nil {
+		return err
+	}
+
+	fmt.Println("Broadcasted Transaction ID: ")
+	fmt.Printf("\t%s\n", broadcastResponse.TxIDs[0])
+
+	if conf.Verbose {
+		fmt.Println("Serialized Transaction (can be parsed via the `parse` command or resent via `broadcast`): ")
+		fmt.Printf("\t%x\n\n", signedTransaction)
+	}
+
+	return nil
+}
@@ -9,7 +9,6 @@ import (
 	"github.com/Hoosat-Oy/HTND/cmd/htnwallet/daemon/client"
 	"github.com/Hoosat-Oy/HTND/cmd/htnwallet/daemon/pb"
 	"github.com/Hoosat-Oy/HTND/cmd/htnwallet/keys"
-	"github.com/Hoosat-Oy/HTND/cmd/htnwallet/libhtnwallet"
 	"github.com/Hoosat-Oy/HTND/cmd/htnwallet/utils"
 	"github.com/pkg/errors"
 )
@@ -20,7 +19,7 @@ func send(conf *sendConfig) error {
 		return err
 	}
 
-	if len(keysFile.ExtendedPublicKeys) > len(keysFile.EncryptedMnemonics) {
+	if !conf.externalSignerFlags.isSet() && len(keysFile.ExtendedPublicKeys) > len(keysFile.EncryptedMnemonics) {
 		return errors.Errorf("Cannot use 'send' command for multisig wallet without all of the keys")
 	}
 
@@ -54,10 +53,7 @@ func send(conf *sendConfig) error {
 		return err
 	}
 
-	if len(conf.Password) == 0 {
-		conf.Password = keys.GetPassword("Password:")
-	}
-	mnemonics, err := keysFile.DecryptMnemonics(conf.Password)
+	signTransaction, err := newTransactionSigner(conf.NetParams(), keysFile, conf.Password, &conf.externalSignerFlags)
 	if err != nil {
 		if strings.Contains(err.Error(), "message authentication failed") {
 			fmt.Fprintf(os.Stderr, "Password decryption failed. Sometimes this is a result of not "+
@@ -68,7 +64,7 @@ func send(conf *sendConfig) error {
 
 	signedTransactions := make([][]byte, len(createUnsignedTransactionsResponse.UnsignedTransactions))
 	for i, unsignedTransaction := range createUnsignedTransactionsResponse.UnsignedTransactions {
-		signedTransaction, err := libhtnwallet.Sign(conf.NetParams(), mnemonics, unsignedTransaction, keysFile.ECDSA)
+		signedTransaction, err := signTransaction(unsignedTransaction)
 		if err != nil {
 			return err
 		}
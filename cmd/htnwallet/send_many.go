@@ -0,0 +1,114 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strings"
+
+	"github.com/Hoosat-Oy/HTND/cmd/htnwallet/daemon/client"
+	"github.com/Hoosat-Oy/HTND/cmd/htnwallet/daemon/pb"
+	"github.com/Hoosat-Oy/HTND/cmd/htnwallet/keys"
+	"github.com/Hoosat-Oy/HTND/cmd/htnwallet/utils"
+	"github.com/pkg/errors"
+)
+
+func sendMany(conf *sendManyConfig) error {
+	keysFile, err := keys.ReadKeysFile(conf.NetParams(), conf.KeysFile)
+	if err != nil {
+		return err
+	}
+
+	if !conf.externalSignerFlags.isSet() && len(keysFile.ExtendedPublicKeys) > len(keysFile.EncryptedMnemonics) {
+		return errors.Errorf("Cannot use 'send-many' command for multisig wallet without all of the keys")
+	}
+
+	recipientsData, err := ioutil.ReadFile(conf.RecipientsFile)
+	if err != nil {
+		return errors.Wrapf(err, "Could not read recipients from %s", conf.RecipientsFile)
+	}
+	recipients, err := utils.ParseRecipients(recipientsData)
+	if err != nil {
+		return err
+	}
+	payments := make([]*pb.Payment, len(recipients))
+	for i, recipient := range recipients {
+		payments[i] = &pb.Payment{Address: recipient.Address, Amount: recipient.Amount}
+	}
+
+	daemonClient, tearDown, err := client.Connect(conf.DaemonAddress)
+	if err != nil {
+		return err
+	}
+	defer tearDown()
+
+	ctx, cancel := context.WithTimeout(context.Background(), daemonTimeout)
+	defer cancel()
+
+	createUnsignedTransactionsResponse, err :=
+		daemonClient.CreateUnsignedSendManyTransactions(ctx, &pb.CreateUnsignedSendManyTransactionsRequest{
+			Payments:                 payments,
+			From:                     conf.FromAddresses,
+			UseExistingChangeAddress: conf.UseExistingChangeAddress,
+		})
+	if err != nil {
+		return err
+	}
+
+	signTransaction, err := newTransactionSigner(conf.NetParams(), keysFile, conf.Password, &conf.externalSignerFlags)
+	if err != nil {
+		if strings.Contains(err.Error(), "message authentication failed") {
+			fmt.Fprintf(os.Stderr, "Password decryption failed. Sometimes this is a result of not "+
+				"specifying the same keys file used by the wallet daemon process.\n")
+		}
+		return err
+	}
+
+	signedTransactions := make([][]byte, len(createUnsignedTransactionsResponse.UnsignedTransactions))
+	for i, unsignedTransaction := range createUnsignedTransactionsResponse.UnsignedTransactions {
+		signedTransaction, err := signTransaction(unsignedTransaction)
+		if err != nil {
+			return err
+		}
+		signedTransactions[i] = signedTransaction
+	}
+
+	fmt.Printf("Broadcasting %d transaction(s) paying %d recipient(s)\n", len(signedTransactions), len(recipients))
+	// Since we waited for user input when getting the password, which could take unbound amount of time -
+	// create a new context for broadcast, to reset the timeout.
+	broadcastCtx, broadcastCancel := context.WithTimeout(context.Background(), daemonTimeout)
+	defer broadcastCancel()
+
+	txIDs := make([]string, 0, len(signedTransactions))
+	const chunkSize = 100 // To avoid sending a message bigger than the gRPC max message size, we split it to chunks
+	for offset := 0; offset < len(signedTransactions); offset += chunkSize {
+		end := len(signedTransactions)
+		if offset+chunkSize <= len(signedTransactions) {
+			end = offset + chunkSize
+		}
+
+		chunk := signedTransactions[offset:end]
+		response, err := daemonClient.Broadcast(broadcastCtx, &pb.BroadcastRequest{Transactions: chunk})
+		if err != nil {
+			return err
+		}
+		txIDs = append(txIDs, response.TxIDs...)
+		fmt.Printf("Broadcasted %d transaction(s) (broadcasted %.2f%% of the transactions so far)\n", len(chunk), 100*float64(end)/float64(len(signedTransactions)))
+	}
+
+	fmt.Println("Recipient Transaction ID(s): ")
+	for i, recipient := range recipients {
+		transactionIndex := createUnsignedTransactionsResponse.PaymentTransactionIndexes[i]
+		fmt.Printf("\t%s\t%s\t%s\n", recipient.Address, utils.FomatHSAT(recipient.Amount), txIDs[transactionIndex])
+	}
+
+	if conf.Verbose {
+		fmt.Println("Serialized Transaction(s) (can be parsed via the `parse` command or resent via `broadcast`): ")
+		for _, signedTx := range signedTransactions {
+			fmt.Printf("\t%x\n\n", signedTx)
+		}
+	}
+
+	return nil
+}
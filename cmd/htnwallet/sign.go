@@ -24,10 +24,7 @@ func sign(conf *signConfig) error {
 		return err
 	}
 
-	if len(conf.Password) == 0 {
-		conf.Password = keys.GetPassword("Password:")
-	}
-	privateKeys, err := keysFile.DecryptMnemonics(conf.Password)
+	signTransaction, err := newTransactionSigner(conf.NetParams(), keysFile, conf.Password, &conf.externalSignerFlags)
 	if err != nil {
 		return err
 	}
@@ -47,8 +44,7 @@ func sign(conf *signConfig) error {
 
 	updatedPartiallySignedTransactions := make([][]byte, len(partiallySignedTransactions))
 	for i, partiallySignedTransaction := range partiallySignedTransactions {
-		updatedPartiallySignedTransactions[i], err =
-			libhtnwallet.Sign(conf.NetParams(), privateKeys, partiallySignedTransaction, keysFile.ECDSA)
+		updatedPartiallySignedTransactions[i], err = signTransaction(partiallySignedTransaction)
 		if err != nil {
 			return err
 		}
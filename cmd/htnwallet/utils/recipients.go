@@ -0,0 +1,113 @@
+package utils
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// Recipient is a single address and the amount of sompi to pay it
+type Recipient struct {
+	Address string
+	Amount  uint64
+}
+
+type jsonRecipient struct {
+	Address string      `json:"address"`
+	Amount  json.Number `json:"amount"`
+}
+
+// ParseRecipients parses a list of recipients, where each amount is given in Hoosat (e.g. 1234.12345678).
+// The list is either a JSON array of {"address": ..., "amount": ...} objects, or CSV lines of the
+// form `address,amount`, optionally preceded by an `address,amount` header line.
+func ParseRecipients(data []byte) ([]*Recipient, error) {
+	data = bytes.TrimSpace(data)
+	if len(data) == 0 {
+		return nil, errors.Errorf("the recipients list is empty")
+	}
+
+	var recipients []*Recipient
+	var err error
+	if data[0] == '[' {
+		recipients, err = parseJSONRecipients(data)
+	} else {
+		recipients, err = parseCSVRecipients(data)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if len(recipients) == 0 {
+		return nil, errors.Errorf("the recipients list is empty")
+	}
+	return recipients, nil
+}
+
+func parseJSONRecipients(data []byte) ([]*Recipient, error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	decoder.DisallowUnknownFields()
+
+	var jsonRecipients []*jsonRecipient
+	err := decoder.Decode(&jsonRecipients)
+	if err != nil {
+		return nil, errors.Wrapf(err, "error parsing recipients JSON")
+	}
+
+	recipients := make([]*Recipient, len(jsonRecipients))
+	for i, jsonRecipient := range jsonRecipients {
+		recipients[i], err = newRecipient(i+1, jsonRecipient.Address, jsonRecipient.Amount.String())
+		if err != nil {
+			return nil, err
+		}
+	}
+	return recipients, nil
+}
+
+func parseCSVRecipients(data []byte) ([]*Recipient, error) {
+	reader := csv.NewReader(bytes.NewReader(data))
+	reader.FieldsPerRecord = 2
+	reader.TrimLeadingSpace = true
+	reader.Comment = '#'
+
+	var recipients []*Recipient
+	for lineNumber := 1; ; lineNumber++ {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, errors.Wrapf(err, "error parsing recipients CSV")
+		}
+
+		address, amount := strings.TrimSpace(record[0]), strings.TrimSpace(record[1])
+		if lineNumber == 1 && strings.EqualFold(address, "address") && strings.EqualFold(amount, "amount") {
+			continue
+		}
+
+		recipient, err := newRecipient(len(recipients)+1, address, amount)
+		if err != nil {
+			return nil, err
+		}
+		recipients = append(recipients, recipient)
+	}
+	return recipients, nil
+}
+
+func newRecipient(index int, address string, amount string) (*Recipient, error) {
+	if address == "" {
+		return nil, errors.Errorf("recipient #%d has no address", index)
+	}
+	sompi, err := KasToSompi(amount)
+	if err != nil {
+		return nil, errors.Wrapf(err, "recipient #%d (%s) has an invalid amount '%s'", index, address, amount)
+	}
+	if sompi == 0 {
+		return nil, errors.Errorf("recipient #%d (%s) has a zero amount", index, address)
+	}
+	return &Recipient{Address: address, Amount: sompi}, nil
+}
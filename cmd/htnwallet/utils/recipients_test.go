@@ -0,0 +1,65 @@
+package utils
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseRecipients(t *testing.T) {
+	expected := []*Recipient{
+		{Address: "hoosat:qz0s9f5hh9l4y3wmkg6ytcjnn6wgukv0hsqnmjukstrexeaanrt8q0a8cvyj7", Amount: 100000000},
+		{Address: "hoosat:qrf3gnwhhfqt6qnkdcwstmxh9w9dhy0zqjh6d3p7thquwlvhglk8kl3xeqs9s", Amount: 1234512345678},
+	}
+
+	validCases := []struct {
+		name string
+		data string
+	}{
+		{
+			name: "CSV",
+			data: "hoosat:qz0s9f5hh9l4y3wmkg6ytcjnn6wgukv0hsqnmjukstrexeaanrt8q0a8cvyj7,1\n" +
+				"hoosat:qrf3gnwhhfqt6qnkdcwstmxh9w9dhy0zqjh6d3p7thquwlvhglk8kl3xeqs9s,12345.12345678\n",
+		},
+		{
+			name: "CSV with header, comments and spaces",
+			data: "address,amount\n" +
+				"# pool payouts\n" +
+				"hoosat:qz0s9f5hh9l4y3wmkg6ytcjnn6wgukv0hsqnmjukstrexeaanrt8q0a8cvyj7, 1\n" +
+				"hoosat:qrf3gnwhhfqt6qnkdcwstmxh9w9dhy0zqjh6d3p7thquwlvhglk8kl3xeqs9s, 12345.12345678",
+		},
+		{
+			name: "JSON",
+			data: `[{"address": "hoosat:qz0s9f5hh9l4y3wmkg6ytcjnn6wgukv0hsqnmjukstrexeaanrt8q0a8cvyj7", "amount": 1},
+				{"address": "hoosat:qrf3gnwhhfqt6qnkdcwstmxh9w9dhy0zqjh6d3p7thquwlvhglk8kl3xeqs9s", "amount": "12345.12345678"}]`,
+		},
+	}
+
+	for _, validCase := range validCases {
+		recipients, err := ParseRecipients([]byte(validCase.data))
+		if err != nil {
+			t.Errorf("%s: ParseRecipients: %s", validCase.name, err)
+			continue
+		}
+		if !reflect.DeepEqual(recipients, expected) {
+			t.Errorf("%s: unexpected recipients %+v", validCase.name, recipients)
+		}
+	}
+
+	invalidCases := []string{
+		"",
+		"address,amount\n",
+		"hoosat:qz0s9f5hh9l4y3wmkg6ytcjnn6wgukv0hsqnmjukstrexeaanrt8q0a8cvyj7",
+		"hoosat:qz0s9f5hh9l4y3wmkg6ytcjnn6wgukv0hsqnmjukstrexeaanrt8q0a8cvyj7,0",
+		"hoosat:qz0s9f5hh9l4y3wmkg6ytcjnn6wgukv0hsqnmjukstrexeaanrt8q0a8cvyj7,-1",
+		",1",
+		`[{"address": "hoosat:qz0s9f5hh9l4y3wmkg6ytcjnn6wgukv0hsqnmjukstrexeaanrt8q0a8cvyj7", "amount": 1.123456789}]`,
+		`[{"address": "hoosat:qz0s9f5hh9l4y3wmkg6ytcjnn6wgukv0hsqnmjukstrexeaanrt8q0a8cvyj7", "value": 1}]`,
+	}
+
+	for _, invalidCase := range invalidCases {
+		_, err := ParseRecipients([]byte(invalidCase))
+		if err == nil {
+			t.Errorf("Expected an error but succeeded parsing %q", invalidCase)
+		}
+	}
+}
@@ -0,0 +1,114 @@
+package consensus
+
+// The caches of the consensus stores that are sized by cacheSizes
+const (
+	mergeDepthRootCache       = "merge-depth-roots"
+	daaWindowCache            = "daa-window"
+	acceptanceDataCache       = "acceptance-data"
+	blockCache                = "blocks"
+	blockHeaderCache          = "block-headers"
+	blockStatusCache          = "block-statuses"
+	multisetCache             = "multisets"
+	utxoDiffCache             = "utxo-diffs"
+	virtualUTXOSetCache       = "virtual-utxo-set"
+	finalityCache             = "finality-points"
+	headersSelectedChainCache = "headers-selected-chain"
+	daaScoreCache             = "daa-scores"
+	daaAddedBlocksCache       = "daa-added-blocks"
+	windowHeapSliceCache      = "block-window-heap-slices"
+	reachabilityDataCache     = "reachability-data"
+	blockRelationCache        = "block-relations"
+	ghostdagDataCache         = "ghostdag-data"
+)
+
+// minCacheSize is the smallest capacity a cache gets when the caches are
+// sized by a memory budget
+const minCacheSize = 100
+
+// cacheSpec describes a cache of a consensus store
+type cacheSpec struct {
+	// defaultSize is the capacity of the cache when there's no memory budget
+	defaultSize int
+	// entrySize is a rough estimate of the memory a single entry of the
+	// cache takes, including the overhead of its map
+	entrySize uint64
+}
+
+// cacheSpecs returns the specs of the caches of the consensus stores. The
+// caches of the stores of the block levels above zero are small and aren't
+// preallocated, so they keep their sizes.
+func cacheSpecs(config *Config) map[string]cacheSpec {
+	pruningWindowSize := int(config.PruningDepth())
+
+	// This is used for caches that are used as part of deletePastBlocks that need to traverse until
+	// the previous pruning point.
+	pruningWindowSizePlusFinalityDepth := int(config.PruningDepth() + config.FinalityDepth())
+
+	ghostdagDataCacheSize := pruningWindowSize * 2
+	if ghostdagDataCacheSize < config.DifficultyAdjustmentWindowSize {
+		ghostdagDataCacheSize = config.DifficultyAdjustmentWindowSize
+	}
+
+	return map[string]cacheSpec{
+		mergeDepthRootCache:       {defaultSize: 200, entrySize: 100},
+		daaWindowCache:            {defaultSize: 10_000, entrySize: 200},
+		acceptanceDataCache:       {defaultSize: 200, entrySize: 20_000},
+		blockCache:                {defaultSize: 200, entrySize: 50_000},
+		blockHeaderCache:          {defaultSize: 10_000, entrySize: 1_500},
+		blockStatusCache:          {defaultSize: pruningWindowSizePlusFinalityDepth, entrySize: 60},
+		multisetCache:             {defaultSize: 200, entrySize: 200},
+		utxoDiffCache:             {defaultSize: 200, entrySize: 20_000},
+		virtualUTXOSetCache:       {defaultSize: 10_000, entrySize: 200},
+		finalityCache:             {defaultSize: 200, entrySize: 100},
+		headersSelectedChainCache: {defaultSize: pruningWindowSize, entrySize: 160},
+		daaScoreCache:             {defaultSize: pruningWindowSize, entrySize: 60},
+		daaAddedBlocksCache:       {defaultSize: int(config.FinalityDepth()), entrySize: 300},
+		windowHeapSliceCache:      {defaultSize: 2000, entrySize: uint64(config.DifficultyAdjustmentWindowSize) * 64},
+		reachabilityDataCache:     {defaultSize: pruningWindowSizePlusFinalityDepth * 2, entrySize: 300},
+		blockRelationCache:        {defaultSize: pruningWindowSizePlusFinalityDepth, entrySize: 250},
+		ghostdagDataCache:         {defaultSize: ghostdagDataCacheSize, entrySize: 600},
+	}
+}
+
+// cacheSizes maps the caches of the consensus stores to their capacities
+type cacheSizes map[string]int
+
+// newCacheSizes returns the capacities of the caches of the consensus stores.
+// Without a memory budget the caches get their default sizes. Otherwise, the
+// budget is split between the caches in proportion to the memory they take
+// with their default sizes, so every cache shrinks or grows by the same ratio.
+func newCacheSizes(config *Config) cacheSizes {
+	specs := cacheSpecs(config)
+	sizes := make(cacheSizes, len(specs))
+	if config.CacheMemoryBudget == 0 {
+		for name, spec := range specs {
+			sizes[name] = spec.defaultSize
+		}
+		return sizes
+	}
+
+	defaultMemory := estimatedCacheMemory(specs, nil)
+	ratio := float64(config.CacheMemoryBudget) / float64(defaultMemory)
+	for name, spec := range specs {
+		size := int(float64(spec.defaultSize) * ratio)
+		if size < minCacheSize {
+			size = minCacheSize
+		}
+		sizes[name] = size
+	}
+	return sizes
+}
+
+// estimatedCacheMemory returns a rough estimate of the memory the given caches
+// take when they're full. nil sizes stand for the default sizes.
+func estimatedCacheMemory(specs map[string]cacheSpec, sizes cacheSizes) uint64 {
+	memory := uint64(0)
+	for name, spec := range specs {
+		size := spec.defaultSize
+		if sizes != nil {
+			size = sizes[name]
+		}
+		memory += uint64(size) * spec.entrySize
+	}
+	return memory
+}
@@ -0,0 +1,80 @@
+package consensus
+
+import (
+	"testing"
+
+	"github.com/Hoosat-Oy/HTND/domain/dagconfig"
+)
+
+func TestNewCacheSizes(t *testing.T) {
+	config := &Config{Params: dagconfig.MainnetParams}
+	specs := cacheSpecs(config)
+
+	defaultSizes := newCacheSizes(config)
+	for name, spec := range specs {
+		if defaultSizes[name] != spec.defaultSize {
+			t.Fatalf("expected the default size %d for the %s cache, got %d", spec.defaultSize, name, defaultSizes[name])
+		}
+	}
+	defaultMemory := estimatedCacheMemory(specs, defaultSizes)
+
+	const budget = 128 * 1024 * 1024
+	config.CacheMemoryBudget = budget
+	budgetSizes := newCacheSizes(config)
+	for name, size := range budgetSizes {
+		if size < minCacheSize {
+			t.Fatalf("the %s cache got %d entries, which is less than the minimum of %d", name, size, minCacheSize)
+		}
+		if size > defaultSizes[name] {
+			t.Fatalf("the %s cache got %d entries with a budget smaller than the default memory, "+
+				"which is more than its default size %d", name, size, defaultSizes[name])
+		}
+	}
+	budgetMemory := estimatedCacheMemory(specs, budgetSizes)
+	minimumsMemory := uint64(0)
+	for _, spec := range specs {
+		minimumsMemory += minCacheSize * spec.entrySize
+	}
+	if budgetMemory > budget+minimumsMemory {
+		t.Fatalf("the caches take %d bytes, which exceeds the budget of %d bytes", budgetMemory, budget)
+	}
+	if budgetMemory >= defaultMemory {
+		t.Fatalf("the caches take %d bytes with the budget, and %d bytes without it", budgetMemory, defaultMemory)
+	}
+}
+
+func TestCacheStats(t *testing.T) {
+	config := &Config{Params: dagconfig.MainnetParams, CacheMemoryBudget: 16 * 1024 * 1024}
+	tc, teardown, err := NewFactory().NewTestConsensus(config, "TestCacheStats")
+	if err != nil {
+		t.Fatalf("Error setting up consensus: %+v", err)
+	}
+	defer teardown(false)
+
+	_, err = tc.GetBlockHeader(config.GenesisHash)
+	if err != nil {
+		t.Fatalf("GetBlockHeader: %+v", err)
+	}
+	_, err = tc.GetBlockHeader(config.GenesisHash)
+	if err != nil {
+		t.Fatalf("GetBlockHeader: %+v", err)
+	}
+
+	sizes := newCacheSizes(config)
+	found := false
+	for _, stats := range tc.CacheStats() {
+		if stats.Name != blockHeaderCache {
+			continue
+		}
+		found = true
+		if stats.Capacity != sizes[blockHeaderCache] {
+			t.Fatalf("expected the capacity %d for the block header cache, got %d", sizes[blockHeaderCache], stats.Capacity)
+		}
+		if stats.Hits == 0 {
+			t.Fatalf("expected the block header cache to be hit")
+		}
+	}
+	if !found {
+		t.Fatalf("the stats of the block header cache are missing")
+	}
+}
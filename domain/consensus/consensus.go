@@ -60,6 +60,9 @@ type consensus struct {
 	daaBlocksStore                      model.DAABlocksStore
 	blocksWithTrustedDataDAAWindowStore model.BlocksWithTrustedDataDAAWindowStore
 
+	// stores are the block level zero stores whose cache statistics are reported by CacheStats
+	stores []model.Store
+
 	consensusEventsChan chan externalapi.ConsensusEvent
 	virtualNotUpdated   bool
 }
@@ -1146,3 +1149,15 @@ func (s *consensus) isNearlySyncedNoLock() (bool, error) {
 		virtualSelectedParentHeader.TimeInMilliseconds())
 	return false, nil
 }
+
+// CacheStats returns the usage statistics of the caches of the consensus stores
+func (s *consensus) CacheStats() []*externalapi.CacheStats {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	var cacheStats []*externalapi.CacheStats
+	for _, store := range s.stores {
+		cacheStats = append(cacheStats, store.CacheStats()...)
+	}
+	return cacheStats
+}
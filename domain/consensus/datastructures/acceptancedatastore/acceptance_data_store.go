@@ -27,6 +27,13 @@ func New(prefixBucket model.DBBucket, cacheSize int, preallocate bool) model.Acc
 	}
 }
 
+// CacheStats returns the usage statistics of the caches of the store
+func (ads *acceptanceDataStore) CacheStats() []*externalapi.CacheStats {
+	return []*externalapi.CacheStats{
+		ads.cache.Stats("acceptance-data"),
+	}
+}
+
 // Stage stages the given acceptanceData for the given blockHash
 func (ads *acceptanceDataStore) Stage(stagingArea *model.StagingArea, blockHash *externalapi.DomainHash, acceptanceData externalapi.AcceptanceData) {
 	stagingShard := ads.stagingShard(stagingArea)
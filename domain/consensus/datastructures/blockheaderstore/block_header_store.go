@@ -38,6 +38,13 @@ func New(dbContext model.DBReader, prefixBucket model.DBBucket, cacheSize int, p
 	return blockHeaderStore, nil
 }
 
+// CacheStats returns the usage statistics of the caches of the store
+func (bhs *blockHeaderStore) CacheStats() []*externalapi.CacheStats {
+	return []*externalapi.CacheStats{
+		bhs.cache.Stats("block-headers"),
+	}
+}
+
 func (bhs *blockHeaderStore) initializeCount(dbContext model.DBReader) error {
 	count := uint64(0)
 	hasCountBytes, err := dbContext.Has(bhs.countKey)
@@ -27,6 +27,13 @@ func New(prefixBucket model.DBBucket, cacheSize int, preallocate bool) model.Blo
 	}
 }
 
+// CacheStats returns the usage statistics of the caches of the store
+func (brs *blockRelationStore) CacheStats() []*externalapi.CacheStats {
+	return []*externalapi.CacheStats{
+		brs.cache.Stats("block-relations"),
+	}
+}
+
 func (brs *blockRelationStore) StageBlockRelation(stagingArea *model.StagingArea, blockHash *externalapi.DomainHash, blockRelations *model.BlockRelations) {
 	stagingShard := brs.stagingShard(stagingArea)
 
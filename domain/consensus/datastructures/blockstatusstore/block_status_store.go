@@ -27,6 +27,13 @@ func New(prefixBucket model.DBBucket, cacheSize int, preallocate bool) model.Blo
 	}
 }
 
+// CacheStats returns the usage statistics of the caches of the store
+func (bss *blockStatusStore) CacheStats() []*externalapi.CacheStats {
+	return []*externalapi.CacheStats{
+		bss.cache.Stats("block-statuses"),
+	}
+}
+
 // Stage stages the given blockStatus for the given blockHash
 func (bss *blockStatusStore) Stage(stagingArea *model.StagingArea, blockHash *externalapi.DomainHash, blockStatus externalapi.BlockStatus) {
 	stagingShard := bss.stagingShard(stagingArea)
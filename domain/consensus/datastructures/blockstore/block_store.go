@@ -38,6 +38,13 @@ func New(dbContext model.DBReader, prefixBucket model.DBBucket, cacheSize int, p
 	return blockStore, nil
 }
 
+// CacheStats returns the usage statistics of the caches of the store
+func (bs *blockStore) CacheStats() []*externalapi.CacheStats {
+	return []*externalapi.CacheStats{
+		bs.cache.Stats("blocks"),
+	}
+}
+
 func (bs *blockStore) initializeCount(dbContext model.DBReader) error {
 	count := uint64(0)
 	hasCountBytes, err := dbContext.Has(bs.countKey)
@@ -22,6 +22,13 @@ func New(cacheSize int, preallocate bool) model.WindowHeapSliceStore {
 	}
 }
 
+// CacheStats returns the usage statistics of the caches of the store
+func (bss *blockWindowHeapSliceStore) CacheStats() []*externalapi.CacheStats {
+	return []*externalapi.CacheStats{
+		bss.cache.Stats("block-window-heap-slices"),
+	}
+}
+
 // Stage stages the given blockStatus for the given blockHash
 func (bss *blockWindowHeapSliceStore) Stage(stagingArea *model.StagingArea, blockHash *externalapi.DomainHash, windowSize int, heapSlice []*externalapi.BlockGHOSTDAGDataHashPair) {
 	stagingShard := bss.stagingShard(stagingArea)
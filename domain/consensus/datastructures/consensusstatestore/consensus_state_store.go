@@ -30,6 +30,13 @@ func New(prefixBucket model.DBBucket, utxoSetCacheSize int, preallocate bool) mo
 	}
 }
 
+// CacheStats returns the usage statistics of the caches of the store
+func (css *consensusStateStore) CacheStats() []*externalapi.CacheStats {
+	return []*externalapi.CacheStats{
+		css.virtualUTXOSetCache.Stats("virtual-utxo-set"),
+	}
+}
+
 func (css *consensusStateStore) IsStaged(stagingArea *model.StagingArea) bool {
 	return css.stagingShard(stagingArea).isStaged()
 }
@@ -31,6 +31,14 @@ func New(prefixBucket model.DBBucket, daaScoreCacheSize int, daaAddedBlocksCache
 	}
 }
 
+// CacheStats returns the usage statistics of the caches of the store
+func (daas *daaBlocksStore) CacheStats() []*externalapi.CacheStats {
+	return []*externalapi.CacheStats{
+		daas.daaScoreLRUCache.Stats("daa-scores"),
+		daas.daaAddedBlocksLRUCache.Stats("daa-added-blocks"),
+	}
+}
+
 func (daas *daaBlocksStore) StageDAAScore(stagingArea *model.StagingArea, blockHash *externalapi.DomainHash, daaScore uint64) {
 	stagingShard := daas.stagingShard(stagingArea)
 
@@ -30,6 +30,13 @@ func New(prefixBucket model.DBBucket, cacheSize int, preallocate bool) model.Blo
 	}
 }
 
+// CacheStats returns the usage statistics of the caches of the store
+func (daaws *daaWindowStore) CacheStats() []*externalapi.CacheStats {
+	return []*externalapi.CacheStats{
+		daaws.cache.Stats("daa-window"),
+	}
+}
+
 func (daaws *daaWindowStore) Stage(stagingArea *model.StagingArea, blockHash *externalapi.DomainHash, index uint64, pair *externalapi.BlockGHOSTDAGDataHashPair) {
 	stagingShard := daaws.stagingShard(stagingArea)
 
@@ -24,6 +24,13 @@ func New(prefixBucket model.DBBucket, cacheSize int, preallocate bool) model.Fin
 	}
 }
 
+// CacheStats returns the usage statistics of the caches of the store
+func (fs *finalityStore) CacheStats() []*externalapi.CacheStats {
+	return []*externalapi.CacheStats{
+		fs.cache.Stats("finality-points"),
+	}
+}
+
 func (fs *finalityStore) StageFinalityPoint(stagingArea *model.StagingArea, blockHash *externalapi.DomainHash, finalityPointHash *externalapi.DomainHash) {
 	stagingShard := fs.stagingShard(stagingArea)
 
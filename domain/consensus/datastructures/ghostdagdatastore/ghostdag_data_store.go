@@ -30,6 +30,13 @@ func New(prefixBucket model.DBBucket, cacheSize int, preallocate bool) model.GHO
 	}
 }
 
+// CacheStats returns the usage statistics of the caches of the store
+func (gds *ghostdagDataStore) CacheStats() []*externalapi.CacheStats {
+	return []*externalapi.CacheStats{
+		gds.cache.Stats("ghostdag-data"),
+	}
+}
+
 // Stage stages the given blockGHOSTDAGData for the given blockHash
 func (gds *ghostdagDataStore) Stage(stagingArea *model.StagingArea, blockHash *externalapi.DomainHash,
 	blockGHOSTDAGData *externalapi.BlockGHOSTDAGData, isTrustedData bool) {
@@ -40,6 +40,14 @@ func New(prefixBucket model.DBBucket, cacheSize int, preallocate bool) model.Hea
 	}
 }
 
+// CacheStats returns the usage statistics of the caches of the store
+func (hscs *headersSelectedChainStore) CacheStats() []*externalapi.CacheStats {
+	return []*externalapi.CacheStats{
+		hscs.cacheByIndex.Stats("headers-selected-chain-by-index"),
+		hscs.cacheByHash.Stats("headers-selected-chain-by-hash"),
+	}
+}
+
 // Stage stages the given chain changes
 func (hscs *headersSelectedChainStore) Stage(dbContext model.DBReader, stagingArea *model.StagingArea, chainChanges *externalapi.SelectedChainPath) error {
 	stagingShard := hscs.stagingShard(stagingArea)
@@ -24,6 +24,11 @@ func New(prefixBucket model.DBBucket) model.HeaderSelectedTipStore {
 	}
 }
 
+// CacheStats returns nil, since the store only caches a single value
+func (hsts *headerSelectedTipStore) CacheStats() []*externalapi.CacheStats {
+	return nil
+}
+
 func (hsts *headerSelectedTipStore) Has(dbContext model.DBReader, stagingArea *model.StagingArea) (bool, error) {
 	stagingShard := hsts.stagingShard(stagingArea)
 
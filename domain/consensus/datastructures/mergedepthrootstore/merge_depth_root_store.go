@@ -24,6 +24,13 @@ func New(prefixBucket model.DBBucket, cacheSize int, preallocate bool) model.Mer
 	}
 }
 
+// CacheStats returns the usage statistics of the caches of the store
+func (mdrs *mergeDepthRootStore) CacheStats() []*externalapi.CacheStats {
+	return []*externalapi.CacheStats{
+		mdrs.cache.Stats("merge-depth-roots"),
+	}
+}
+
 func (mdrs *mergeDepthRootStore) StageMergeDepthRoot(stagingArea *model.StagingArea, blockHash *externalapi.DomainHash, root *externalapi.DomainHash) {
 	stagingShard := mdrs.stagingShard(stagingArea)
 
@@ -27,6 +27,13 @@ func New(prefixBucket model.DBBucket, cacheSize int, preallocate bool) model.Mul
 	}
 }
 
+// CacheStats returns the usage statistics of the caches of the store
+func (ms *multisetStore) CacheStats() []*externalapi.CacheStats {
+	return []*externalapi.CacheStats{
+		ms.cache.Stats("multisets"),
+	}
+}
+
 // Stage stages the given multiset for the given blockHash
 func (ms *multisetStore) Stage(stagingArea *model.StagingArea, blockHash *externalapi.DomainHash, multiset model.Multiset) {
 	stagingShard := ms.stagingShard(stagingArea)
@@ -51,6 +51,13 @@ func New(prefixBucket model.DBBucket, cacheSize int, preallocate bool) model.Pru
 	}
 }
 
+// CacheStats returns the usage statistics of the caches of the store
+func (ps *pruningStore) CacheStats() []*externalapi.CacheStats {
+	return []*externalapi.CacheStats{
+		ps.pruningPointByIndexCache.Stats("pruning-points-by-index"),
+	}
+}
+
 func (ps *pruningStore) StagePruningPointCandidate(stagingArea *model.StagingArea, candidate *externalapi.DomainHash) {
 	stagingShard := ps.stagingShard(stagingArea)
 
@@ -34,6 +34,13 @@ func New(prefixBucket model.DBBucket, cacheSize int, preallocate bool) model.Rea
 	}
 }
 
+// CacheStats returns the usage statistics of the caches of the store
+func (rds *reachabilityDataStore) CacheStats() []*externalapi.CacheStats {
+	return []*externalapi.CacheStats{
+		rds.reachabilityDataCache.Stats("reachability-data"),
+	}
+}
+
 // StageReachabilityData stages the given reachabilityData for the given blockHash
 func (rds *reachabilityDataStore) StageReachabilityData(stagingArea *model.StagingArea, blockHash *externalapi.DomainHash, reachabilityData model.ReachabilityData) {
 	stagingShard := rds.stagingShard(stagingArea)
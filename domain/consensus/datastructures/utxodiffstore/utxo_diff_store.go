@@ -33,6 +33,14 @@ func New(prefixBucket model.DBBucket, cacheSize int, preallocate bool) model.UTX
 	}
 }
 
+// CacheStats returns the usage statistics of the caches of the store
+func (uds *utxoDiffStore) CacheStats() []*externalapi.CacheStats {
+	return []*externalapi.CacheStats{
+		uds.utxoDiffCache.Stats("utxo-diffs"),
+		uds.utxoDiffChildCache.Stats("utxo-diff-children"),
+	}
+}
+
 // Stage stages the given utxoDiff for the given blockHash
 func (uds *utxoDiffStore) Stage(stagingArea *model.StagingArea, blockHash *externalapi.DomainHash,
 	utxoDiff externalapi.UTXODiff, utxoDiffChild *externalapi.DomainHash) {
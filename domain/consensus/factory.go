@@ -55,7 +55,7 @@ import (
 	"github.com/Hoosat-Oy/HTND/domain/consensus/processes/transactionvalidator"
 	"github.com/Hoosat-Oy/HTND/domain/dagconfig"
 	infrastructuredatabase "github.com/Hoosat-Oy/HTND/infrastructure/db/database"
-	"github.com/Hoosat-Oy/HTND/infrastructure/db/database/ldb"
+	"github.com/Hoosat-Oy/HTND/infrastructure/db/database/backend"
 )
 
 const (
@@ -71,6 +71,11 @@ type Config struct {
 	IsArchival bool
 	// EnableSanityCheckPruningUTXOSet checks the full pruning point utxo set against the commitment at every pruning movement
 	EnableSanityCheckPruningUTXOSet bool
+	// VerifyVirtualUTXOSet checks the full virtual utxo set against its commitment when the consensus is created
+	VerifyVirtualUTXOSet bool
+	// CacheMemoryBudget is the memory in bytes that the caches of the consensus stores may take. Zero keeps the
+	// default cache sizes
+	CacheMemoryBudget uint64
 
 	SkipAddingGenesis bool
 }
@@ -86,6 +91,7 @@ type Factory interface {
 	SetTestDataDir(dataDir string)
 	SetTestGHOSTDAGManager(ghostdagConstructor GHOSTDAGManagerConstructor)
 	SetTestLevelDBCacheSize(cacheSizeMiB int)
+	SetTestDatabaseType(dbType string)
 	SetTestPreAllocateCache(preallocateCaches bool)
 	SetTestPastMedianTimeManager(medianTimeConstructor PastMedianTimeManagerConstructor)
 	SetTestDifficultyManager(difficultyConstructor DifficultyManagerConstructor)
@@ -98,6 +104,7 @@ type factory struct {
 	difficultyConstructor    DifficultyManagerConstructor
 	cacheSizeMiB             *int
 	preallocateCaches        *bool
+	dbType                   string
 }
 
 // NewFactory creates a new Consensus factory
@@ -117,8 +124,6 @@ func (f *factory) NewConsensus(config *Config, db infrastructuredatabase.Databas
 	dbManager := consensusdatabase.New(db)
 	prefixBucket := consensusdatabase.MakeBucket(dbPrefix.Serialize())
 
-	pruningWindowSizeForCaches := int(config.PruningDepth())
-
 	var preallocateCaches bool
 	if f.preallocateCaches != nil {
 		preallocateCaches = *f.preallocateCaches
@@ -126,37 +131,39 @@ func (f *factory) NewConsensus(config *Config, db infrastructuredatabase.Databas
 		preallocateCaches = defaultPreallocateCaches
 	}
 
-	// This is used for caches that are used as part of deletePastBlocks that need to traverse until
-	// the previous pruning point.
-	pruningWindowSizePlusFinalityDepthForCache := int(config.PruningDepth() + config.FinalityDepth())
+	cacheSizes := newCacheSizes(config)
+	if config.CacheMemoryBudget != 0 {
+		log.Infof("The consensus caches are sized to take up to about %d MiB",
+			estimatedCacheMemory(cacheSpecs(config), cacheSizes)/(1024*1024))
+	}
 
 	// Data Structures
-	mergeDepthRootStore := mergedepthrootstore.New(prefixBucket, 200, preallocateCaches)
-	daaWindowStore := daawindowstore.New(prefixBucket, 10_000, preallocateCaches)
-	acceptanceDataStore := acceptancedatastore.New(prefixBucket, 200, preallocateCaches)
-	blockStore, err := blockstore.New(dbManager, prefixBucket, 200, preallocateCaches)
+	mergeDepthRootStore := mergedepthrootstore.New(prefixBucket, cacheSizes[mergeDepthRootCache], preallocateCaches)
+	daaWindowStore := daawindowstore.New(prefixBucket, cacheSizes[daaWindowCache], preallocateCaches)
+	acceptanceDataStore := acceptancedatastore.New(prefixBucket, cacheSizes[acceptanceDataCache], preallocateCaches)
+	blockStore, err := blockstore.New(dbManager, prefixBucket, cacheSizes[blockCache], preallocateCaches)
 	if err != nil {
 		return nil, false, err
 	}
-	blockHeaderStore, err := blockheaderstore.New(dbManager, prefixBucket, 10_000, preallocateCaches)
+	blockHeaderStore, err := blockheaderstore.New(dbManager, prefixBucket, cacheSizes[blockHeaderCache], preallocateCaches)
 	if err != nil {
 		return nil, false, err
 	}
 
-	blockStatusStore := blockstatusstore.New(prefixBucket, pruningWindowSizePlusFinalityDepthForCache, preallocateCaches)
-	multisetStore := multisetstore.New(prefixBucket, 200, preallocateCaches)
+	blockStatusStore := blockstatusstore.New(prefixBucket, cacheSizes[blockStatusCache], preallocateCaches)
+	multisetStore := multisetstore.New(prefixBucket, cacheSizes[multisetCache], preallocateCaches)
 	pruningStore := pruningstore.New(prefixBucket, 2, preallocateCaches)
-	utxoDiffStore := utxodiffstore.New(prefixBucket, 200, preallocateCaches)
-	consensusStateStore := consensusstatestore.New(prefixBucket, 10_000, preallocateCaches)
+	utxoDiffStore := utxodiffstore.New(prefixBucket, cacheSizes[utxoDiffCache], preallocateCaches)
+	consensusStateStore := consensusstatestore.New(prefixBucket, cacheSizes[virtualUTXOSetCache], preallocateCaches)
 
 	headersSelectedTipStore := headersselectedtipstore.New(prefixBucket)
-	finalityStore := finalitystore.New(prefixBucket, 200, preallocateCaches)
-	headersSelectedChainStore := headersselectedchainstore.New(prefixBucket, pruningWindowSizeForCaches, preallocateCaches)
-	daaBlocksStore := daablocksstore.New(prefixBucket, pruningWindowSizeForCaches, int(config.FinalityDepth()), preallocateCaches)
-	windowHeapSliceStore := blockwindowheapslicestore.New(2000, preallocateCaches)
+	finalityStore := finalitystore.New(prefixBucket, cacheSizes[finalityCache], preallocateCaches)
+	headersSelectedChainStore := headersselectedchainstore.New(prefixBucket, cacheSizes[headersSelectedChainCache], preallocateCaches)
+	daaBlocksStore := daablocksstore.New(prefixBucket, cacheSizes[daaScoreCache], cacheSizes[daaAddedBlocksCache], preallocateCaches)
+	windowHeapSliceStore := blockwindowheapslicestore.New(cacheSizes[windowHeapSliceCache], preallocateCaches)
 
-	newReachabilityDataStore := reachabilitydatastore.New(prefixBucket, pruningWindowSizePlusFinalityDepthForCache*2, preallocateCaches)
-	blockRelationStores, reachabilityDataStores, ghostdagDataStores := dagStores(config, prefixBucket, pruningWindowSizePlusFinalityDepthForCache, pruningWindowSizeForCaches, preallocateCaches)
+	newReachabilityDataStore := reachabilitydatastore.New(prefixBucket, cacheSizes[reachabilityDataCache], preallocateCaches)
+	blockRelationStores, reachabilityDataStores, ghostdagDataStores := dagStores(config, prefixBucket, cacheSizes, preallocateCaches)
 	oldReachabilityManager := reachabilitymanager.New(
 		dbManager,
 		ghostdagDataStores[0],
@@ -519,6 +526,25 @@ func (f *factory) NewConsensus(config *Config, db infrastructuredatabase.Databas
 		headersSelectedChainStore:           headersSelectedChainStore,
 		daaBlocksStore:                      daaBlocksStore,
 		blocksWithTrustedDataDAAWindowStore: daaWindowStore,
+		stores: []model.Store{
+			mergeDepthRootStore,
+			daaWindowStore,
+			acceptanceDataStore,
+			blockStore,
+			blockHeaderStore,
+			blockStatusStore,
+			multisetStore,
+			pruningStore,
+			utxoDiffStore,
+			consensusStateStore,
+			finalityStore,
+			headersSelectedChainStore,
+			daaBlocksStore,
+			windowHeapSliceStore,
+			reachabilityDataStore,
+			blockRelationStore,
+			ghostdagDataStore,
+		},
 
 		consensusEventsChan: consensusEventsChan,
 		virtualNotUpdated:   true,
@@ -564,6 +590,21 @@ func (f *factory) NewConsensus(config *Config, db infrastructuredatabase.Databas
 		return nil, false, err
 	}
 
+	isVirtualStateConsistent, err := consensusStateManager.IsVirtualStateConsistent(config.VerifyVirtualUTXOSet)
+	if err != nil {
+		return nil, false, err
+	}
+	if !isVirtualStateConsistent {
+		log.Warnf("The virtual state is inconsistent. Rolling it back to the pruning point...")
+		err = consensusStateManager.RollbackVirtualToPruningPoint()
+		if err != nil {
+			return nil, false, errors.Wrapf(err, "failed rolling back the virtual state to the "+
+				"pruning point -- the database has to be reset with --reset-db")
+		}
+		log.Warnf("The virtual state was rolled back to the pruning point. " +
+			"The blocks above it will be revalidated")
+	}
+
 	return c, false, nil
 }
 
@@ -585,7 +626,7 @@ func (f *factory) NewTestConsensus(config *Config, testName string) (
 	if f.preallocateCaches == nil {
 		f.SetTestPreAllocateCache(defaultTestPreallocateCaches)
 	}
-	db, err := ldb.NewLevelDB(datadir, cacheSizeMiB)
+	db, err := backend.Open(f.dbType, datadir, cacheSizeMiB, false)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -646,33 +687,33 @@ func (f *factory) SetTestDifficultyManager(difficultyConstructor DifficultyManag
 func (f *factory) SetTestLevelDBCacheSize(cacheSizeMiB int) {
 	f.cacheSizeMiB = &cacheSizeMiB
 }
+
+// SetTestDatabaseType sets the database backend that test consensuses are created with
+func (f *factory) SetTestDatabaseType(dbType string) {
+	f.dbType = dbType
+}
 func (f *factory) SetTestPreAllocateCache(preallocateCaches bool) {
 	f.preallocateCaches = &preallocateCaches
 }
 
 func dagStores(config *Config,
 	prefixBucket model.DBBucket,
-	pruningWindowSizePlusFinalityDepthForCache, pruningWindowSizeForCaches int,
+	cacheSizes cacheSizes,
 	preallocateCaches bool) ([]model.BlockRelationStore, []model.ReachabilityDataStore, []model.GHOSTDAGDataStore) {
 
 	blockRelationStores := make([]model.BlockRelationStore, config.MaxBlockLevel+1)
 	reachabilityDataStores := make([]model.ReachabilityDataStore, config.MaxBlockLevel+1)
 	ghostdagDataStores := make([]model.GHOSTDAGDataStore, config.MaxBlockLevel+1)
 
-	ghostdagDataCacheSize := pruningWindowSizeForCaches * 2
-	if ghostdagDataCacheSize < config.DifficultyAdjustmentWindowSize {
-		ghostdagDataCacheSize = config.DifficultyAdjustmentWindowSize
-	}
-
 	for i := 0; i <= config.MaxBlockLevel; i++ {
 		prefixBucket := prefixBucket.Bucket([]byte{byte(i)})
 		if i == 0 {
-			blockRelationStores[i] = blockrelationstore.New(prefixBucket, pruningWindowSizePlusFinalityDepthForCache, preallocateCaches)
-			reachabilityDataStores[i] = reachabilitydatastore.New(prefixBucket, pruningWindowSizePlusFinalityDepthForCache*2, preallocateCaches)
-			ghostdagDataStores[i] = ghostdagdatastore.New(prefixBucket, ghostdagDataCacheSize, preallocateCaches)
+			blockRelationStores[i] = blockrelationstore.New(prefixBucket, cacheSizes[blockRelationCache], preallocateCaches)
+			reachabilityDataStores[i] = reachabilitydatastore.New(prefixBucket, cacheSizes[reachabilityDataCache], preallocateCaches)
+			ghostdagDataStores[i] = ghostdagdatastore.New(prefixBucket, cacheSizes[ghostdagDataCache], preallocateCaches)
 		} else {
 			blockRelationStores[i] = blockrelationstore.New(prefixBucket, 200, false)
-			reachabilityDataStores[i] = reachabilitydatastore.New(prefixBucket, pruningWindowSizePlusFinalityDepthForCache, false)
+			reachabilityDataStores[i] = reachabilitydatastore.New(prefixBucket, cacheSizes[reachabilityDataCache]/2, false)
 			ghostdagDataStores[i] = ghostdagdatastore.New(prefixBucket, 200, false)
 		}
 	}
@@ -0,0 +1,10 @@
+package externalapi
+
+// CacheStats holds the usage statistics of an in-memory cache of the consensus
+type CacheStats struct {
+	Name     string
+	Size     int
+	Capacity int
+	Hits     uint64
+	Misses   uint64
+}
@@ -1,5 +1,9 @@
 package model
 
+import "github.com/Hoosat-Oy/HTND/domain/consensus/model/externalapi"
+
 // Store is a common interface for data stores
 type Store interface {
+	// CacheStats returns the usage statistics of the in-memory caches of the store
+	CacheStats() []*externalapi.CacheStats
 }
@@ -0,0 +1,267 @@
+package consensusstatemanager
+
+import (
+	"github.com/Hoosat-Oy/HTND/domain/consensus/database"
+	"github.com/Hoosat-Oy/HTND/domain/consensus/model"
+	"github.com/Hoosat-Oy/HTND/domain/consensus/model/externalapi"
+	"github.com/Hoosat-Oy/HTND/domain/consensus/utils/multiset"
+	"github.com/Hoosat-Oy/HTND/domain/consensus/utils/utxo"
+	"github.com/Hoosat-Oy/HTND/infrastructure/logger"
+	"github.com/Hoosat-Oy/HTND/util/staging"
+)
+
+// rollbackUTXOChunkSize is the amount of UTXOs copied from the pruning
+// point UTXO set into the imported UTXO set in a single database transaction
+const rollbackUTXOChunkSize = 1000
+
+// IsVirtualStateConsistent checks that the virtual has a UTXO-valid selected
+// parent and that all of its parents are DAG tips. If verifyUTXOSet is set, it
+// also checks that the multiset of the virtual UTXO set matches the virtual's
+// UTXO commitment, which requires going over the whole UTXO set.
+func (csm *consensusStateManager) IsVirtualStateConsistent(verifyUTXOSet bool) (bool, error) {
+	onEnd := logger.LogAndMeasureExecutionTime(log, "IsVirtualStateConsistent")
+	defer onEnd()
+
+	stagingArea := model.NewStagingArea()
+
+	// A consensus without a pruning point hasn't built its virtual yet
+	hasPruningPoint, err := csm.pruningStore.HasPruningPoint(csm.databaseContext, stagingArea)
+	if err != nil {
+		return false, err
+	}
+	if !hasPruningPoint {
+		return true, nil
+	}
+
+	isConsistent, err := csm.isVirtualStateConsistent(stagingArea, verifyUTXOSet)
+	if database.IsNotFoundError(err) {
+		log.Warnf("Missing virtual state data: %s", err)
+		return false, nil
+	}
+	return isConsistent, err
+}
+
+func (csm *consensusStateManager) isVirtualStateConsistent(stagingArea *model.StagingArea, verifyUTXOSet bool) (bool, error) {
+	virtualSelectedParent, err := csm.virtualSelectedParent(stagingArea)
+	if err != nil {
+		return false, err
+	}
+	virtualSelectedParentStatus, err := csm.blockStatusStore.Get(csm.databaseContext, stagingArea, virtualSelectedParent)
+	if err != nil {
+		return false, err
+	}
+	if virtualSelectedParentStatus != externalapi.StatusUTXOValid {
+		log.Warnf("The virtual selected parent %s has status %s", virtualSelectedParent, virtualSelectedParentStatus)
+		return false, nil
+	}
+
+	virtualParents, err := csm.dagTopologyManager.Parents(stagingArea, model.VirtualBlockHash)
+	if err != nil {
+		return false, err
+	}
+	tips, err := csm.consensusStateStore.Tips(stagingArea, csm.databaseContext)
+	if err != nil {
+		return false, err
+	}
+	tipsSet := make(map[externalapi.DomainHash]struct{}, len(tips))
+	for _, tip := range tips {
+		tipsSet[*tip] = struct{}{}
+	}
+	for _, virtualParent := range virtualParents {
+		if _, ok := tipsSet[*virtualParent]; !ok {
+			log.Warnf("The virtual parent %s is not a DAG tip", virtualParent)
+			return false, nil
+		}
+	}
+
+	virtualMultiset, err := csm.multisetStore.Get(csm.databaseContext, stagingArea, model.VirtualBlockHash)
+	if err != nil {
+		return false, err
+	}
+	if !verifyUTXOSet {
+		return true, nil
+	}
+
+	log.Infof("Verifying the virtual UTXO set against its commitment %s", virtualMultiset.Hash())
+	virtualUTXOSetIterator, err := csm.consensusStateStore.VirtualUTXOSetIterator(csm.databaseContext, stagingArea)
+	if err != nil {
+		return false, err
+	}
+	defer virtualUTXOSetIterator.Close()
+
+	utxoSetMultiset := multiset.New()
+	for ok := virtualUTXOSetIterator.First(); ok; ok = virtualUTXOSetIterator.Next() {
+		outpoint, entry, err := virtualUTXOSetIterator.Get()
+		if err != nil {
+			return false, err
+		}
+		err = addUTXOToMultiset(utxoSetMultiset, entry, outpoint)
+		if err != nil {
+			return false, err
+		}
+	}
+
+	if !utxoSetMultiset.Hash().Equal(virtualMultiset.Hash()) {
+		log.Warnf("The virtual UTXO set multiset is %s while the virtual UTXO commitment is %s",
+			utxoSetMultiset.Hash(), virtualMultiset.Hash())
+		return false, nil
+	}
+	return true, nil
+}
+
+// RollbackVirtualToPruningPoint rebuilds the virtual state from the UTXO set of
+// the current pruning point. All the blocks in the future of the pruning point
+// go back to be pending UTXO verification, so that the next virtual resolution
+// revalidates them on top of the pruning point UTXO set.
+func (csm *consensusStateManager) RollbackVirtualToPruningPoint() error {
+	onEnd := logger.LogAndMeasureExecutionTime(log, "RollbackVirtualToPruningPoint")
+	defer onEnd()
+
+	pruningPoint, err := csm.pruningStore.PruningPoint(csm.databaseContext, model.NewStagingArea())
+	if err != nil {
+		return err
+	}
+	log.Infof("Rolling back the virtual state to the pruning point %s", pruningPoint)
+
+	err = csm.resetPruningPointFuture(pruningPoint)
+	if err != nil {
+		return err
+	}
+
+	err = csm.copyPruningPointUTXOSetToImportedUTXOSet()
+	if err != nil {
+		return err
+	}
+
+	// From here on the rollback is the same as importing the pruning point UTXO set
+	// from a peer, including the recovery from a crash in the middle of it.
+	return csm.ImportPruningPointUTXOSet(model.NewStagingArea(), pruningPoint)
+}
+
+// resetPruningPointFuture marks the UTXO-valid blocks in the future of the pruning point as
+// pending verification and deletes their UTXO diffs. The UTXO diff of the pruning point is
+// deleted as well, since its UTXO diff child points into its future.
+func (csm *consensusStateManager) resetPruningPointFuture(pruningPoint *externalapi.DomainHash) error {
+	stagingArea := model.NewStagingArea()
+	csm.utxoDiffStore.Delete(stagingArea, pruningPoint)
+
+	tips, err := csm.consensusStateStore.Tips(stagingArea, csm.databaseContext)
+	if err != nil {
+		return err
+	}
+
+	visited := make(map[externalapi.DomainHash]struct{})
+	queue := append([]*externalapi.DomainHash{}, tips...)
+	markedBlocks := 0
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+		if _, ok := visited[*current]; ok {
+			continue
+		}
+		visited[*current] = struct{}{}
+
+		if current.Equal(pruningPoint) {
+			continue
+		}
+		isInFutureOfPruningPoint, err := csm.dagTopologyManager.IsAncestorOf(stagingArea, pruningPoint, current)
+		if err != nil {
+			return err
+		}
+		if !isInFutureOfPruningPoint {
+			continue
+		}
+
+		status, err := csm.blockStatusStore.Get(csm.databaseContext, stagingArea, current)
+		if err != nil {
+			return err
+		}
+		if status == externalapi.StatusUTXOValid {
+			csm.blockStatusStore.Stage(stagingArea, current, externalapi.StatusUTXOPendingVerification)
+			csm.utxoDiffStore.Delete(stagingArea, current)
+			markedBlocks++
+		}
+
+		parents, err := csm.dagTopologyManager.Parents(stagingArea, current)
+		if err != nil {
+			return err
+		}
+		queue = append(queue, parents...)
+	}
+
+	log.Infof("Marked %d blocks above the pruning point as pending UTXO verification", markedBlocks)
+	return staging.CommitAllChanges(csm.databaseContext, stagingArea)
+}
+
+func (csm *consensusStateManager) copyPruningPointUTXOSetToImportedUTXOSet() error {
+	err := csm.pruningStore.ClearImportedPruningPointMultiset(csm.databaseContext)
+	if err != nil {
+		return err
+	}
+	err = csm.pruningStore.ClearImportedPruningPointUTXOs(csm.databaseContext)
+	if err != nil {
+		return err
+	}
+
+	// The multiset is written up front, since the pruning point UTXO set may be empty
+	importedMultiset := multiset.New()
+	dbTx, err := csm.databaseContext.Begin()
+	if err != nil {
+		return err
+	}
+	err = csm.pruningStore.UpdateImportedPruningPointMultiset(dbTx, importedMultiset)
+	if err != nil {
+		dbTx.RollbackUnlessClosed()
+		return err
+	}
+	err = dbTx.Commit()
+	if err != nil {
+		return err
+	}
+
+	var fromOutpoint *externalapi.DomainOutpoint
+	for {
+		outpointAndUTXOEntryPairs, err := csm.pruningStore.PruningPointUTXOs(
+			csm.databaseContext, fromOutpoint, rollbackUTXOChunkSize)
+		if err != nil {
+			return err
+		}
+		if len(outpointAndUTXOEntryPairs) == 0 {
+			break
+		}
+
+		for _, outpointAndUTXOEntryPair := range outpointAndUTXOEntryPairs {
+			serializedUTXO, err := utxo.SerializeUTXO(outpointAndUTXOEntryPair.UTXOEntry, outpointAndUTXOEntryPair.Outpoint)
+			if err != nil {
+				return err
+			}
+			importedMultiset.Add(serializedUTXO)
+		}
+
+		dbTx, err := csm.databaseContext.Begin()
+		if err != nil {
+			return err
+		}
+		err = csm.pruningStore.AppendImportedPruningPointUTXOs(dbTx, outpointAndUTXOEntryPairs)
+		if err != nil {
+			dbTx.RollbackUnlessClosed()
+			return err
+		}
+		err = csm.pruningStore.UpdateImportedPruningPointMultiset(dbTx, importedMultiset)
+		if err != nil {
+			dbTx.RollbackUnlessClosed()
+			return err
+		}
+		err = dbTx.Commit()
+		if err != nil {
+			return err
+		}
+
+		if len(outpointAndUTXOEntryPairs) < rollbackUTXOChunkSize {
+			break
+		}
+		fromOutpoint = outpointAndUTXOEntryPairs[len(outpointAndUTXOEntryPairs)-1].Outpoint
+	}
+
+	return nil
+}
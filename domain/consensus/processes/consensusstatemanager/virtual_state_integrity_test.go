@@ -0,0 +1,120 @@
+package consensusstatemanager_test
+
+import (
+	"testing"
+
+	"github.com/Hoosat-Oy/HTND/domain/consensus"
+	"github.com/Hoosat-Oy/HTND/domain/consensus/model"
+	"github.com/Hoosat-Oy/HTND/domain/consensus/model/externalapi"
+	"github.com/Hoosat-Oy/HTND/domain/consensus/utils/testutils"
+	"github.com/Hoosat-Oy/HTND/domain/consensus/utils/utxo"
+	"github.com/Hoosat-Oy/HTND/util/staging"
+)
+
+func TestRollbackVirtualToPruningPoint(t *testing.T) {
+	testutils.ForAllNets(t, true, func(t *testing.T, consensusConfig *consensus.Config) {
+		factory := consensus.NewFactory()
+		tc, teardown, err := factory.NewTestConsensus(consensusConfig, "TestRollbackVirtualToPruningPoint")
+		if err != nil {
+			t.Fatalf("Error setting up consensus: %+v", err)
+		}
+		defer teardown(false)
+
+		tipHash := consensusConfig.GenesisHash
+		for i := 0; i < 3; i++ {
+			tipHash, _, err = tc.AddBlock([]*externalapi.DomainHash{tipHash}, nil, nil)
+			if err != nil {
+				t.Fatalf("AddBlock: %+v", err)
+			}
+		}
+
+		isConsistent, err := tc.ConsensusStateManager().IsVirtualStateConsistent(true)
+		if err != nil {
+			t.Fatalf("IsVirtualStateConsistent: %+v", err)
+		}
+		if !isConsistent {
+			t.Fatalf("Expected the virtual state of a new DAG to be consistent")
+		}
+
+		// Corrupt the virtual UTXO set by adding a UTXO to it without updating the virtual multiset
+		fakeOutpoint := externalapi.DomainOutpoint{
+			TransactionID: *externalapi.NewDomainTransactionIDFromByteArray(&[externalapi.DomainHashSize]byte{1}),
+			Index:         0,
+		}
+		fakeEntry := utxo.NewUTXOEntry(1, &externalapi.ScriptPublicKey{Script: []byte{1}, Version: 0}, false, 0)
+		corruptingDiff, err := utxo.NewUTXODiffFromCollections(
+			utxo.NewUTXOCollection(map[externalapi.DomainOutpoint]externalapi.UTXOEntry{fakeOutpoint: fakeEntry}),
+			utxo.NewUTXOCollection(map[externalapi.DomainOutpoint]externalapi.UTXOEntry{}))
+		if err != nil {
+			t.Fatalf("NewUTXODiffFromCollections: %+v", err)
+		}
+		stagingArea := model.NewStagingArea()
+		tc.ConsensusStateStore().StageVirtualUTXODiff(stagingArea, corruptingDiff)
+		err = staging.CommitAllChanges(tc.DatabaseContext(), stagingArea)
+		if err != nil {
+			t.Fatalf("CommitAllChanges: %+v", err)
+		}
+
+		// The cheap checks don't go over the UTXO set, so they shouldn't notice the corruption
+		isConsistent, err = tc.ConsensusStateManager().IsVirtualStateConsistent(false)
+		if err != nil {
+			t.Fatalf("IsVirtualStateConsistent: %+v", err)
+		}
+		if !isConsistent {
+			t.Fatalf("Expected the virtual state to pass the checks that don't verify the UTXO set")
+		}
+		isConsistent, err = tc.ConsensusStateManager().IsVirtualStateConsistent(true)
+		if err != nil {
+			t.Fatalf("IsVirtualStateConsistent: %+v", err)
+		}
+		if isConsistent {
+			t.Fatalf("Expected the corrupted virtual UTXO set to fail verification")
+		}
+
+		err = tc.ConsensusStateManager().RollbackVirtualToPruningPoint()
+		if err != nil {
+			t.Fatalf("RollbackVirtualToPruningPoint: %+v", err)
+		}
+
+		pruningPoint, err := tc.PruningPoint()
+		if err != nil {
+			t.Fatalf("PruningPoint: %+v", err)
+		}
+		virtualSelectedParent, err := tc.GetVirtualSelectedParent()
+		if err != nil {
+			t.Fatalf("GetVirtualSelectedParent: %+v", err)
+		}
+		if !virtualSelectedParent.Equal(pruningPoint) {
+			t.Fatalf("Expected the virtual selected parent to be the pruning point %s after the rollback, got %s",
+				pruningPoint, virtualSelectedParent)
+		}
+		tipStatus, err := tc.BlockStatusStore().Get(tc.DatabaseContext(), model.NewStagingArea(), tipHash)
+		if err != nil {
+			t.Fatalf("BlockStatusStore.Get: %+v", err)
+		}
+		if tipStatus != externalapi.StatusUTXOPendingVerification {
+			t.Fatalf("Expected the tip to be pending verification after the rollback, got %s", tipStatus)
+		}
+
+		err = tc.ResolveVirtual(nil)
+		if err != nil {
+			t.Fatalf("ResolveVirtual: %+v", err)
+		}
+
+		virtualSelectedParent, err = tc.GetVirtualSelectedParent()
+		if err != nil {
+			t.Fatalf("GetVirtualSelectedParent: %+v", err)
+		}
+		if !virtualSelectedParent.Equal(tipHash) {
+			t.Fatalf("Expected the virtual selected parent to be %s after resolving the virtual, got %s",
+				tipHash, virtualSelectedParent)
+		}
+		isConsistent, err = tc.ConsensusStateManager().IsVirtualStateConsistent(true)
+		if err != nil {
+			t.Fatalf("IsVirtualStateConsistent: %+v", err)
+		}
+		if !isConsistent {
+			t.Fatalf("Expected the virtual state to be consistent after the rollback")
+		}
+	})
+}
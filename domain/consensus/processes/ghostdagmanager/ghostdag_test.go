@@ -320,6 +320,10 @@ func (ds *GHOSTDAGDataStoreImpl) IsStaged(*model.StagingArea) bool {
 	panic("implement me")
 }
 
+func (ds *GHOSTDAGDataStoreImpl) CacheStats() []*externalapi.CacheStats {
+	return nil
+}
+
 func (ds *GHOSTDAGDataStoreImpl) Commit(dbTx model.DBTransaction) error {
 	panic("implement me")
 }
@@ -426,6 +430,8 @@ func (b *blockHeadersStore) Stage(stagingArea *model.StagingArea, blockHash *ext
 
 func (b *blockHeadersStore) IsStaged(*model.StagingArea) bool { panic("unimplemented") }
 
+func (b *blockHeadersStore) CacheStats() []*externalapi.CacheStats { return nil }
+
 func (b *blockHeadersStore) BlockHeader(dbContext model.DBReader, stagingArea *model.StagingArea, blockHash *externalapi.DomainHash) (externalapi.BlockHeader, error) {
 	header, ok := b.dagMap[*blockHash]
 	if ok {
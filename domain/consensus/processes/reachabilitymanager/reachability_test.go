@@ -38,6 +38,10 @@ func (r *reachabilityDataStoreMock) IsStaged(*model.StagingArea) bool {
 	panic("implement me")
 }
 
+func (r *reachabilityDataStoreMock) CacheStats() []*externalapi.CacheStats {
+	return nil
+}
+
 func (r *reachabilityDataStoreMock) ReachabilityData(dbContext model.DBReader, stagingArea *model.StagingArea, blockHash *externalapi.DomainHash) (model.ReachabilityData, error) {
 
 	return r.reachabilityDataStaging[*blockHash], nil
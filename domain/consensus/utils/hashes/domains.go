@@ -17,6 +17,7 @@ const (
 	proofOfWorkDomain             = "ProofOfWorkHash"
 	heavyHashDomain               = "HeavyHash"
 	merkleBranchDomain            = "MerkleBranchHash"
+	personalMessageSigningDomain  = "PersonalMessageSigningHash"
 )
 
 // transactionSigningECDSADomainHash is a hashed version of transcationSigningECDSADomain that is used
@@ -149,3 +150,12 @@ func NewMerkleBranchHashWriter() HashWriter {
 // 	}
 // 	return HashWriter{blake}
 // }
+
+// NewPersonalMessageSigningHashWriter Returns a new HashWriter used for signing on an arbitrary message,
+// such as one proving the ownership of an address
+func NewPersonalMessageSigningHashWriter() HashWriter {
+	var fixedSizeKey [32]byte
+	copy(fixedSizeKey[:], personalMessageSigningDomain)
+	blake := blake3.New(32, fixedSizeKey[:])
+	return HashWriter{blake}
+}
@@ -9,6 +9,8 @@ import (
 type LRUCache struct {
 	cache    map[externalapi.DomainHash]interface{}
 	capacity int
+	hits     uint64
+	misses   uint64
 }
 
 // New creates a new LRUCache
@@ -37,6 +39,7 @@ func (c *LRUCache) Add(key *externalapi.DomainHash, value interface{}) {
 // Get returns the entry for the given key, or (nil, false) otherwise
 func (c *LRUCache) Get(key *externalapi.DomainHash) (interface{}, bool) {
 	value, ok := c.cache[*key]
+	c.recordLookup(ok)
 	if !ok {
 		return nil, false
 	}
@@ -46,6 +49,7 @@ func (c *LRUCache) Get(key *externalapi.DomainHash) (interface{}, bool) {
 // Has returns whether the LRUCache contains the given key
 func (c *LRUCache) Has(key *externalapi.DomainHash) bool {
 	_, ok := c.cache[*key]
+	c.recordLookup(ok)
 	return ok
 }
 
@@ -63,3 +67,22 @@ func (c *LRUCache) evictRandom() {
 	}
 	c.Remove(&keyToEvict)
 }
+
+// Stats returns the usage statistics of the LRUCache under the given name
+func (c *LRUCache) Stats(name string) *externalapi.CacheStats {
+	return &externalapi.CacheStats{
+		Name:     name,
+		Size:     len(c.cache),
+		Capacity: c.capacity,
+		Hits:     c.hits,
+		Misses:   c.misses,
+	}
+}
+
+func (c *LRUCache) recordLookup(found bool) {
+	if found {
+		c.hits++
+	} else {
+		c.misses++
+	}
+}
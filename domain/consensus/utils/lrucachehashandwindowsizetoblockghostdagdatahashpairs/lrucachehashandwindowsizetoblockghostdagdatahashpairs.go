@@ -19,6 +19,8 @@ func newKey(blockHash *externalapi.DomainHash, windowSize int) lruKey {
 type LRUCache struct {
 	cache    map[lruKey][]*externalapi.BlockGHOSTDAGDataHashPair
 	capacity int
+	hits     uint64
+	misses   uint64
 }
 
 // New creates a new LRUCache
@@ -49,6 +51,7 @@ func (c *LRUCache) Add(blockHash *externalapi.DomainHash, windowSize int, value
 func (c *LRUCache) Get(blockHash *externalapi.DomainHash, windowSize int) ([]*externalapi.BlockGHOSTDAGDataHashPair, bool) {
 	key := newKey(blockHash, windowSize)
 	value, ok := c.cache[key]
+	c.recordLookup(ok)
 	if !ok {
 		return nil, false
 	}
@@ -59,6 +62,7 @@ func (c *LRUCache) Get(blockHash *externalapi.DomainHash, windowSize int) ([]*ex
 func (c *LRUCache) Has(blockHash *externalapi.DomainHash, windowSize int) bool {
 	key := newKey(blockHash, windowSize)
 	_, ok := c.cache[key]
+	c.recordLookup(ok)
 	return ok
 }
 
@@ -77,3 +81,22 @@ func (c *LRUCache) evictRandom() {
 	}
 	c.Remove(&keyToEvict.blockHash, keyToEvict.windowSize)
 }
+
+// Stats returns the usage statistics of the LRUCache under the given name
+func (c *LRUCache) Stats(name string) *externalapi.CacheStats {
+	return &externalapi.CacheStats{
+		Name:     name,
+		Size:     len(c.cache),
+		Capacity: c.capacity,
+		Hits:     c.hits,
+		Misses:   c.misses,
+	}
+}
+
+func (c *LRUCache) recordLookup(found bool) {
+	if found {
+		c.hits++
+	} else {
+		c.misses++
+	}
+}
@@ -19,6 +19,8 @@ func newKey(blockHash *externalapi.DomainHash, index uint64) lruKey {
 type LRUCache struct {
 	cache    map[lruKey]*externalapi.BlockGHOSTDAGDataHashPair
 	capacity int
+	hits     uint64
+	misses   uint64
 }
 
 // New creates a new LRUCache
@@ -49,6 +51,7 @@ func (c *LRUCache) Add(blockHash *externalapi.DomainHash, index uint64, value *e
 func (c *LRUCache) Get(blockHash *externalapi.DomainHash, index uint64) (*externalapi.BlockGHOSTDAGDataHashPair, bool) {
 	key := newKey(blockHash, index)
 	value, ok := c.cache[key]
+	c.recordLookup(ok)
 	if !ok {
 		return nil, false
 	}
@@ -59,6 +62,7 @@ func (c *LRUCache) Get(blockHash *externalapi.DomainHash, index uint64) (*extern
 func (c *LRUCache) Has(blockHash *externalapi.DomainHash, index uint64) bool {
 	key := newKey(blockHash, index)
 	_, ok := c.cache[key]
+	c.recordLookup(ok)
 	return ok
 }
 
@@ -77,3 +81,22 @@ func (c *LRUCache) evictRandom() {
 	}
 	c.Remove(&keyToEvict.blockHash, keyToEvict.index)
 }
+
+// Stats returns the usage statistics of the LRUCache under the given name
+func (c *LRUCache) Stats(name string) *externalapi.CacheStats {
+	return &externalapi.CacheStats{
+		Name:     name,
+		Size:     len(c.cache),
+		Capacity: c.capacity,
+		Hits:     c.hits,
+		Misses:   c.misses,
+	}
+}
+
+func (c *LRUCache) recordLookup(found bool) {
+	if found {
+		c.hits++
+	} else {
+		c.misses++
+	}
+}
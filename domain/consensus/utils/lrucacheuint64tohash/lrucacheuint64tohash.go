@@ -7,6 +7,8 @@ import "github.com/Hoosat-Oy/HTND/domain/consensus/model/externalapi"
 type LRUCache struct {
 	cache    map[uint64]*externalapi.DomainHash
 	capacity int
+	hits     uint64
+	misses   uint64
 }
 
 // New creates a new LRUCache
@@ -35,6 +37,7 @@ func (c *LRUCache) Add(key uint64, value *externalapi.DomainHash) {
 // Get returns the entry for the given key, or (nil, false) otherwise
 func (c *LRUCache) Get(key uint64) (*externalapi.DomainHash, bool) {
 	value, ok := c.cache[key]
+	c.recordLookup(ok)
 	if !ok {
 		return nil, false
 	}
@@ -44,6 +47,7 @@ func (c *LRUCache) Get(key uint64) (*externalapi.DomainHash, bool) {
 // Has returns whether the LRUCache contains the given key
 func (c *LRUCache) Has(key uint64) bool {
 	_, ok := c.cache[key]
+	c.recordLookup(ok)
 	return ok
 }
 
@@ -61,3 +65,22 @@ func (c *LRUCache) evictRandom() {
 	}
 	c.Remove(keyToEvict)
 }
+
+// Stats returns the usage statistics of the LRUCache under the given name
+func (c *LRUCache) Stats(name string) *externalapi.CacheStats {
+	return &externalapi.CacheStats{
+		Name:     name,
+		Size:     len(c.cache),
+		Capacity: c.capacity,
+		Hits:     c.hits,
+		Misses:   c.misses,
+	}
+}
+
+func (c *LRUCache) recordLookup(found bool) {
+	if found {
+		c.hits++
+	} else {
+		c.misses++
+	}
+}
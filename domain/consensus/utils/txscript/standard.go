@@ -5,6 +5,7 @@
 package txscript
 
 import (
+	"encoding/binary"
 	"fmt"
 
 	"github.com/Hoosat-Oy/HTND/domain/consensus/model/externalapi"
@@ -441,3 +442,116 @@ func ExtractAtomicSwapDataPushes(version uint16, scriptPubKey []byte) (*AtomicSw
 	}
 	return pushes, nil
 }
+
+// VaultDataPushes houses the data pushes found in time-locked vault scripts.
+// A vault script is spendable by TimeLockedPubKey once the transaction lock
+// time reaches LockTime, and by RecoveryPubKey at any time.
+type VaultDataPushes struct {
+	LockTime          uint64
+	TimeLockedPubKey  []byte
+	IsTimeLockedECDSA bool
+	RecoveryPubKey    []byte
+	IsRecoveryECDSA   bool
+}
+
+// VaultScript creates a time-locked vault script out of the given data pushes.
+// The script is of the form:
+//
+//	OP_IF <lock time> OP_CHECKLOCKTIMEVERIFY <time-locked pubkey> OP_CHECKSIG
+//	OP_ELSE <recovery pubkey> OP_CHECKSIG OP_ENDIF
+//
+// where OP_CHECKSIGECDSA is used in place of OP_CHECKSIG for ECDSA public keys.
+// Vault scripts are not standard script public keys, and should be used with P2SH.
+func VaultScript(pushes *VaultDataPushes) ([]byte, error) {
+	if pushes.LockTime == 0 {
+		return nil, errors.New("vault lock time must be positive")
+	}
+	timeLockedCheckSigOp, err := vaultCheckSigOp(pushes.TimeLockedPubKey, pushes.IsTimeLockedECDSA)
+	if err != nil {
+		return nil, err
+	}
+	recoveryCheckSigOp, err := vaultCheckSigOp(pushes.RecoveryPubKey, pushes.IsRecoveryECDSA)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewScriptBuilder().
+		AddOp(OpIf).
+		AddLockTimeNumber(pushes.LockTime).AddOp(OpCheckLockTimeVerify).
+		AddData(pushes.TimeLockedPubKey).AddOp(timeLockedCheckSigOp).
+		AddOp(OpElse).
+		AddData(pushes.RecoveryPubKey).AddOp(recoveryCheckSigOp).
+		AddOp(OpEndIf).
+		Script()
+}
+
+func vaultCheckSigOp(pubKey []byte, isECDSA bool) (byte, error) {
+	if isECDSA {
+		if len(pubKey) != 33 {
+			return 0, scriptError(ErrPubKeyFormat,
+				fmt.Sprintf("ECDSA public key must be 33 bytes long, but is %d bytes long", len(pubKey)))
+		}
+		return OpCheckSigECDSA, nil
+	}
+	if len(pubKey) != 32 {
+		return 0, scriptError(ErrPubKeyFormat,
+			fmt.Sprintf("schnorr public key must be 32 bytes long, but is %d bytes long", len(pubKey)))
+	}
+	return OpCheckSig, nil
+}
+
+// ExtractVaultDataPushes returns the data pushes from a time-locked vault
+// script, as created by VaultScript. If the script is not a vault script,
+// ExtractVaultDataPushes returns (nil, nil). Non-nil errors are returned for
+// unparsable scripts.
+func ExtractVaultDataPushes(version uint16, script []byte) (*VaultDataPushes, error) {
+	pops, err := parseScript(script)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(pops) != 9 {
+		return nil, nil
+	}
+	lockTime, isLockTime := vaultLockTime(&pops[1])
+	isVault := pops[0].opcode.value == OpIf &&
+		isLockTime &&
+		pops[2].opcode.value == OpCheckLockTimeVerify &&
+		isVaultPubKeyCheck(pops[3], pops[4]) &&
+		pops[5].opcode.value == OpElse &&
+		isVaultPubKeyCheck(pops[6], pops[7]) &&
+		pops[8].opcode.value == OpEndIf
+	if !isVault {
+		return nil, nil
+	}
+
+	return &VaultDataPushes{
+		LockTime:          lockTime,
+		TimeLockedPubKey:  pops[3].data,
+		IsTimeLockedECDSA: pops[4].opcode.value == OpCheckSigECDSA,
+		RecoveryPubKey:    pops[6].data,
+		IsRecoveryECDSA:   pops[7].opcode.value == OpCheckSigECDSA,
+	}, nil
+}
+
+// vaultLockTime returns the lock time pushed by pop, interpreted the same way
+// OP_CHECKLOCKTIMEVERIFY interprets it. AddLockTimeNumber pushes small lock
+// times using the small integer opcodes, so these are handled as well.
+func vaultLockTime(pop *parsedOpcode) (uint64, bool) {
+	switch {
+	case pop.opcode.value == Op1Negate:
+		return uint64(scriptNum(-1).Bytes()[0]), true
+	case isSmallInt(pop.opcode):
+		return uint64(asSmallInt(pop.opcode)), true
+	case pop.data != nil && len(pop.data) <= 8:
+		lockTimeBytes := make([]byte, 8)
+		copy(lockTimeBytes, pop.data)
+		return binary.LittleEndian.Uint64(lockTimeBytes), true
+	}
+	return 0, false
+}
+
+func isVaultPubKeyCheck(pubKeyPop, checkSigPop parsedOpcode) bool {
+	return (pubKeyPop.opcode.value == OpData32 && checkSigPop.opcode.value == OpCheckSig) ||
+		(pubKeyPop.opcode.value == OpData33 && checkSigPop.opcode.value == OpCheckSigECDSA)
+}
@@ -567,3 +567,79 @@ func TestStringifyClass(t *testing.T) {
 		}
 	}
 }
+
+func TestVaultScript(t *testing.T) {
+	schnorrPubKey := bytes.Repeat([]byte{0x02}, 32)
+	ecdsaPubKey := append([]byte{0x03}, bytes.Repeat([]byte{0x04}, 32)...)
+
+	tests := []struct {
+		name   string
+		pushes *VaultDataPushes
+	}{
+		{
+			name: "schnorr keys",
+			pushes: &VaultDataPushes{LockTime: 123456789, TimeLockedPubKey: schnorrPubKey,
+				RecoveryPubKey: schnorrPubKey},
+		},
+		{
+			name: "ECDSA keys",
+			pushes: &VaultDataPushes{LockTime: 1 << 40, TimeLockedPubKey: ecdsaPubKey, IsTimeLockedECDSA: true,
+				RecoveryPubKey: ecdsaPubKey, IsRecoveryECDSA: true},
+		},
+		{
+			name: "mixed keys",
+			pushes: &VaultDataPushes{LockTime: 0x80, TimeLockedPubKey: schnorrPubKey,
+				RecoveryPubKey: ecdsaPubKey, IsRecoveryECDSA: true},
+		},
+		{
+			name:   "small integer lock time",
+			pushes: &VaultDataPushes{LockTime: 16, TimeLockedPubKey: schnorrPubKey, RecoveryPubKey: schnorrPubKey},
+		},
+		{
+			name:   "negative one lock time",
+			pushes: &VaultDataPushes{LockTime: 0x81, TimeLockedPubKey: schnorrPubKey, RecoveryPubKey: schnorrPubKey},
+		},
+	}
+
+	for _, test := range tests {
+		script, err := VaultScript(test.pushes)
+		if err != nil {
+			t.Fatalf("%s: VaultScript: %s", test.name, err)
+		}
+		pushes, err := ExtractVaultDataPushes(0, script)
+		if err != nil {
+			t.Fatalf("%s: ExtractVaultDataPushes: %s", test.name, err)
+		}
+		if !reflect.DeepEqual(pushes, test.pushes) {
+			t.Errorf("%s: unexpected data pushes. Want: %+v, got: %+v", test.name, test.pushes, pushes)
+		}
+	}
+
+	_, err := VaultScript(&VaultDataPushes{LockTime: 0, TimeLockedPubKey: schnorrPubKey, RecoveryPubKey: schnorrPubKey})
+	if err == nil {
+		t.Errorf("VaultScript unexpectedly succeeded with a zero lock time")
+	}
+	_, err = VaultScript(&VaultDataPushes{LockTime: 1, TimeLockedPubKey: schnorrPubKey, IsTimeLockedECDSA: true,
+		RecoveryPubKey: schnorrPubKey})
+	if err == nil {
+		t.Errorf("VaultScript unexpectedly succeeded with a schnorr public key marked as ECDSA")
+	}
+
+	nonVaultScripts := []string{
+		"DATA_32 0x0202020202020202020202020202020202020202020202020202020202020202 CHECKSIG",
+		"IF 100 CHECKSEQUENCEVERIFY DATA_32 0x0202020202020202020202020202020202020202020202020202020202020202 " +
+			"CHECKSIG ELSE DATA_32 0x0202020202020202020202020202020202020202020202020202020202020202 CHECKSIG ENDIF",
+		"IF 100 CHECKLOCKTIMEVERIFY DATA_32 0x0202020202020202020202020202020202020202020202020202020202020202 " +
+			"CHECKSIGECDSA ELSE DATA_32 0x0202020202020202020202020202020202020202020202020202020202020202 CHECKSIG ENDIF",
+	}
+	for _, nonVaultScript := range nonVaultScripts {
+		pushes, err := ExtractVaultDataPushes(0, mustParseShortForm(nonVaultScript, 0))
+		if err != nil {
+			t.Fatalf("ExtractVaultDataPushes: %s", err)
+		}
+		if pushes != nil {
+			t.Errorf("ExtractVaultDataPushes unexpectedly extracted %+v out of a non-vault script %s",
+				pushes, nonVaultScript)
+		}
+	}
+}
@@ -0,0 +1,290 @@
+package utxoindex
+
+import (
+	"bytes"
+	"fmt"
+	"time"
+
+	"github.com/Hoosat-Oy/HTND/domain/consensus/model/externalapi"
+	"github.com/Hoosat-Oy/HTND/domain/consensus/ruleerrors"
+	"github.com/Hoosat-Oy/HTND/infrastructure/db/database"
+	"github.com/Hoosat-Oy/HTND/util/panics"
+	"github.com/pkg/errors"
+)
+
+var spawn = panics.GoroutineWrapperFunc(log)
+
+// buildStep is the amount of UTXOs that are read from the consensus at a time
+// while the index is built. It's a variable so tests can read smaller pages.
+var buildStep = 1000
+
+// buildRetryInterval is how long a build waits before it retries reading the
+// consensus UTXO set when the index lags behind the virtual and no update
+// arrives in the meantime
+const buildRetryInterval = time.Second
+
+// IndexBuildingError is returned by the queries of a UTXO index that is
+// still being built
+type IndexBuildingError struct {
+	// Progress is the progress of the build, in percents
+	Progress uint32
+}
+
+func (e *IndexBuildingError) Error() string {
+	return fmt.Sprintf("UTXO index building, %d%% done", e.Progress)
+}
+
+// utxoIndexBuild is a background build of the UTXO index from the virtual UTXO
+// set of the consensus.
+//
+// The virtual UTXO set is read in the order of its serialized outpoints, while
+// the virtual keeps changing. The index holds exactly the UTXOs of the virtual
+// parents of the index up to serializedScannedOutpoint: Update applies the changes
+// to the UTXOs that were already scanned and skips the rest, and the next UTXOs
+// are read only when the virtual of the consensus is the one of the index, so
+// they already include every skipped change and none that Update didn't apply yet.
+type utxoIndexBuild struct {
+	// scannedOutpoints are the outpoints of the last read page, in
+	// ascending order. The next page is read from the last of them that
+	// is still unspent.
+	scannedOutpoints          []*externalapi.DomainOutpoint
+	serializedScannedOutpoint []byte
+	progress                  uint32
+
+	virtualChanged chan struct{}
+	quit           chan struct{}
+	done           chan struct{}
+}
+
+func newUTXOIndexBuild() *utxoIndexBuild {
+	return &utxoIndexBuild{
+		virtualChanged: make(chan struct{}, 1),
+		quit:           make(chan struct{}),
+		done:           make(chan struct{}),
+	}
+}
+
+// isScanned returns whether the given outpoint was already read from the
+// consensus by the build
+func (b *utxoIndexBuild) isScanned(outpoint *externalapi.DomainOutpoint) (bool, error) {
+	if b.serializedScannedOutpoint == nil {
+		return false, nil
+	}
+	serializedOutpoint, err := serializeOutpoint(outpoint)
+	if err != nil {
+		return false, err
+	}
+	return bytes.Compare(serializedOutpoint, b.serializedScannedOutpoint) <= 0, nil
+}
+
+// Rebuild deletes the whole UTXO index and rebuilds it from consensus in the
+// background. The index keeps following the virtual while it's built, but its
+// queries return an IndexBuildingError until the build is done.
+func (ui *UTXOIndex) Rebuild() {
+	ui.stopBuild()
+
+	ui.mutex.Lock()
+	defer ui.mutex.Unlock()
+
+	build := newUTXOIndexBuild()
+	ui.build = build
+	spawn("UTXOIndex.runBuild", func() {
+		defer close(build.done)
+
+		err := ui.runBuild(build)
+		if err != nil {
+			panic(errors.Wrap(err, "failed building the UTXO index"))
+		}
+	})
+}
+
+// BuildProgress returns whether the UTXO index is being built and the progress
+// of the build, in percents
+func (ui *UTXOIndex) BuildProgress() (isBuilding bool, progress uint32) {
+	ui.mutex.Lock()
+	defer ui.mutex.Unlock()
+
+	if ui.build == nil {
+		return false, 0
+	}
+	return true, ui.build.progress
+}
+
+// Close stops the build of the UTXO index, if there's one, and waits for it
+// to return. A stopped build is resumed from scratch the next time the index
+// is created.
+func (ui *UTXOIndex) Close() {
+	ui.stopBuild()
+}
+
+func (ui *UTXOIndex) stopBuild() {
+	ui.mutex.Lock()
+	build := ui.build
+	ui.build = nil
+	ui.mutex.Unlock()
+
+	if build != nil {
+		close(build.quit)
+		<-build.done
+	}
+}
+
+// buildingError returns an IndexBuildingError if the index is being built.
+// This is called while ui.mutex is held.
+func (ui *UTXOIndex) buildingError() error {
+	if ui.build == nil {
+		return nil
+	}
+	return &IndexBuildingError{Progress: ui.build.progress}
+}
+
+func (ui *UTXOIndex) runBuild(build *utxoIndexBuild) error {
+	isStarted, err := ui.startBuild(build)
+	if err != nil || !isStarted {
+		return err
+	}
+
+	for {
+		isDone, isVirtualAhead, err := ui.buildNextPage(build)
+		if err != nil || isDone {
+			return err
+		}
+		if !isVirtualAhead {
+			select {
+			case <-build.quit:
+				return nil
+			default:
+			}
+			continue
+		}
+
+		// The index lags behind the virtual of the consensus, so
+		// the build waits for the next update
+		select {
+		case <-build.quit:
+			return nil
+		case <-build.virtualChanged:
+		case <-time.After(buildRetryInterval):
+		}
+	}
+}
+
+// startBuild deletes the whole UTXO index and starts following the virtual.
+// It returns false if the build was stopped before it started.
+func (ui *UTXOIndex) startBuild(build *utxoIndexBuild) (bool, error) {
+	ui.mutex.Lock()
+	defer ui.mutex.Unlock()
+
+	if ui.build != build {
+		return false, nil
+	}
+
+	log.Infof("Starting UTXO index build")
+
+	ui.store.discard()
+	err := ui.store.deleteAll()
+	if err != nil {
+		return false, err
+	}
+
+	err = ui.store.initializeCirculatingSompiSupply() //At this point the database is empty, so the sole purpose of this call is to initialize the circulating supply key
+	if err != nil {
+		return false, err
+	}
+
+	err = ui.store.initializeAggregates()
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// buildNextPage adds the next page of the virtual UTXO set to the index. It
+// returns whether the build is done, or whether the page couldn't be read
+// because the virtual of the consensus is ahead of the index.
+func (ui *UTXOIndex) buildNextPage(build *utxoIndexBuild) (isDone bool, isVirtualAhead bool, err error) {
+	ui.mutex.Lock()
+	defer ui.mutex.Unlock()
+
+	if ui.build != build {
+		return true, false, nil
+	}
+
+	virtualUTXOs, err := ui.nextVirtualUTXOs(build)
+	if err != nil {
+		if errors.Is(err, ruleerrors.ErrGetVirtualUTXOsWrongVirtualParents) {
+			return false, true, nil
+		}
+		return false, false, err
+	}
+
+	// A page that was read from an earlier outpoint, because the
+	// last scanned one was spent, starts with outpoints that were
+	// already scanned
+	unscannedUTXOs := virtualUTXOs
+	for len(unscannedUTXOs) > 0 {
+		isScanned, err := build.isScanned(unscannedUTXOs[0].Outpoint)
+		if err != nil {
+			return false, false, err
+		}
+		if !isScanned {
+			break
+		}
+		unscannedUTXOs = unscannedUTXOs[1:]
+	}
+
+	err = ui.store.addAndCommitOutpointsWithoutTransaction(unscannedUTXOs)
+	if err != nil {
+		return false, false, err
+	}
+
+	if len(virtualUTXOs) < buildStep {
+		// This has to be done last to mark that the build went smoothly and no
+		// rebuild has to be done next time.
+		err = ui.store.updateAndCommitVirtualParentsWithoutTransaction(ui.virtualParents)
+		if err != nil {
+			return false, false, err
+		}
+		ui.build = nil
+		log.Infof("Finished UTXO index build")
+		return true, false, nil
+	}
+
+	build.scannedOutpoints = make([]*externalapi.DomainOutpoint, len(virtualUTXOs))
+	for i, virtualUTXO := range virtualUTXOs {
+		build.scannedOutpoints[i] = virtualUTXO.Outpoint
+	}
+	if len(unscannedUTXOs) == 0 {
+		return false, false, nil
+	}
+	lastOutpoint := virtualUTXOs[len(virtualUTXOs)-1].Outpoint
+	build.serializedScannedOutpoint, err = serializeOutpoint(lastOutpoint)
+	if err != nil {
+		return false, false, err
+	}
+
+	// Transaction IDs are hashes, so the outpoints are spread uniformly
+	// over the serialized outpoint order
+	transactionID := lastOutpoint.TransactionID.ByteArray()
+	progress := uint32(uint64(transactionID[0])<<8|uint64(transactionID[1])) * 100 / (1 << 16)
+	if progress > build.progress {
+		build.progress = progress
+		log.Infof("UTXO index build %d%% done", progress)
+	}
+	return false, false, nil
+}
+
+// nextVirtualUTXOs reads the virtual UTXOs that follow the last scanned outpoint
+// of the given build that is still unspent. If all the outpoints of the last
+// page were spent, the whole virtual UTXO set is read again from its start.
+func (ui *UTXOIndex) nextVirtualUTXOs(build *utxoIndexBuild) ([]*externalapi.OutpointAndUTXOEntryPair, error) {
+	for i := len(build.scannedOutpoints) - 1; i >= 0; i-- {
+		virtualUTXOs, err := ui.domain.Consensus().GetVirtualUTXOs(
+			ui.virtualParents, build.scannedOutpoints[i], buildStep)
+		if database.IsNotFoundError(err) {
+			continue
+		}
+		return virtualUTXOs, err
+	}
+	return ui.domain.Consensus().GetVirtualUTXOs(ui.virtualParents, nil, buildStep)
+}
@@ -0,0 +1,407 @@
+package utxoindex
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Hoosat-Oy/HTND/domain"
+	"github.com/Hoosat-Oy/HTND/domain/consensus"
+	"github.com/Hoosat-Oy/HTND/domain/consensus/model/externalapi"
+	"github.com/Hoosat-Oy/HTND/domain/consensus/utils/constants"
+	"github.com/Hoosat-Oy/HTND/domain/consensus/utils/testutils"
+	"github.com/Hoosat-Oy/HTND/domain/consensus/utils/txscript"
+	"github.com/Hoosat-Oy/HTND/domain/dagconfig"
+	"github.com/Hoosat-Oy/HTND/domain/miningmanager/mempool"
+	"github.com/Hoosat-Oy/HTND/infrastructure/db/database/ldb"
+	"github.com/pkg/errors"
+)
+
+func Test_isSkippedByBuild(t *testing.T) {
+	outpoint := func(transactionIDByte byte, index uint32) *externalapi.DomainOutpoint {
+		transactionID := externalapi.NewDomainTransactionIDFromByteArray(&[externalapi.DomainHashSize]byte{transactionIDByte})
+		return externalapi.NewDomainOutpoint(transactionID, index)
+	}
+
+	ui := &UTXOIndex{}
+	isSkipped, err := ui.isSkippedByBuild(outpoint(1, 0))
+	if err != nil {
+		t.Fatalf("isSkippedByBuild: %+v", err)
+	}
+	if isSkipped {
+		t.Fatalf("An update was skipped while the index isn't built")
+	}
+
+	ui.build = newUTXOIndexBuild()
+	isSkipped, err = ui.isSkippedByBuild(outpoint(1, 0))
+	if err != nil {
+		t.Fatalf("isSkippedByBuild: %+v", err)
+	}
+	if !isSkipped {
+		t.Fatalf("An update wasn't skipped before the build read any UTXO")
+	}
+
+	ui.build.serializedScannedOutpoint, err = serializeOutpoint(outpoint(2, 5))
+	if err != nil {
+		t.Fatalf("serializeOutpoint: %+v", err)
+	}
+	tests := []struct {
+		outpoint          *externalapi.DomainOutpoint
+		expectedIsSkipped bool
+	}{
+		{outpoint: outpoint(1, 300), expectedIsSkipped: false},
+		{outpoint: outpoint(2, 0), expectedIsSkipped: false},
+		{outpoint: outpoint(2, 5), expectedIsSkipped: false},
+		{outpoint: outpoint(2, 6), expectedIsSkipped: true},
+		{outpoint: outpoint(2, 300), expectedIsSkipped: true},
+		{outpoint: outpoint(3, 0), expectedIsSkipped: true},
+	}
+	for _, test := range tests {
+		isSkipped, err := ui.isSkippedByBuild(test.outpoint)
+		if err != nil {
+			t.Fatalf("isSkippedByBuild: %+v", err)
+		}
+		if isSkipped != test.expectedIsSkipped {
+			t.Errorf("Expected isSkipped %t for outpoint %s, got %t", test.expectedIsSkipped, test.outpoint, isSkipped)
+		}
+	}
+}
+
+func Test_buildingError(t *testing.T) {
+	ui := &UTXOIndex{}
+	err := ui.buildingError()
+	if err != nil {
+		t.Fatalf("Got a building error while the index isn't built: %s", err)
+	}
+
+	ui.build = newUTXOIndexBuild()
+	ui.build.progress = 42
+	err = ui.buildingError()
+	var indexBuildingErr *IndexBuildingError
+	if !errors.As(err, &indexBuildingErr) {
+		t.Fatalf("Expected an IndexBuildingError, got %v", err)
+	}
+	if err.Error() != "UTXO index building, 42% done" {
+		t.Fatalf("Unexpected error message: %s", err)
+	}
+}
+
+func TestBuildFromStartWhenLastPageIsSpent(t *testing.T) {
+	test := newBuildTest(t)
+	defer test.teardown()
+
+	// Every page holds two UTXOs, so a page is spent altogether
+	// by a single transaction
+	test.setBuildStep(2)
+
+	build := newUTXOIndexBuild()
+	test.ui.build = build
+	isStarted, err := test.ui.startBuild(build)
+	if err != nil {
+		t.Fatalf("startBuild: %+v", err)
+	}
+	if !isStarted {
+		t.Fatalf("startBuild unexpectedly didn't start the build")
+	}
+
+	isLastPageSpent := false
+	for {
+		isDone, isVirtualAhead, err := test.ui.buildNextPage(build)
+		if err != nil {
+			t.Fatalf("buildNextPage: %+v", err)
+		}
+		if isVirtualAhead {
+			t.Fatalf("The virtual is ahead of an index that received every update")
+		}
+		if isDone {
+			break
+		}
+		if isLastPageSpent {
+			continue
+		}
+
+		// Spend the last page as soon as it's spendable, so the next page
+		// is read from the start of the virtual UTXO set
+		lastPage, isSpendable := test.spendableUTXOs(build.scannedOutpoints)
+		if !isSpendable {
+			continue
+		}
+		test.addBlock(test.spendTransaction(lastPage, 1))
+		isLastPageSpent = true
+	}
+	if !isLastPageSpent {
+		t.Fatalf("The last page was never spendable")
+	}
+	if test.ui.build != nil {
+		t.Fatalf("The build is done but still set on the index")
+	}
+
+	test.expectIndexEqualsVirtualUTXOSet()
+}
+
+func TestRebuildWhileBlocksAreAdded(t *testing.T) {
+	test := newBuildTest(t)
+	defer test.teardown()
+	test.setBuildStep(2)
+
+	test.ui.Rebuild()
+	for i := 0; ; i++ {
+		isBuilding, _ := test.ui.BuildProgress()
+		if !isBuilding {
+			break
+		}
+		if i == 1000 {
+			t.Fatalf("The build didn't finish after %d blocks", i)
+		}
+
+		// Spend the first spendable UTXO of the virtual, which races
+		// with the pages the build reads
+		virtualUTXOs := test.virtualUTXOs()
+		for _, virtualUTXO := range virtualUTXOs {
+			if virtualUTXO.UTXOEntry.ScriptPublicKey().Equal(test.scriptPublicKey) {
+				test.addBlock(test.spendTransaction([]*externalapi.OutpointAndUTXOEntryPair{virtualUTXO}, 2))
+				break
+			}
+		}
+
+		// The build reads pages only while the index is synced with the
+		// virtual, so it needs some time between the blocks
+		time.Sleep(time.Millisecond)
+	}
+	test.addBlock()
+
+	test.expectIndexEqualsVirtualUTXOSet()
+}
+
+// buildTest is a UTXO index over the consensus of a domain, which is set up
+// with UTXOs that pay to an anyone-can-spend script
+type buildTest struct {
+	t      *testing.T
+	domain domain.Domain
+	ui     *UTXOIndex
+
+	scriptPublicKey *externalapi.ScriptPublicKey
+	redeemScript    []byte
+
+	teardown func()
+}
+
+func newBuildTest(t *testing.T) *buildTest {
+	// The dev fee validation only recognizes mainnet addresses, so the test DAG,
+	// whose blocks pay the dev fee, is built with the mainnet params
+	consensusConfig := &consensus.Config{Params: dagconfig.MainnetParams}
+	consensusConfig.SkipProofOfWork = true
+	consensusConfig.BlockCoinbaseMaturity = 0
+
+	db, err := ldb.NewLevelDB(t.TempDir(), 8)
+	if err != nil {
+		t.Fatalf("NewLevelDB: %+v", err)
+	}
+	domainInstance, err := domain.New(consensusConfig, mempool.DefaultConfig(&consensusConfig.Params), db)
+	if err != nil {
+		t.Fatalf("New: %+v", err)
+	}
+
+	scriptPublicKey, redeemScript := testutils.OpTrueScript()
+	test := &buildTest{
+		t:      t,
+		domain: domainInstance,
+		ui: &UTXOIndex{
+			domain: domainInstance,
+			store:  newUTXOIndexStore(db),
+		},
+		scriptPublicKey: scriptPublicKey,
+		redeemScript:    redeemScript,
+	}
+	test.teardown = func() {
+		test.ui.Close()
+		db.Close()
+	}
+
+	err = test.ui.Reset()
+	if err != nil {
+		t.Fatalf("Reset: %+v", err)
+	}
+	for i := 0; i < 5; i++ {
+		test.addBlock()
+	}
+	// Split a coinbase output into consecutive outpoints, so that pages
+	// of the virtual UTXO set are spendable altogether
+	spendableUTXOs, _ := test.spendableUTXOs(nil)
+	test.addBlock(test.spendTransaction(spendableUTXOs[:1], 20))
+	test.addBlock()
+	return test
+}
+
+func (test *buildTest) setBuildStep(step int) {
+	originalBuildStep := buildStep
+	buildStep = step
+	teardown := test.teardown
+	test.teardown = func() {
+		teardown()
+		buildStep = originalBuildStep
+	}
+}
+
+// addBlock adds a block with the given transactions to the consensus, and
+// updates the UTXO index with the resulting virtual changes
+func (test *buildTest) addBlock(transactions ...*externalapi.DomainTransaction) {
+	coinbaseData := &externalapi.DomainCoinbaseData{
+		ScriptPublicKey: test.scriptPublicKey,
+		ExtraData:       []byte{},
+	}
+	block, err := test.domain.Consensus().BuildBlock(coinbaseData, transactions)
+	if err != nil {
+		test.t.Fatalf("BuildBlock: %+v", err)
+	}
+	err = test.domain.Consensus().ValidateAndInsertBlock(block, true, new(externalapi.DomainHash))
+	if err != nil {
+		test.t.Fatalf("ValidateAndInsertBlock: %+v", err)
+	}
+
+	for {
+		select {
+		case event := <-test.domain.ConsensusEventsChannel():
+			virtualChangeSet, ok := event.(*externalapi.VirtualChangeSet)
+			if !ok {
+				continue
+			}
+			_, err := test.ui.Update(virtualChangeSet)
+			if err != nil {
+				test.t.Fatalf("Update: %+v", err)
+			}
+		default:
+			return
+		}
+	}
+}
+
+func (test *buildTest) virtualUTXOs() []*externalapi.OutpointAndUTXOEntryPair {
+	virtualInfo, err := test.domain.Consensus().GetVirtualInfo()
+	if err != nil {
+		test.t.Fatalf("GetVirtualInfo: %+v", err)
+	}
+	// The test DAG is small enough for its whole UTXO set to fit in one page
+	const limit = 10000
+	virtualUTXOs, err := test.domain.Consensus().GetVirtualUTXOs(virtualInfo.ParentHashes, nil, limit)
+	if err != nil {
+		test.t.Fatalf("GetVirtualUTXOs: %+v", err)
+	}
+	if len(virtualUTXOs) == limit {
+		test.t.Fatalf("The virtual UTXO set doesn't fit in one page")
+	}
+	return virtualUTXOs
+}
+
+// spendableUTXOs returns the virtual UTXOs of the given outpoints, or every
+// virtual UTXO if outpoints is nil, that pay to the anyone-can-spend script.
+// It returns false if any of the given outpoints isn't such a UTXO.
+func (test *buildTest) spendableUTXOs(outpoints []*externalapi.DomainOutpoint) (
+	[]*externalapi.OutpointAndUTXOEntryPair, bool) {
+
+	virtualUTXOs := test.virtualUTXOs()
+	spendableUTXOsByOutpoint := make(map[externalapi.DomainOutpoint]*externalapi.OutpointAndUTXOEntryPair)
+	var spendableUTXOs []*externalapi.OutpointAndUTXOEntryPair
+	for _, virtualUTXO := range virtualUTXOs {
+		if virtualUTXO.UTXOEntry.ScriptPublicKey().Equal(test.scriptPublicKey) {
+			spendableUTXOsByOutpoint[*virtualUTXO.Outpoint] = virtualUTXO
+			spendableUTXOs = append(spendableUTXOs, virtualUTXO)
+		}
+	}
+	if outpoints == nil {
+		return spendableUTXOs, true
+	}
+
+	spendableUTXOs = make([]*externalapi.OutpointAndUTXOEntryPair, len(outpoints))
+	for i, outpoint := range outpoints {
+		spendableUTXO, ok := spendableUTXOsByOutpoint[*outpoint]
+		if !ok {
+			return nil, false
+		}
+		spendableUTXOs[i] = spendableUTXO
+	}
+	return spendableUTXOs, true
+}
+
+// spendTransaction returns a transaction that spends the given UTXOs into
+// the given amount of outputs to the anyone-can-spend script
+func (test *buildTest) spendTransaction(utxos []*externalapi.OutpointAndUTXOEntryPair,
+	outputCount int) *externalapi.DomainTransaction {
+
+	const fee = 10000
+
+	signatureScript, err := txscript.PayToScriptHashSignatureScript(test.redeemScript, nil)
+	if err != nil {
+		test.t.Fatalf("PayToScriptHashSignatureScript: %+v", err)
+	}
+	inputs := make([]*externalapi.DomainTransactionInput, len(utxos))
+	totalValue := uint64(0)
+	for i, utxo := range utxos {
+		inputs[i] = &externalapi.DomainTransactionInput{
+			PreviousOutpoint: *utxo.Outpoint,
+			SignatureScript:  signatureScript,
+			Sequence:         constants.MaxTxInSequenceNum,
+		}
+		totalValue += utxo.UTXOEntry.Amount()
+	}
+	outputs := make([]*externalapi.DomainTransactionOutput, outputCount)
+	for i := range outputs {
+		outputs[i] = &externalapi.DomainTransactionOutput{
+			ScriptPublicKey: test.scriptPublicKey,
+			Value:           (totalValue - fee) / uint64(outputCount),
+		}
+	}
+	return &externalapi.DomainTransaction{
+		Version: constants.MaxTransactionVersion,
+		Inputs:  inputs,
+		Outputs: outputs,
+		Payload: []byte{},
+	}
+}
+
+// expectIndexEqualsVirtualUTXOSet checks that the UTXO index holds exactly
+// the virtual UTXO set, and that its circulating supply is the total value
+// of the virtual UTXO set
+func (test *buildTest) expectIndexEqualsVirtualUTXOSet() {
+	virtualUTXOs := test.virtualUTXOs()
+	expectedUTXOsByScriptPublicKey := make(map[ScriptPublicKeyString]UTXOOutpointEntryPairs)
+	scriptPublicKeys := make(map[ScriptPublicKeyString]*externalapi.ScriptPublicKey)
+	expectedSupply := uint64(0)
+	for _, virtualUTXO := range virtualUTXOs {
+		scriptPublicKey := virtualUTXO.UTXOEntry.ScriptPublicKey()
+		scriptPublicKeyString := ScriptPublicKeyString(scriptPublicKey.String())
+		if _, ok := expectedUTXOsByScriptPublicKey[scriptPublicKeyString]; !ok {
+			expectedUTXOsByScriptPublicKey[scriptPublicKeyString] = make(UTXOOutpointEntryPairs)
+			scriptPublicKeys[scriptPublicKeyString] = scriptPublicKey
+		}
+		expectedUTXOsByScriptPublicKey[scriptPublicKeyString][*virtualUTXO.Outpoint] = virtualUTXO.UTXOEntry
+		expectedSupply += virtualUTXO.UTXOEntry.Amount()
+	}
+
+	for scriptPublicKeyString, expectedUTXOs := range expectedUTXOsByScriptPublicKey {
+		utxos, err := test.ui.UTXOs(scriptPublicKeys[scriptPublicKeyString])
+		if err != nil {
+			test.t.Fatalf("UTXOs: %+v", err)
+		}
+		if len(utxos) != len(expectedUTXOs) {
+			test.t.Fatalf("Expected %d UTXOs in the index for %s, got %d",
+				len(expectedUTXOs), scriptPublicKeyString, len(utxos))
+		}
+		for outpoint, expectedEntry := range expectedUTXOs {
+			entry, ok := utxos[outpoint]
+			if !ok {
+				test.t.Fatalf("Outpoint %s is missing from the index", outpoint)
+			}
+			if !entry.Equal(expectedEntry) {
+				test.t.Fatalf("Unexpected entry of outpoint %s in the index", outpoint)
+			}
+		}
+	}
+
+	supply, err := test.ui.GetCirculatingSompiSupply()
+	if err != nil {
+		test.t.Fatalf("GetCirculatingSompiSupply: %+v", err)
+	}
+	if supply != expectedSupply {
+		test.t.Fatalf("Expected a circulating supply of %d, got %d", expectedSupply, supply)
+	}
+}
@@ -0,0 +1,122 @@
+package utxoindex
+
+import (
+	"github.com/Hoosat-Oy/HTND/infrastructure/db/database"
+)
+
+// migrationBatchSize is the amount of UTXOs that are aggregated in a single
+// database transaction by MigrateAggregates. Tests lower it to migrate a few
+// UTXOs in several batches.
+var migrationBatchSize = 10000
+
+// MigrateAggregates adds the aggregates of its script public keys to a UTXO
+// index that was built before they were maintained. The key of the last
+// aggregated UTXO is stored in progressKey together with every batch, so an
+// interrupted migration resumes from where it stopped.
+func MigrateAggregates(db database.Database, progressKey *database.Key) error {
+	hasAggregates, err := db.Has(hasAggregatesKey)
+	if err != nil {
+		return err
+	}
+	if hasAggregates {
+		return nil
+	}
+
+	store := newUTXOIndexStore(db)
+	var lastSuffix []byte
+	serializedProgress, err := db.Get(progressKey)
+	if err == nil {
+		lastSuffix = serializedProgress
+	} else if !database.IsNotFoundError(err) {
+		return err
+	}
+
+	migratedUTXOCount := 0
+	for {
+		aggregateDiffs, batchLastSuffix, batchSize, err := aggregateNextMigrationBatch(db, lastSuffix)
+		if err != nil {
+			return err
+		}
+		if batchSize == 0 {
+			break
+		}
+
+		err = commitMigrationBatch(db, store, aggregateDiffs, progressKey, batchLastSuffix)
+		if err != nil {
+			return err
+		}
+
+		lastSuffix = batchLastSuffix
+		migratedUTXOCount += batchSize
+		log.Infof("Aggregated %d UTXOs of the UTXO index", migratedUTXOCount)
+		if batchSize < migrationBatchSize {
+			break
+		}
+	}
+
+	return store.initializeAggregates()
+}
+
+func commitMigrationBatch(db database.Database, store *utxoIndexStore,
+	aggregateDiffs map[ScriptPublicKeyString]*aggregateDiff, progressKey *database.Key, batchLastSuffix []byte) error {
+
+	dbTransaction, err := db.Begin()
+	if err != nil {
+		return err
+	}
+	defer dbTransaction.RollbackUnlessClosed()
+
+	err = store.updateAggregates(dbTransaction, aggregateDiffs)
+	if err != nil {
+		return err
+	}
+	err = dbTransaction.Put(progressKey, batchLastSuffix)
+	if err != nil {
+		return err
+	}
+	return dbTransaction.Commit()
+}
+
+// aggregateNextMigrationBatch returns the aggregate diffs of the UTXOs of the
+// index that follow the one with the given key suffix, up to migrationBatchSize
+// of them. A nil lastSuffix starts from the first UTXO.
+func aggregateNextMigrationBatch(db database.Database, lastSuffix []byte) (
+	aggregateDiffs map[ScriptPublicKeyString]*aggregateDiff, batchLastSuffix []byte, batchSize int, err error) {
+
+	cursor, err := db.Cursor(utxoIndexBucket)
+	if err != nil {
+		return nil, nil, 0, err
+	}
+	defer cursor.Close()
+
+	if lastSuffix != nil {
+		err = cursor.Seek(utxoIndexBucket.Key(lastSuffix))
+		if err != nil {
+			return nil, nil, 0, err
+		}
+	}
+
+	aggregateDiffs = make(map[ScriptPublicKeyString]*aggregateDiff)
+	for batchSize < migrationBatchSize && cursor.Next() {
+		key, err := cursor.Key()
+		if err != nil {
+			return nil, nil, 0, err
+		}
+		serializedUTXOEntry, err := cursor.Value()
+		if err != nil {
+			return nil, nil, 0, err
+		}
+		utxoEntry, err := deserializeUTXOEntry(serializedUTXOEntry)
+		if err != nil {
+			return nil, nil, 0, err
+		}
+
+		diff := aggregateDiffOf(aggregateDiffs, ScriptPublicKeyString(utxoEntry.ScriptPublicKey().String()))
+		diff.addedAmount += utxoEntry.Amount()
+		diff.addedCount++
+		// The suffix may change on the next call to cursor.Next
+		batchLastSuffix = append([]byte(nil), key.Suffix()...)
+		batchSize++
+	}
+	return aggregateDiffs, batchLastSuffix, batchSize, nil
+}
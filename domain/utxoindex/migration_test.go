@@ -0,0 +1,114 @@
+package utxoindex
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/Hoosat-Oy/HTND/domain/consensus/model/externalapi"
+	"github.com/Hoosat-Oy/HTND/domain/consensus/utils/utxo"
+	"github.com/Hoosat-Oy/HTND/infrastructure/db/database"
+)
+
+func TestMigrateAggregates(t *testing.T) {
+	store, teardown := newUTXOIndexStoreForTest(t)
+	defer teardown()
+
+	scriptPublicKey := &externalapi.ScriptPublicKey{Script: []byte{1, 2, 3}, Version: 0}
+	// A script that starts with the script above followed by the bucket separator
+	otherScriptPublicKey := &externalapi.ScriptPublicKey{Script: []byte{1, 2, 3, '/', 4}, Version: 0}
+
+	err := store.addAndCommitOutpointsWithoutTransaction([]*externalapi.OutpointAndUTXOEntryPair{
+		{Outpoint: testOutpoint(1, 0), UTXOEntry: utxo.NewUTXOEntry(10, scriptPublicKey, false, 0)},
+		{Outpoint: testOutpoint(1, 1), UTXOEntry: utxo.NewUTXOEntry(20, scriptPublicKey, false, 0)},
+		{Outpoint: testOutpoint(2, 0), UTXOEntry: utxo.NewUTXOEntry(5, otherScriptPublicKey, false, 0)},
+	})
+	if err != nil {
+		t.Fatalf("addAndCommitOutpointsWithoutTransaction: %+v", err)
+	}
+
+	// Simulate an index that was built before the aggregates were maintained
+	err = store.deleteBucket(utxoIndexAggregatesBucket)
+	if err != nil {
+		t.Fatalf("deleteBucket: %+v", err)
+	}
+	expectAggregate(t, store, scriptPublicKey, &UTXOAggregate{Balance: 0, UTXOCount: 0})
+
+	progressKey := database.MakeBucket([]byte("test-migration-progress")).Key([]byte("aggregates"))
+	err = MigrateAggregates(store.database, progressKey)
+	if err != nil {
+		t.Fatalf("MigrateAggregates: %+v", err)
+	}
+	expectAggregate(t, store, scriptPublicKey, &UTXOAggregate{Balance: 30, UTXOCount: 2})
+	expectAggregate(t, store, otherScriptPublicKey, &UTXOAggregate{Balance: 5, UTXOCount: 1})
+
+	hasAggregates, err := store.database.Has(hasAggregatesKey)
+	if err != nil {
+		t.Fatalf("Has: %+v", err)
+	}
+	if !hasAggregates {
+		t.Fatalf("MigrateAggregates didn't mark the index as having aggregates")
+	}
+
+	// A migrated index isn't aggregated again
+	err = MigrateAggregates(store.database, progressKey)
+	if err != nil {
+		t.Fatalf("MigrateAggregates: %+v", err)
+	}
+	expectAggregate(t, store, scriptPublicKey, &UTXOAggregate{Balance: 30, UTXOCount: 2})
+}
+
+func TestMigrateAggregatesResume(t *testing.T) {
+	store, teardown := newUTXOIndexStoreForTest(t)
+	defer teardown()
+
+	originalMigrationBatchSize := migrationBatchSize
+	migrationBatchSize = 2
+	defer func() { migrationBatchSize = originalMigrationBatchSize }()
+
+	scriptPublicKey := &externalapi.ScriptPublicKey{Script: []byte{1, 2, 3}, Version: 0}
+	otherScriptPublicKey := &externalapi.ScriptPublicKey{Script: []byte{4, 5, 6}, Version: 0}
+
+	err := store.addAndCommitOutpointsWithoutTransaction([]*externalapi.OutpointAndUTXOEntryPair{
+		{Outpoint: testOutpoint(1, 0), UTXOEntry: utxo.NewUTXOEntry(10, scriptPublicKey, false, 0)},
+		{Outpoint: testOutpoint(1, 1), UTXOEntry: utxo.NewUTXOEntry(20, scriptPublicKey, false, 0)},
+		{Outpoint: testOutpoint(2, 0), UTXOEntry: utxo.NewUTXOEntry(40, scriptPublicKey, false, 0)},
+		{Outpoint: testOutpoint(3, 0), UTXOEntry: utxo.NewUTXOEntry(5, otherScriptPublicKey, false, 0)},
+		{Outpoint: testOutpoint(3, 1), UTXOEntry: utxo.NewUTXOEntry(7, otherScriptPublicKey, false, 0)},
+	})
+	if err != nil {
+		t.Fatalf("addAndCommitOutpointsWithoutTransaction: %+v", err)
+	}
+	err = store.deleteBucket(utxoIndexAggregatesBucket)
+	if err != nil {
+		t.Fatalf("deleteBucket: %+v", err)
+	}
+
+	// Simulate a migration that was interrupted after committing its
+	// first batch
+	progressKey := database.MakeBucket([]byte("test-migration-progress")).Key([]byte("aggregates"))
+	aggregateDiffs, batchLastSuffix, batchSize, err := aggregateNextMigrationBatch(store.database, nil)
+	if err != nil {
+		t.Fatalf("aggregateNextMigrationBatch: %+v", err)
+	}
+	if batchSize != migrationBatchSize {
+		t.Fatalf("expected a first batch of %d UTXOs, got %d", migrationBatchSize, batchSize)
+	}
+	err = commitMigrationBatch(store.database, store, aggregateDiffs, progressKey, batchLastSuffix)
+	if err != nil {
+		t.Fatalf("commitMigrationBatch: %+v", err)
+	}
+	serializedProgress, err := store.database.Get(progressKey)
+	if err != nil {
+		t.Fatalf("Get: %+v", err)
+	}
+	if !bytes.Equal(serializedProgress, batchLastSuffix) {
+		t.Fatalf("the progress of the migration wasn't committed with its batch")
+	}
+
+	err = MigrateAggregates(store.database, progressKey)
+	if err != nil {
+		t.Fatalf("MigrateAggregates: %+v", err)
+	}
+	expectAggregate(t, store, scriptPublicKey, &UTXOAggregate{Balance: 70, UTXOCount: 3})
+	expectAggregate(t, store, otherScriptPublicKey, &UTXOAggregate{Balance: 12, UTXOCount: 2})
+}
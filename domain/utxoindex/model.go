@@ -21,3 +21,10 @@ type UTXOChanges struct {
 	Added   map[ScriptPublicKeyString]UTXOOutpointEntryPairs
 	Removed map[ScriptPublicKeyString]UTXOOutpointEntryPairs
 }
+
+// UTXOAggregate is the total amount and count of the UTXOs of a
+// script public key
+type UTXOAggregate struct {
+	Balance   uint64
+	UTXOCount uint64
+}
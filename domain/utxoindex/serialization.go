@@ -71,3 +71,24 @@ func deserializeHashes(serializedHashes []byte) ([]*externalapi.DomainHash, erro
 
 	return hashes, nil
 }
+
+// utxoAggregateLength is the length of a serialized UTXOAggregate
+const utxoAggregateLength = 16
+
+func serializeUTXOAggregate(aggregate *UTXOAggregate) []byte {
+	serializedAggregate := make([]byte, utxoAggregateLength)
+	binary.LittleEndian.PutUint64(serializedAggregate[:8], aggregate.Balance)
+	binary.LittleEndian.PutUint64(serializedAggregate[8:], aggregate.UTXOCount)
+	return serializedAggregate
+}
+
+func deserializeUTXOAggregate(serializedAggregate []byte) (*UTXOAggregate, error) {
+	if len(serializedAggregate) != utxoAggregateLength {
+		return nil, errors.Errorf("serialized UTXO aggregate is %d bytes instead of %d",
+			len(serializedAggregate), utxoAggregateLength)
+	}
+	return &UTXOAggregate{
+		Balance:   binary.LittleEndian.Uint64(serializedAggregate[:8]),
+		UTXOCount: binary.LittleEndian.Uint64(serializedAggregate[8:]),
+	}, nil
+}
@@ -1,6 +1,7 @@
 package utxoindex
 
 import (
+	"bytes"
 	"encoding/binary"
 
 	"github.com/Hoosat-Oy/HTND/domain/consensus/database/binaryserialization"
@@ -13,6 +14,13 @@ import (
 var utxoIndexBucket = database.MakeBucket([]byte("utxo-index"))
 var virtualParentsKey = database.MakeBucket([]byte("")).Key([]byte("utxo-index-virtual-parents"))
 var circulatingSupplyKey = database.MakeBucket([]byte("")).Key([]byte("utxo-index-circulating-supply"))
+var utxoIndexAggregatesBucket = database.MakeBucket([]byte("utxo-index-aggregates"))
+
+// hasAggregatesKey marks an index that maintains the aggregates of its
+// script public keys. Indexes that were built before the aggregates were
+// added get them from MigrateAggregates, and are rebuilt if they're opened
+// without it.
+var hasAggregatesKey = database.MakeBucket([]byte("")).Key([]byte("utxo-index-has-aggregates"))
 
 type utxoIndexStore struct {
 	database database.Database
@@ -120,10 +128,12 @@ func (uis *utxoIndexStore) commit() error {
 	defer dbTransaction.RollbackUnlessClosed()
 
 	toRemoveSompiSupply := uint64(0)
+	aggregateDiffs := make(map[ScriptPublicKeyString]*aggregateDiff)
 
 	for scriptPublicKeyString, toRemoveUTXOOutpointEntryPairs := range uis.toRemove {
 		scriptPublicKey := externalapi.NewScriptPublicKeyFromString(string(scriptPublicKeyString))
 		bucket := uis.bucketForScriptPublicKey(scriptPublicKey)
+		diff := aggregateDiffOf(aggregateDiffs, scriptPublicKeyString)
 		for outpointToRemove, utxoEntryToRemove := range toRemoveUTXOOutpointEntryPairs {
 			key, err := uis.convertOutpointToKey(bucket, &outpointToRemove)
 			if err != nil {
@@ -134,6 +144,8 @@ func (uis *utxoIndexStore) commit() error {
 				return err
 			}
 			toRemoveSompiSupply = toRemoveSompiSupply + utxoEntryToRemove.Amount()
+			diff.removedAmount += utxoEntryToRemove.Amount()
+			diff.removedCount++
 		}
 	}
 
@@ -142,6 +154,7 @@ func (uis *utxoIndexStore) commit() error {
 	for scriptPublicKeyString, toAddUTXOOutpointEntryPairs := range uis.toAdd {
 		scriptPublicKey := externalapi.NewScriptPublicKeyFromString(string(scriptPublicKeyString))
 		bucket := uis.bucketForScriptPublicKey(scriptPublicKey)
+		diff := aggregateDiffOf(aggregateDiffs, scriptPublicKeyString)
 		for outpointToAdd, utxoEntryToAdd := range toAddUTXOOutpointEntryPairs {
 			key, err := uis.convertOutpointToKey(bucket, &outpointToAdd)
 			if err != nil {
@@ -156,15 +169,25 @@ func (uis *utxoIndexStore) commit() error {
 				return err
 			}
 			toAddSompiSupply = toAddSompiSupply + utxoEntryToAdd.Amount()
+			diff.addedAmount += utxoEntryToAdd.Amount()
+			diff.addedCount++
 		}
 	}
 
-	serializeParentHashes := serializeHashes(uis.virtualParents)
-	err = dbTransaction.Put(virtualParentsKey, serializeParentHashes)
+	err = uis.updateAggregates(dbTransaction, aggregateDiffs)
 	if err != nil {
 		return err
 	}
 
+	// The virtual parents aren't staged while the index is built
+	if uis.virtualParents != nil {
+		serializeParentHashes := serializeHashes(uis.virtualParents)
+		err = dbTransaction.Put(virtualParentsKey, serializeParentHashes)
+		if err != nil {
+			return err
+		}
+	}
+
 	err = uis.updateCirculatingSompiSupply(dbTransaction, toAddSompiSupply, toRemoveSompiSupply)
 	if err != nil {
 		return err
@@ -181,6 +204,7 @@ func (uis *utxoIndexStore) commit() error {
 
 func (uis *utxoIndexStore) addAndCommitOutpointsWithoutTransaction(utxoPairs []*externalapi.OutpointAndUTXOEntryPair) error {
 	toAddSompiSupply := uint64(0)
+	aggregateDiffs := make(map[ScriptPublicKeyString]*aggregateDiff)
 	for _, pair := range utxoPairs {
 		bucket := uis.bucketForScriptPublicKey(pair.UTXOEntry.ScriptPublicKey())
 		key, err := uis.convertOutpointToKey(bucket, pair.Outpoint)
@@ -198,6 +222,9 @@ func (uis *utxoIndexStore) addAndCommitOutpointsWithoutTransaction(utxoPairs []*
 			return err
 		}
 		toAddSompiSupply = toAddSompiSupply + pair.UTXOEntry.Amount()
+		diff := aggregateDiffOf(aggregateDiffs, ScriptPublicKeyString(pair.UTXOEntry.ScriptPublicKey().String()))
+		diff.addedAmount += pair.UTXOEntry.Amount()
+		diff.addedCount++
 	}
 
 	err := uis.updateCirculatingSompiSupplyWithoutTransaction(toAddSompiSupply, uint64(0))
@@ -205,6 +232,11 @@ func (uis *utxoIndexStore) addAndCommitOutpointsWithoutTransaction(utxoPairs []*
 		return err
 	}
 
+	err = uis.updateAggregates(uis.database, aggregateDiffs)
+	if err != nil {
+		return err
+	}
+
 	return nil
 }
 
@@ -297,6 +329,79 @@ func (uis *utxoIndexStore) getUTXOOutpointEntryPairs(scriptPublicKey *externalap
 	return utxoOutpointEntryPairs, nil
 }
 
+// getUTXOOutpointEntryPairsPage returns up to limit UTXOs of the given scriptPublicKey,
+// in the order of their serialized outpoints, that follow fromOutpoint. A nil
+// fromOutpoint starts from the first UTXO.
+func (uis *utxoIndexStore) getUTXOOutpointEntryPairsPage(scriptPublicKey *externalapi.ScriptPublicKey,
+	fromOutpoint *externalapi.DomainOutpoint, limit int) ([]*externalapi.OutpointAndUTXOEntryPair, error) {
+
+	if uis.isAnythingStaged() {
+		return nil, errors.Errorf("cannot get utxo outpoint entry pairs while staging isn't empty")
+	}
+
+	bucket := uis.bucketForScriptPublicKey(scriptPublicKey)
+	cursor, err := uis.database.Cursor(bucket)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close()
+
+	// isAtNextUTXO is whether the cursor is already at the first UTXO to
+	// return, rather than right before it
+	isAtNextUTXO := false
+	if fromOutpoint != nil {
+		serializedFromOutpoint, err := serializeOutpoint(fromOutpoint)
+		if err != nil {
+			return nil, err
+		}
+		// fromOutpoint may have been spent since it was returned, so the
+		// cursor is moved to the first UTXO that isn't before it
+		found, err := cursor.SeekGreaterOrEqual(bucket.Key(serializedFromOutpoint))
+		if err != nil {
+			return nil, err
+		}
+		if !found {
+			return []*externalapi.OutpointAndUTXOEntryPair{}, nil
+		}
+		key, err := cursor.Key()
+		if err != nil {
+			return nil, err
+		}
+		isAtNextUTXO = !bytes.Equal(key.Suffix(), serializedFromOutpoint)
+	}
+
+	pairs := make([]*externalapi.OutpointAndUTXOEntryPair, 0)
+	for len(pairs) < limit {
+		if isAtNextUTXO {
+			isAtNextUTXO = false
+		} else if !cursor.Next() {
+			break
+		}
+
+		key, err := cursor.Key()
+		if err != nil {
+			return nil, err
+		}
+		outpoint, err := uis.convertKeyToOutpoint(key)
+		if err != nil {
+			return nil, err
+		}
+		serializedUTXOEntry, err := cursor.Value()
+		if err != nil {
+			return nil, err
+		}
+		utxoEntry, err := deserializeUTXOEntry(serializedUTXOEntry)
+		if err != nil {
+			return nil, err
+		}
+		pairs = append(pairs, &externalapi.OutpointAndUTXOEntryPair{
+			Outpoint:  outpoint,
+			UTXOEntry: utxoEntry,
+		})
+	}
+	return pairs, nil
+}
+
 func (uis *utxoIndexStore) getVirtualParents() ([]*externalapi.DomainHash, error) {
 	if uis.isAnythingStaged() {
 		return nil, errors.Errorf("cannot get the virtual parents while staging isn't empty")
@@ -323,7 +428,23 @@ func (uis *utxoIndexStore) deleteAll() error {
 		return err
 	}
 
-	cursor, err := uis.database.Cursor(utxoIndexBucket)
+	err = uis.database.Delete(hasAggregatesKey)
+	if err != nil {
+		return err
+	}
+
+	for _, bucket := range []*database.Bucket{utxoIndexBucket, utxoIndexAggregatesBucket} {
+		err := uis.deleteBucket(bucket)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (uis *utxoIndexStore) deleteBucket(bucket *database.Bucket) error {
+	cursor, err := uis.database.Cursor(bucket)
 	if err != nil {
 		return err
 	}
@@ -431,3 +552,79 @@ func (uis *utxoIndexStore) getCirculatingSompiSupply() (uint64, error) {
 	}
 	return binaryserialization.DeserializeUint64(circulatingSupply)
 }
+
+// aggregateDiff is the change in the aggregate of a script public key
+type aggregateDiff struct {
+	addedAmount   uint64
+	removedAmount uint64
+	addedCount    uint64
+	removedCount  uint64
+}
+
+func aggregateDiffOf(aggregateDiffs map[ScriptPublicKeyString]*aggregateDiff,
+	scriptPublicKeyString ScriptPublicKeyString) *aggregateDiff {
+
+	diff, ok := aggregateDiffs[scriptPublicKeyString]
+	if !ok {
+		diff = &aggregateDiff{}
+		aggregateDiffs[scriptPublicKeyString] = diff
+	}
+	return diff
+}
+
+func (uis *utxoIndexStore) aggregateKey(scriptPublicKeyString ScriptPublicKeyString) *database.Key {
+	return utxoIndexAggregatesBucket.Key([]byte(scriptPublicKeyString))
+}
+
+func (uis *utxoIndexStore) initializeAggregates() error {
+	return uis.database.Put(hasAggregatesKey, []byte{})
+}
+
+// updateAggregates applies the given diffs to the aggregates of their script
+// public keys. The aggregates of script public keys that are left without
+// UTXOs are deleted.
+func (uis *utxoIndexStore) updateAggregates(dataAccessor database.DataAccessor,
+	aggregateDiffs map[ScriptPublicKeyString]*aggregateDiff) error {
+
+	for scriptPublicKeyString, diff := range aggregateDiffs {
+		key := uis.aggregateKey(scriptPublicKeyString)
+		aggregate, err := getAggregate(dataAccessor, key)
+		if err != nil {
+			return err
+		}
+		if aggregate.UTXOCount+diff.addedCount < diff.removedCount {
+			return errors.Errorf("cannot remove %d UTXOs from scriptPublicKey %x, which has %d UTXOs",
+				diff.removedCount, []byte(scriptPublicKeyString), aggregate.UTXOCount+diff.addedCount)
+		}
+		aggregate.Balance = aggregate.Balance + diff.addedAmount - diff.removedAmount
+		aggregate.UTXOCount = aggregate.UTXOCount + diff.addedCount - diff.removedCount
+
+		if aggregate.UTXOCount == 0 {
+			err = dataAccessor.Delete(key)
+		} else {
+			err = dataAccessor.Put(key, serializeUTXOAggregate(aggregate))
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func getAggregate(dataAccessor database.DataAccessor, key *database.Key) (*UTXOAggregate, error) {
+	serializedAggregate, err := dataAccessor.Get(key)
+	if err != nil {
+		if database.IsNotFoundError(err) {
+			return &UTXOAggregate{}, nil
+		}
+		return nil, err
+	}
+	return deserializeUTXOAggregate(serializedAggregate)
+}
+
+func (uis *utxoIndexStore) getAggregate(scriptPublicKey *externalapi.ScriptPublicKey) (*UTXOAggregate, error) {
+	if uis.isAnythingStaged() {
+		return nil, errors.Errorf("cannot get the aggregate of a scriptPublicKey while staging isn't empty")
+	}
+	return getAggregate(uis.database, uis.aggregateKey(ScriptPublicKeyString(scriptPublicKey.String())))
+}
@@ -0,0 +1,151 @@
+package utxoindex
+
+import (
+	"testing"
+
+	"github.com/Hoosat-Oy/HTND/domain/consensus/model/externalapi"
+	"github.com/Hoosat-Oy/HTND/domain/consensus/utils/utxo"
+	"github.com/Hoosat-Oy/HTND/infrastructure/db/database/ldb"
+)
+
+func newUTXOIndexStoreForTest(t *testing.T) (store *utxoIndexStore, teardown func()) {
+	db, err := ldb.NewLevelDB(t.TempDir(), 8)
+	if err != nil {
+		t.Fatalf("NewLevelDB: %+v", err)
+	}
+	store = newUTXOIndexStore(db)
+	err = store.initializeCirculatingSompiSupply()
+	if err != nil {
+		t.Fatalf("initializeCirculatingSompiSupply: %+v", err)
+	}
+	return store, func() {
+		db.Close()
+	}
+}
+
+func testOutpoint(transactionIDByte byte, index uint32) *externalapi.DomainOutpoint {
+	transactionID := externalapi.NewDomainTransactionIDFromByteArray(&[externalapi.DomainHashSize]byte{transactionIDByte})
+	return externalapi.NewDomainOutpoint(transactionID, index)
+}
+
+func TestAggregates(t *testing.T) {
+	store, teardown := newUTXOIndexStoreForTest(t)
+	defer teardown()
+
+	scriptPublicKey := &externalapi.ScriptPublicKey{Script: []byte{1, 2, 3}, Version: 0}
+	otherScriptPublicKey := &externalapi.ScriptPublicKey{Script: []byte{4, 5, 6}, Version: 0}
+
+	err := store.addAndCommitOutpointsWithoutTransaction([]*externalapi.OutpointAndUTXOEntryPair{
+		{Outpoint: testOutpoint(1, 0), UTXOEntry: utxo.NewUTXOEntry(10, scriptPublicKey, false, 0)},
+		{Outpoint: testOutpoint(1, 1), UTXOEntry: utxo.NewUTXOEntry(20, scriptPublicKey, false, 0)},
+		{Outpoint: testOutpoint(2, 0), UTXOEntry: utxo.NewUTXOEntry(5, otherScriptPublicKey, false, 0)},
+	})
+	if err != nil {
+		t.Fatalf("addAndCommitOutpointsWithoutTransaction: %+v", err)
+	}
+	expectAggregate(t, store, scriptPublicKey, &UTXOAggregate{Balance: 30, UTXOCount: 2})
+	expectAggregate(t, store, otherScriptPublicKey, &UTXOAggregate{Balance: 5, UTXOCount: 1})
+
+	err = store.remove(scriptPublicKey, testOutpoint(1, 0), utxo.NewUTXOEntry(10, scriptPublicKey, false, 0))
+	if err != nil {
+		t.Fatalf("remove: %+v", err)
+	}
+	err = store.add(scriptPublicKey, testOutpoint(3, 0), utxo.NewUTXOEntry(100, scriptPublicKey, false, 0))
+	if err != nil {
+		t.Fatalf("add: %+v", err)
+	}
+	err = store.remove(otherScriptPublicKey, testOutpoint(2, 0), utxo.NewUTXOEntry(5, otherScriptPublicKey, false, 0))
+	if err != nil {
+		t.Fatalf("remove: %+v", err)
+	}
+	err = store.commit()
+	if err != nil {
+		t.Fatalf("commit: %+v", err)
+	}
+	expectAggregate(t, store, scriptPublicKey, &UTXOAggregate{Balance: 120, UTXOCount: 2})
+	expectAggregate(t, store, otherScriptPublicKey, &UTXOAggregate{Balance: 0, UTXOCount: 0})
+
+	has, err := store.database.Has(store.aggregateKey(ScriptPublicKeyString(otherScriptPublicKey.String())))
+	if err != nil {
+		t.Fatalf("Has: %+v", err)
+	}
+	if has {
+		t.Fatalf("The aggregate of a scriptPublicKey without UTXOs wasn't deleted")
+	}
+
+	err = store.deleteAll()
+	if err != nil {
+		t.Fatalf("deleteAll: %+v", err)
+	}
+	expectAggregate(t, store, scriptPublicKey, &UTXOAggregate{Balance: 0, UTXOCount: 0})
+}
+
+func expectAggregate(t *testing.T, store *utxoIndexStore, scriptPublicKey *externalapi.ScriptPublicKey,
+	expected *UTXOAggregate) {
+
+	aggregate, err := store.getAggregate(scriptPublicKey)
+	if err != nil {
+		t.Fatalf("getAggregate: %+v", err)
+	}
+	if *aggregate != *expected {
+		t.Fatalf("Expected aggregate %+v, got %+v", expected, aggregate)
+	}
+}
+
+func TestGetUTXOOutpointEntryPairsPage(t *testing.T) {
+	store, teardown := newUTXOIndexStoreForTest(t)
+	defer teardown()
+
+	scriptPublicKey := &externalapi.ScriptPublicKey{Script: []byte{1, 2, 3}, Version: 0}
+	pairs := make([]*externalapi.OutpointAndUTXOEntryPair, 10)
+	for i := range pairs {
+		pairs[i] = &externalapi.OutpointAndUTXOEntryPair{
+			Outpoint:  testOutpoint(byte(i), 0),
+			UTXOEntry: utxo.NewUTXOEntry(uint64(i+1), scriptPublicKey, false, 0),
+		}
+	}
+	err := store.addAndCommitOutpointsWithoutTransaction(pairs)
+	if err != nil {
+		t.Fatalf("addAndCommitOutpointsWithoutTransaction: %+v", err)
+	}
+
+	page, err := store.getUTXOOutpointEntryPairsPage(scriptPublicKey, nil, 4)
+	if err != nil {
+		t.Fatalf("getUTXOOutpointEntryPairsPage: %+v", err)
+	}
+	expectPage(t, page, pairs[:4])
+
+	page, err = store.getUTXOOutpointEntryPairsPage(scriptPublicKey, page[len(page)-1].Outpoint, 4)
+	if err != nil {
+		t.Fatalf("getUTXOOutpointEntryPairsPage: %+v", err)
+	}
+	expectPage(t, page, pairs[4:8])
+
+	// The last outpoint of the page is spent before the next page is requested
+	err = store.remove(scriptPublicKey, pairs[7].Outpoint, pairs[7].UTXOEntry)
+	if err != nil {
+		t.Fatalf("remove: %+v", err)
+	}
+	err = store.commit()
+	if err != nil {
+		t.Fatalf("commit: %+v", err)
+	}
+	page, err = store.getUTXOOutpointEntryPairsPage(scriptPublicKey, page[len(page)-1].Outpoint, 4)
+	if err != nil {
+		t.Fatalf("getUTXOOutpointEntryPairsPage: %+v", err)
+	}
+	expectPage(t, page, pairs[8:])
+}
+
+func expectPage(t *testing.T, page []*externalapi.OutpointAndUTXOEntryPair,
+	expected []*externalapi.OutpointAndUTXOEntryPair) {
+
+	if len(page) != len(expected) {
+		t.Fatalf("Expected a page of %d UTXOs, got %d", len(expected), len(page))
+	}
+	for i := range page {
+		if !page[i].Outpoint.Equal(expected[i].Outpoint) {
+			t.Fatalf("Expected outpoint %s in index %d of the page, got %s", expected[i].Outpoint, i, page[i].Outpoint)
+		}
+	}
+}
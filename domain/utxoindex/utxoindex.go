@@ -15,10 +15,19 @@ type UTXOIndex struct {
 	domain domain.Domain
 	store  *utxoIndexStore
 
+	// virtualParents are the virtual parents of the last virtual change
+	// that was applied to the index
+	virtualParents []*externalapi.DomainHash
+
+	// build is the running background build of the index, or nil
+	// if the index is synced with the virtual
+	build *utxoIndexBuild
+
 	mutex sync.Mutex
 }
 
-// New creates a new UTXO index.
+// New creates a new UTXO index. An index that isn't synced with the virtual
+// is rebuilt in the background, see Rebuild.
 //
 // NOTE: While this is called no new blocks can be added to the consensus.
 func New(domain domain.Domain, database database.Database) (*UTXOIndex, error) {
@@ -37,19 +46,30 @@ func New(domain domain.Domain, database database.Database) (*UTXOIndex, error) {
 		return nil, err
 	}
 
-	if !isSynced || !hasCirculatingSupplyKey {
+	hasAggregates, err := utxoIndex.store.database.Has(hasAggregatesKey)
+	if err != nil {
+		return nil, err
+	}
 
-		err := utxoIndex.Reset()
-		if err != nil {
-			return nil, err
-		}
+	virtualInfo, err := domain.Consensus().GetVirtualInfo()
+	if err != nil {
+		return nil, err
+	}
+	utxoIndex.virtualParents = virtualInfo.ParentHashes
+
+	if !isSynced || !hasCirculatingSupplyKey || !hasAggregates {
+		utxoIndex.Rebuild()
 	}
 
 	return utxoIndex, nil
 }
 
-// Reset deletes the whole UTXO index and resyncs it from consensus.
+// Reset deletes the whole UTXO index and resyncs it from consensus. Unlike
+// Rebuild, this returns only once the index is synced, and stops a running
+// build.
 func (ui *UTXOIndex) Reset() error {
+	ui.stopBuild()
+
 	ui.mutex.Lock()
 	defer ui.mutex.Unlock()
 
@@ -70,6 +90,11 @@ func (ui *UTXOIndex) Reset() error {
 		return err
 	}
 
+	err = ui.store.initializeAggregates()
+	if err != nil {
+		return err
+	}
+
 	var fromOutpoint *externalapi.DomainOutpoint
 	for {
 		const step = 1000
@@ -96,6 +121,8 @@ func (ui *UTXOIndex) Reset() error {
 		return err
 	}
 
+	ui.virtualParents = virtualInfo.ParentHashes
+
 	log.Infof("Finished UTXO index reset")
 	return nil
 }
@@ -136,7 +163,16 @@ func (ui *UTXOIndex) Update(virtualChangeSet *externalapi.VirtualChangeSet) (*UT
 		return nil, err
 	}
 
-	ui.store.updateVirtualParents(virtualChangeSet.VirtualParents)
+	ui.virtualParents = virtualChangeSet.VirtualParents
+	if ui.build != nil {
+		// The virtual parents are stored only once the build is done
+		select {
+		case ui.build.virtualChanged <- struct{}{}:
+		default:
+		}
+	} else {
+		ui.store.updateVirtualParents(virtualChangeSet.VirtualParents)
+	}
 
 	added, removed, _ := ui.store.stagedData()
 	utxoIndexChanges := &UTXOChanges{
@@ -153,6 +189,19 @@ func (ui *UTXOIndex) Update(virtualChangeSet *externalapi.VirtualChangeSet) (*UT
 	return utxoIndexChanges, nil
 }
 
+// isSkippedByBuild returns whether an update to the given outpoint is skipped
+// because a running build didn't read it from the consensus yet
+func (ui *UTXOIndex) isSkippedByBuild(outpoint *externalapi.DomainOutpoint) (bool, error) {
+	if ui.build == nil {
+		return false, nil
+	}
+	isScanned, err := ui.build.isScanned(outpoint)
+	if err != nil {
+		return false, err
+	}
+	return !isScanned, nil
+}
+
 func (ui *UTXOIndex) addUTXOs(toAdd externalapi.UTXOCollection) error {
 	iterator := toAdd.Iterator()
 	defer iterator.Close()
@@ -161,6 +210,13 @@ func (ui *UTXOIndex) addUTXOs(toAdd externalapi.UTXOCollection) error {
 		if err != nil {
 			return err
 		}
+		isSkipped, err := ui.isSkippedByBuild(outpoint)
+		if err != nil {
+			return err
+		}
+		if isSkipped {
+			continue
+		}
 
 		log.Tracef("Adding outpoint %s to UTXO index", outpoint)
 		err = ui.store.add(entry.ScriptPublicKey(), outpoint, entry)
@@ -179,6 +235,13 @@ func (ui *UTXOIndex) removeUTXOs(toRemove externalapi.UTXOCollection) error {
 		if err != nil {
 			return err
 		}
+		isSkipped, err := ui.isSkippedByBuild(outpoint)
+		if err != nil {
+			return err
+		}
+		if isSkipped {
+			continue
+		}
 
 		log.Tracef("Removing outpoint %s from UTXO index", outpoint)
 		err = ui.store.remove(entry.ScriptPublicKey(), outpoint, entry)
@@ -197,14 +260,56 @@ func (ui *UTXOIndex) UTXOs(scriptPublicKey *externalapi.ScriptPublicKey) (UTXOOu
 	ui.mutex.Lock()
 	defer ui.mutex.Unlock()
 
+	err := ui.buildingError()
+	if err != nil {
+		return nil, err
+	}
 	return ui.store.getUTXOOutpointEntryPairs(scriptPublicKey)
 }
 
+// UTXOsPage returns up to limit UTXOs of the given scriptPublicKey that follow
+// fromOutpoint, in the order of their serialized outpoints. A nil fromOutpoint
+// starts from the first UTXO. fromOutpoint doesn't have to be unspent, so the
+// UTXOs of a scriptPublicKey may be paged through by passing the last outpoint
+// of the previous page.
+func (ui *UTXOIndex) UTXOsPage(scriptPublicKey *externalapi.ScriptPublicKey, fromOutpoint *externalapi.DomainOutpoint,
+	limit int) ([]*externalapi.OutpointAndUTXOEntryPair, error) {
+
+	onEnd := logger.LogAndMeasureExecutionTime(log, "UTXOIndex.UTXOsPage")
+	defer onEnd()
+
+	ui.mutex.Lock()
+	defer ui.mutex.Unlock()
+
+	err := ui.buildingError()
+	if err != nil {
+		return nil, err
+	}
+	return ui.store.getUTXOOutpointEntryPairsPage(scriptPublicKey, fromOutpoint, limit)
+}
+
+// Aggregate returns the total amount and count of the UTXOs of the given
+// scriptPublicKey, which the index maintains along with the UTXOs themselves
+func (ui *UTXOIndex) Aggregate(scriptPublicKey *externalapi.ScriptPublicKey) (*UTXOAggregate, error) {
+	ui.mutex.Lock()
+	defer ui.mutex.Unlock()
+
+	err := ui.buildingError()
+	if err != nil {
+		return nil, err
+	}
+	return ui.store.getAggregate(scriptPublicKey)
+}
+
 // GetCirculatingSompiSupply returns the current circulating supply of sompis in the network
 func (ui *UTXOIndex) GetCirculatingSompiSupply() (uint64, error) {
 
 	ui.mutex.Lock()
 	defer ui.mutex.Unlock()
 
+	err := ui.buildingError()
+	if err != nil {
+		return 0, err
+	}
 	return ui.store.getCirculatingSompiSupply()
 }
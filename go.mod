@@ -16,6 +16,7 @@ require (
 	github.com/pkg/errors v0.9.1
 	github.com/syndtr/goleveldb v1.0.1-0.20190923125748-758128399b1d
 	github.com/tyler-smith/go-bip39 v1.1.0
+	go.etcd.io/bbolt v1.3.8
 	golang.org/x/crypto v0.1.0
 	golang.org/x/term v0.5.0
 	google.golang.org/grpc v1.38.0
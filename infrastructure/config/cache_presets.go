@@ -0,0 +1,31 @@
+package config
+
+// CachePreset holds the cache sizes chosen with --cachepreset
+type CachePreset struct {
+	// CacheMemMiB is the memory budget of the caches of the consensus stores.
+	// Zero keeps their default sizes.
+	CacheMemMiB uint64
+	// DatabaseCacheMiB is the size of the cache of the database
+	DatabaseCacheMiB int
+}
+
+const defaultCachePreset = "default"
+
+// CachePresets are the presets that can be chosen with --cachepreset
+var CachePresets = map[string]*CachePreset{
+	// The default sizes suit machines with 8GB of RAM or more
+	defaultCachePreset: {
+		CacheMemMiB:      0,
+		DatabaseCacheMiB: 256,
+	},
+	// vps suits machines with 2 to 4GB of RAM
+	"vps": {
+		CacheMemMiB:      512,
+		DatabaseCacheMiB: 128,
+	},
+	// raspberrypi suits single board computers and other machines with 1 to 2GB of RAM
+	"raspberrypi": {
+		CacheMemMiB:      128,
+		DatabaseCacheMiB: 32,
+	},
+}
@@ -18,7 +18,9 @@ import (
 
 	"github.com/Hoosat-Oy/HTND/domain/consensus/model/externalapi"
 	"github.com/Hoosat-Oy/HTND/domain/dagconfig"
+	"github.com/Hoosat-Oy/HTND/infrastructure/db/database/backend"
 	"github.com/Hoosat-Oy/HTND/infrastructure/logger"
+	"github.com/Hoosat-Oy/HTND/infrastructure/network/noise"
 	"github.com/Hoosat-Oy/HTND/util"
 	"github.com/Hoosat-Oy/HTND/util/network"
 	"github.com/Hoosat-Oy/HTND/version"
@@ -49,11 +51,13 @@ const (
 	defaultMinRelayTxFee         = 1e-5 // 1 sompi per byte
 	defaultMaxOrphanTransactions = 100
 	//DefaultMaxOrphanTxSize is the default maximum size for an orphan transaction
-	DefaultMaxOrphanTxSize  = 100_000
-	defaultSigCacheMaxSize  = 100_000
-	sampleConfigFilename    = "sample-htnd.conf"
-	defaultMaxUTXOCacheSize = 5_000_000_000
-	defaultProtocolVersion  = 5
+	DefaultMaxOrphanTxSize         = 100_000
+	defaultSigCacheMaxSize         = 100_000
+	sampleConfigFilename           = "sample-htnd.conf"
+	defaultMaxUTXOCacheSize        = 5_000_000_000
+	defaultProtocolVersion         = 6
+	defaultTxRelayInboundInterval  = 5 * time.Second
+	defaultTxRelayOutboundInterval = 2 * time.Second
 )
 
 var (
@@ -81,8 +85,8 @@ type Flags struct {
 	ConfigFile                      string        `short:"C" long:"configfile" description:"Path to configuration file"`
 	AppDir                          string        `short:"b" long:"appdir" description:"Directory to store data"`
 	LogDir                          string        `long:"logdir" description:"Directory to log output."`
-	AddPeers                        []string      `short:"a" long:"addpeer" description:"Add a peer to connect with at startup"`
-	ConnectPeers                    []string      `long:"connect" description:"Connect only to the specified peers at startup"`
+	AddPeers                        []string      `short:"a" long:"addpeer" description:"Add a peer to connect with at startup -- Prefix it with <public key>@ to require that the peer has this P2P identity"`
+	ConnectPeers                    []string      `long:"connect" description:"Connect only to the specified peers at startup -- Prefix a peer with <public key>@ to require that it has this P2P identity"`
 	DisableListen                   bool          `long:"nolisten" description:"Disable listening for incoming connections -- NOTE: Listening is automatically disabled if the --connect or --proxy options are used without also specifying listen interfaces via --listen"`
 	Listeners                       []string      `long:"listen" description:"Add an interface/port to listen for connections (default all interfaces port: 42421, testnet: 42423)"`
 	TargetOutboundPeers             int           `long:"outpeers" description:"Target number of outbound peers"`
@@ -90,6 +94,8 @@ type Flags struct {
 	EnableBanning                   bool          `long:"enablebanning" description:"Enable banning of misbehaving peers"`
 	BanDuration                     time.Duration `long:"banduration" description:"How long to ban misbehaving peers. Valid time units are {s, m, h}. Minimum 1 second"`
 	BanThreshold                    uint32        `long:"banthreshold" description:"Maximum allowed ban score before disconnecting and banning misbehaving peers."`
+	MaxUploadRate                   uint64        `long:"maxuploadrate" description:"Max rate in KiB/s at which IBD data is uploaded to all peers combined -- 0 means no limit"`
+	MaxPeerUploadRate               uint64        `long:"maxpeeruploadrate" description:"Max rate in KiB/s at which IBD data is uploaded to a single peer -- 0 means no limit"`
 	Whitelists                      []string      `long:"whitelist" description:"Add an IP network or IP that will not be banned. (eg. 192.168.1.0/24 or ::1)"`
 	RPCListeners                    []string      `long:"rpclisten" description:"Add an interface/port to listen for RPC connections (default port: 42420, testnet: 16210)"`
 	RPCCert                         string        `long:"rpccert" description:"File containing the certificate file"`
@@ -106,10 +112,21 @@ type Flags struct {
 	Proxy                           string        `long:"proxy" description:"Connect via SOCKS5 proxy (eg. 127.0.0.1:9050)"`
 	ProxyUser                       string        `long:"proxyuser" description:"Username for proxy server"`
 	ProxyPass                       string        `long:"proxypass" default-mask:"-" description:"Password for proxy server"`
-	DbType                          string        `long:"dbtype" description:"Database backend to use for the Block DAG"`
+	OnionProxy                      string        `long:"onion" description:"Connect to Tor onion peers via this SOCKS5 proxy (eg. 127.0.0.1:9050) -- Defaults to --proxy"`
+	TorControl                      string        `long:"torcontrol" description:"Address of the Tor control port, used by --listenonion (eg. 127.0.0.1:9051)"`
+	TorPassword                     string        `long:"torpassword" default-mask:"-" description:"Password for the Tor control port, if it uses password authentication"`
+	ListenOnion                     bool          `long:"listenonion" description:"Create an ephemeral Tor onion service for the P2P listener through --torcontrol and advertise its address to peers"`
+	DbType                          string        `long:"dbtype" description:"Database backend to use for the Block DAG {leveldb, bbolt} -- Defaults to the backend of the existing database, or leveldb for a new one"`
+	DbSync                          bool          `long:"dbsync" description:"Sync the database to disk at every consensus commit, so that a power loss can't corrupt the consensus state -- Slows down block processing on leveldb"`
+	ExportSnapshot                  string        `long:"export-snapshot" description:"Write the pruning point proof, headers, anticone, trusted data and UTXO set to the given snapshot file and exit"`
+	ImportSnapshot                  string        `long:"import-snapshot" description:"Bootstrap the node from a snapshot file written by --export-snapshot instead of downloading the pruning point UTXO set from peers"`
+	ExportBlocks                    string        `long:"export-blocks" description:"Write every block of the DAG in topological order to the given block archive file and exit. Requires an archival node (--archival)"`
+	ImportBlocks                    string        `long:"import-blocks" description:"Replay the blocks of a block archive file written by --export-blocks into the DAG before starting the node"`
+	DryRunMigrations                bool          `long:"dry-run-migrations" description:"List the database schema migrations that are pending for the existing database and exit without applying them"`
 	Profile                         string        `long:"profile" description:"Enable HTTP profiling on given port -- NOTE port must be between 1024 and 65536"`
 	LogLevel                        string        `short:"d" long:"loglevel" description:"Logging level for all subsystems {trace, debug, info, warn, error, critical} -- You may also specify <subsystem>=<level>,<subsystem2>=<level>,... to set the log level for individual subsystems -- Use show to list available subsystems"`
-	Upnp                            bool          `long:"upnp" description:"Use UPnP to map our listening port outside of NAT"`
+	NoP2PEncryption                 bool          `long:"nop2pencryption" description:"Disable the encryption of P2P connections with peers that support it"`
+	Upnp                            bool          `long:"upnp" description:"Use UPnP or NAT-PMP to map our listening port outside of NAT"`
 	MinRelayTxFee                   float64       `long:"minrelaytxfee" description:"The minimum transaction fee in HTN/kB to be considered a non-zero fee."`
 	MaxOrphanTxs                    uint64        `long:"maxorphantx" description:"Max number of orphan transactions to keep in memory"`
 	BlockMaxMass                    uint64        `long:"blockmaxmass" description:"Maximum transaction mass to be used when creating a block"`
@@ -117,10 +134,15 @@ type Flags struct {
 	NoPeerBloomFilters              bool          `long:"nopeerbloomfilters" description:"Disable bloom filtering support"`
 	SigCacheMaxSize                 uint          `long:"sigcachemaxsize" description:"The maximum number of entries in the signature verification cache"`
 	BlocksOnly                      bool          `long:"blocksonly" description:"Do not accept transactions from remote peers."`
+	TxRelayInboundInterval          time.Duration `long:"txrelayinboundinterval" description:"Average random delay before new transactions are announced to inbound peers -- 0 announces them right away"`
+	TxRelayOutboundInterval         time.Duration `long:"txrelayoutboundinterval" description:"Average random delay before new transactions are announced to outbound peers -- 0 announces them right away"`
+	TxStem                          bool          `long:"txstem" description:"Forward transactions submitted over RPC to a single outbound peer first, and announce them to all peers only after a random delay"`
 	RelayNonStd                     bool          `long:"relaynonstd" description:"Relay non-standard transactions regardless of the default settings for the active network."`
 	RejectNonStd                    bool          `long:"rejectnonstd" description:"Reject non-standard transactions regardless of the default settings for the active network."`
 	ResetDatabase                   bool          `long:"reset-db" description:"Reset database before starting node. It's needed when switching between subnetworks."`
 	MaxUTXOCacheSize                uint64        `long:"maxutxocachesize" description:"Max size of loaded UTXO into ram from the disk in bytes"`
+	CacheMem                        uint64        `long:"cachemem" description:"Memory in MiB for the caches of the consensus stores, split between the stores by weight -- 0 keeps the default cache sizes (default: set by --cachepreset)"`
+	CachePreset                     string        `long:"cachepreset" description:"Cache sizes for the available memory {default, vps, raspberrypi} -- vps suits 2-4GB of RAM and raspberrypi suits 1-2GB of RAM"`
 	UTXOIndex                       bool          `long:"utxoindex" description:"Enable the UTXO index"`
 	IsArchivalNode                  bool          `long:"archival" description:"Run as an archival node: don't delete old block data when moving the pruning point (Warning: heavy disk usage)'"`
 	AllowSubmitBlockWhenNotSynced   bool          `long:"allow-submit-block-when-not-synced" hidden:"true" description:"Allow the node to accept blocks from RPC while not synced (this flag is mainly used for testing)"`
@@ -135,12 +157,21 @@ type Flags struct {
 // See loadConfig for details on the configuration load process.
 type Config struct {
 	*Flags
-	Lookup        func(string) ([]net.IP, error)
-	Dial          func(string, string, time.Duration) (net.Conn, error)
+	Lookup       func(string) ([]net.IP, error)
+	Dial         func(string, string, time.Duration) (net.Conn, error)
+	CanDialOnion bool // true if Dial can connect to onion addresses
+	// PinnedPeerKeys maps the addresses of peers from --addpeer and --connect to the
+	// P2P identities they must have
+	PinnedPeerKeys map[string][]byte
+	// P2PStaticKey is the key that identifies this node in encrypted P2P connections.
+	// A random key is used if it's nil.
+	P2PStaticKey  *noise.StaticKey
 	MiningAddrs   []util.Address
 	MinRelayTxFee util.Amount
 	Whitelists    []*net.IPNet
 	SubnetworkID  *externalapi.DomainSubnetworkID // nil in full nodes
+	// DatabaseCacheMiB is the size of the database cache, set by --cachepreset
+	DatabaseCacheMiB int
 }
 
 // ServiceOptions defines the configuration options for the daemon as a service on
@@ -174,25 +205,28 @@ func newConfigParser(cfgFlags *Flags, options flags.Options) *flags.Parser {
 
 func defaultFlags() *Flags {
 	return &Flags{
-		ConfigFile:           defaultConfigFile,
-		LogLevel:             defaultLogLevel,
-		TargetOutboundPeers:  defaultTargetOutboundPeers,
-		MaxInboundPeers:      defaultMaxInboundPeers,
-		BanDuration:          defaultBanDuration,
-		BanThreshold:         defaultBanThreshold,
-		RPCMaxClients:        DefaultMaxRPCClients,
-		RPCMaxWebsockets:     defaultMaxRPCWebsockets,
-		RPCMaxConcurrentReqs: defaultMaxRPCConcurrentReqs,
-		AppDir:               defaultDataDir,
-		RPCKey:               defaultRPCKeyFile,
-		RPCCert:              defaultRPCCertFile,
-		BlockMaxMass:         defaultBlockMaxMass,
-		MaxOrphanTxs:         defaultMaxOrphanTransactions,
-		SigCacheMaxSize:      defaultSigCacheMaxSize,
-		MinRelayTxFee:        defaultMinRelayTxFee,
-		MaxUTXOCacheSize:     defaultMaxUTXOCacheSize,
-		ServiceOptions:       &ServiceOptions{},
-		ProtocolVersion:      defaultProtocolVersion,
+		ConfigFile:              defaultConfigFile,
+		LogLevel:                defaultLogLevel,
+		TargetOutboundPeers:     defaultTargetOutboundPeers,
+		MaxInboundPeers:         defaultMaxInboundPeers,
+		BanDuration:             defaultBanDuration,
+		BanThreshold:            defaultBanThreshold,
+		RPCMaxClients:           DefaultMaxRPCClients,
+		RPCMaxWebsockets:        defaultMaxRPCWebsockets,
+		RPCMaxConcurrentReqs:    defaultMaxRPCConcurrentReqs,
+		AppDir:                  defaultDataDir,
+		RPCKey:                  defaultRPCKeyFile,
+		RPCCert:                 defaultRPCCertFile,
+		BlockMaxMass:            defaultBlockMaxMass,
+		MaxOrphanTxs:            defaultMaxOrphanTransactions,
+		SigCacheMaxSize:         defaultSigCacheMaxSize,
+		MinRelayTxFee:           defaultMinRelayTxFee,
+		MaxUTXOCacheSize:        defaultMaxUTXOCacheSize,
+		CachePreset:             defaultCachePreset,
+		ServiceOptions:          &ServiceOptions{},
+		ProtocolVersion:         defaultProtocolVersion,
+		TxRelayInboundInterval:  defaultTxRelayInboundInterval,
+		TxRelayOutboundInterval: defaultTxRelayOutboundInterval,
 	}
 }
 
@@ -373,33 +407,76 @@ func LoadConfig() (*Config, error) {
 		return nil, err
 	}
 
+	if !backend.IsSupported(cfg.DbType) {
+		str := "%s: The dbtype option must be one of {%s} -- parsed [%s]"
+		err := errors.Errorf(str, funcName, strings.Join(backend.Types, ", "), cfg.DbType)
+		fmt.Fprintln(os.Stderr, err)
+		fmt.Fprintln(os.Stderr, usageMessage)
+		return nil, err
+	}
+
+	isExporting := cfg.ExportSnapshot != "" || cfg.ExportBlocks != ""
+	isImporting := cfg.ImportSnapshot != "" || cfg.ImportBlocks != ""
+	if isExporting && isImporting {
+		str := "%s: export-snapshot and export-blocks cannot be used together with import-snapshot or import-blocks"
+		err := errors.Errorf(str, funcName)
+		fmt.Fprintln(os.Stderr, err)
+		fmt.Fprintln(os.Stderr, usageMessage)
+		return nil, err
+	}
+	if cfg.ImportSnapshot != "" && cfg.ImportBlocks != "" {
+		str := "%s: import-snapshot and import-blocks cannot be used together -- choose only one"
+		err := errors.Errorf(str, funcName)
+		fmt.Fprintln(os.Stderr, err)
+		fmt.Fprintln(os.Stderr, usageMessage)
+		return nil, err
+	}
+	if cfg.ExportSnapshot != "" {
+		cfg.ExportSnapshot = cleanAndExpandPath(cfg.ExportSnapshot)
+	}
+	if cfg.ImportSnapshot != "" {
+		cfg.ImportSnapshot = cleanAndExpandPath(cfg.ImportSnapshot)
+	}
+	if cfg.ExportBlocks != "" {
+		cfg.ExportBlocks = cleanAndExpandPath(cfg.ExportBlocks)
+	}
+	if cfg.ImportBlocks != "" {
+		cfg.ImportBlocks = cleanAndExpandPath(cfg.ImportBlocks)
+	}
+
+	cachePreset, ok := CachePresets[cfg.CachePreset]
+	if !ok {
+		str := "%s: The cachepreset option must be one of default, vps or raspberrypi -- got %s"
+		err := errors.Errorf(str, funcName, cfg.CachePreset)
+		fmt.Fprintln(os.Stderr, err)
+		fmt.Fprintln(os.Stderr, usageMessage)
+		return nil, err
+	}
+	if cfg.CacheMem == 0 {
+		cfg.CacheMem = cachePreset.CacheMemMiB
+	}
+	cfg.DatabaseCacheMiB = cachePreset.DatabaseCacheMiB
+
+	if cfg.TxRelayInboundInterval < 0 || cfg.TxRelayOutboundInterval < 0 {
+		str := "%s: The txrelayinboundinterval and txrelayoutboundinterval options may not be negative"
+		err := errors.Errorf(str, funcName)
+		fmt.Fprintln(os.Stderr, err)
+		fmt.Fprintln(os.Stderr, usageMessage)
+		return nil, err
+	}
+
 	// Validate any given whitelisted IP addresses and networks.
-	if len(cfg.Whitelists) > 0 {
-		var ip net.IP
+	if len(cfg.Flags.Whitelists) > 0 {
 		cfg.Whitelists = make([]*net.IPNet, 0, len(cfg.Flags.Whitelists))
 
 		for _, addr := range cfg.Flags.Whitelists {
-			_, ipnet, err := net.ParseCIDR(addr)
+			ipnet, err := ParseWhitelist(addr)
 			if err != nil {
-				ip = net.ParseIP(addr)
-				if ip == nil {
-					str := "%s: The whitelist value of '%s' is invalid"
-					err = errors.Errorf(str, funcName, addr)
-					fmt.Fprintln(os.Stderr, err)
-					fmt.Fprintln(os.Stderr, usageMessage)
-					return nil, err
-				}
-				var bits int
-				if ip.To4() == nil {
-					// IPv6
-					bits = 128
-				} else {
-					bits = 32
-				}
-				ipnet = &net.IPNet{
-					IP:   ip,
-					Mask: net.CIDRMask(bits, bits),
-				}
+				str := "%s: The whitelist value of '%s' is invalid"
+				err = errors.Errorf(str, funcName, addr)
+				fmt.Fprintln(os.Stderr, err)
+				fmt.Fprintln(os.Stderr, usageMessage)
+				return nil, err
 			}
 			cfg.Whitelists = append(cfg.Whitelists, ipnet)
 		}
@@ -527,6 +604,28 @@ func LoadConfig() (*Config, error) {
 		return nil, err
 	}
 
+	// Take the pinned identities out of the peer addresses
+	cfg.PinnedPeerKeys = make(map[string][]byte)
+	cfg.AddPeers, err = extractPinnedPeerKeys(cfg.AddPeers, cfg.NetParams().DefaultPort, cfg.PinnedPeerKeys)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%s: %s\n", funcName, err)
+		fmt.Fprintln(os.Stderr, usageMessage)
+		return nil, err
+	}
+	cfg.ConnectPeers, err = extractPinnedPeerKeys(cfg.ConnectPeers, cfg.NetParams().DefaultPort, cfg.PinnedPeerKeys)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%s: %s\n", funcName, err)
+		fmt.Fprintln(os.Stderr, usageMessage)
+		return nil, err
+	}
+	if cfg.NoP2PEncryption && len(cfg.PinnedPeerKeys) > 0 {
+		str := "%s: peers with pinned identities can not be used with --nop2pencryption"
+		err := errors.Errorf(str, funcName)
+		fmt.Fprintln(os.Stderr, err)
+		fmt.Fprintln(os.Stderr, usageMessage)
+		return nil, err
+	}
+
 	// Add default port to all added peer addresses if needed and remove
 	// duplicate addresses.
 	cfg.AddPeers, err = network.NormalizeAddresses(cfg.AddPeers,
@@ -566,6 +665,52 @@ func LoadConfig() (*Config, error) {
 		cfg.Dial = proxy.DialTimeout
 	}
 
+	// Onion addresses can only be reached through a Tor SOCKS5 proxy, which is
+	// --onion if set, and --proxy otherwise
+	var onionDial func(string, string, time.Duration) (net.Conn, error)
+	if cfg.OnionProxy != "" {
+		_, _, err := net.SplitHostPort(cfg.OnionProxy)
+		if err != nil {
+			str := "%s: Onion proxy address '%s' is invalid: %s"
+			err := errors.Errorf(str, funcName, cfg.OnionProxy, err)
+			fmt.Fprintln(os.Stderr, err)
+			fmt.Fprintln(os.Stderr, usageMessage)
+			return nil, err
+		}
+		onionDial = (&socks.Proxy{Addr: cfg.OnionProxy}).DialTimeout
+	} else if cfg.Proxy != "" {
+		onionDial = cfg.Dial
+	}
+	dial := cfg.Dial
+	cfg.Dial = func(network string, address string, timeout time.Duration) (net.Conn, error) {
+		host, _, err := net.SplitHostPort(address)
+		if err == nil && strings.HasSuffix(host, ".onion") {
+			if onionDial == nil {
+				return nil, errors.Errorf("can not connect to %s without --onion or --proxy", address)
+			}
+			return onionDial(network, address, timeout)
+		}
+		return dial(network, address, timeout)
+	}
+	cfg.CanDialOnion = onionDial != nil
+
+	if cfg.ListenOnion {
+		if cfg.TorControl == "" {
+			str := "%s: --listenonion requires --torcontrol"
+			err := errors.Errorf(str, funcName)
+			fmt.Fprintln(os.Stderr, err)
+			fmt.Fprintln(os.Stderr, usageMessage)
+			return nil, err
+		}
+		if cfg.DisableListen {
+			str := "%s: --listenonion can not be used when listening is disabled"
+			err := errors.Errorf(str, funcName)
+			fmt.Fprintln(os.Stderr, err)
+			fmt.Fprintln(os.Stderr, usageMessage)
+			return nil, err
+		}
+	}
+
 	// Warn about missing config file only after all other configuration is
 	// done. This prevents the warning on help messages and invalid
 	// options. Note this should go directly before the return.
@@ -595,3 +740,45 @@ func createDefaultConfigFile(destinationPath string) error {
 
 	return err
 }
+
+// ParseWhitelist parses a whitelisted network, which is either in CIDR notation or
+// a single IP
+func ParseWhitelist(addr string) (*net.IPNet, error) {
+	_, ipnet, err := net.ParseCIDR(addr)
+	if err == nil {
+		return ipnet, nil
+	}
+	ip := net.ParseIP(addr)
+	if ip == nil {
+		return nil, errors.Errorf("'%s' is neither an IP nor a network in CIDR notation", addr)
+	}
+	if ip.To4() != nil {
+		return &net.IPNet{IP: ip.To4(), Mask: net.CIDRMask(32, 32)}, nil
+	}
+	return &net.IPNet{IP: ip, Mask: net.CIDRMask(128, 128)}, nil
+}
+
+// extractPinnedPeerKeys removes the <public key>@ prefixes from the given peer addresses.
+// The keys are added to pinnedKeys, under the normalized address of their peer.
+func extractPinnedPeerKeys(addresses []string, defaultPort string, pinnedKeys map[string][]byte) ([]string, error) {
+	peerAddresses := make([]string, len(addresses))
+	for i, address := range addresses {
+		separatorIndex := strings.LastIndex(address, "@")
+		if separatorIndex == -1 {
+			peerAddresses[i] = address
+			continue
+		}
+
+		publicKey, err := noise.ParsePublicKey(address[:separatorIndex])
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid pinned identity for peer %s", address)
+		}
+		peerAddress, err := network.NormalizeAddress(address[separatorIndex+1:], defaultPort)
+		if err != nil {
+			return nil, err
+		}
+		pinnedKeys[peerAddress] = publicKey
+		peerAddresses[i] = peerAddress
+	}
+	return peerAddresses, nil
+}
@@ -0,0 +1,117 @@
+package backend
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/Hoosat-Oy/HTND/infrastructure/db/database"
+	"github.com/Hoosat-Oy/HTND/infrastructure/db/database/boltdb"
+	"github.com/Hoosat-Oy/HTND/infrastructure/db/database/ldb"
+	"github.com/pkg/errors"
+)
+
+const (
+	// LevelDB is the leveldb database backend
+	LevelDB = "leveldb"
+
+	// Bolt is the bbolt database backend
+	Bolt = "bbolt"
+
+	// Default is the backend that is used for new databases when none is selected
+	Default = LevelDB
+)
+
+// Types are all the supported database backends
+var Types = []string{LevelDB, Bolt}
+
+// IsSupported returns whether the given database type is supported.
+// An empty type is supported, and means that the type is detected.
+func IsSupported(dbType string) bool {
+	if dbType == "" {
+		return true
+	}
+	for _, supportedType := range Types {
+		if dbType == supportedType {
+			return true
+		}
+	}
+	return false
+}
+
+// Detect returns the type of the database in the given directory,
+// or an empty string if the directory doesn't contain a database
+func Detect(path string) (string, error) {
+	_, err := os.Stat(filepath.Join(path, boltdb.FileName))
+	if err == nil {
+		return Bolt, nil
+	}
+	if !os.IsNotExist(err) {
+		return "", errors.WithStack(err)
+	}
+
+	// Every leveldb database has a CURRENT file that points to its manifest
+	_, err = os.Stat(filepath.Join(path, "CURRENT"))
+	if err == nil {
+		return LevelDB, nil
+	}
+	if !os.IsNotExist(err) {
+		return "", errors.WithStack(err)
+	}
+	return "", nil
+}
+
+// Open opens the database of the given type in the given directory. If dbType is empty,
+// the type of the existing database is used, or Default if there is none. It's an error
+// to open an existing database as a different type than the one it was created with.
+// If syncCommits is set, every committed transaction is synced to disk before the commit
+// returns. bbolt always syncs its commits, so syncCommits only affects leveldb.
+func Open(dbType string, path string, cacheSizeMiB int, syncCommits bool) (database.Database, error) {
+	if !IsSupported(dbType) {
+		return nil, errors.Errorf("unsupported database type '%s' -- supported types are: %s",
+			dbType, strings.Join(Types, ", "))
+	}
+
+	existingType, err := Detect(path)
+	if err != nil {
+		return nil, err
+	}
+	if dbType == "" {
+		dbType = existingType
+		if dbType == "" {
+			dbType = Default
+		}
+	}
+	if existingType != "" && existingType != dbType {
+		return nil, errors.Errorf("the database in %s is a %s database and can't be opened as %s -- "+
+			"use --dbtype=%s or remove the database with --reset-db", path, existingType, dbType, existingType)
+	}
+
+	switch dbType {
+	case Bolt:
+		return boltdb.NewBoltDB(path)
+	default:
+		if syncCommits {
+			return ldb.NewDurableLevelDB(path, cacheSizeMiB)
+		}
+		return ldb.NewLevelDB(path, cacheSizeMiB)
+	}
+}
+
+// OpenReadOnly opens the existing database in the given directory for reading only,
+// whatever its type is. It's meant for inspecting the database of a node that isn't running.
+func OpenReadOnly(path string, cacheSizeMiB int) (database.Database, error) {
+	dbType, err := Detect(path)
+	if err != nil {
+		return nil, err
+	}
+
+	switch dbType {
+	case Bolt:
+		return boltdb.NewReadOnlyBoltDB(path)
+	case LevelDB:
+		return ldb.NewReadOnlyLevelDB(path, cacheSizeMiB)
+	default:
+		return nil, errors.Errorf("there is no database in %s", path)
+	}
+}
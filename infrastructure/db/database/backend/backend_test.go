@@ -0,0 +1,126 @@
+package backend
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/Hoosat-Oy/HTND/infrastructure/db/database"
+	"github.com/Hoosat-Oy/HTND/infrastructure/db/database/boltdb"
+	"github.com/Hoosat-Oy/HTND/infrastructure/db/database/ldb"
+)
+
+func TestOpenDetectsExistingBackend(t *testing.T) {
+	for _, dbType := range Types {
+		path := t.TempDir()
+
+		detectedType, err := Detect(path)
+		if err != nil {
+			t.Fatalf("Detect: %s", err)
+		}
+		if detectedType != "" {
+			t.Fatalf("detected a %s database in an empty directory", detectedType)
+		}
+
+		db, err := Open(dbType, path, 8, false)
+		if err != nil {
+			t.Fatalf("Open %s: %s", dbType, err)
+		}
+		err = db.Close()
+		if err != nil {
+			t.Fatalf("Close: %s", err)
+		}
+
+		detectedType, err = Detect(path)
+		if err != nil {
+			t.Fatalf("Detect: %s", err)
+		}
+		if detectedType != dbType {
+			t.Fatalf("expected to detect a %s database, got '%s'", dbType, detectedType)
+		}
+
+		// Opening without a type should open the existing backend
+		db, err = Open("", path, 8, false)
+		if err != nil {
+			t.Fatalf("Open: %s", err)
+		}
+		switch dbType {
+		case LevelDB:
+			_, ok := db.(*ldb.LevelDB)
+			if !ok {
+				t.Fatalf("expected a leveldb database, got %T", db)
+			}
+		case Bolt:
+			_, ok := db.(*boltdb.BoltDB)
+			if !ok {
+				t.Fatalf("expected a bbolt database, got %T", db)
+			}
+		}
+		err = db.Close()
+		if err != nil {
+			t.Fatalf("Close: %s", err)
+		}
+
+		// Opening as any other type should fail
+		for _, otherType := range Types {
+			if otherType == dbType {
+				continue
+			}
+			_, err = Open(otherType, path, 8, false)
+			if err == nil || !strings.Contains(err.Error(), "can't be opened as") {
+				t.Fatalf("expected an error when opening a %s database as %s, got %v", dbType, otherType, err)
+			}
+		}
+	}
+}
+
+func TestOpenUnsupportedType(t *testing.T) {
+	_, err := Open("sqlite", t.TempDir(), 8, false)
+	if err == nil || !strings.Contains(err.Error(), "unsupported database type") {
+		t.Fatalf("expected an unsupported database type error, got %v", err)
+	}
+}
+
+func TestOpenReadOnly(t *testing.T) {
+	key := database.MakeBucket(nil).Key([]byte("key"))
+	for _, dbType := range Types {
+		path := t.TempDir()
+
+		_, err := OpenReadOnly(path, 8)
+		if err == nil {
+			t.Fatalf("expected an error when opening an empty directory as read-only")
+		}
+
+		db, err := Open(dbType, path, 8, false)
+		if err != nil {
+			t.Fatalf("Open %s: %s", dbType, err)
+		}
+		err = db.Put(key, []byte("value"))
+		if err != nil {
+			t.Fatalf("Put: %s", err)
+		}
+		err = db.Close()
+		if err != nil {
+			t.Fatalf("Close: %s", err)
+		}
+
+		db, err = OpenReadOnly(path, 8)
+		if err != nil {
+			t.Fatalf("OpenReadOnly %s: %s", dbType, err)
+		}
+		value, err := db.Get(key)
+		if err != nil {
+			t.Fatalf("Get: %s", err)
+		}
+		if string(value) != "value" {
+			t.Fatalf("expected 'value', got '%s'", value)
+		}
+		err = db.Put(key, []byte("other value"))
+		if err == nil {
+			t.Fatalf("expected writing into a read-only %s database to fail", dbType)
+		}
+		err = db.Close()
+		if err != nil {
+			t.Fatalf("Close: %s", err)
+		}
+	}
+}
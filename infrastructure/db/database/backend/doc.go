@@ -0,0 +1,13 @@
+/*
+Package backend opens the database backend that is selected with --dbtype.
+
+Every backend implements the interfaces defined in the database package.
+Supported backends are:
+
+  - leveldb: the default, implemented by the ldb package
+  - bbolt: a single file B+tree, implemented by the boltdb package
+
+A database directory holds a single backend, which is detected when the
+database is opened, so --dbtype only matters when creating a new database.
+*/
+package backend
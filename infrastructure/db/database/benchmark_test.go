@@ -0,0 +1,122 @@
+package database_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/Hoosat-Oy/HTND/infrastructure/db/database"
+)
+
+// benchmarkForAllDatabaseTypes runs the given benchmark for every
+// database type defined in databasePrepareFuncs, so that the backends
+// can be compared with each other.
+func benchmarkForAllDatabaseTypes(b *testing.B, benchmarkName string,
+	benchmarkFunc func(b *testing.B, db database.Database)) {
+
+	for _, prepareDatabase := range databasePrepareFuncs {
+		func() {
+			db, dbType, teardownFunc := prepareDatabase(b, benchmarkName)
+			defer teardownFunc()
+
+			b.Run(dbType, func(b *testing.B) {
+				benchmarkFunc(b, db)
+			})
+		}()
+	}
+}
+
+// BenchmarkTransactionCommit measures committing transactions of a size
+// that is typical for adding a block
+func BenchmarkTransactionCommit(b *testing.B) {
+	benchmarkForAllDatabaseTypes(b, "BenchmarkTransactionCommit", func(b *testing.B, db database.Database) {
+		const putsPerTransaction = 1000
+		bucket := database.MakeBucket([]byte("bucket"))
+		value := make([]byte, 100)
+
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			dbTx, err := db.Begin()
+			if err != nil {
+				b.Fatalf("Begin: %s", err)
+			}
+			for j := 0; j < putsPerTransaction; j++ {
+				err := dbTx.Put(bucket.Key([]byte(fmt.Sprintf("key%d-%d", i, j))), value)
+				if err != nil {
+					b.Fatalf("Put: %s", err)
+				}
+			}
+			err = dbTx.Commit()
+			if err != nil {
+				b.Fatalf("Commit: %s", err)
+			}
+		}
+	})
+}
+
+// BenchmarkGet measures random reads
+func BenchmarkGet(b *testing.B) {
+	benchmarkForAllDatabaseTypes(b, "BenchmarkGet", func(b *testing.B, db database.Database) {
+		const entryCount = 10_000
+		keys := populateBucketForBenchmark(b, db, entryCount)
+
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			_, err := db.Get(keys[i%entryCount])
+			if err != nil {
+				b.Fatalf("Get: %s", err)
+			}
+		}
+	})
+}
+
+// BenchmarkCursor measures iterating over a whole bucket, which is
+// what loading the UTXO set and pruning mostly do
+func BenchmarkCursor(b *testing.B) {
+	benchmarkForAllDatabaseTypes(b, "BenchmarkCursor", func(b *testing.B, db database.Database) {
+		const entryCount = 10_000
+		populateBucketForBenchmark(b, db, entryCount)
+
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			cursor, err := db.Cursor(database.MakeBucket([]byte("bucket")))
+			if err != nil {
+				b.Fatalf("Cursor: %s", err)
+			}
+			count := 0
+			for ok := cursor.First(); ok; ok = cursor.Next() {
+				_, err := cursor.Value()
+				if err != nil {
+					b.Fatalf("Value: %s", err)
+				}
+				count++
+			}
+			if count != entryCount {
+				b.Fatalf("expected %d entries, got %d", entryCount, count)
+			}
+			cursor.Close()
+		}
+	})
+}
+
+func populateBucketForBenchmark(b *testing.B, db database.Database, entryCount int) []*database.Key {
+	bucket := database.MakeBucket([]byte("bucket"))
+	value := make([]byte, 100)
+	keys := make([]*database.Key, entryCount)
+
+	dbTx, err := db.Begin()
+	if err != nil {
+		b.Fatalf("Begin: %s", err)
+	}
+	for i := range keys {
+		keys[i] = bucket.Key([]byte(fmt.Sprintf("key%d", i)))
+		err := dbTx.Put(keys[i], value)
+		if err != nil {
+			b.Fatalf("Put: %s", err)
+		}
+	}
+	err = dbTx.Commit()
+	if err != nil {
+		b.Fatalf("Commit: %s", err)
+	}
+	return keys
+}
@@ -0,0 +1,233 @@
+package boltdb
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/Hoosat-Oy/HTND/infrastructure/db/database"
+	"github.com/pkg/errors"
+	"go.etcd.io/bbolt"
+)
+
+// FileName is the name of the bbolt file inside the database directory
+const FileName = "htnd.bolt"
+
+// compactTxMaxSize is the amount of bytes that are copied in a single transaction while compacting
+const compactTxMaxSize = 64 * 1024 * 1024
+
+// dataBucketName is the name of the bbolt bucket that holds all the data.
+// Database buckets are key prefixes, exactly like in leveldb, so they
+// don't map to bbolt buckets.
+var dataBucketName = []byte("data")
+
+// BoltDB defines a thin wrapper around bbolt.
+type BoltDB struct {
+	path string
+
+	// lock is only held for writing while the database is being compacted,
+	// since compacting replaces the underlying bbolt instance
+	lock sync.RWMutex
+	bolt *bbolt.DB
+}
+
+// NewBoltDB opens a bbolt instance inside the directory defined by the given path.
+// bbolt relies on the OS page cache rather than a cache of its own, so unlike
+// leveldb it doesn't take a cache size.
+func NewBoltDB(path string) (*BoltDB, error) {
+	err := os.MkdirAll(path, 0700)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	filePath := filepath.Join(path, FileName)
+	bolt, err := openBolt(filePath, false)
+	if err != nil {
+		return nil, err
+	}
+
+	db := &BoltDB{
+		path: filePath,
+		bolt: bolt,
+	}
+	return db, nil
+}
+
+// NewReadOnlyBoltDB opens the existing bbolt instance inside the directory defined by
+// the given path for reading only. Any attempt to write into it returns an error.
+func NewReadOnlyBoltDB(path string) (*BoltDB, error) {
+	filePath := filepath.Join(path, FileName)
+	bolt, err := openBolt(filePath, true)
+	if err != nil {
+		return nil, err
+	}
+
+	db := &BoltDB{
+		path: filePath,
+		bolt: bolt,
+	}
+	return db, nil
+}
+
+func openBolt(filePath string, readOnly bool) (*bbolt.DB, error) {
+	bolt, err := bbolt.Open(filePath, 0600, &bbolt.Options{
+		Timeout:        time.Second,
+		NoFreelistSync: true,
+		FreelistType:   bbolt.FreelistMapType,
+		ReadOnly:       readOnly,
+	})
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed opening %s", filePath)
+	}
+	if readOnly {
+		return bolt, nil
+	}
+
+	err = bolt.Update(func(tx *bbolt.Tx) error {
+		_, err := tx.CreateBucketIfNotExists(dataBucketName)
+		return err
+	})
+	if err != nil {
+		bolt.Close()
+		return nil, errors.WithStack(err)
+	}
+	return bolt, nil
+}
+
+// Compact compacts the bbolt instance by copying it into a new file and replacing the old
+// file with it. bbolt never shrinks its file on its own, so this is the only way to give
+// back the space that deleted data took.
+func (db *BoltDB) Compact() error {
+	db.lock.Lock()
+	defer db.lock.Unlock()
+
+	compactedPath := db.path + ".compact"
+	err := os.RemoveAll(compactedPath)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	compacted, err := bbolt.Open(compactedPath, 0600, &bbolt.Options{Timeout: time.Second})
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	err = bbolt.Compact(compacted, db.bolt, compactTxMaxSize)
+	if err != nil {
+		compacted.Close()
+		os.Remove(compactedPath)
+		return errors.WithStack(err)
+	}
+	err = compacted.Close()
+	if err != nil {
+		os.Remove(compactedPath)
+		return errors.WithStack(err)
+	}
+
+	err = db.bolt.Close()
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	err = os.Rename(compactedPath, db.path)
+	if err != nil {
+		// Keep using the database as it was before compaction
+		bolt, openErr := openBolt(db.path, false)
+		if openErr != nil {
+			return errors.Wrapf(err, "failed reopening the database after a failed compaction: %s", openErr)
+		}
+		db.bolt = bolt
+		return errors.WithStack(err)
+	}
+
+	db.bolt, err = openBolt(db.path, false)
+	return err
+}
+
+// Close closes the bbolt instance.
+func (db *BoltDB) Close() error {
+	db.lock.Lock()
+	defer db.lock.Unlock()
+
+	err := db.bolt.Close()
+	return errors.WithStack(err)
+}
+
+// update runs the given function within a read-write bbolt transaction over the data bucket
+func (db *BoltDB) update(function func(bucket *bbolt.Bucket) error) error {
+	db.lock.RLock()
+	defer db.lock.RUnlock()
+
+	err := db.bolt.Update(func(tx *bbolt.Tx) error {
+		return function(tx.Bucket(dataBucketName))
+	})
+	return errors.WithStack(err)
+}
+
+// view runs the given function within a read-only bbolt transaction over the data bucket
+func (db *BoltDB) view(function func(bucket *bbolt.Bucket) error) error {
+	db.lock.RLock()
+	defer db.lock.RUnlock()
+
+	err := db.bolt.View(func(tx *bbolt.Tx) error {
+		return function(tx.Bucket(dataBucketName))
+	})
+	return errors.WithStack(err)
+}
+
+// Put sets the value for the given key. It overwrites
+// any previous value for that key.
+func (db *BoltDB) Put(key *database.Key, value []byte) error {
+	return db.update(func(bucket *bbolt.Bucket) error {
+		return bucket.Put(key.Bytes(), value)
+	})
+}
+
+// Get gets the value for the given key. It returns
+// ErrNotFound if the given key does not exist.
+func (db *BoltDB) Get(key *database.Key) ([]byte, error) {
+	var data []byte
+	found := false
+	err := db.view(func(bucket *bbolt.Bucket) error {
+		keyBytes := key.Bytes()
+		foundKey, value := bucket.Cursor().Seek(keyBytes)
+		if foundKey == nil || !bytes.Equal(foundKey, keyBytes) {
+			return nil
+		}
+		found = true
+		// Values returned by bbolt are only valid during the transaction
+		data = make([]byte, len(value))
+		copy(data, value)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if !found {
+		return nil, errors.Wrapf(database.ErrNotFound, "key %s not found", key)
+	}
+	return data, nil
+}
+
+// Has returns true if the database does contains the
+// given key.
+func (db *BoltDB) Has(key *database.Key) (bool, error) {
+	exists := false
+	err := db.view(func(bucket *bbolt.Bucket) error {
+		keyBytes := key.Bytes()
+		foundKey, _ := bucket.Cursor().Seek(keyBytes)
+		exists = foundKey != nil && bytes.Equal(foundKey, keyBytes)
+		return nil
+	})
+	if err != nil {
+		return false, err
+	}
+	return exists, nil
+}
+
+// Delete deletes the value for the given key. Will not
+// return an error if the key doesn't exist.
+func (db *BoltDB) Delete(key *database.Key) error {
+	return db.update(func(bucket *bbolt.Bucket) error {
+		return bucket.Delete(key.Bytes())
+	})
+}
@@ -0,0 +1,210 @@
+package boltdb
+
+import (
+	"bytes"
+
+	"github.com/Hoosat-Oy/HTND/infrastructure/db/database"
+	"github.com/pkg/errors"
+	"go.etcd.io/bbolt"
+)
+
+const (
+	// cursorChunkMaxEntries and cursorChunkMaxBytes bound the amount of
+	// entries that a cursor reads ahead within a single bbolt transaction
+	cursorChunkMaxEntries = 1000
+	cursorChunkMaxBytes   = 4 * 1024 * 1024
+)
+
+type cursorEntry struct {
+	key   []byte
+	value []byte
+}
+
+// BoltDBCursor iterates over the entries of a bucket in chunks.
+//
+// A bbolt read transaction that stays open while the same goroutine writes can
+// deadlock bbolt when it needs to grow its memory map, so unlike a leveldb iterator,
+// the cursor doesn't hold a transaction open. Instead, it reads a chunk of entries at
+// a time, each within its own short transaction. This means that the cursor is not a
+// snapshot: entries that are written after the current chunk was read may be seen.
+type BoltDBCursor struct {
+	db     *BoltDB
+	bucket *database.Bucket
+
+	entries     []cursorEntry
+	index       int
+	isLastChunk bool
+	isStarted   bool
+	err         error
+
+	isClosed bool
+}
+
+// Cursor begins a new cursor over the given prefix.
+func (db *BoltDB) Cursor(bucket *database.Bucket) (database.Cursor, error) {
+	return &BoltDBCursor{
+		db:       db,
+		bucket:   bucket,
+		isClosed: false,
+	}, nil
+}
+
+// loadChunk reads the next chunk of entries, starting at the first key that is greater
+// than or equal to from. If skipFrom is set, an entry whose key equals from is skipped.
+func (c *BoltDBCursor) loadChunk(from []byte, skipFrom bool) {
+	c.isStarted = true
+	c.entries = c.entries[:0]
+	c.index = 0
+	prefix := c.bucket.Path()
+
+	c.err = c.db.view(func(bucket *bbolt.Bucket) error {
+		boltCursor := bucket.Cursor()
+		key, value := boltCursor.Seek(from)
+		if skipFrom && key != nil && bytes.Equal(key, from) {
+			key, value = boltCursor.Next()
+		}
+
+		chunkBytes := 0
+		for key != nil && bytes.HasPrefix(key, prefix) &&
+			len(c.entries) < cursorChunkMaxEntries && chunkBytes < cursorChunkMaxBytes {
+
+			// Keys and values returned by bbolt are only valid during the transaction
+			entry := cursorEntry{
+				key:   make([]byte, len(key)),
+				value: make([]byte, len(value)),
+			}
+			copy(entry.key, key)
+			copy(entry.value, value)
+			c.entries = append(c.entries, entry)
+			chunkBytes += len(key) + len(value)
+
+			key, value = boltCursor.Next()
+		}
+		c.isLastChunk = key == nil || !bytes.HasPrefix(key, prefix)
+		return nil
+	})
+	if c.err != nil {
+		c.entries = c.entries[:0]
+		c.isLastChunk = true
+	}
+}
+
+// Next moves the iterator to the next key/value pair. It returns whether the
+// iterator is exhausted. Panics if the cursor is closed.
+func (c *BoltDBCursor) Next() bool {
+	if c.isClosed {
+		panic("cannot call next on a closed cursor")
+	}
+	if !c.isStarted {
+		return c.First()
+	}
+	if c.index+1 < len(c.entries) {
+		c.index++
+		return true
+	}
+	if c.isLastChunk || len(c.entries) == 0 {
+		c.index = len(c.entries)
+		return false
+	}
+	c.loadChunk(c.entries[len(c.entries)-1].key, true)
+	return len(c.entries) > 0
+}
+
+// First moves the iterator to the first key/value pair. It returns false if
+// such a pair does not exist. Panics if the cursor is closed.
+func (c *BoltDBCursor) First() bool {
+	if c.isClosed {
+		panic("cannot call first on a closed cursor")
+	}
+	c.loadChunk(c.bucket.Path(), false)
+	return len(c.entries) > 0
+}
+
+// Seek moves the iterator to the first key/value pair whose key is greater
+// than or equal to the given key. It returns ErrNotFound if such pair does not
+// exist.
+func (c *BoltDBCursor) Seek(key *database.Key) error {
+	if c.isClosed {
+		return errors.New("cannot seek a closed cursor")
+	}
+
+	keyBytes := key.Bytes()
+	from := keyBytes
+	if bytes.Compare(from, c.bucket.Path()) < 0 {
+		from = c.bucket.Path()
+	}
+	c.loadChunk(from, false)
+	if c.err != nil {
+		return c.err
+	}
+	if len(c.entries) == 0 || !bytes.Equal(c.entries[0].key, keyBytes) {
+		return errors.Wrapf(database.ErrNotFound, "key %s not found", key)
+	}
+	return nil
+}
+
+// SeekGreaterOrEqual moves the iterator to the first key/value pair whose key
+// is greater than or equal to the given key. Unlike Seek, the given key doesn't
+// have to exist. It returns false if such pair does not exist.
+func (c *BoltDBCursor) SeekGreaterOrEqual(key *database.Key) (bool, error) {
+	if c.isClosed {
+		return false, errors.New("cannot seek a closed cursor")
+	}
+
+	from := key.Bytes()
+	if bytes.Compare(from, c.bucket.Path()) < 0 {
+		from = c.bucket.Path()
+	}
+	c.loadChunk(from, false)
+	if c.err != nil {
+		return false, c.err
+	}
+	return len(c.entries) > 0, nil
+}
+
+// Key returns the key of the current key/value pair, or ErrNotFound if done.
+// Note that the key is trimmed to not include the prefix the cursor was opened
+// with. The caller should not modify the contents of the returned slice, and
+// its contents may change on the next call to Next.
+func (c *BoltDBCursor) Key() (*database.Key, error) {
+	if c.isClosed {
+		return nil, errors.New("cannot get the key of a closed cursor")
+	}
+	if c.err != nil {
+		return nil, c.err
+	}
+	if c.index >= len(c.entries) {
+		return nil, errors.Wrapf(database.ErrNotFound, "cannot get the "+
+			"key of an exhausted cursor")
+	}
+	suffix := bytes.TrimPrefix(c.entries[c.index].key, c.bucket.Path())
+	return c.bucket.Key(suffix), nil
+}
+
+// Value returns the value of the current key/value pair, or ErrNotFound if done.
+// The caller should not modify the contents of the returned slice, and its
+// contents may change on the next call to Next.
+func (c *BoltDBCursor) Value() ([]byte, error) {
+	if c.isClosed {
+		return nil, errors.New("cannot get the value of a closed cursor")
+	}
+	if c.err != nil {
+		return nil, c.err
+	}
+	if c.index >= len(c.entries) {
+		return nil, errors.Wrapf(database.ErrNotFound, "cannot get the "+
+			"value of an exhausted cursor")
+	}
+	return c.entries[c.index].value, nil
+}
+
+// Close releases associated resources.
+func (c *BoltDBCursor) Close() error {
+	if c.isClosed {
+		return errors.New("cannot close an already closed cursor")
+	}
+	c.isClosed = true
+	c.entries = nil
+	c.bucket = nil
+	return nil
+}
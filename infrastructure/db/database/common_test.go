@@ -6,19 +6,22 @@ import (
 	"testing"
 
 	"github.com/Hoosat-Oy/HTND/infrastructure/db/database"
+	"github.com/Hoosat-Oy/HTND/infrastructure/db/database/boltdb"
 	"github.com/Hoosat-Oy/HTND/infrastructure/db/database/ldb"
 )
 
-type databasePrepareFunc func(t *testing.T, testName string) (db database.Database, name string, teardownFunc func())
+type databasePrepareFunc func(t testing.TB, testName string) (db database.Database, name string, teardownFunc func())
 
 // databasePrepareFuncs is a set of functions, in which each function
 // prepares a separate database type for testing.
 // See testForAllDatabaseTypes for further details.
 var databasePrepareFuncs = []databasePrepareFunc{
 	prepareLDBForTest,
+	prepareDurableLDBForTest,
+	prepareBoltForTest,
 }
 
-func prepareLDBForTest(t *testing.T, testName string) (db database.Database, name string, teardownFunc func()) {
+func prepareLDBForTest(t testing.TB, testName string) (db database.Database, name string, teardownFunc func()) {
 	// Create a temp db to run tests against
 	path, err := ioutil.TempDir("", testName)
 	if err != nil {
@@ -40,6 +43,50 @@ func prepareLDBForTest(t *testing.T, testName string) (db database.Database, nam
 	return db, "ldb", teardownFunc
 }
 
+func prepareDurableLDBForTest(t testing.TB, testName string) (db database.Database, name string, teardownFunc func()) {
+	// Create a temp db to run tests against
+	path, err := ioutil.TempDir("", testName)
+	if err != nil {
+		t.Fatalf("%s: TempDir unexpectedly "+
+			"failed: %s", testName, err)
+	}
+	db, err = ldb.NewDurableLevelDB(path, 8)
+	if err != nil {
+		t.Fatalf("%s: Open unexpectedly "+
+			"failed: %s", testName, err)
+	}
+	teardownFunc = func() {
+		err = db.Close()
+		if err != nil {
+			t.Fatalf("%s: Close unexpectedly "+
+				"failed: %s", testName, err)
+		}
+	}
+	return db, "ldb-durable", teardownFunc
+}
+
+func prepareBoltForTest(t testing.TB, testName string) (db database.Database, name string, teardownFunc func()) {
+	// Create a temp db to run tests against
+	path, err := ioutil.TempDir("", testName)
+	if err != nil {
+		t.Fatalf("%s: TempDir unexpectedly "+
+			"failed: %s", testName, err)
+	}
+	db, err = boltdb.NewBoltDB(path)
+	if err != nil {
+		t.Fatalf("%s: Open unexpectedly "+
+			"failed: %s", testName, err)
+	}
+	teardownFunc = func() {
+		err = db.Close()
+		if err != nil {
+			t.Fatalf("%s: Close unexpectedly "+
+				"failed: %s", testName, err)
+		}
+	}
+	return db, "bbolt", teardownFunc
+}
+
 // testForAllDatabaseTypes runs the given testFunc for every database
 // type defined in databasePrepareFuncs. This is to make sure that
 // all supported database types adhere to the assumptions defined in
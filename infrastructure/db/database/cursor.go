@@ -15,6 +15,11 @@ type Cursor interface {
 	// exist.
 	Seek(key *Key) error
 
+	// SeekGreaterOrEqual moves the iterator to the first key/value pair whose key
+	// is greater than or equal to the given key. Unlike Seek, the given key doesn't
+	// have to exist. It returns false if such pair does not exist.
+	SeekGreaterOrEqual(key *Key) (bool, error)
+
 	// Key returns the key of the current key/value pair, or ErrNotFound if done.
 	// The caller should not modify the contents of the returned key, and
 	// its contents may change on the next call to Next.
@@ -253,6 +253,64 @@ func testCursorSeek(t *testing.T, db database.Database, testName string) {
 	}
 }
 
+func TestCursorSeekGreaterOrEqual(t *testing.T) {
+	testForAllDatabaseTypes(t, "TestCursorSeekGreaterOrEqual", testCursorSeekGreaterOrEqual)
+}
+
+func testCursorSeekGreaterOrEqual(t *testing.T, db database.Database, testName string) {
+	entries := populateDatabaseForTest(t, db, testName)
+
+	tests := []struct {
+		name          string
+		key           *database.Key
+		expectedIndex int
+	}{
+		{name: "existing key", key: entries[3].key, expectedIndex: 3},
+		{name: "missing key", key: database.MakeBucket(nil).Key([]byte("key35")), expectedIndex: 4},
+		{name: "key before all entries", key: database.MakeBucket(nil).Key([]byte("a")), expectedIndex: 0},
+		{name: "key after all entries", key: database.MakeBucket(nil).Key([]byte("key99")), expectedIndex: -1},
+	}
+	for _, test := range tests {
+		cursor := prepareCursorForTest(t, db, testName)
+		found, err := cursor.SeekGreaterOrEqual(test.key)
+		if err != nil {
+			t.Fatalf("%s: %s: SeekGreaterOrEqual unexpectedly "+
+				"failed: %s", testName, test.name, err)
+		}
+		if test.expectedIndex == -1 {
+			if found {
+				t.Fatalf("%s: %s: SeekGreaterOrEqual unexpectedly "+
+					"found a key", testName, test.name)
+			}
+			cursor.Close()
+			continue
+		}
+		if !found {
+			t.Fatalf("%s: %s: SeekGreaterOrEqual unexpectedly "+
+				"found no key", testName, test.name)
+		}
+
+		// Make sure that the cursor is at the expected entry, and that Next
+		// moves to the one after it
+		for i := test.expectedIndex; i < test.expectedIndex+2; i++ {
+			if i > test.expectedIndex && !cursor.Next() {
+				t.Fatalf("%s: %s: cursor unexpectedly "+
+					"done", testName, test.name)
+			}
+			cursorKey, err := cursor.Key()
+			if err != nil {
+				t.Fatalf("%s: %s: Key unexpectedly "+
+					"failed: %s", testName, test.name, err)
+			}
+			if !reflect.DeepEqual(cursorKey, entries[i].key) {
+				t.Fatalf("%s: %s: Cursor returned "+
+					"wrong key. Want: %s, got: %s", testName, test.name, entries[i].key, cursorKey)
+			}
+		}
+		cursor.Close()
+	}
+}
+
 func TestCursorCloseErrors(t *testing.T) {
 	testForAllDatabaseTypes(t, "TestCursorCloseErrors", testCursorCloseErrors)
 }
@@ -278,6 +336,13 @@ func testCursorCloseErrors(t *testing.T, db database.Database, testName string)
 				return cursor.Seek(database.MakeBucket(nil).Key([]byte{}))
 			},
 		},
+		{
+			name: "SeekGreaterOrEqual",
+			function: func() error {
+				_, err := cursor.SeekGreaterOrEqual(database.MakeBucket(nil).Key([]byte{}))
+				return err
+			},
+		},
 		{
 			name: "Key",
 			function: func() error {
@@ -344,3 +409,193 @@ func testCursorCloseFirstAndNext(t *testing.T, db database.Database, testName st
 		cursor.Next()
 	}()
 }
+
+func validateCurrentCursorKeyAndValue(t *testing.T, testName string, cursor database.Cursor,
+	expectedKey *database.Key, expectedValue []byte) {
+
+	cursorKey, err := cursor.Key()
+	if err != nil {
+		t.Fatalf("%s: Key "+
+			"unexpectedly failed: %s", testName, err)
+	}
+	if !reflect.DeepEqual(cursorKey, expectedKey) {
+		t.Fatalf("%s: Key "+
+			"returned wrong key. Want: %s, got: %s",
+			testName, string(expectedKey.Bytes()), string(cursorKey.Bytes()))
+	}
+	cursorValue, err := cursor.Value()
+	if err != nil {
+		t.Fatalf("%s: Value "+
+			"unexpectedly failed for key %s: %s",
+			testName, cursorKey, err)
+	}
+	if !bytes.Equal(cursorValue, expectedValue) {
+		t.Fatalf("%s: Value "+
+			"returned wrong value for key %s. Want: %s, got: %s",
+			testName, cursorKey, string(expectedValue), string(cursorValue))
+	}
+}
+
+// TestCursorSanity validates typical cursor usage, including
+// opening a cursor over some existing data, seeking back
+// and forth over that data, and getting some keys/values out
+// of the cursor.
+func TestCursorSanity(t *testing.T) {
+	testForAllDatabaseTypes(t, "TestCursorSanity", testCursorSanity)
+}
+
+func testCursorSanity(t *testing.T, db database.Database, testName string) {
+	// Write some data to the database
+	bucket := database.MakeBucket([]byte("bucket"))
+	for i := 0; i < 10; i++ {
+		key := fmt.Sprintf("key%d", i)
+		value := fmt.Sprintf("value%d", i)
+		err := db.Put(bucket.Key([]byte(key)), []byte(value))
+		if err != nil {
+			t.Fatalf("%s: Put "+
+				"unexpectedly failed: %s", testName, err)
+		}
+	}
+
+	// Open a new cursor
+	cursor, err := db.Cursor(bucket)
+	if err != nil {
+		t.Fatalf("%s: Cursor "+
+			"unexpectedly failed: %s", testName, err)
+	}
+	defer func() {
+		err := cursor.Close()
+		if err != nil {
+			t.Fatalf("%s: Close "+
+				"unexpectedly failed: %s", testName, err)
+		}
+	}()
+
+	// Seek to first key and make sure its key and value are correct
+	hasNext := cursor.First()
+	if !hasNext {
+		t.Fatalf("%s: First "+
+			"unexpectedly returned non-existance", testName)
+	}
+	expectedKey := bucket.Key([]byte("key0"))
+	expectedValue := []byte("value0")
+	validateCurrentCursorKeyAndValue(t, testName, cursor, expectedKey, expectedValue)
+
+	// Seek to a non-existant key
+	err = cursor.Seek(database.MakeBucket(nil).Key([]byte("doesn't exist")))
+	if err == nil {
+		t.Fatalf("%s: Seek "+
+			"unexpectedly succeeded", testName)
+	}
+	if !database.IsNotFoundError(err) {
+		t.Fatalf("%s: Seek "+
+			"returned wrong error: %s", testName, err)
+	}
+
+	// Seek to the last key
+	err = cursor.Seek(bucket.Key([]byte("key9")))
+	if err != nil {
+		t.Fatalf("%s: Seek "+
+			"unexpectedly failed: %s", testName, err)
+	}
+	expectedKey = bucket.Key([]byte("key9"))
+	expectedValue = []byte("value9")
+	validateCurrentCursorKeyAndValue(t, testName, cursor, expectedKey, expectedValue)
+
+	// Call Next to get to the end of the cursor. This should
+	// return false to signify that there are no items after that.
+	// Key and Value calls should return ErrNotFound.
+	hasNext = cursor.Next()
+	if hasNext {
+		t.Fatalf("%s: Next "+
+			"after last value is unexpectedly not done", testName)
+	}
+	_, err = cursor.Key()
+	if err == nil {
+		t.Fatalf("%s: Key "+
+			"unexpectedly succeeded", testName)
+	}
+	if !database.IsNotFoundError(err) {
+		t.Fatalf("%s: Key "+
+			"returned wrong error: %s", testName, err)
+	}
+	_, err = cursor.Value()
+	if err == nil {
+		t.Fatalf("%s: Value "+
+			"unexpectedly succeeded", testName)
+	}
+	if !database.IsNotFoundError(err) {
+		t.Fatalf("%s: Value "+
+			"returned wrong error: %s", testName, err)
+	}
+}
+
+// TestCursorManyEntries makes sure that cursors iterate over big
+// buckets correctly, and don't leak into neighbouring buckets.
+func TestCursorManyEntries(t *testing.T) {
+	testForAllDatabaseTypes(t, "TestCursorManyEntries", testCursorManyEntries)
+}
+
+func testCursorManyEntries(t *testing.T, db database.Database, testName string) {
+	const entryCount = 2500
+
+	// Write the entries along with some entries in the buckets before and after
+	bucket := database.MakeBucket([]byte("b"))
+	dbTx, err := db.Begin()
+	if err != nil {
+		t.Fatalf("%s: Begin "+
+			"unexpectedly failed: %s", testName, err)
+	}
+	for _, neighbourBucket := range []*database.Bucket{database.MakeBucket([]byte("a")), database.MakeBucket([]byte("c"))} {
+		err := dbTx.Put(neighbourBucket.Key([]byte("key")), []byte("value"))
+		if err != nil {
+			t.Fatalf("%s: Put "+
+				"unexpectedly failed: %s", testName, err)
+		}
+	}
+	for i := 0; i < entryCount; i++ {
+		err := dbTx.Put(bucket.Key([]byte(fmt.Sprintf("key%05d", i))), []byte(fmt.Sprintf("value%d", i)))
+		if err != nil {
+			t.Fatalf("%s: Put "+
+				"unexpectedly failed: %s", testName, err)
+		}
+	}
+	err = dbTx.Commit()
+	if err != nil {
+		t.Fatalf("%s: Commit "+
+			"unexpectedly failed: %s", testName, err)
+	}
+
+	cursor, err := db.Cursor(bucket)
+	if err != nil {
+		t.Fatalf("%s: Cursor "+
+			"unexpectedly failed: %s", testName, err)
+	}
+	defer cursor.Close()
+
+	count := 0
+	for ok := cursor.First(); ok; ok = cursor.Next() {
+		validateCurrentCursorKeyAndValue(t, testName, cursor,
+			bucket.Key([]byte(fmt.Sprintf("key%05d", count))), []byte(fmt.Sprintf("value%d", count)))
+		count++
+	}
+	if count != entryCount {
+		t.Fatalf("%s: cursor "+
+			"returned %d entries. Want: %d", testName, count, entryCount)
+	}
+
+	// Seek into the middle of the bucket and make sure that iteration continues from there
+	err = cursor.Seek(bucket.Key([]byte(fmt.Sprintf("key%05d", 1999))))
+	if err != nil {
+		t.Fatalf("%s: Seek "+
+			"unexpectedly failed: %s", testName, err)
+	}
+	count = 1
+	for cursor.Next() {
+		count++
+	}
+	if count != entryCount-1999 {
+		t.Fatalf("%s: cursor "+
+			"returned %d entries after Seek. Want: %d", testName, count, entryCount-1999)
+	}
+}
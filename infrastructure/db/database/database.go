@@ -20,3 +20,12 @@ type Database interface {
 	// Close closes the database.
 	Close() error
 }
+
+// RangeCompactor is implemented by databases that can compact a
+// range of their keys at a time
+type RangeCompactor interface {
+	// CompactRange compacts the keys from start, inclusive, up to
+	// limit, exclusive. A nil start stands for the first key of the
+	// database, and a nil limit for the last one.
+	CompactRange(start []byte, limit []byte) error
+}
@@ -167,3 +167,45 @@ func testDatabaseDelete(t *testing.T, db database.Database, testName string) {
 			"unexpectedly returned that the value exists", testName)
 	}
 }
+
+func TestDatabaseCompact(t *testing.T) {
+	testForAllDatabaseTypes(t, "TestDatabaseCompact", testDatabaseCompact)
+}
+
+func testDatabaseCompact(t *testing.T, db database.Database, testName string) {
+	entries := populateDatabaseForTest(t, db, testName)
+
+	// Delete half of the entries so that there's something to compact
+	for _, entry := range entries[:len(entries)/2] {
+		err := db.Delete(entry.key)
+		if err != nil {
+			t.Fatalf("%s: Delete "+
+				"unexpectedly failed: %s", testName, err)
+		}
+	}
+
+	err := db.Compact()
+	if err != nil {
+		t.Fatalf("%s: Compact "+
+			"unexpectedly failed: %s", testName, err)
+	}
+
+	// Make sure that the database is still usable and holds exactly the remaining entries
+	for i, entry := range entries {
+		exists, err := db.Has(entry.key)
+		if err != nil {
+			t.Fatalf("%s: Has "+
+				"unexpectedly failed: %s", testName, err)
+		}
+		if exists != (i >= len(entries)/2) {
+			t.Fatalf("%s: Has "+
+				"returned %t for key %s after compaction", testName, exists, entry.key)
+		}
+	}
+	key := database.MakeBucket(nil).Key([]byte("after compaction"))
+	err = db.Put(key, []byte("value"))
+	if err != nil {
+		t.Fatalf("%s: Put "+
+			"unexpectedly failed: %s", testName, err)
+	}
+}
@@ -6,8 +6,10 @@ Package database provides a database for htnd.
 This package provides a database layer to store and retrieve data in a simple
 and efficient manner.
 
-The current backend is ffldb, which makes use of leveldb, flat files, and strict
-checksums in key areas to ensure data integrity.
+The available backends are leveldb (the ldb package) and bbolt (the boltdb
+package). The backend package opens the one that is selected with --dbtype.
+The tests in this package run against every backend, so they double as a
+conformance suite for new backends.
 
 Implementors of additional backends are required to implement the following interfaces:
 
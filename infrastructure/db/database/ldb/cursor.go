@@ -68,6 +68,19 @@ func (c *LevelDBCursor) Seek(key *database.Key) error {
 	return nil
 }
 
+// SeekGreaterOrEqual moves the iterator to the first key/value pair whose key
+// is greater than or equal to the given key. Unlike Seek, the given key doesn't
+// have to exist. It returns false if such pair does not exist.
+func (c *LevelDBCursor) SeekGreaterOrEqual(key *database.Key) (bool, error) {
+	if c.isClosed {
+		return false, errors.New("cannot seek a closed cursor")
+	}
+
+	// The iterator is bounded by the prefix of the bucket, so it doesn't
+	// move outside of it
+	return c.ldbIterator.Seek(key.Bytes()), nil
+}
+
 // Key returns the key of the current key/value pair, or ErrNotFound if done.
 // Note that the key is trimmed to not include the prefix the cursor was opened
 // with. The caller should not modify the contents of the returned slice, and
@@ -12,12 +12,52 @@ import (
 // LevelDB defines a thin wrapper around leveldb.
 type LevelDB struct {
 	ldb *leveldb.DB
+
+	// commitWriteOptions are the write options used when
+	// committing a transaction
+	commitWriteOptions *opt.WriteOptions
 }
 
 // NewLevelDB opens a leveldb instance defined by the given path.
+// Writes are never synced to disk, so a power loss may lose or
+// corrupt the most recent writes.
 func NewLevelDB(path string, cacheSizeMiB int) (*LevelDB, error) {
+	return newLevelDB(path, cacheSizeMiB, false)
+}
+
+// NewDurableLevelDB opens a leveldb instance defined by the given path.
+// Every transaction commit is synced to disk before it returns, so a
+// committed transaction survives a power loss.
+func NewDurableLevelDB(path string, cacheSizeMiB int) (*LevelDB, error) {
+	return newLevelDB(path, cacheSizeMiB, true)
+}
+
+// NewReadOnlyLevelDB opens the existing leveldb instance defined by the
+// given path for reading only. Any attempt to write into it returns an error.
+func NewReadOnlyLevelDB(path string, cacheSizeMiB int) (*LevelDB, error) {
+	options := Options()
+	options.BlockCacheCapacity = cacheSizeMiB * opt.MiB
+	options.ReadOnly = true
+	options.ErrorIfMissing = true
+	ldb, err := leveldb.OpenFile(path, &options)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	db := &LevelDB{
+		ldb:                ldb,
+		commitWriteOptions: &opt.WriteOptions{},
+	}
+	return db, nil
+}
+
+func newLevelDB(path string, cacheSizeMiB int, syncCommits bool) (*LevelDB, error) {
 	// Open leveldb. If it doesn't exist, create it.
 	options := Options()
+	if syncCommits {
+		// NoSync makes leveldb ignore WriteOptions.Sync
+		options.NoSync = false
+	}
 	options.BlockCacheCapacity = cacheSizeMiB * opt.MiB
 	options.WriteBuffer = (cacheSizeMiB * opt.MiB) / 2
 	ldb, err := leveldb.OpenFile(path, &options)
@@ -44,7 +84,8 @@ func NewLevelDB(path string, cacheSizeMiB int) (*LevelDB, error) {
 	}
 
 	db := &LevelDB{
-		ldb: ldb,
+		ldb:                ldb,
+		commitWriteOptions: &opt.WriteOptions{Sync: syncCommits},
 	}
 	return db, nil
 }
@@ -55,6 +96,13 @@ func (db *LevelDB) Compact() error {
 	return errors.WithStack(err)
 }
 
+// CompactRange compacts the keys of the leveldb instance from start,
+// inclusive, up to limit, exclusive.
+func (db *LevelDB) CompactRange(start []byte, limit []byte) error {
+	err := db.ldb.CompactRange(util.Range{Start: start, Limit: limit})
+	return errors.WithStack(err)
+}
+
 // Close closes the leveldb instance.
 func (db *LevelDB) Close() error {
 	err := db.ldb.Close()
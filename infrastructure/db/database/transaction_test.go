@@ -494,3 +494,222 @@ func testTransactionRollbackUnlessClosed(t *testing.T, db database.Database, tes
 			"unexpectedly failed: %s", testName, err)
 	}
 }
+
+func TestTransactionSanity(t *testing.T) {
+	testForAllDatabaseTypes(t, "TestTransactionSanity", testTransactionSanity)
+}
+
+func testTransactionSanity(t *testing.T, db database.Database, testName string) {
+	// Case 1. Write in tx and then read directly from the DB
+	// Begin a new transaction
+	dbTx, err := db.Begin()
+	if err != nil {
+		t.Fatalf("%s: Begin "+
+			"unexpectedly failed: %s", testName, err)
+	}
+
+	// Put something into the transaction
+	key := database.MakeBucket(nil).Key([]byte("key"))
+	putData := []byte("Hello world!")
+	err = dbTx.Put(key, putData)
+	if err != nil {
+		t.Fatalf("%s: Put "+
+			"returned unexpected error: %s", testName, err)
+	}
+
+	// Get from the key previously put to. Since the tx is not
+	// yet committed, this should return ErrNotFound.
+	_, err = db.Get(key)
+	if err == nil {
+		t.Fatalf("%s: Get "+
+			"unexpectedly succeeded", testName)
+	}
+	if !database.IsNotFoundError(err) {
+		t.Fatalf("%s: Get "+
+			"returned wrong error: %s", testName, err)
+	}
+
+	// Commit the transaction
+	err = dbTx.Commit()
+	if err != nil {
+		t.Fatalf("%s: Commit "+
+			"returned unexpected error: %s", testName, err)
+	}
+
+	// Get from the key previously put to. Now that the tx was
+	// committed, this should succeed.
+	getData, err := db.Get(key)
+	if err != nil {
+		t.Fatalf("%s: Get "+
+			"returned unexpected error: %s", testName, err)
+	}
+
+	// Make sure that the put data and the get data are equal
+	if !bytes.Equal(getData, putData) {
+		t.Fatalf("%s: get "+
+			"data and put data are not equal. Put: %s, got: %s",
+			testName, string(putData), string(getData))
+	}
+
+	// Case 2. Write directly to the DB and then read from a tx
+	// Put something into the db
+	key = database.MakeBucket(nil).Key([]byte("key2"))
+	putData = []byte("Goodbye world!")
+	err = db.Put(key, putData)
+	if err != nil {
+		t.Fatalf("%s: Put "+
+			"returned unexpected error: %s", testName, err)
+	}
+
+	// Begin a new transaction
+	dbTx, err = db.Begin()
+	if err != nil {
+		t.Fatalf("%s: Begin "+
+			"unexpectedly failed: %s", testName, err)
+	}
+
+	// Get from the key previously put to
+	getData, err = dbTx.Get(key)
+	if err != nil {
+		t.Fatalf("%s: Get "+
+			"returned unexpected error: %s", testName, err)
+	}
+
+	// Make sure that the put data and the get data are equal
+	if !bytes.Equal(getData, putData) {
+		t.Fatalf("%s: get "+
+			"data and put data are not equal. Put: %s, got: %s",
+			testName, string(putData), string(getData))
+	}
+
+	// Rollback the transaction
+	err = dbTx.Rollback()
+	if err != nil {
+		t.Fatalf("%s: rollback "+
+			"returned unexpected error: %s", testName, err)
+	}
+}
+
+func TestTransactionCloseErrors(t *testing.T) {
+	testForAllDatabaseTypes(t, "TestTransactionCloseErrors", testTransactionCloseErrors)
+}
+
+func testTransactionCloseErrors(t *testing.T, db database.Database, testName string) {
+	tests := []struct {
+		name string
+
+		// function is the Transaction function that
+		// we're verifying whether it returns an error after
+		// the transaction had been closed.
+		function          func(dbTx database.Transaction) error
+		shouldReturnError bool
+	}{
+		{
+			name: "Put",
+			function: func(dbTx database.Transaction) error {
+				return dbTx.Put(database.MakeBucket(nil).Key([]byte("key")), []byte("value"))
+			},
+			shouldReturnError: true,
+		},
+		{
+			name: "Get",
+			function: func(dbTx database.Transaction) error {
+				_, err := dbTx.Get(database.MakeBucket(nil).Key([]byte("key")))
+				return err
+			},
+			shouldReturnError: true,
+		},
+		{
+			name: "Has",
+			function: func(dbTx database.Transaction) error {
+				_, err := dbTx.Has(database.MakeBucket(nil).Key([]byte("key")))
+				return err
+			},
+			shouldReturnError: true,
+		},
+		{
+			name: "Delete",
+			function: func(dbTx database.Transaction) error {
+				return dbTx.Delete(database.MakeBucket(nil).Key([]byte("key")))
+			},
+			shouldReturnError: true,
+		},
+		{
+			name: "Cursor",
+			function: func(dbTx database.Transaction) error {
+				_, err := dbTx.Cursor(database.MakeBucket([]byte("bucket")))
+				return err
+			},
+			shouldReturnError: true,
+		},
+		{
+			name:              "Rollback",
+			function:          database.Transaction.Rollback,
+			shouldReturnError: true,
+		},
+		{
+			name:              "Commit",
+			function:          database.Transaction.Commit,
+			shouldReturnError: true,
+		},
+		{
+			name:              "RollbackUnlessClosed",
+			function:          database.Transaction.RollbackUnlessClosed,
+			shouldReturnError: false,
+		},
+	}
+
+	for _, test := range tests {
+		// Begin a new transaction to test Commit
+		commitTx, err := db.Begin()
+		if err != nil {
+			t.Fatalf("%s: Begin "+
+				"unexpectedly failed: %s", testName, err)
+		}
+
+		// Commit the Commit test transaction
+		err = commitTx.Commit()
+		if err != nil {
+			t.Fatalf("%s: Commit "+
+				"unexpectedly failed: %s", testName, err)
+		}
+
+		// Begin a new transaction to test Rollback
+		rollbackTx, err := db.Begin()
+		if err != nil {
+			t.Fatalf("%s: Begin "+
+				"unexpectedly failed: %s", testName, err)
+		}
+
+		// Rollback the Rollback test transaction
+		err = rollbackTx.Rollback()
+		if err != nil {
+			t.Fatalf("%s: Rollback "+
+				"unexpectedly failed: %s", testName, err)
+		}
+
+		expectedErrContainsString := "closed transaction"
+
+		// Make sure that the test function returns a "closed transaction" error
+		// for both the commitTx and the rollbackTx
+		for _, closedTx := range []database.Transaction{commitTx, rollbackTx} {
+			err = test.function(closedTx)
+			if test.shouldReturnError {
+				if err == nil {
+					t.Fatalf("%s: %s "+
+						"unexpectedly succeeded", testName, test.name)
+				}
+				if !strings.Contains(err.Error(), expectedErrContainsString) {
+					t.Fatalf("%s: %s "+
+						"returned wrong error. Want: %s, got: %s",
+						testName, test.name, expectedErrContainsString, err)
+				}
+			} else {
+				if err != nil {
+					t.Fatalf("%s: %s "+
+						"unexpectedly failed: %s", testName, test.name, err)
+				}
+			}
+		}
+	}
+}
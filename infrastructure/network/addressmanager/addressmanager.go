@@ -5,7 +5,11 @@
 package addressmanager
 
 import (
+	"bytes"
+	"math/rand"
 	"net"
+	"reflect"
+	"sort"
 	"sync"
 	"time"
 
@@ -17,8 +21,10 @@ import (
 )
 
 const (
-	maxAddresses                   = 4096
 	connectionFailedCountForRemove = 4
+
+	// maxAnchors is the maximum number of anchor addresses that are kept
+	maxAnchors = 2
 )
 
 // addressRandomizer is the interface for the randomizer needed for the AddressManager.
@@ -35,6 +41,10 @@ type addressKey struct {
 type address struct {
 	netAddress            *appmessage.NetAddress
 	connectionFailedCount uint64
+	// source is the IP of the peer that told us about this address
+	source ipv6
+	// tried is true if the address is in the tried table, and false if it's in the new one
+	tried bool
 }
 
 type ipv6 [net.IPv6len]byte
@@ -57,9 +67,18 @@ func netAddressKey(netAddress *appmessage.NetAddress) addressKey {
 
 // AddressManager provides a concurrency safe address manager for caching potential
 // peers on the Hoosat network.
+//
+// Addresses are kept in two tables: the new table holds addresses we have heard of,
+// and the tried table holds addresses we have successfully connected to. Both tables
+// are divided into buckets that are chosen by a secret key, the network group of the
+// address and, for the new table, the network group of the peer that advertised it.
+// This way a single peer flooding us with addresses can only fill a small part of the
+// new table, and can't evict the addresses we already know to be good.
 type AddressManager struct {
 	store          *addressStore
 	localAddresses *localAddressManager
+	newTable       *addressTable
+	triedTable     *addressTable
 	mutex          sync.Mutex
 	cfg            *Config
 	random         addressRandomizer
@@ -76,67 +95,189 @@ func New(cfg *Config, database database.Database) (*AddressManager, error) {
 		return nil, err
 	}
 
-	return &AddressManager{
+	addressManager := &AddressManager{
 		store:          addressStore,
 		localAddresses: localAddresses,
+		newTable:       newAddressTable(newBucketCount),
+		triedTable:     newAddressTable(triedBucketCount),
 		random:         NewAddressRandomize(connectionFailedCountForRemove),
 		cfg:            cfg,
-	}, nil
+	}
+	err = addressManager.restoreTables()
+	if err != nil {
+		return nil, err
+	}
+	return addressManager, nil
 }
 
-func (am *AddressManager) addAddressNoLock(netAddress *appmessage.NetAddress) error {
-	if !IsRoutable(netAddress, am.cfg.AcceptUnroutable) {
-		return nil
+// restoreTables puts the addresses loaded by the store in their buckets. Tried
+// addresses are placed first, so that they're preferred if buckets overflow.
+func (am *AddressManager) restoreTables() error {
+	var newAddresses []*address
+	for key, address := range am.store.notBannedAddresses {
+		if !address.tried {
+			newAddresses = append(newAddresses, address)
+			continue
+		}
+		err := am.addToTriedNoLock(key, address)
+		if err != nil {
+			return err
+		}
 	}
-
-	key := netAddressKey(netAddress)
-	// We mark `connectionFailedCount` as 0 only after first success
-	address := &address{netAddress: netAddress, connectionFailedCount: 1}
-	err := am.store.add(key, address)
-	if err != nil {
-		return err
+	for _, address := range newAddresses {
+		key := netAddressKey(address.netAddress)
+		if _, ok := am.store.getNotBanned(key); !ok {
+			// The address was evicted while restoring
+			continue
+		}
+		err := am.addToNewNoLock(key, address)
+		if err != nil {
+			return err
+		}
 	}
 
-	if am.store.notBannedCount() > maxAddresses {
-		allAddresses := am.store.getAllNotBanned()
+	log.Debugf("Restored %d new and %d tried addresses", am.newTable.count(), am.triedTable.count())
+	return nil
+}
+
+func (am *AddressManager) newBucket(address *address) int {
+	group := am.GroupKey(address.netAddress)
+	sourceGroup := am.GroupKey(&appmessage.NetAddress{IP: address.source[:]})
+	return newBucket(am.store.bucketKey, group, sourceGroup)
+}
+
+func (am *AddressManager) triedBucket(key addressKey, address *address) int {
+	return triedBucket(am.store.bucketKey, key, am.GroupKey(address.netAddress))
+}
 
-		maxConnectionFailedCount := uint64(0)
-		toRemove := allAddresses[0]
-		for _, address := range allAddresses[1:] {
-			if address.connectionFailedCount > maxConnectionFailedCount {
-				maxConnectionFailedCount = address.connectionFailedCount
-				toRemove = address
-			}
+// worstInBucket returns the key of the worst address in the given bucket of the given table
+func (am *AddressManager) worstInBucket(table *addressTable, bucket int) (addressKey, *address) {
+	var worstKey addressKey
+	var worst *address
+	for _, key := range table.bucketKeys(bucket) {
+		address, ok := am.store.getNotBanned(key)
+		if !ok {
+			continue
+		}
+		if worst == nil || address.worseThan(worst) {
+			worstKey = key
+			worst = address
 		}
+	}
+	return worstKey, worst
+}
 
-		err := am.removeAddressNoLock(toRemove.netAddress)
+// addToNewNoLock places the given stored address in its new bucket. If the bucket
+// is full its worst address is removed from the address manager.
+func (am *AddressManager) addToNewNoLock(key addressKey, address *address) error {
+	bucket := am.newBucket(address)
+	if am.newTable.isFull(bucket) {
+		worstKey, worst := am.worstInBucket(am.newTable, bucket)
+		log.Debugf("New bucket %d is full - evicting %s", bucket, worst.netAddress.TCPAddress())
+		am.newTable.remove(bucket, worstKey)
+		err := am.store.remove(worstKey)
 		if err != nil {
 			return err
 		}
 	}
+
+	am.newTable.add(bucket, key)
+	if address.tried {
+		address.tried = false
+		return am.store.updateNotBanned(key, address)
+	}
 	return nil
 }
 
-func (am *AddressManager) removeAddressNoLock(address *appmessage.NetAddress) error {
-	key := netAddressKey(address)
+// addToTriedNoLock places the given stored address in its tried bucket. If the bucket
+// is full its worst address is moved back to the new table to make room.
+func (am *AddressManager) addToTriedNoLock(key addressKey, address *address) error {
+	bucket := am.triedBucket(key, address)
+	if am.triedTable.isFull(bucket) {
+		worstKey, worst := am.worstInBucket(am.triedTable, bucket)
+		log.Debugf("Tried bucket %d is full - moving %s back to the new table",
+			bucket, worst.netAddress.TCPAddress())
+		am.triedTable.remove(bucket, worstKey)
+		err := am.addToNewNoLock(worstKey, worst)
+		if err != nil {
+			return err
+		}
+	}
+
+	am.triedTable.add(bucket, key)
+	if !address.tried {
+		address.tried = true
+		return am.store.updateNotBanned(key, address)
+	}
+	return nil
+}
+
+func (am *AddressManager) addAddressNoLock(netAddress *appmessage.NetAddress, source *appmessage.NetAddress) error {
+	if !IsRoutable(netAddress, am.cfg.AcceptUnroutable) {
+		return nil
+	}
+
+	key := netAddressKey(netAddress)
+	if am.store.isNotBanned(key) {
+		return nil
+	}
+
+	// We mark `connectionFailedCount` as 0 only after first success
+	address := &address{netAddress: netAddress, connectionFailedCount: 1}
+	copy(address.source[:], source.IP.To16())
+	err := am.store.add(key, address)
+	if err != nil {
+		return err
+	}
+	return am.addToNewNoLock(key, address)
+}
+
+func (am *AddressManager) removeAddressNoLock(netAddress *appmessage.NetAddress) error {
+	key := netAddressKey(netAddress)
+	address, ok := am.store.getNotBanned(key)
+	if !ok {
+		return nil
+	}
+	if address.tried {
+		am.triedTable.remove(am.triedBucket(key, address), key)
+	} else {
+		am.newTable.remove(am.newBucket(address), key)
+	}
 	return am.store.remove(key)
 }
 
-// AddAddress adds address to the address manager
+// AddAddress adds address to the address manager. The address is considered
+// to be its own source.
 func (am *AddressManager) AddAddress(address *appmessage.NetAddress) error {
 	am.mutex.Lock()
 	defer am.mutex.Unlock()
 
-	return am.addAddressNoLock(address)
+	return am.addAddressNoLock(address, address)
 }
 
-// AddAddresses adds addresses to the address manager
+// AddAddresses adds addresses to the address manager. Every address is
+// considered to be its own source.
 func (am *AddressManager) AddAddresses(addresses ...*appmessage.NetAddress) error {
 	am.mutex.Lock()
 	defer am.mutex.Unlock()
 
 	for _, address := range addresses {
-		err := am.addAddressNoLock(address)
+		err := am.addAddressNoLock(address, address)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// AddAddressesFromSource adds addresses that were advertised by source to the
+// address manager
+func (am *AddressManager) AddAddressesFromSource(source *appmessage.NetAddress, addresses ...*appmessage.NetAddress) error {
+	am.mutex.Lock()
+	defer am.mutex.Unlock()
+
+	for _, address := range addresses {
+		err := am.addAddressNoLock(address, source)
 		if err != nil {
 			return err
 		}
@@ -166,15 +307,24 @@ func (am *AddressManager) MarkConnectionFailure(address *appmessage.NetAddress)
 	entry.connectionFailedCount = entry.connectionFailedCount + 1
 
 	if entry.connectionFailedCount >= connectionFailedCountForRemove {
+		if entry.tried {
+			// Addresses that used to be good get another round of attempts in the new table
+			log.Debugf("Address %s has failed %d connection attempts - moving it back to the new table",
+				address, entry.connectionFailedCount)
+			am.triedTable.remove(am.triedBucket(key, entry), key)
+			entry.connectionFailedCount = 1
+			return am.addToNewNoLock(key, entry)
+		}
+
 		log.Debugf("Address %s has failed %d connection attempts - removing from address manager",
 			address, entry.connectionFailedCount)
-		return am.store.remove(key)
+		return am.removeAddressNoLock(address)
 	}
 	return am.store.updateNotBanned(key, entry)
 }
 
 // MarkConnectionSuccess notifies the address manager that the given address
-// has successfully connected
+// has successfully connected. This moves it to the tried table.
 func (am *AddressManager) MarkConnectionSuccess(address *appmessage.NetAddress) error {
 	am.mutex.Lock()
 	defer am.mutex.Unlock()
@@ -185,6 +335,10 @@ func (am *AddressManager) MarkConnectionSuccess(address *appmessage.NetAddress)
 		return errors.Errorf("address %s is not registered with the address manager", address.TCPAddress())
 	}
 	entry.connectionFailedCount = 0
+	if !entry.tried {
+		am.newTable.remove(am.newBucket(entry), key)
+		return am.addToTriedNoLock(key, entry)
+	}
 	return am.store.updateNotBanned(key, entry)
 }
 
@@ -204,18 +358,75 @@ func (am *AddressManager) BannedAddresses() []*appmessage.NetAddress {
 	return am.store.getAllBannedNetAddresses()
 }
 
-// notBannedAddressesWithException returns all not banned addresses with excpetion
-func (am *AddressManager) notBannedAddressesWithException(exceptions []*appmessage.NetAddress) []*address {
+// notBannedAddressesWithException returns all not banned addresses with excpetion,
+// split into the tried and the new ones
+func (am *AddressManager) notBannedAddressesWithException(exceptions []*appmessage.NetAddress) (
+	triedAddresses []*address, newAddresses []*address) {
+
 	am.mutex.Lock()
 	defer am.mutex.Unlock()
 
-	return am.store.getAllNotBannedNetAddressesWithout(exceptions)
+	for _, address := range am.store.getAllNotBannedNetAddressesWithout(exceptions) {
+		if address.tried {
+			triedAddresses = append(triedAddresses, address)
+		} else {
+			newAddresses = append(newAddresses, address)
+		}
+	}
+	return triedAddresses, newAddresses
 }
 
-// RandomAddresses returns count addresses at random that aren't banned and aren't in exceptions
+// RandomAddresses returns count addresses at random that aren't banned and aren't in exceptions.
+// Every address is picked from the tried or the new table with equal probability, so that
+// addresses we never connected to can't crowd out the ones we know to be good.
 func (am *AddressManager) RandomAddresses(count int, exceptions []*appmessage.NetAddress) []*appmessage.NetAddress {
-	validAddresses := am.notBannedAddressesWithException(exceptions)
-	return am.random.RandomAddresses(validAddresses, count)
+	triedAddresses, newAddresses := am.notBannedAddressesWithException(exceptions)
+
+	triedCount := 0
+	for i := 0; i < count; i++ {
+		if rand.Intn(2) == 0 {
+			triedCount++
+		}
+	}
+	if triedCount > len(triedAddresses) {
+		triedCount = len(triedAddresses)
+	}
+	if count-triedCount > len(newAddresses) {
+		triedCount = count - len(newAddresses)
+		if triedCount > len(triedAddresses) {
+			triedCount = len(triedAddresses)
+		}
+	}
+
+	result := am.random.RandomAddresses(triedAddresses, triedCount)
+	return append(result, am.random.RandomAddresses(newAddresses, count-triedCount)...)
+}
+
+// Anchors returns the anchor addresses, which are the addresses of outgoing peers
+// we were connected to before the last shutdown
+func (am *AddressManager) Anchors() []*appmessage.NetAddress {
+	am.mutex.Lock()
+	defer am.mutex.Unlock()
+
+	return am.store.getAnchors()
+}
+
+// SetAnchors persists up to maxAnchors of the given addresses as the anchor
+// addresses to reconnect to after a restart
+func (am *AddressManager) SetAnchors(addresses []*appmessage.NetAddress) error {
+	am.mutex.Lock()
+	defer am.mutex.Unlock()
+
+	if len(addresses) > maxAnchors {
+		addresses = addresses[:maxAnchors]
+	}
+
+	currentAnchors := netAddressesKeys(am.store.anchorAddresses)
+	newAnchors := netAddressesKeys(addresses)
+	if reflect.DeepEqual(currentAnchors, newAnchors) {
+		return nil
+	}
+	return am.store.setAnchors(addresses)
 }
 
 // BestLocalAddress returns the most appropriate local address to use
@@ -224,28 +435,88 @@ func (am *AddressManager) BestLocalAddress(remoteAddress *appmessage.NetAddress)
 	return am.localAddresses.bestLocalAddress(remoteAddress)
 }
 
-// Ban marks the given address as banned
-func (am *AddressManager) Ban(addressToBan *appmessage.NetAddress) error {
+// AddLocalAddress adds netAddress to the list of known local addresses to
+// advertise to peers with the given priority
+func (am *AddressManager) AddLocalAddress(netAddress *appmessage.NetAddress, priority AddressPriority) error {
+	return am.localAddresses.addLocalNetAddress(netAddress, priority)
+}
+
+// maxBanReasonLength is the maximum length of the reason of a ban. Longer reasons are truncated.
+const maxBanReasonLength = 256
+
+// BanInfo describes the ban of an IP
+type BanInfo struct {
+	Address  *appmessage.NetAddress
+	Reason   string
+	BannedAt mstime.Time
+	// ExpiresAt is zero if the ban never expires
+	ExpiresAt mstime.Time
+}
+
+// Ban marks the IP of the given address as banned for the given duration, or forever
+// if duration is 0. If the IP is already banned, its ban is replaced.
+func (am *AddressManager) Ban(addressToBan *appmessage.NetAddress, duration time.Duration, reason string) error {
 	am.mutex.Lock()
 	defer am.mutex.Unlock()
 
 	keyToBan := netAddressKey(addressToBan)
-	keysToDelete := make([]addressKey, 0)
+	addressesToRemove := make([]*appmessage.NetAddress, 0)
 	for _, address := range am.store.getAllNotBannedNetAddresses() {
 		key := netAddressKey(address)
 		if key.address.equal(keyToBan.address) {
-			keysToDelete = append(keysToDelete, key)
+			addressesToRemove = append(addressesToRemove, address)
 		}
 	}
-	for _, key := range keysToDelete {
-		err := am.store.remove(key)
+	for _, address := range addressesToRemove {
+		err := am.removeAddressNoLock(address)
 		if err != nil {
 			return err
 		}
 	}
 
-	address := &address{netAddress: addressToBan}
-	return am.store.addBanned(keyToBan, address)
+	if len(reason) > maxBanReasonLength {
+		reason = reason[:maxBanReasonLength]
+	}
+	ban := &ban{
+		address:  &address{netAddress: addressToBan},
+		reason:   reason,
+		bannedAt: mstime.Now(),
+	}
+	if duration != 0 {
+		ban.expiresAt = ban.bannedAt.Add(duration.Truncate(time.Millisecond))
+	}
+	return am.store.addBanned(keyToBan, ban)
+}
+
+// Bans returns the bans that haven't expired, ordered by the time they were made
+func (am *AddressManager) Bans() ([]*BanInfo, error) {
+	am.mutex.Lock()
+	defer am.mutex.Unlock()
+
+	var bans []*BanInfo
+	for _, ban := range am.store.getAllBans() {
+		key := netAddressKey(ban.address.netAddress)
+		isExpired, err := am.unbanIfExpired(key)
+		if err != nil {
+			return nil, err
+		}
+		if isExpired {
+			continue
+		}
+		bans = append(bans, &BanInfo{
+			Address:   ban.address.netAddress,
+			Reason:    ban.reason,
+			BannedAt:  ban.bannedAt,
+			ExpiresAt: ban.expiresAt,
+		})
+	}
+	sort.Slice(bans, func(i, j int) bool {
+		if bans[i].BannedAt.UnixMilliseconds() != bans[j].BannedAt.UnixMilliseconds() {
+			return bans[i].BannedAt.Before(bans[j].BannedAt)
+		}
+		return bytes.Compare(bans[i].Address.IP.To16(), bans[j].Address.IP.To16()) < 0
+	})
+	return bans, nil
 }
 
 // Unban unmarks the given address as banned
@@ -268,7 +539,7 @@ func (am *AddressManager) IsBanned(address *appmessage.NetAddress) (bool, error)
 	defer am.mutex.Unlock()
 
 	key := netAddressKey(address)
-	err := am.unbanIfOldEnough(key)
+	_, err := am.unbanIfExpired(key)
 	if err != nil {
 		return false, err
 	}
@@ -283,18 +554,17 @@ func (am *AddressManager) IsBanned(address *appmessage.NetAddress) (bool, error)
 	return true, nil
 }
 
-func (am *AddressManager) unbanIfOldEnough(key addressKey) error {
-	address, ok := am.store.getBanned(key)
-	if !ok {
-		return nil
+// unbanIfExpired removes the ban of the given key if it expired, and returns whether it did
+func (am *AddressManager) unbanIfExpired(key addressKey) (bool, error) {
+	ban, ok := am.store.getBanned(key)
+	if !ok || ban.expiresAt.IsZero() || mstime.Now().Before(ban.expiresAt) {
+		return false, nil
 	}
 
-	const maxBanTime = 24 * time.Hour
-	if mstime.Since(address.netAddress.Timestamp) > maxBanTime {
-		err := am.store.removeBanned(key)
-		if err != nil {
-			return err
-		}
+	log.Infof("The ban of %s expired", ban.address.netAddress.IP)
+	err := am.store.removeBanned(key)
+	if err != nil {
+		return false, err
 	}
-	return nil
+	return true, nil
 }
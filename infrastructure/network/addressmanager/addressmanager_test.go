@@ -8,6 +8,7 @@ import (
 	"net"
 	"reflect"
 	"testing"
+	"time"
 
 	"github.com/Hoosat-Oy/HTND/app/appmessage"
 	"github.com/Hoosat-Oy/HTND/infrastructure/config"
@@ -178,7 +179,7 @@ func TestAddressManager(t *testing.T) {
 
 	// Ban a different address
 	addressToBan := testAddress3
-	err = addressManager.Ban(addressToBan)
+	err = addressManager.Ban(addressToBan, time.Hour, "test")
 	if err != nil {
 		t.Fatalf("Ban() failed: %s", err)
 	}
@@ -258,7 +259,7 @@ func TestRestoreAddressManager(t *testing.T) {
 
 	// Ban one of the addresses
 	addressToBan := testAddress1
-	err = addressManager.Ban(addressToBan)
+	err = addressManager.Ban(addressToBan, time.Hour, "test")
 	if err != nil {
 		t.Fatalf("Ban() failed: %s", err)
 	}
@@ -305,70 +306,134 @@ func TestRestoreAddressManager(t *testing.T) {
 	}
 }
 
-func TestOverfillAddressManager(t *testing.T) {
-	addressManager, teardown := newAddressManagerForTest(t, "TestAddressManager")
+func TestAddressManagerSourceBucketing(t *testing.T) {
+	addressManager, teardown := newAddressManagerForTest(t, "TestAddressManagerSourceBucketing")
 	defer teardown()
 
 	generateTestAddresses := func(amount int) []*appmessage.NetAddress {
 		testAddresses := make([]*appmessage.NetAddress, 0, amount)
-		for i := byte(0); i < 128; i++ {
-			for j := byte(0); j < 128; j++ {
-				testAddress := &appmessage.NetAddress{IP: net.IP{1, 2, i, j}, Timestamp: mstime.Now()}
-				testAddresses = append(testAddresses, testAddress)
-				if len(testAddresses) == amount {
-					break
-				}
-			}
-			if len(testAddresses) == amount {
-				break
-			}
+		for i := 0; i < amount; i++ {
+			testAddress := &appmessage.NetAddress{IP: net.IP{byte(1 + i/256), 2, 3, byte(i)}, Timestamp: mstime.Now()}
+			testAddresses = append(testAddresses, testAddress)
 		}
 		return testAddresses
 	}
 
-	// Add a single test address to the address manager
-	testAddress := &appmessage.NetAddress{IP: net.IP{5, 6, 0, 0}, Timestamp: mstime.Now()}
-	err := addressManager.AddAddress(testAddress)
+	// Add an address that was advertised by an honest peer and connect to it
+	honestSource := &appmessage.NetAddress{IP: net.IP{5, 6, 0, 0}}
+	honestAddress := &appmessage.NetAddress{IP: net.IP{7, 8, 0, 0}, Timestamp: mstime.Now()}
+	err := addressManager.AddAddressesFromSource(honestSource, honestAddress)
 	if err != nil {
-		t.Fatalf("AddAddress: %s", err)
+		t.Fatalf("AddAddressesFromSource: %s", err)
+	}
+	err = addressManager.MarkConnectionSuccess(honestAddress)
+	if err != nil {
+		t.Fatalf("MarkConnectionSuccess: %s", err)
 	}
 
-	// Add `maxAddresses-1` addresses to the address manager
-	addresses := generateTestAddresses(maxAddresses - 1)
-	err = addressManager.AddAddresses(addresses...)
+	// Flood the address manager with addresses from many groups, all advertised by a single source
+	attackerSource := &appmessage.NetAddress{IP: net.IP{9, 9, 0, 0}}
+	err = addressManager.AddAddressesFromSource(attackerSource, generateTestAddresses(4096)...)
 	if err != nil {
-		t.Fatalf("AddAddresses: %s", err)
+		t.Fatalf("AddAddressesFromSource: %s", err)
 	}
 
-	// Make sure that it now contains exactly `maxAddresses` entries
+	// Make sure the attacker could only fill the buckets of its source group
+	maxAddressesFromSource := newBucketsPerSourceGroup * bucketSize
 	returnedAddresses := addressManager.Addresses()
-	if len(returnedAddresses) != maxAddresses {
-		t.Fatalf("Unexpected address amount. Want: %d, got: %d", maxAddresses, len(returnedAddresses))
+	if len(returnedAddresses) > maxAddressesFromSource+1 {
+		t.Fatalf("Unexpected address amount. Want at most: %d, got: %d",
+			maxAddressesFromSource+1, len(returnedAddresses))
 	}
 
-	// Mark the first test address as a connection failure
-	err = addressManager.MarkConnectionFailure(testAddress)
+	// Make sure the honest address wasn't evicted
+	found := false
+	for _, address := range returnedAddresses {
+		if address.IP.Equal(honestAddress.IP) {
+			found = true
+			break
+		}
+	}
+	if !found {
+		t.Fatalf("The honest address was evicted by the addresses of a single source")
+	}
+}
+
+func TestAddressManagerTriedTable(t *testing.T) {
+	cfg := config.DefaultConfig()
+
+	datadir := t.TempDir()
+	database, err := ldb.NewLevelDB(datadir, 8)
 	if err != nil {
-		t.Fatalf("MarkConnectionFailure: %s", err)
+		t.Fatalf("Could not create a database: %s", err)
 	}
+	defer database.Close()
 
-	// Add one more address to the address manager
-	err = addressManager.AddAddress(&appmessage.NetAddress{IP: net.IP{7, 8, 0, 0}, Timestamp: mstime.Now()})
+	addressManager, err := New(NewConfig(cfg), database)
 	if err != nil {
-		t.Fatalf("AddAddress: %s", err)
+		t.Fatalf("Error creating address manager: %s", err)
 	}
 
-	// Make sure that it now still contains exactly `maxAddresses` entries
-	returnedAddresses = addressManager.Addresses()
-	if len(returnedAddresses) != maxAddresses {
-		t.Fatalf("Unexpected address amount. Want: %d, got: %d", maxAddresses, len(returnedAddresses))
+	testAddress1 := &appmessage.NetAddress{IP: net.ParseIP("1.2.3.4"), Timestamp: mstime.Now()}
+	testAddress2 := &appmessage.NetAddress{IP: net.ParseIP("5.6.8.8"), Timestamp: mstime.Now()}
+	err = addressManager.AddAddresses(testAddress1, testAddress2)
+	if err != nil {
+		t.Fatalf("AddAddresses() failed: %s", err)
 	}
 
-	// Make sure that the first address is no longer in the
-	// connection manager
-	for _, address := range returnedAddresses {
-		if address.IP.Equal(testAddress.IP) {
-			t.Fatalf("Unexpectedly found testAddress returned addresses")
+	// A successful connection moves the address to the tried table
+	err = addressManager.MarkConnectionSuccess(testAddress1)
+	if err != nil {
+		t.Fatalf("MarkConnectionSuccess() failed: %s", err)
+	}
+	if addressManager.triedTable.count() != 1 || addressManager.newTable.count() != 1 {
+		t.Fatalf("Unexpected table sizes. Want: 1 tried and 1 new, got: %d tried and %d new",
+			addressManager.triedTable.count(), addressManager.newTable.count())
+	}
+
+	// Make sure that the tables are restored after a restart
+	err = addressManager.SetAnchors([]*appmessage.NetAddress{testAddress1})
+	if err != nil {
+		t.Fatalf("SetAnchors() failed: %s", err)
+	}
+	err = database.Close()
+	if err != nil {
+		t.Fatalf("Close() failed: %s", err)
+	}
+	database, err = ldb.NewLevelDB(datadir, 8)
+	if err != nil {
+		t.Fatalf("Could not create a database: %s", err)
+	}
+	defer database.Close()
+	addressManager, err = New(NewConfig(cfg), database)
+	if err != nil {
+		t.Fatalf("Error creating address manager: %s", err)
+	}
+	if addressManager.triedTable.count() != 1 || addressManager.newTable.count() != 1 {
+		t.Fatalf("Unexpected table sizes after restart. Want: 1 tried and 1 new, got: %d tried and %d new",
+			addressManager.triedTable.count(), addressManager.newTable.count())
+	}
+
+	anchors := addressManager.Anchors()
+	if len(anchors) != 1 || !anchors[0].IP.Equal(testAddress1.IP) {
+		t.Fatalf("Unexpected anchors after restart. Want: [%s], got: %v", testAddress1.IP, anchors)
+	}
+
+	// Repeated connection failures move a tried address back to the new table
+	for i := 0; i < connectionFailedCountForRemove; i++ {
+		err = addressManager.MarkConnectionFailure(testAddress1)
+		if err != nil {
+			t.Fatalf("MarkConnectionFailure() failed: %s", err)
 		}
 	}
+	if addressManager.triedTable.count() != 0 || addressManager.newTable.count() != 2 {
+		t.Fatalf("Unexpected table sizes. Want: 0 tried and 2 new, got: %d tried and %d new",
+			addressManager.triedTable.count(), addressManager.newTable.count())
+	}
+
+	// RandomAddresses still returns both addresses
+	randomAddresses := addressManager.RandomAddresses(2, nil)
+	if len(randomAddresses) != 2 {
+		t.Fatalf("Unexpected amount of random addresses. Want: 2, got: %d", len(randomAddresses))
+	}
 }
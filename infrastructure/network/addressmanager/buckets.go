@@ -0,0 +1,110 @@
+package addressmanager
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+)
+
+const (
+	// newBucketCount is the number of buckets in the new table, which holds
+	// addresses we have heard of but haven't connected to yet
+	newBucketCount = 256
+
+	// triedBucketCount is the number of buckets in the tried table, which holds
+	// addresses we have successfully connected to
+	triedBucketCount = 64
+
+	// bucketSize is the maximum number of addresses in a single bucket
+	bucketSize = 16
+
+	// newBucketsPerSourceGroup is the number of new buckets the addresses
+	// advertised by a single source group can be spread over. This limits
+	// the part of the new table a single attacker can fill.
+	newBucketsPerSourceGroup = 32
+
+	// triedBucketsPerGroup is the number of tried buckets the addresses of
+	// a single group can be spread over
+	triedBucketsPerGroup = 8
+)
+
+// addressTable is a table of addresses divided into buckets of a fixed size
+type addressTable struct {
+	buckets []map[addressKey]struct{}
+}
+
+func newAddressTable(bucketCount int) *addressTable {
+	buckets := make([]map[addressKey]struct{}, bucketCount)
+	for i := range buckets {
+		buckets[i] = make(map[addressKey]struct{}, bucketSize)
+	}
+	return &addressTable{buckets: buckets}
+}
+
+func (at *addressTable) add(bucket int, key addressKey) {
+	at.buckets[bucket][key] = struct{}{}
+}
+
+func (at *addressTable) remove(bucket int, key addressKey) {
+	delete(at.buckets[bucket], key)
+}
+
+func (at *addressTable) isFull(bucket int) bool {
+	return len(at.buckets[bucket]) >= bucketSize
+}
+
+func (at *addressTable) bucketKeys(bucket int) []addressKey {
+	keys := make([]addressKey, 0, len(at.buckets[bucket]))
+	for key := range at.buckets[bucket] {
+		keys = append(keys, key)
+	}
+	return keys
+}
+
+func (at *addressTable) count() int {
+	count := 0
+	for _, bucket := range at.buckets {
+		count += len(bucket)
+	}
+	return count
+}
+
+// newBucket returns the new table bucket of an address with the given group that
+// was advertised by the given source group. The bucket is keyed by the secret
+// bucketKey, so that an attacker can't choose addresses that land in a specific bucket.
+func newBucket(bucketKey []byte, group string, sourceGroup string) int {
+	groupHash := keyedHash(bucketKey, []byte(group), []byte(sourceGroup)) % newBucketsPerSourceGroup
+	groupHashBytes := make([]byte, 8)
+	binary.LittleEndian.PutUint64(groupHashBytes, groupHash)
+	return int(keyedHash(bucketKey, []byte(sourceGroup), groupHashBytes) % newBucketCount)
+}
+
+// triedBucket returns the tried table bucket of the address with the given key and group
+func triedBucket(bucketKey []byte, key addressKey, group string) int {
+	addressHash := keyedHash(bucketKey, key.address[:], []byte{byte(key.port), byte(key.port >> 8)}) % triedBucketsPerGroup
+	addressHashBytes := make([]byte, 8)
+	binary.LittleEndian.PutUint64(addressHashBytes, addressHash)
+	return int(keyedHash(bucketKey, []byte(group), addressHashBytes) % triedBucketCount)
+}
+
+func keyedHash(bucketKey []byte, parts ...[]byte) uint64 {
+	hasher := sha256.New()
+	hasher.Write(bucketKey)
+	for _, part := range parts {
+		// Length-prefix every part so that different splits of the same bytes hash differently
+		lengthBytes := make([]byte, 8)
+		binary.LittleEndian.PutUint64(lengthBytes, uint64(len(part)))
+		hasher.Write(lengthBytes)
+		hasher.Write(part)
+	}
+	return binary.LittleEndian.Uint64(hasher.Sum(nil))
+}
+
+// worseThan returns whether address is a worse candidate to keep than other. Addresses
+// that failed more connection attempts are worse, and between those that failed the same
+// number of attempts the address that was seen longest ago is worse.
+func (a *address) worseThan(other *address) bool {
+	if a.connectionFailedCount != other.connectionFailedCount {
+		return a.connectionFailedCount > other.connectionFailedCount
+	}
+	return a.netAddress.Timestamp.Before(other.netAddress.Timestamp)
+}
@@ -13,6 +13,7 @@ type Config struct {
 	ExternalIPs      []string
 	Listeners        []string
 	Lookup           func(string) ([]net.IP, error)
+	Whitelists       []*net.IPNet
 }
 
 // NewConfig returns a new address manager Config.
@@ -23,5 +24,6 @@ func NewConfig(cfg *config.Config) *Config {
 		ExternalIPs:      cfg.ExternalIPs,
 		Listeners:        cfg.Listeners,
 		Lookup:           cfg.Lookup,
+		Whitelists:       cfg.Whitelists,
 	}
 }
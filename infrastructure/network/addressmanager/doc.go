@@ -24,6 +24,14 @@ generally helps provide greater peer diversity, and perhaps more importantly,
 drastically reduces the chances an attacker is able to coerce your peer into
 only connecting to nodes they control.
 
+Addresses are kept in a "new" table of addresses we have heard of and a "tried"
+table of addresses we have successfully connected to. The bucket of an address
+in the new table is derived from a secret key, its group, and the group of the
+peer that advertised it, so a single peer can only fill a small number of
+buckets. When a bucket is full its worst address is evicted. The addresses of
+the outgoing peers are also persisted as anchors, which are reconnected to first
+after a restart.
+
 The address manager also understands routability and tries hard to only return
 routable addresses. In addition, it uses the information provided by the caller
 about connected, known good, and attempted addresses to periodically purge
@@ -319,13 +319,22 @@ func reachabilityFrom(localAddress, remoteAddress *appmessage.NetAddress, accept
 		return IsValid(na) && !(IsRFC1918(na) || IsRFC2544(na) ||
 			IsRFC3927(na) || IsRFC4862(na) || IsRFC3849(na) ||
 			IsRFC4843(na) || IsRFC5737(na) || IsRFC6598(na) ||
-			IsLocal(na) || (IsRFC4193(na)))
+			IsLocal(na) || (IsRFC4193(na) && !IsOnionV3(na)))
 	}
 
 	if !IsRoutable(remoteAddress) {
 		return Unreachable
 	}
 
+	// Onion addresses are best advertised to onion peers, and are otherwise
+	// advertised only if there's no better address
+	if IsOnionV3(localAddress) {
+		if IsOnionV3(remoteAddress) {
+			return Private
+		}
+		return Default
+	}
+
 	if IsRFC4380(remoteAddress) {
 		if !IsRoutable(localAddress) {
 			return Default
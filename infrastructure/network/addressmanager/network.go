@@ -5,6 +5,7 @@
 package addressmanager
 
 import (
+	"fmt"
 	"net"
 
 	"github.com/Hoosat-Oy/HTND/app/appmessage"
@@ -39,6 +40,10 @@ var (
 	// by RFC4193 (FC00::/7).
 	rfc4193Net = ipNet("FC00::", 7, 128)
 
+	// onionCatNet defines the IPv6 address block used to support Tor.
+	// Onion addresses are given IPs in this block (FD87:D87E:EB43::/48).
+	onionCatNet = ipNet("fd87:d87e:eb43::", 48, 128)
+
 	// rfc4380Net specifies the IPv6 teredo tunneling over UDP address block
 	// as defined by RFC4380 (2001::/32).
 	rfc4380Net = ipNet("2001::", 32, 128)
@@ -215,11 +220,18 @@ func IsRoutable(na *appmessage.NetAddress, acceptUnroutable bool) bool {
 	return IsValid(na) && !(IsRFC1918(na) || IsRFC2544(na) ||
 		IsRFC3927(na) || IsRFC4862(na) || IsRFC3849(na) ||
 		IsRFC4843(na) || IsRFC5737(na) || IsRFC6598(na) ||
-		IsLocal(na) || (IsRFC4193(na)))
+		IsLocal(na) || (IsRFC4193(na) && !IsOnionV3(na)))
+}
+
+// IsOnionV3 returns whether or not the passed address is the address of a Tor
+// v3 onion service, with an OnionCat IP derived from its public key
+func IsOnionV3(na *appmessage.NetAddress) bool {
+	return na.IsOnionV3() && onionCatNet.Contains(na.IP)
 }
 
 // GroupKey returns a string representing the network group an address is part
-// of. This is the /16 for IPv4, the /32 (/36 for he.net) for IPv6, the string
+// of. This is the /16 for IPv4, the /32 (/36 for he.net) for IPv6, one of 16
+// groups for onion addresses, the string
 // "local" for a local address, and the string "unroutable" for an unroutable
 // address.
 func (am *AddressManager) GroupKey(na *appmessage.NetAddress) string {
@@ -229,6 +241,11 @@ func (am *AddressManager) GroupKey(na *appmessage.NetAddress) string {
 	if !IsRoutable(na, am.cfg.AcceptUnroutable) {
 		return "unroutable"
 	}
+	if IsOnionV3(na) {
+		// Onion services can be created at will, so they're grouped by the first
+		// 4 bits of their public key only, so that all of them fit in 16 groups
+		return fmt.Sprintf("tor:%d", na.OnionV3PublicKey[0]>>4)
+	}
 	if IsIPv4(na) {
 		return na.IP.Mask(net.CIDRMask(16, 32)).String()
 	}
@@ -1,8 +1,10 @@
 package addressmanager
 
 import (
+	"crypto/rand"
 	"encoding/binary"
 	"net"
+	"time"
 
 	"github.com/Hoosat-Oy/HTND/app/appmessage"
 	"github.com/Hoosat-Oy/HTND/infrastructure/db/database"
@@ -11,21 +13,57 @@ import (
 )
 
 var notBannedAddressBucket = database.MakeBucket([]byte("not-banned-addresses"))
-var bannedAddressBucket = database.MakeBucket([]byte("banned-addresses"))
+var banBucket = database.MakeBucket([]byte("bans"))
+var whitelistBucket = database.MakeBucket([]byte("whitelisted-subnets"))
+var anchorAddressBucket = database.MakeBucket([]byte("anchor-addresses"))
+var bucketKeyDatabaseKey = database.MakeBucket([]byte("address-manager")).Key([]byte("bucket-key"))
+
+// legacyBannedAddressBucket holds the bans that were stored before bans had a reason and an
+// expiry time. They are moved to banBucket when the store is loaded.
+var legacyBannedAddressBucket = database.MakeBucket([]byte("banned-addresses"))
+
+// legacyBanDuration is how long the bans in legacyBannedAddressBucket used to last
+const legacyBanDuration = 24 * time.Hour
+
+const bucketKeyLength = 32
 
 type addressStore struct {
 	database           database.Database
 	notBannedAddresses map[addressKey]*address
-	bannedAddresses    map[ipv6]*address
+	bannedAddresses    map[ipv6]*ban
+	whitelist          map[string]*whitelistEntry
+	anchorAddresses    []*appmessage.NetAddress
+	bucketKey          []byte
+}
+
+// ban is a banned IP, along with why and until when it's banned
+type ban struct {
+	address  *address
+	reason   string
+	bannedAt mstime.Time
+	// expiresAt is zero if the ban never expires
+	expiresAt mstime.Time
+}
+
+// whitelistEntry is a subnet whose peers are never banned
+type whitelistEntry struct {
+	subnet  *net.IPNet
+	reason  string
+	addedAt mstime.Time
 }
 
 func newAddressStore(database database.Database) (*addressStore, error) {
 	addressStore := &addressStore{
 		database:           database,
 		notBannedAddresses: map[addressKey]*address{},
-		bannedAddresses:    map[ipv6]*address{},
+		bannedAddresses:    map[ipv6]*ban{},
+		whitelist:          map[string]*whitelistEntry{},
+	}
+	err := addressStore.restoreBucketKey()
+	if err != nil {
+		return nil, err
 	}
-	err := addressStore.restoreNotBannedAddresses()
+	err = addressStore.restoreNotBannedAddresses()
 	if err != nil {
 		return nil, err
 	}
@@ -33,13 +71,44 @@ func newAddressStore(database database.Database) (*addressStore, error) {
 	if err != nil {
 		return nil, err
 	}
+	err = addressStore.restoreWhitelist()
+	if err != nil {
+		return nil, err
+	}
+	err = addressStore.restoreAnchorAddresses()
+	if err != nil {
+		return nil, err
+	}
 
-	log.Infof("Loaded %d addresses and %d banned addresses",
-		len(addressStore.notBannedAddresses), len(addressStore.bannedAddresses))
+	log.Infof("Loaded %d addresses, %d banned addresses, %d whitelisted subnets and %d anchor addresses",
+		len(addressStore.notBannedAddresses), len(addressStore.bannedAddresses), len(addressStore.whitelist),
+		len(addressStore.anchorAddresses))
 
 	return addressStore, nil
 }
 
+// restoreBucketKey loads the secret key the address buckets are keyed by, or
+// generates and stores a new one if this is the first time the store is used.
+// The key is persisted so that addresses keep their buckets across restarts.
+func (as *addressStore) restoreBucketKey() error {
+	bucketKey, err := as.database.Get(bucketKeyDatabaseKey)
+	if err == nil {
+		as.bucketKey = bucketKey
+		return nil
+	}
+	if !database.IsNotFoundError(err) {
+		return err
+	}
+
+	bucketKey = make([]byte, bucketKeyLength)
+	_, err = rand.Read(bucketKey)
+	if err != nil {
+		return err
+	}
+	as.bucketKey = bucketKey
+	return as.database.Put(bucketKeyDatabaseKey, bucketKey)
+}
+
 func (as *addressStore) restoreNotBannedAddresses() error {
 	cursor, err := as.database.Cursor(notBannedAddressBucket)
 	if err != nil {
@@ -65,7 +134,7 @@ func (as *addressStore) restoreNotBannedAddresses() error {
 }
 
 func (as *addressStore) restoreBannedAddresses() error {
-	cursor, err := as.database.Cursor(bannedAddressBucket)
+	cursor, err := as.database.Cursor(banBucket)
 	if err != nil {
 		return err
 	}
@@ -78,18 +147,115 @@ func (as *addressStore) restoreBannedAddresses() error {
 		var ipv6 ipv6
 		copy(ipv6[:], databaseKey.Suffix())
 
-		serializedNetAddress, err := cursor.Value()
+		serializedBan, err := cursor.Value()
 		if err != nil {
 			return err
 		}
-		netAddress := as.deserializeAddress(serializedNetAddress)
-		as.bannedAddresses[ipv6] = netAddress
+		ban, err := as.deserializeBan(serializedBan)
+		if err != nil {
+			return err
+		}
+		as.bannedAddresses[ipv6] = ban
+	}
+	return as.migrateLegacyBannedAddresses()
+}
+
+// migrateLegacyBannedAddresses moves the bans from legacyBannedAddressBucket to banBucket.
+// These bans were made at the timestamp of their address, and last legacyBanDuration.
+func (as *addressStore) migrateLegacyBannedAddresses() error {
+	cursor, err := as.database.Cursor(legacyBannedAddressBucket)
+	if err != nil {
+		return err
+	}
+	var legacyKeys []*database.Key
+	var legacyBans []*ban
+	for ok := cursor.First(); ok; ok = cursor.Next() {
+		databaseKey, err := cursor.Key()
+		if err != nil {
+			cursor.Close()
+			return err
+		}
+		serializedAddress, err := cursor.Value()
+		if err != nil {
+			cursor.Close()
+			return err
+		}
+		address := as.deserializeAddress(serializedAddress)
+		// The key is only valid until the cursor moves, so it's copied
+		suffix := make([]byte, len(databaseKey.Suffix()))
+		copy(suffix, databaseKey.Suffix())
+		legacyKeys = append(legacyKeys, legacyBannedAddressBucket.Key(suffix))
+		legacyBans = append(legacyBans, &ban{
+			address:   address,
+			bannedAt:  address.netAddress.Timestamp,
+			expiresAt: address.netAddress.Timestamp.Add(legacyBanDuration),
+		})
+	}
+	cursor.Close()
+
+	for i, legacyBan := range legacyBans {
+		key := netAddressKey(legacyBan.address.netAddress)
+		if _, ok := as.bannedAddresses[key.address]; !ok {
+			err := as.addBanned(key, legacyBan)
+			if err != nil {
+				return err
+			}
+		}
+		err := as.database.Delete(legacyKeys[i])
+		if err != nil {
+			return err
+		}
+	}
+	if len(legacyBans) > 0 {
+		log.Infof("Migrated %d banned addresses", len(legacyBans))
+	}
+	return nil
+}
+
+func (as *addressStore) restoreWhitelist() error {
+	cursor, err := as.database.Cursor(whitelistBucket)
+	if err != nil {
+		return err
+	}
+	defer cursor.Close()
+	for ok := cursor.First(); ok; ok = cursor.Next() {
+		databaseKey, err := cursor.Key()
+		if err != nil {
+			return err
+		}
+		_, subnet, err := net.ParseCIDR(string(databaseKey.Suffix()))
+		if err != nil {
+			return errors.Wrapf(err, "malformed whitelisted subnet %s", databaseKey.Suffix())
+		}
+
+		serializedEntry, err := cursor.Value()
+		if err != nil {
+			return err
+		}
+		entry, err := as.deserializeWhitelistEntry(subnet, serializedEntry)
+		if err != nil {
+			return err
+		}
+		as.whitelist[subnet.String()] = entry
 	}
 	return nil
 }
 
-func (as *addressStore) notBannedCount() int {
-	return len(as.notBannedAddresses)
+func (as *addressStore) restoreAnchorAddresses() error {
+	cursor, err := as.database.Cursor(anchorAddressBucket)
+	if err != nil {
+		return err
+	}
+	defer cursor.Close()
+	for ok := cursor.First(); ok; ok = cursor.Next() {
+		serializedAddress, err := cursor.Value()
+		if err != nil {
+			return err
+		}
+		anchorAddress := as.deserializeAddress(serializedAddress)
+		as.anchorAddresses = append(as.anchorAddresses, anchorAddress.netAddress)
+	}
+	return nil
 }
 
 func (as *addressStore) add(key addressKey, address *address) error {
@@ -129,14 +295,6 @@ func (as *addressStore) remove(key addressKey) error {
 	return as.database.Delete(databaseKey)
 }
 
-func (as *addressStore) getAllNotBanned() []*address {
-	addresses := make([]*address, 0, len(as.notBannedAddresses))
-	for _, address := range as.notBannedAddresses {
-		addresses = append(addresses, address)
-	}
-	return addresses
-}
-
 func (as *addressStore) getAllNotBannedNetAddresses() []*appmessage.NetAddress {
 	addresses := make([]*appmessage.NetAddress, 0, len(as.notBannedAddresses))
 	for _, address := range as.notBannedAddresses {
@@ -162,16 +320,13 @@ func (as *addressStore) isNotBanned(key addressKey) bool {
 	return ok
 }
 
-func (as *addressStore) addBanned(key addressKey, address *address) error {
-	if _, ok := as.bannedAddresses[key.address]; ok {
-		return nil
-	}
-
-	as.bannedAddresses[key.address] = address
+// addBanned adds the given ban, or replaces the ban of the same IP if there is one
+func (as *addressStore) addBanned(key addressKey, ban *ban) error {
+	as.bannedAddresses[key.address] = ban
 
 	databaseKey := as.bannedDatabaseKey(key)
-	serializedAddress := as.serializeAddress(address)
-	return as.database.Put(databaseKey, serializedAddress)
+	serializedBan := as.serializeBan(ban)
+	return as.database.Put(databaseKey, serializedBan)
 }
 
 func (as *addressStore) removeBanned(key addressKey) error {
@@ -183,20 +338,82 @@ func (as *addressStore) removeBanned(key addressKey) error {
 
 func (as *addressStore) getAllBannedNetAddresses() []*appmessage.NetAddress {
 	bannedAddresses := make([]*appmessage.NetAddress, 0, len(as.bannedAddresses))
-	for _, bannedAddress := range as.bannedAddresses {
-		bannedAddresses = append(bannedAddresses, bannedAddress.netAddress)
+	for _, ban := range as.bannedAddresses {
+		bannedAddresses = append(bannedAddresses, ban.address.netAddress)
 	}
 	return bannedAddresses
 }
 
+func (as *addressStore) getAllBans() []*ban {
+	bans := make([]*ban, 0, len(as.bannedAddresses))
+	for _, ban := range as.bannedAddresses {
+		bans = append(bans, ban)
+	}
+	return bans
+}
+
 func (as *addressStore) isBanned(key addressKey) bool {
 	_, ok := as.bannedAddresses[key.address]
 	return ok
 }
 
-func (as *addressStore) getBanned(key addressKey) (*address, bool) {
-	bannedAddress, ok := as.bannedAddresses[key.address]
-	return bannedAddress, ok
+func (as *addressStore) getBanned(key addressKey) (*ban, bool) {
+	ban, ok := as.bannedAddresses[key.address]
+	return ban, ok
+}
+
+func (as *addressStore) addWhitelistEntry(entry *whitelistEntry) error {
+	as.whitelist[entry.subnet.String()] = entry
+
+	databaseKey := as.whitelistDatabaseKey(entry.subnet)
+	return as.database.Put(databaseKey, as.serializeWhitelistEntry(entry))
+}
+
+func (as *addressStore) removeWhitelistEntry(subnet *net.IPNet) error {
+	delete(as.whitelist, subnet.String())
+
+	return as.database.Delete(as.whitelistDatabaseKey(subnet))
+}
+
+func (as *addressStore) getWhitelistEntry(subnet *net.IPNet) (*whitelistEntry, bool) {
+	entry, ok := as.whitelist[subnet.String()]
+	return entry, ok
+}
+
+func (as *addressStore) getAllWhitelistEntries() []*whitelistEntry {
+	entries := make([]*whitelistEntry, 0, len(as.whitelist))
+	for _, entry := range as.whitelist {
+		entries = append(entries, entry)
+	}
+	return entries
+}
+
+func (as *addressStore) getAnchors() []*appmessage.NetAddress {
+	anchorAddresses := make([]*appmessage.NetAddress, len(as.anchorAddresses))
+	copy(anchorAddresses, as.anchorAddresses)
+	return anchorAddresses
+}
+
+// setAnchors replaces the stored anchor addresses with the given ones
+func (as *addressStore) setAnchors(anchorAddresses []*appmessage.NetAddress) error {
+	for _, anchorAddress := range as.anchorAddresses {
+		err := as.database.Delete(as.anchorDatabaseKey(netAddressKey(anchorAddress)))
+		if err != nil {
+			return err
+		}
+	}
+
+	as.anchorAddresses = make([]*appmessage.NetAddress, 0, len(anchorAddresses))
+	for _, anchorAddress := range anchorAddresses {
+		databaseKey := as.anchorDatabaseKey(netAddressKey(anchorAddress))
+		serializedAddress := as.serializeAddress(&address{netAddress: anchorAddress})
+		err := as.database.Put(databaseKey, serializedAddress)
+		if err != nil {
+			return err
+		}
+		as.anchorAddresses = append(as.anchorAddresses, anchorAddress)
+	}
+	return nil
 }
 
 // netAddressKeys returns a key of the ip address to use it in maps.
@@ -214,8 +431,17 @@ func (as *addressStore) notBannedDatabaseKey(key addressKey) *database.Key {
 	return notBannedAddressBucket.Key(serializedKey)
 }
 
+func (as *addressStore) anchorDatabaseKey(key addressKey) *database.Key {
+	serializedKey := as.serializeAddressKey(key)
+	return anchorAddressBucket.Key(serializedKey)
+}
+
 func (as *addressStore) bannedDatabaseKey(key addressKey) *database.Key {
-	return bannedAddressBucket.Key(key.address[:])
+	return banBucket.Key(key.address[:])
+}
+
+func (as *addressStore) whitelistDatabaseKey(subnet *net.IPNet) *database.Key {
+	return whitelistBucket.Key([]byte(subnet.String()))
 }
 
 func (as *addressStore) serializeAddressKey(key addressKey) []byte {
@@ -240,14 +466,27 @@ func (as *addressStore) deserializeAddressKey(serializedKey []byte) addressKey {
 	}
 }
 
+// legacySerializedAddressSize is the size of addresses that were serialized
+// before the source and tried fields were added
+const legacySerializedAddressSize = 16 + 2 + 8 + 8 // ipv6 + port + timestamp + connectionFailedCount
+
+// serializedAddressSize is the size of addresses that are reached by IP. Onion
+// addresses are followed by their public key.
+const serializedAddressSize = legacySerializedAddressSize + 16 + 1 // source ipv6 + tried
+
 func (as *addressStore) serializeAddress(address *address) []byte {
-	serializedSize := 16 + 2 + 8 + 8 // ipv6 + port + timestamp + connectionFailedCount
+	serializedSize := serializedAddressSize + len(address.netAddress.OnionV3PublicKey)
 	serializedNetAddress := make([]byte, serializedSize)
 
 	copy(serializedNetAddress[:], address.netAddress.IP.To16()[:])
 	binary.LittleEndian.PutUint16(serializedNetAddress[16:], address.netAddress.Port)
 	binary.LittleEndian.PutUint64(serializedNetAddress[18:], uint64(address.netAddress.Timestamp.UnixMilliseconds()))
 	binary.LittleEndian.PutUint64(serializedNetAddress[26:], uint64(address.connectionFailedCount))
+	copy(serializedNetAddress[34:], address.source[:])
+	if address.tried {
+		serializedNetAddress[50] = 1
+	}
+	copy(serializedNetAddress[serializedAddressSize:], address.netAddress.OnionV3PublicKey)
 
 	return serializedNetAddress
 }
@@ -260,12 +499,100 @@ func (as *addressStore) deserializeAddress(serializedAddress []byte) *address {
 	timestamp := mstime.UnixMilliseconds(int64(binary.LittleEndian.Uint64(serializedAddress[18:])))
 	connectionFailedCount := binary.LittleEndian.Uint64(serializedAddress[26:])
 
+	var source ipv6
+	var tried bool
+	if len(serializedAddress) > legacySerializedAddressSize {
+		copy(source[:], serializedAddress[34:])
+		tried = serializedAddress[50] == 1
+	} else {
+		// Addresses stored before sources were recorded are treated as their own
+		// source, and those that were connected successfully are considered tried
+		copy(source[:], ip)
+		tried = connectionFailedCount == 0
+	}
+
+	var onionV3PublicKey []byte
+	if len(serializedAddress) == serializedAddressSize+appmessage.OnionV3PublicKeySize {
+		onionV3PublicKey = make([]byte, appmessage.OnionV3PublicKeySize)
+		copy(onionV3PublicKey, serializedAddress[serializedAddressSize:])
+	}
+
 	return &address{
 		netAddress: &appmessage.NetAddress{
-			IP:        ip,
-			Port:      port,
-			Timestamp: timestamp,
+			IP:               ip,
+			Port:             port,
+			Timestamp:        timestamp,
+			OnionV3PublicKey: onionV3PublicKey,
 		},
 		connectionFailedCount: connectionFailedCount,
+		source:                source,
+		tried:                 tried,
+	}
+}
+
+// serializeTime serializes t as milliseconds since the epoch, and the zero time as 0
+func serializeTime(t mstime.Time) uint64 {
+	if t.IsZero() {
+		return 0
+	}
+	return uint64(t.UnixMilliseconds())
+}
+
+func deserializeTime(milliseconds uint64) mstime.Time {
+	if milliseconds == 0 {
+		return mstime.Time{}
+	}
+	return mstime.UnixMilliseconds(int64(milliseconds))
+}
+
+// serializeBan serializes a ban as its ban time, its expiry time, the length of its
+// reason and its reason, followed by its serialized address
+func (as *addressStore) serializeBan(ban *ban) []byte {
+	serializedAddress := as.serializeAddress(ban.address)
+	serializedBan := make([]byte, 8+8+2+len(ban.reason)+len(serializedAddress))
+
+	binary.LittleEndian.PutUint64(serializedBan[0:], serializeTime(ban.bannedAt))
+	binary.LittleEndian.PutUint64(serializedBan[8:], serializeTime(ban.expiresAt))
+	binary.LittleEndian.PutUint16(serializedBan[16:], uint16(len(ban.reason)))
+	copy(serializedBan[18:], ban.reason)
+	copy(serializedBan[18+len(ban.reason):], serializedAddress)
+
+	return serializedBan
+}
+
+func (as *addressStore) deserializeBan(serializedBan []byte) (*ban, error) {
+	if len(serializedBan) < 18 {
+		return nil, errors.Errorf("malformed ban of length %d", len(serializedBan))
+	}
+	reasonLength := int(binary.LittleEndian.Uint16(serializedBan[16:]))
+	if len(serializedBan) < 18+reasonLength+legacySerializedAddressSize {
+		return nil, errors.Errorf("malformed ban of length %d", len(serializedBan))
+	}
+
+	return &ban{
+		bannedAt:  deserializeTime(binary.LittleEndian.Uint64(serializedBan[0:])),
+		expiresAt: deserializeTime(binary.LittleEndian.Uint64(serializedBan[8:])),
+		reason:    string(serializedBan[18 : 18+reasonLength]),
+		address:   as.deserializeAddress(serializedBan[18+reasonLength:]),
+	}, nil
+}
+
+// serializeWhitelistEntry serializes a whitelist entry as the time it was added followed
+// by its reason. The subnet itself is the database key.
+func (as *addressStore) serializeWhitelistEntry(entry *whitelistEntry) []byte {
+	serializedEntry := make([]byte, 8+len(entry.reason))
+	binary.LittleEndian.PutUint64(serializedEntry, serializeTime(entry.addedAt))
+	copy(serializedEntry[8:], entry.reason)
+	return serializedEntry
+}
+
+func (as *addressStore) deserializeWhitelistEntry(subnet *net.IPNet, serializedEntry []byte) (*whitelistEntry, error) {
+	if len(serializedEntry) < 8 {
+		return nil, errors.Errorf("malformed whitelist entry of length %d", len(serializedEntry))
 	}
+	return &whitelistEntry{
+		subnet:  subnet,
+		addedAt: deserializeTime(binary.LittleEndian.Uint64(serializedEntry)),
+		reason:  string(serializedEntry[8:]),
+	}, nil
 }
@@ -37,6 +37,8 @@ func TestAddressSerialization(t *testing.T) {
 			Timestamp: mstime.Now(),
 		},
 		connectionFailedCount: 98465,
+		source:                ipv6{0x26, 0x02, 0x01},
+		tried:                 true,
 	}
 
 	serializedTestAddress := addressStore.serializeAddress(testAddress)
@@ -46,3 +48,31 @@ func TestAddressSerialization(t *testing.T) {
 			"testAddress:%+v\ndeserializedTestAddress:%+v", testAddress, deserializedTestAddress)
 	}
 }
+
+func TestOnionAddressSerialization(t *testing.T) {
+	addressManager, teardown := newAddressManagerForTest(t, "TestOnionAddressSerialization")
+	defer teardown()
+	addressStore := addressManager.store
+
+	publicKey, err := appmessage.ParseOnionV3Host("pg6mmjiyjmcrsslvykfwnntlaru7p5svn6y2ymmju6nubxndf4pscryd.onion")
+	if err != nil {
+		t.Fatalf("ParseOnionV3Host: %s", err)
+	}
+	onionAddress := appmessage.NewNetAddressOnionV3(publicKey, 42421)
+	if !IsRoutable(onionAddress, false) {
+		t.Fatalf("Onion address %s is unexpectedly not routable", onionAddress)
+	}
+
+	testAddress := &address{
+		netAddress:            onionAddress,
+		connectionFailedCount: 1,
+	}
+	copy(testAddress.source[:], onionAddress.IP)
+
+	serializedTestAddress := addressStore.serializeAddress(testAddress)
+	deserializedTestAddress := addressStore.deserializeAddress(serializedTestAddress)
+	if !reflect.DeepEqual(testAddress, deserializedTestAddress) {
+		t.Fatalf("testAddress and deserializedTestAddress are not equal\n"+
+			"testAddress:%+v\ndeserializedTestAddress:%+v", testAddress, deserializedTestAddress)
+	}
+}
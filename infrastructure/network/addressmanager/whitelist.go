@@ -0,0 +1,102 @@
+package addressmanager
+
+import (
+	"net"
+	"sort"
+
+	"github.com/Hoosat-Oy/HTND/util/mstime"
+	"github.com/pkg/errors"
+)
+
+// ErrWhitelistedInConfig is returned when trying to remove a subnet that was whitelisted
+// with --whitelist, which can only be removed by changing the configuration
+var ErrWhitelistedInConfig = errors.New("subnet is whitelisted in the configuration")
+
+// WhitelistEntry is a subnet whose peers are never banned
+type WhitelistEntry struct {
+	Subnet  *net.IPNet
+	Reason  string
+	AddedAt mstime.Time
+	// IsFromConfig is true for the subnets that were whitelisted with --whitelist
+	IsFromConfig bool
+}
+
+// IsWhitelisted returns whether ip belongs to a whitelisted subnet, either from the
+// configuration or from the persisted whitelist
+func (am *AddressManager) IsWhitelisted(ip net.IP) bool {
+	for _, subnet := range am.cfg.Whitelists {
+		if subnet.Contains(ip) {
+			return true
+		}
+	}
+
+	am.mutex.Lock()
+	defer am.mutex.Unlock()
+
+	for _, entry := range am.store.getAllWhitelistEntries() {
+		if entry.subnet.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// Whitelist returns all the whitelisted subnets. The ones from the configuration come
+// first, followed by the persisted ones in the order they were added.
+func (am *AddressManager) Whitelist() []*WhitelistEntry {
+	whitelist := make([]*WhitelistEntry, 0, len(am.cfg.Whitelists))
+	for _, subnet := range am.cfg.Whitelists {
+		whitelist = append(whitelist, &WhitelistEntry{Subnet: subnet, IsFromConfig: true})
+	}
+
+	am.mutex.Lock()
+	defer am.mutex.Unlock()
+
+	entries := am.store.getAllWhitelistEntries()
+	sort.Slice(entries, func(i, j int) bool {
+		if entries[i].addedAt.UnixMilliseconds() != entries[j].addedAt.UnixMilliseconds() {
+			return entries[i].addedAt.Before(entries[j].addedAt)
+		}
+		return entries[i].subnet.String() < entries[j].subnet.String()
+	})
+	for _, entry := range entries {
+		whitelist = append(whitelist, &WhitelistEntry{
+			Subnet:  entry.subnet,
+			Reason:  entry.reason,
+			AddedAt: entry.addedAt,
+		})
+	}
+	return whitelist
+}
+
+// AddToWhitelist persistently whitelists the given subnet. If the subnet is already
+// whitelisted, its reason is replaced.
+func (am *AddressManager) AddToWhitelist(subnet *net.IPNet, reason string) error {
+	am.mutex.Lock()
+	defer am.mutex.Unlock()
+
+	if len(reason) > maxBanReasonLength {
+		reason = reason[:maxBanReasonLength]
+	}
+	return am.store.addWhitelistEntry(&whitelistEntry{
+		subnet:  subnet,
+		reason:  reason,
+		addedAt: mstime.Now(),
+	})
+}
+
+// RemoveFromWhitelist removes the given subnet from the persisted whitelist
+func (am *AddressManager) RemoveFromWhitelist(subnet *net.IPNet) error {
+	am.mutex.Lock()
+	defer am.mutex.Unlock()
+
+	if _, ok := am.store.getWhitelistEntry(subnet); !ok {
+		for _, configSubnet := range am.cfg.Whitelists {
+			if configSubnet.String() == subnet.String() {
+				return errors.Wrapf(ErrWhitelistedInConfig, "cannot remove %s", subnet)
+			}
+		}
+		return errors.Wrapf(ErrAddressNotFound, "subnet %s is not whitelisted", subnet)
+	}
+	return am.store.removeWhitelistEntry(subnet)
+}
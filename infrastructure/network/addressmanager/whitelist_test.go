@@ -0,0 +1,207 @@
+package addressmanager
+
+import (
+	"net"
+	"testing"
+	"time"
+
+	"github.com/Hoosat-Oy/HTND/app/appmessage"
+	"github.com/Hoosat-Oy/HTND/infrastructure/config"
+	"github.com/Hoosat-Oy/HTND/infrastructure/db/database/ldb"
+	"github.com/Hoosat-Oy/HTND/util/mstime"
+	"github.com/pkg/errors"
+)
+
+func TestBanExpiryAndReason(t *testing.T) {
+	addressManager, teardown := newAddressManagerForTest(t, "TestBanExpiryAndReason")
+	defer teardown()
+
+	permanentlyBanned := appmessage.NewNetAddressIPPort(net.ParseIP("1.2.3.4"), 0)
+	temporarilyBanned := appmessage.NewNetAddressIPPort(net.ParseIP("5.6.7.8"), 0)
+	err := addressManager.Ban(permanentlyBanned, 0, "sent invalid blocks")
+	if err != nil {
+		t.Fatalf("Ban: %s", err)
+	}
+	err = addressManager.Ban(temporarilyBanned, 50*time.Millisecond, "spam")
+	if err != nil {
+		t.Fatalf("Ban: %s", err)
+	}
+
+	bans, err := addressManager.Bans()
+	if err != nil {
+		t.Fatalf("Bans: %s", err)
+	}
+	if len(bans) != 2 {
+		t.Fatalf("expected 2 bans, got %d", len(bans))
+	}
+	if bans[0].Reason != "sent invalid blocks" || !bans[0].ExpiresAt.IsZero() {
+		t.Fatalf("unexpected permanent ban %+v", bans[0])
+	}
+	if bans[1].Reason != "spam" || bans[1].ExpiresAt.IsZero() {
+		t.Fatalf("unexpected temporary ban %+v", bans[1])
+	}
+
+	time.Sleep(100 * time.Millisecond)
+
+	isBanned, err := addressManager.IsBanned(temporarilyBanned)
+	if err != nil && !errors.Is(err, ErrAddressNotFound) {
+		t.Fatalf("IsBanned: %s", err)
+	}
+	if isBanned {
+		t.Fatalf("the ban of %s didn't expire", temporarilyBanned.IP)
+	}
+	bans, err = addressManager.Bans()
+	if err != nil {
+		t.Fatalf("Bans: %s", err)
+	}
+	if len(bans) != 1 || !bans[0].Address.IP.Equal(permanentlyBanned.IP) {
+		t.Fatalf("expected only the permanent ban to be left, got %d bans", len(bans))
+	}
+}
+
+func TestBansAndWhitelistPersistence(t *testing.T) {
+	cfg := config.DefaultConfig()
+	configSubnet, err := config.ParseWhitelist("10.0.0.0/8")
+	if err != nil {
+		t.Fatalf("ParseWhitelist: %s", err)
+	}
+	cfg.Whitelists = []*net.IPNet{configSubnet}
+
+	datadir := t.TempDir()
+	database, err := ldb.NewLevelDB(datadir, 8)
+	if err != nil {
+		t.Fatalf("Could not create a database: %s", err)
+	}
+	addressManager, err := New(NewConfig(cfg), database)
+	if err != nil {
+		t.Fatalf("Error creating address manager: %s", err)
+	}
+
+	bannedAddress := appmessage.NewNetAddressIPPort(net.ParseIP("1.2.3.4"), 0)
+	err = addressManager.Ban(bannedAddress, time.Hour, "sent invalid blocks")
+	if err != nil {
+		t.Fatalf("Ban: %s", err)
+	}
+	subnet, err := config.ParseWhitelist("192.168.0.0/24")
+	if err != nil {
+		t.Fatalf("ParseWhitelist: %s", err)
+	}
+	err = addressManager.AddToWhitelist(subnet, "local network")
+	if err != nil {
+		t.Fatalf("AddToWhitelist: %s", err)
+	}
+	singleIP, err := config.ParseWhitelist("2001:db8::1")
+	if err != nil {
+		t.Fatalf("ParseWhitelist: %s", err)
+	}
+	err = addressManager.AddToWhitelist(singleIP, "")
+	if err != nil {
+		t.Fatalf("AddToWhitelist: %s", err)
+	}
+
+	err = database.Close()
+	if err != nil {
+		t.Fatalf("Close: %s", err)
+	}
+	database, err = ldb.NewLevelDB(datadir, 8)
+	if err != nil {
+		t.Fatalf("Could not create a database: %s", err)
+	}
+	defer database.Close()
+	addressManager, err = New(NewConfig(cfg), database)
+	if err != nil {
+		t.Fatalf("Error creating address manager: %s", err)
+	}
+
+	bans, err := addressManager.Bans()
+	if err != nil {
+		t.Fatalf("Bans: %s", err)
+	}
+	if len(bans) != 1 || bans[0].Reason != "sent invalid blocks" || bans[0].ExpiresAt.IsZero() {
+		t.Fatalf("the ban wasn't restored correctly: %+v", bans)
+	}
+
+	for _, test := range []struct {
+		ip            string
+		isWhitelisted bool
+	}{
+		{ip: "10.1.2.3", isWhitelisted: true},
+		{ip: "192.168.0.17", isWhitelisted: true},
+		{ip: "192.168.1.17", isWhitelisted: false},
+		{ip: "2001:db8::1", isWhitelisted: true},
+		{ip: "2001:db8::2", isWhitelisted: false},
+	} {
+		if addressManager.IsWhitelisted(net.ParseIP(test.ip)) != test.isWhitelisted {
+			t.Fatalf("expected IsWhitelisted(%s) to be %t", test.ip, test.isWhitelisted)
+		}
+	}
+
+	whitelist := addressManager.Whitelist()
+	if len(whitelist) != 3 {
+		t.Fatalf("expected 3 whitelisted subnets, got %d", len(whitelist))
+	}
+	if !whitelist[0].IsFromConfig || whitelist[0].Subnet.String() != "10.0.0.0/8" {
+		t.Fatalf("expected the subnet from the configuration to come first, got %s", whitelist[0].Subnet)
+	}
+	if whitelist[1].Subnet.String() != "192.168.0.0/24" || whitelist[1].Reason != "local network" {
+		t.Fatalf("unexpected whitelist entry %+v", whitelist[1])
+	}
+
+	err = addressManager.RemoveFromWhitelist(configSubnet)
+	if !errors.Is(err, ErrWhitelistedInConfig) {
+		t.Fatalf("expected ErrWhitelistedInConfig, got %v", err)
+	}
+	err = addressManager.RemoveFromWhitelist(subnet)
+	if err != nil {
+		t.Fatalf("RemoveFromWhitelist: %s", err)
+	}
+	err = addressManager.RemoveFromWhitelist(subnet)
+	if !errors.Is(err, ErrAddressNotFound) {
+		t.Fatalf("expected ErrAddressNotFound, got %v", err)
+	}
+	if addressManager.IsWhitelisted(net.ParseIP("192.168.0.17")) {
+		t.Fatalf("192.168.0.17 is still whitelisted after its subnet was removed")
+	}
+}
+
+func TestLegacyBanMigration(t *testing.T) {
+	addressManager, teardown := newAddressManagerForTest(t, "TestLegacyBanMigration")
+	defer teardown()
+	store := addressManager.store
+
+	recentBan := &address{netAddress: &appmessage.NetAddress{IP: net.ParseIP("1.2.3.4"), Timestamp: mstime.Now()}}
+	oldBan := &address{netAddress: &appmessage.NetAddress{IP: net.ParseIP("5.6.7.8"),
+		Timestamp: mstime.Now().Add(-2 * legacyBanDuration)}}
+	for _, legacyBan := range []*address{recentBan, oldBan} {
+		key := netAddressKey(legacyBan.netAddress)
+		err := store.database.Put(legacyBannedAddressBucket.Key(key.address[:]), store.serializeAddress(legacyBan))
+		if err != nil {
+			t.Fatalf("Put: %s", err)
+		}
+	}
+
+	err := store.restoreBannedAddresses()
+	if err != nil {
+		t.Fatalf("restoreBannedAddresses: %s", err)
+	}
+
+	bans, err := addressManager.Bans()
+	if err != nil {
+		t.Fatalf("Bans: %s", err)
+	}
+	if len(bans) != 1 || !bans[0].Address.IP.Equal(recentBan.netAddress.IP) {
+		t.Fatalf("expected only the recent legacy ban to be left, got %d bans", len(bans))
+	}
+	if bans[0].ExpiresAt.UnixMilliseconds() != recentBan.netAddress.Timestamp.Add(legacyBanDuration).UnixMilliseconds() {
+		t.Fatalf("unexpected expiry time %s for a legacy ban", bans[0].ExpiresAt)
+	}
+
+	cursor, err := store.database.Cursor(legacyBannedAddressBucket)
+	if err != nil {
+		t.Fatalf("Cursor: %s", err)
+	}
+	defer cursor.Close()
+	if cursor.First() {
+		t.Fatalf("legacy bans were left in the database after their migration")
+	}
+}
@@ -34,11 +34,17 @@ type ConnectionManager struct {
 
 	activeRequested  map[string]*connectionRequest
 	pendingRequested map[string]*connectionRequest
-	activeOutgoing   map[string]struct{}
+	activeOutgoing   map[string]*appmessage.NetAddress
 	targetOutgoing   int
 	activeIncoming   map[string]struct{}
 	maxIncoming      int
 
+	// pendingAnchors are the anchor addresses loaded on startup that weren't connected to yet
+	pendingAnchors []*appmessage.NetAddress
+
+	peerInfoProvider PeerInfoProvider
+	netGroupKey      []byte
+
 	stop                   uint32
 	connectionRequestsLock sync.RWMutex
 
@@ -54,7 +60,9 @@ func New(cfg *config.Config, netAdapter *netadapter.NetAdapter, addressManager *
 		addressManager:   addressManager,
 		activeRequested:  map[string]*connectionRequest{},
 		pendingRequested: map[string]*connectionRequest{},
-		activeOutgoing:   map[string]struct{}{},
+		activeOutgoing:   map[string]*appmessage.NetAddress{},
+		pendingAnchors:   addressManager.Anchors(),
+		netGroupKey:      newNetGroupKey(),
 		activeIncoming:   map[string]struct{}{},
 		resetLoopChan:    make(chan struct{}),
 		loopTicker:       time.NewTicker(connectionsLoopInterval),
@@ -136,17 +144,18 @@ func (c *ConnectionManager) ConnectionCount() int {
 // ErrCannotBanPermanent is the error returned when trying to ban a permanent peer.
 var ErrCannotBanPermanent = errors.New("ErrCannotBanPermanent")
 
-// Ban marks the given netConnection as banned
-func (c *ConnectionManager) Ban(netConnection *netadapter.NetConnection) error {
+// Ban marks the given netConnection as banned for --banduration because of the given reason
+func (c *ConnectionManager) Ban(netConnection *netadapter.NetConnection, reason string) error {
 	if c.isPermanent(netConnection.Address()) {
 		return errors.Wrapf(ErrCannotBanPermanent, "Cannot ban %s because it's a permanent connection", netConnection.Address())
 	}
 
-	return c.addressManager.Ban(netConnection.NetAddress())
+	return c.addressManager.Ban(netConnection.NetAddress(), c.cfg.BanDuration, reason)
 }
 
-// BanByIP bans the given IP and disconnects from all the connection with that IP.
-func (c *ConnectionManager) BanByIP(ip net.IP) error {
+// BanByIP bans the given IP for the given duration, or forever if duration is 0, and
+// disconnects from all the connection with that IP.
+func (c *ConnectionManager) BanByIP(ip net.IP, duration time.Duration, reason string) error {
 	ipHasPermanentConnection, err := c.ipHasPermanentConnection(ip)
 	if err != nil {
 		return err
@@ -163,7 +172,7 @@ func (c *ConnectionManager) BanByIP(ip net.IP) error {
 		}
 	}
 
-	return c.addressManager.Ban(appmessage.NewNetAddressIPPort(ip, 0))
+	return c.addressManager.Ban(appmessage.NewNetAddressIPPort(ip, 0), duration, reason)
 }
 
 // IsBanned returns whether the given netConnection is banned
@@ -261,12 +270,21 @@ func (c *ConnectionManager) seedFromDNS() {
 				// Hoosatd uses a lookup of the dns seeder here. Since seeder returns
 				// IPs of nodes and not its own IP, we can not know real IP of
 				// source. So we'll take first returned address as source.
-				_ = c.addressManager.AddAddresses(addresses...)
+				if len(addresses) == 0 {
+					return
+				}
+				_ = c.addressManager.AddAddressesFromSource(addresses[0], addresses...)
 			})
 
 		dnsseed.SeedFromGRPC(cfg.NetParams(), cfg.GRPCSeed, false, nil,
 			func(addresses []*appmessage.NetAddress) {
-				_ = c.addressManager.AddAddresses(addresses...)
+				// Like the DNS seeder, the gRPC seeder returns the IPs of nodes
+				// and not its own IP, so the first returned address is taken
+				// as the source.
+				if len(addresses) == 0 {
+					return
+				}
+				_ = c.addressManager.AddAddressesFromSource(addresses[0], addresses...)
 			})
 	}
 }
@@ -0,0 +1,193 @@
+package connmanager
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"sort"
+	"time"
+
+	"github.com/Hoosat-Oy/HTND/infrastructure/network/netadapter"
+)
+
+const (
+	// evictionProtectedByNetGroup is the number of inbound peers with distinct
+	// network groups that are protected from eviction
+	evictionProtectedByNetGroup = 4
+
+	// evictionProtectedByPing is the number of inbound peers with the lowest ping
+	// that are protected from eviction
+	evictionProtectedByPing = 8
+
+	// evictionProtectedByNovelBlocks is the number of inbound peers that most
+	// recently relayed a block we didn't have that are protected from eviction
+	evictionProtectedByNovelBlocks = 4
+)
+
+// PeerInfo is the information about a connected peer that inbound eviction is based on
+type PeerInfo struct {
+	LastPingDuration   time.Duration
+	LastNovelBlockTime time.Time
+	TimeConnected      time.Duration
+}
+
+// PeerInfoProvider provides the PeerInfo of connected peers
+type PeerInfoProvider interface {
+	// PeerInfo returns the PeerInfo of the peer of the given connection, or false
+	// if the connection didn't complete its handshake yet
+	PeerInfo(connection *netadapter.NetConnection) (*PeerInfo, bool)
+}
+
+// SetPeerInfoProvider sets the provider of the peer information used to choose
+// which inbound peer to evict once there are too many
+func (c *ConnectionManager) SetPeerInfoProvider(peerInfoProvider PeerInfoProvider) {
+	c.peerInfoProvider = peerInfoProvider
+}
+
+type evictionCandidate struct {
+	connection    *netadapter.NetConnection
+	netGroup      string
+	netGroupHash  uint64
+	info          PeerInfo
+	hasCompleted  bool
+	lastPingKnown bool
+}
+
+func (c *ConnectionManager) evictionCandidates(incomingConnectionSet connectionSet) []*evictionCandidate {
+	candidates := make([]*evictionCandidate, 0, len(incomingConnectionSet))
+	for _, connection := range incomingConnectionSet {
+		netGroup := c.addressManager.GroupKey(connection.NetAddress())
+		candidate := &evictionCandidate{
+			connection:   connection,
+			netGroup:     netGroup,
+			netGroupHash: keyedNetGroupHash(c.netGroupKey, netGroup),
+		}
+		if c.peerInfoProvider != nil {
+			info, ok := c.peerInfoProvider.PeerInfo(connection)
+			if ok {
+				candidate.info = *info
+				candidate.hasCompleted = true
+				candidate.lastPingKnown = info.LastPingDuration > 0
+			}
+		}
+		candidates = append(candidates, candidate)
+	}
+	return candidates
+}
+
+// selectConnectionToEvict chooses the inbound connection to disconnect when there are
+// too many of them. The peers that are hardest for an attacker to imitate are protected
+// first: a few peers of distinct network groups, the peers with the lowest ping, the
+// peers that recently relayed blocks we didn't have, and then the longest connected
+// half of the rest. Of the remaining peers, the most recently connected one from the
+// network group with the most connections is evicted. If all the peers are protected,
+// the most recently connected peer is evicted.
+func selectConnectionToEvict(allCandidates []*evictionCandidate) *evictionCandidate {
+	if len(allCandidates) == 0 {
+		return nil
+	}
+
+	candidates := append([]*evictionCandidate{}, allCandidates...)
+
+	// The network group hashes are keyed by a secret, so an attacker can't choose
+	// network groups that are protected
+	sort.Slice(candidates, func(i, j int) bool {
+		return candidates[i].netGroupHash > candidates[j].netGroupHash
+	})
+	candidates = protectDistinctNetGroups(candidates, evictionProtectedByNetGroup)
+
+	sort.SliceStable(candidates, func(i, j int) bool {
+		if candidates[i].lastPingKnown != candidates[j].lastPingKnown {
+			return candidates[i].lastPingKnown
+		}
+		return candidates[i].info.LastPingDuration < candidates[j].info.LastPingDuration
+	})
+	candidates = protect(candidates, evictionProtectedByPing, func(candidate *evictionCandidate) bool {
+		return candidate.lastPingKnown
+	})
+
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].info.LastNovelBlockTime.After(candidates[j].info.LastNovelBlockTime)
+	})
+	candidates = protect(candidates, evictionProtectedByNovelBlocks, func(candidate *evictionCandidate) bool {
+		return !candidate.info.LastNovelBlockTime.IsZero()
+	})
+
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].info.TimeConnected > candidates[j].info.TimeConnected
+	})
+	candidates = protect(candidates, len(candidates)/2, func(candidate *evictionCandidate) bool {
+		return candidate.hasCompleted
+	})
+
+	if len(candidates) == 0 {
+		youngest := allCandidates[0]
+		for _, candidate := range allCandidates[1:] {
+			if candidate.info.TimeConnected < youngest.info.TimeConnected {
+				youngest = candidate
+			}
+		}
+		return youngest
+	}
+
+	connectionsByNetGroup := make(map[string][]*evictionCandidate)
+	for _, candidate := range candidates {
+		connectionsByNetGroup[candidate.netGroup] = append(connectionsByNetGroup[candidate.netGroup], candidate)
+	}
+
+	// The candidates are sorted from the longest connected to the most recently connected,
+	// so the last candidate of every network group is its most recently connected one
+	var evictedGroup []*evictionCandidate
+	for _, candidate := range candidates {
+		group := connectionsByNetGroup[candidate.netGroup]
+		if len(group) > len(evictedGroup) ||
+			(len(group) == len(evictedGroup) &&
+				group[len(group)-1].info.TimeConnected < evictedGroup[len(evictedGroup)-1].info.TimeConnected) {
+			evictedGroup = group
+		}
+	}
+	return evictedGroup[len(evictedGroup)-1]
+}
+
+// protect removes up to count candidates that satisfy the given condition from the
+// start of the sorted candidates, and returns the rest
+func protect(candidates []*evictionCandidate, count int,
+	condition func(candidate *evictionCandidate) bool) []*evictionCandidate {
+
+	remaining := make([]*evictionCandidate, 0, len(candidates))
+	for _, candidate := range candidates {
+		if count > 0 && condition(candidate) {
+			count--
+			continue
+		}
+		remaining = append(remaining, candidate)
+	}
+	return remaining
+}
+
+// protectDistinctNetGroups removes the first candidate of each of the first count distinct
+// network groups from the sorted candidates, and returns the rest
+func protectDistinctNetGroups(candidates []*evictionCandidate, count int) []*evictionCandidate {
+	protectedNetGroups := make(map[string]struct{}, count)
+	return protect(candidates, count, func(candidate *evictionCandidate) bool {
+		if _, ok := protectedNetGroups[candidate.netGroup]; ok {
+			return false
+		}
+		protectedNetGroups[candidate.netGroup] = struct{}{}
+		return true
+	})
+}
+
+func newNetGroupKey() []byte {
+	netGroupKey := make([]byte, 32)
+	_, err := rand.Read(netGroupKey)
+	if err != nil {
+		panic(err)
+	}
+	return netGroupKey
+}
+
+func keyedNetGroupHash(netGroupKey []byte, netGroup string) uint64 {
+	hash := sha256.Sum256(append(append([]byte{}, netGroupKey...), netGroup...))
+	return binary.LittleEndian.Uint64(hash[:])
+}
@@ -0,0 +1,68 @@
+package connmanager
+
+import (
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestSelectConnectionToEvict(t *testing.T) {
+	netGroupKey := newNetGroupKey()
+	newCandidate := func(netGroup string, info PeerInfo) *evictionCandidate {
+		return &evictionCandidate{
+			netGroup:      netGroup,
+			netGroupHash:  keyedNetGroupHash(netGroupKey, netGroup),
+			info:          info,
+			hasCompleted:  true,
+			lastPingKnown: info.LastPingDuration > 0,
+		}
+	}
+
+	// Honest peers from distinct network groups that have been connected for a while
+	var candidates []*evictionCandidate
+	honestCandidates := make(map[*evictionCandidate]bool)
+	for i := 0; i < 10; i++ {
+		candidate := newCandidate(fmt.Sprintf("10.%d.0.0", i), PeerInfo{
+			LastPingDuration: time.Duration(100+i) * time.Millisecond,
+			TimeConnected:    time.Duration(10+i) * time.Hour,
+		})
+		candidates = append(candidates, candidate)
+		honestCandidates[candidate] = true
+	}
+
+	// An attacker that connected many times from a single network group
+	for i := 0; i < 10; i++ {
+		candidates = append(candidates, newCandidate("6.6.0.0", PeerInfo{
+			LastPingDuration: time.Second,
+			TimeConnected:    time.Duration(i) * time.Minute,
+		}))
+	}
+
+	// A peer from the attacker's network group that relayed a block we didn't have
+	novelBlockRelayer := newCandidate("6.6.0.0", PeerInfo{
+		LastNovelBlockTime: time.Now(),
+		TimeConnected:      time.Minute,
+	})
+	candidates = append(candidates, novelBlockRelayer)
+
+	// While most peers are protected, the attacker's connections are evicted first
+	for i := 0; i < 5; i++ {
+		evicted := selectConnectionToEvict(candidates)
+		if evicted == nil {
+			t.Fatalf("No connection was selected for eviction")
+		}
+		if honestCandidates[evicted] {
+			t.Fatalf("An honest peer from %s was evicted while attacker connections remained", evicted.netGroup)
+		}
+		if evicted == novelBlockRelayer {
+			t.Fatalf("A peer that relayed a novel block was evicted")
+		}
+
+		for j, candidate := range candidates {
+			if candidate == evicted {
+				candidates = append(candidates[:j], candidates[j+1:]...)
+				break
+			}
+		}
+	}
+}
@@ -1,7 +1,7 @@
 package connmanager
 
 // checkIncomingConnections makes sure there's no more than maxIncoming incoming connections
-// if there are - it randomly disconnects enough to go below that number
+// if there are - it evicts enough of them to go below that number
 func (c *ConnectionManager) checkIncomingConnections(incomingConnectionSet connectionSet) {
 	if len(incomingConnectionSet) <= c.maxIncoming {
 		return
@@ -11,14 +11,22 @@ func (c *ConnectionManager) checkIncomingConnections(incomingConnectionSet conne
 	log.Debugf("Got %d incoming connections while only %d are allowed. Disconnecting "+
 		"%d", len(incomingConnectionSet), c.maxIncoming, numConnectionsOverMax)
 
-	// randomly disconnect nodes until the number of incoming connections is smaller than maxIncoming
-	for _, connection := range incomingConnectionSet {
-		log.Debugf("Disconnecting %s due to exceeding incoming connections", connection)
-		connection.Disconnect()
-
-		numConnectionsOverMax--
-		if numConnectionsOverMax == 0 {
+	// evict nodes one at a time, so that the protections are recalculated for every eviction
+	candidates := c.evictionCandidates(incomingConnectionSet)
+	for ; numConnectionsOverMax > 0; numConnectionsOverMax-- {
+		evicted := selectConnectionToEvict(candidates)
+		if evicted == nil {
 			break
 		}
+
+		log.Debugf("Evicting %s due to exceeding incoming connections", evicted.connection)
+		evicted.connection.Disconnect()
+
+		for i, candidate := range candidates {
+			if candidate == evicted {
+				candidates = append(candidates[:i], candidates[i+1:]...)
+				break
+			}
+		}
 	}
 }
@@ -1,6 +1,11 @@
 package connmanager
 
-import "github.com/Hoosat-Oy/HTND/app/appmessage"
+import (
+	"sort"
+
+	"github.com/Hoosat-Oy/HTND/app/appmessage"
+	"github.com/Hoosat-Oy/HTND/infrastructure/network/addressmanager"
+)
 
 // checkOutgoingConnections goes over all activeOutgoing and makes sure they are still active.
 // Then it opens connections so that we have targetOutgoing active connections
@@ -31,25 +36,33 @@ func (c *ConnectionManager) checkOutgoingConnections(connSet connectionSet) {
 		liveConnections, c.targetOutgoing, c.targetOutgoing-liveConnections)
 
 	connectionsNeededCount := c.targetOutgoing - len(c.activeOutgoing)
-	netAddresses := c.addressManager.RandomAddresses(connectionsNeededCount, connectedAddresses)
+	usedNetGroups := make(map[string]struct{}, c.targetOutgoing)
+	for _, netAddress := range c.activeOutgoing {
+		usedNetGroups[c.addressManager.GroupKey(netAddress)] = struct{}{}
+	}
+	netAddresses := c.takePendingAnchors(connectionsNeededCount, usedNetGroups)
+	netAddresses = append(netAddresses, c.diverseRandomAddresses(connectionsNeededCount-len(netAddresses),
+		append(connectedAddresses, netAddresses...), usedNetGroups)...)
 
 	for _, netAddress := range netAddresses {
 		addressString := netAddress.TCPAddress().String()
 
 		log.Debugf("Connecting to %s because we have %d outgoing connections and the target is "+
-			"%d", addressString, len(c.activeOutgoing), c.targetOutgoing)
+			"%d", netAddress.DialAddress(), len(c.activeOutgoing), c.targetOutgoing)
 
-		err := c.initiateConnection(addressString)
+		err := c.initiateConnection(netAddress.DialAddress())
 		if err != nil {
-			log.Debugf("Couldn't connect to %s: %s", addressString, err)
+			log.Debugf("Couldn't connect to %s: %s", netAddress.DialAddress(), err)
 			c.addressManager.MarkConnectionFailure(netAddress)
 			continue
 		}
 		c.addressManager.MarkConnectionSuccess(netAddress)
 
-		c.activeOutgoing[addressString] = struct{}{}
+		c.activeOutgoing[addressString] = netAddress
 	}
 
+	c.updateAnchors()
+
 	if len(netAddresses) < connectionsNeededCount {
 		log.Debugf("Need %d more outgoing connections - seeding addresses from DNS",
 			connectionsNeededCount-len(netAddresses))
@@ -59,3 +72,87 @@ func (c *ConnectionManager) checkOutgoingConnections(connSet connectionSet) {
 		c.seedFromDNS()
 	}
 }
+
+// takePendingAnchors returns up to count of the anchor addresses that were loaded on
+// startup. Anchors are connected to before any other address, so that after a restart
+// we reconnect to the peers we already trusted, instead of to whatever an attacker
+// managed to put in the address manager.
+func (c *ConnectionManager) takePendingAnchors(count int, usedNetGroups map[string]struct{}) []*appmessage.NetAddress {
+	anchors := make([]*appmessage.NetAddress, 0, count)
+	for len(anchors) < count && len(c.pendingAnchors) > 0 {
+		anchor := c.pendingAnchors[0]
+		c.pendingAnchors = c.pendingAnchors[1:]
+		if c.canDial(anchor) && c.useNetGroup(anchor, usedNetGroups) {
+			anchors = append(anchors, anchor)
+		}
+	}
+	return anchors
+}
+
+// diverseRandomAddresses returns up to count random addresses that aren't in exceptions, such
+// that no two outgoing connections share a network group. This stops an attacker who controls
+// a single subnet or hosting provider from taking over all our outgoing connections.
+func (c *ConnectionManager) diverseRandomAddresses(count int, exceptions []*appmessage.NetAddress,
+	usedNetGroups map[string]struct{}) []*appmessage.NetAddress {
+
+	netAddresses := make([]*appmessage.NetAddress, 0, count)
+	for len(netAddresses) < count {
+		candidates := c.addressManager.RandomAddresses(count-len(netAddresses), exceptions)
+		if len(candidates) == 0 {
+			break
+		}
+		// Candidates that were skipped are excluded from the next round, so that every
+		// round either adds addresses or shrinks the pool of candidates
+		exceptions = append(exceptions, candidates...)
+		for _, candidate := range candidates {
+			if c.canDial(candidate) && c.useNetGroup(candidate, usedNetGroups) {
+				netAddresses = append(netAddresses, candidate)
+			}
+		}
+	}
+	return netAddresses
+}
+
+// canDial returns false for onion addresses when there's no Tor proxy to reach them through
+func (c *ConnectionManager) canDial(netAddress *appmessage.NetAddress) bool {
+	return !netAddress.IsOnionV3() || c.cfg.CanDialOnion
+}
+
+// useNetGroup marks the network group of the given address as used, and returns false if
+// it was already used. Unroutable addresses, which are used only in test networks, are
+// never rejected.
+func (c *ConnectionManager) useNetGroup(netAddress *appmessage.NetAddress, usedNetGroups map[string]struct{}) bool {
+	if !addressmanager.IsRoutable(netAddress, false) {
+		return true
+	}
+	netGroup := c.addressManager.GroupKey(netAddress)
+	if _, ok := usedNetGroups[netGroup]; ok {
+		return false
+	}
+	usedNetGroups[netGroup] = struct{}{}
+	return true
+}
+
+// updateAnchors persists the addresses of the current outgoing connections as the
+// anchors to reconnect to after a restart
+func (c *ConnectionManager) updateAnchors() {
+	// Losing all outgoing connections happens mostly on shutdown, and the previous
+	// anchors are more useful after a restart than no anchors at all
+	if len(c.activeOutgoing) == 0 {
+		return
+	}
+
+	anchors := make([]*appmessage.NetAddress, 0, len(c.activeOutgoing))
+	for _, netAddress := range c.activeOutgoing {
+		anchors = append(anchors, netAddress)
+	}
+	// Keep the anchors stable between iterations rather than depend on map order
+	sort.Slice(anchors, func(i, j int) bool {
+		return anchors[i].TCPAddress().String() < anchors[j].TCPAddress().String()
+	})
+
+	err := c.addressManager.SetAnchors(anchors)
+	if err != nil {
+		log.Warnf("Couldn't persist the anchor addresses: %s", err)
+	}
+}
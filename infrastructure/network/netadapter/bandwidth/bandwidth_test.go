@@ -0,0 +1,90 @@
+package bandwidth
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Hoosat-Oy/HTND/app/appmessage"
+)
+
+func TestCounterStats(t *testing.T) {
+	counter := NewCounter()
+	counter.AddSent(appmessage.CmdIBDBlock, 1000)
+	counter.AddSent(appmessage.CmdIBDBlock, 500)
+	counter.AddSent(appmessage.CmdPing, 10)
+	counter.AddReceived(appmessage.CmdRequestIBDBlocks, 200)
+	counter.AddReceived(appmessage.CmdPong, 10)
+
+	stats := counter.Stats()
+	if stats.BytesSent != 1510 {
+		t.Fatalf("expected 1510 bytes sent, got %d", stats.BytesSent)
+	}
+	if stats.BytesReceived != 210 {
+		t.Fatalf("expected 210 bytes received, got %d", stats.BytesReceived)
+	}
+
+	commands := make(map[string]*CommandStats)
+	for _, commandStats := range stats.Commands {
+		commands[commandStats.Command] = commandStats
+	}
+	if len(commands) != 4 {
+		t.Fatalf("expected stats for 4 commands, got %d", len(commands))
+	}
+	if commands[appmessage.CmdIBDBlock.String()].BytesSent != 1500 {
+		t.Fatalf("expected 1500 bytes sent in %s messages, got %d",
+			appmessage.CmdIBDBlock, commands[appmessage.CmdIBDBlock.String()].BytesSent)
+	}
+	if commands[appmessage.CmdPong.String()].BytesReceived != 10 {
+		t.Fatalf("expected 10 bytes received in %s messages, got %d",
+			appmessage.CmdPong, commands[appmessage.CmdPong.String()].BytesReceived)
+	}
+	for i := 1; i < len(stats.Commands); i++ {
+		if stats.Commands[i-1].Command > stats.Commands[i].Command {
+			t.Fatalf("command stats are not sorted")
+		}
+	}
+}
+
+func TestCounterChargesOnlyThrottledCommands(t *testing.T) {
+	limiter := NewLimiter(1000)
+	counter := NewCounter()
+	counter.LimitUpload(limiter, nil)
+
+	counter.AddSent(appmessage.CmdPing, 5000)
+	if delay := limiter.Delay(); delay != 0 {
+		t.Fatalf("a non-throttled message was charged to the limiter")
+	}
+
+	counter.AddSent(appmessage.CmdIBDBlock, 3000)
+	delay := limiter.Delay()
+	if delay < 1500*time.Millisecond || delay > 2*time.Second {
+		t.Fatalf("expected a delay of about 2 seconds after going 2000 bytes into debt, got %s", delay)
+	}
+}
+
+func TestLimiterWait(t *testing.T) {
+	limiter := NewLimiter(10000)
+	limiter.Consume(10000 + 1000)
+
+	start := time.Now()
+	limiter.Wait()
+	elapsed := time.Since(start)
+	if elapsed < 50*time.Millisecond {
+		t.Fatalf("expected Wait to block for about 100ms, but it returned after %s", elapsed)
+	}
+	if limiter.Delay() != 0 {
+		t.Fatalf("the limiter is still in debt after Wait")
+	}
+}
+
+func TestNilLimiter(t *testing.T) {
+	limiter := NewLimiter(0)
+	if limiter != nil {
+		t.Fatalf("expected a rate of 0 to return no limiter")
+	}
+	limiter.Consume(1000)
+	limiter.Wait()
+	if limiter.Delay() != 0 {
+		t.Fatalf("a nil limiter shouldn't delay")
+	}
+}
@@ -0,0 +1,136 @@
+package bandwidth
+
+import (
+	"sort"
+	"sync"
+
+	"github.com/Hoosat-Oy/HTND/app/appmessage"
+)
+
+// throttledCommands are the commands that count against the upload limiters. These are the
+// messages that serve another node's IBD, which are by far the bulk of what a node uploads.
+var throttledCommands = map[appmessage.MessageCommand]struct{}{
+	appmessage.CmdIBDBlock:                 {},
+	appmessage.CmdPruningPointUTXOSetChunk: {},
+	appmessage.CmdBlockHeaders:             {},
+	appmessage.CmdPruningPoints:            {},
+	appmessage.CmdPruningPointProof:        {},
+	appmessage.CmdTrustedData:              {},
+	appmessage.CmdBlockWithTrustedData:     {},
+	appmessage.CmdBlockWithTrustedDataV4:   {},
+}
+
+// IsThrottled returns whether messages with the given command count against the upload limiters
+func IsThrottled(command appmessage.MessageCommand) bool {
+	_, ok := throttledCommands[command]
+	return ok
+}
+
+// CommandStats holds the amount of bytes sent and received in messages of a single command
+type CommandStats struct {
+	Command       string
+	BytesSent     uint64
+	BytesReceived uint64
+}
+
+// Stats is a snapshot of the bytes a connection has sent and received
+type Stats struct {
+	BytesSent     uint64
+	BytesReceived uint64
+	Commands      []*CommandStats
+}
+
+// Counter counts the bytes sent and received over a single connection, and charges
+// the sent bytes of throttled messages to its upload limiters
+type Counter struct {
+	lock           sync.Mutex
+	bytesSent      uint64
+	bytesReceived  uint64
+	commands       map[appmessage.MessageCommand]*CommandStats
+	uploadLimiters []*Limiter
+}
+
+// NewCounter returns a new Counter
+func NewCounter() *Counter {
+	return &Counter{
+		commands: make(map[appmessage.MessageCommand]*CommandStats),
+	}
+}
+
+// LimitUpload sets the limiters that the throttled messages sent over this connection are charged to.
+// Nil limiters are ignored.
+func (c *Counter) LimitUpload(limiters ...*Limiter) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	c.uploadLimiters = c.uploadLimiters[:0]
+	for _, limiter := range limiters {
+		if limiter != nil {
+			c.uploadLimiters = append(c.uploadLimiters, limiter)
+		}
+	}
+}
+
+// AddSent records a message of the given command and size that was sent
+func (c *Counter) AddSent(command appmessage.MessageCommand, bytes int) {
+	c.lock.Lock()
+	c.bytesSent += uint64(bytes)
+	c.commandStats(command).BytesSent += uint64(bytes)
+	limiters := c.uploadLimiters
+	c.lock.Unlock()
+
+	if IsThrottled(command) {
+		for _, limiter := range limiters {
+			limiter.Consume(bytes)
+		}
+	}
+}
+
+// AddReceived records a message of the given command and size that was received
+func (c *Counter) AddReceived(command appmessage.MessageCommand, bytes int) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	c.bytesReceived += uint64(bytes)
+	c.commandStats(command).BytesReceived += uint64(bytes)
+}
+
+func (c *Counter) commandStats(command appmessage.MessageCommand) *CommandStats {
+	stats, ok := c.commands[command]
+	if !ok {
+		stats = &CommandStats{Command: command.String()}
+		c.commands[command] = stats
+	}
+	return stats
+}
+
+// WaitForUploadQuota blocks until all the upload limiters of this connection have quota left
+func (c *Counter) WaitForUploadQuota() {
+	c.lock.Lock()
+	limiters := c.uploadLimiters
+	c.lock.Unlock()
+
+	for _, limiter := range limiters {
+		limiter.Wait()
+	}
+}
+
+// Stats returns a snapshot of the bytes counted so far
+func (c *Counter) Stats() *Stats {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	stats := &Stats{
+		BytesSent:     c.bytesSent,
+		BytesReceived: c.bytesReceived,
+		Commands:      make([]*CommandStats, 0, len(c.commands)),
+	}
+	for _, commandStats := range c.commands {
+		commandStatsCopy := *commandStats
+		stats.Commands = append(stats.Commands, &commandStatsCopy)
+	}
+	sort.Slice(stats.Commands, func(i, j int) bool {
+		return stats.Commands[i].Command < stats.Commands[j].Command
+	})
+	return stats
+}
@@ -0,0 +1,78 @@
+package bandwidth
+
+import (
+	"sync"
+	"time"
+)
+
+// Limiter is a token bucket that limits the rate of uploaded bytes.
+//
+// Bytes are charged after they were sent, so a single big message can put the limiter
+// in debt. Senders call Wait before sending, which blocks until the debt is paid off.
+// That way a message never has to be split, and the average rate is still kept.
+type Limiter struct {
+	lock           sync.Mutex
+	bytesPerSecond float64
+	tokens         float64
+	lastUpdate     time.Time
+}
+
+// NewLimiter returns a Limiter that allows bytesPerSecond bytes per second, with bursts of up
+// to one second worth of bytes. It returns nil if bytesPerSecond is 0, which means no limit.
+func NewLimiter(bytesPerSecond uint64) *Limiter {
+	if bytesPerSecond == 0 {
+		return nil
+	}
+	return &Limiter{
+		bytesPerSecond: float64(bytesPerSecond),
+		tokens:         float64(bytesPerSecond),
+		lastUpdate:     time.Now(),
+	}
+}
+
+// refill adds the tokens that accumulated since the last update. It must be called with lock held.
+func (l *Limiter) refill(now time.Time) {
+	l.tokens += now.Sub(l.lastUpdate).Seconds() * l.bytesPerSecond
+	if l.tokens > l.bytesPerSecond {
+		l.tokens = l.bytesPerSecond
+	}
+	l.lastUpdate = now
+}
+
+// Consume charges the given amount of bytes to the limiter
+func (l *Limiter) Consume(bytes int) {
+	if l == nil {
+		return
+	}
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	l.refill(time.Now())
+	l.tokens -= float64(bytes)
+}
+
+// Delay returns how long it would take for the limiter to get out of debt
+func (l *Limiter) Delay() time.Duration {
+	if l == nil {
+		return 0
+	}
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	l.refill(time.Now())
+	if l.tokens >= 0 {
+		return 0
+	}
+	return time.Duration(-l.tokens / l.bytesPerSecond * float64(time.Second))
+}
+
+// Wait blocks until the limiter is out of debt
+func (l *Limiter) Wait() {
+	for {
+		delay := l.Delay()
+		if delay == 0 {
+			return
+		}
+		time.Sleep(delay)
+	}
+}
@@ -6,10 +6,12 @@ import (
 
 	"github.com/Hoosat-Oy/HTND/app/appmessage"
 	"github.com/Hoosat-Oy/HTND/infrastructure/config"
+	"github.com/Hoosat-Oy/HTND/infrastructure/network/netadapter/bandwidth"
 	"github.com/Hoosat-Oy/HTND/infrastructure/network/netadapter/id"
 	routerpkg "github.com/Hoosat-Oy/HTND/infrastructure/network/netadapter/router"
 	"github.com/Hoosat-Oy/HTND/infrastructure/network/netadapter/server"
 	"github.com/Hoosat-Oy/HTND/infrastructure/network/netadapter/server/grpcserver"
+	"github.com/Hoosat-Oy/HTND/infrastructure/network/noise"
 	"github.com/pkg/errors"
 )
 
@@ -31,6 +33,9 @@ type NetAdapter struct {
 	rpcRouterInitializer RouterInitializer
 	stop                 uint32
 
+	// uploadLimiter limits the IBD data uploaded to all peers combined
+	uploadLimiter *bandwidth.Limiter
+
 	p2pConnections     map[*NetConnection]struct{}
 	p2pConnectionsLock sync.RWMutex
 }
@@ -42,7 +47,18 @@ func NewNetAdapter(cfg *config.Config) (*NetAdapter, error) {
 	if err != nil {
 		return nil, err
 	}
-	p2pServer, err := grpcserver.NewP2PServer(cfg.Listeners)
+	var transport *noise.Transport
+	if !cfg.NoP2PEncryption {
+		staticKey := cfg.P2PStaticKey
+		if staticKey == nil {
+			staticKey, err = noise.GenerateStaticKey()
+			if err != nil {
+				return nil, err
+			}
+		}
+		transport = noise.NewTransport(staticKey, cfg.PinnedPeerKeys)
+	}
+	p2pServer, err := grpcserver.NewP2PServer(cfg.Listeners, cfg.Dial, transport)
 	if err != nil {
 		return nil, err
 	}
@@ -56,6 +72,8 @@ func NewNetAdapter(cfg *config.Config) (*NetAdapter, error) {
 		p2pServer: p2pServer,
 		rpcServer: rpcServer,
 
+		uploadLimiter: bandwidth.NewLimiter(cfg.MaxUploadRate * 1024),
+
 		p2pConnections: make(map[*NetConnection]struct{}),
 	}
 
@@ -128,6 +146,7 @@ func (na *NetAdapter) P2PConnectionCount() int {
 }
 
 func (na *NetAdapter) onP2PConnectedHandler(connection server.Connection) error {
+	connection.Bandwidth().LimitUpload(na.uploadLimiter, bandwidth.NewLimiter(na.cfg.MaxPeerUploadRate*1024))
 	netConnection := newNetConnection(connection, na.p2pRouterInitializer, "on P2P connected")
 
 	na.p2pConnectionsLock.Lock()
@@ -5,6 +5,7 @@ import (
 	"sync/atomic"
 
 	"github.com/Hoosat-Oy/HTND/app/appmessage"
+	"github.com/Hoosat-Oy/HTND/infrastructure/network/netadapter/bandwidth"
 	routerpkg "github.com/Hoosat-Oy/HTND/infrastructure/network/netadapter/router"
 	"github.com/pkg/errors"
 
@@ -92,6 +93,17 @@ func (c *NetConnection) Disconnect() {
 	}
 }
 
+// BandwidthStats returns the bytes sent and received over this connection so far
+func (c *NetConnection) BandwidthStats() *bandwidth.Stats {
+	return c.connection.Bandwidth().Stats()
+}
+
+// WaitForUploadQuota blocks until the upload rate limits allow sending more IBD data
+// over this connection
+func (c *NetConnection) WaitForUploadQuota() {
+	c.connection.Bandwidth().WaitForUploadQuota()
+}
+
 // SetOnInvalidMessageHandler sets the invalid message handler for this connection
 func (c *NetConnection) SetOnInvalidMessageHandler(onInvalidMessageHandler server.OnInvalidMessageHandler) {
 	c.connection.SetOnInvalidMessageHandler(onInvalidMessageHandler)
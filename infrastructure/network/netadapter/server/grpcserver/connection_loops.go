@@ -13,6 +13,7 @@ import (
 
 	routerpkg "github.com/Hoosat-Oy/HTND/infrastructure/network/netadapter/router"
 	"github.com/pkg/errors"
+	"google.golang.org/protobuf/proto"
 
 	"github.com/Hoosat-Oy/HTND/infrastructure/network/netadapter/server/grpcserver/protowire"
 )
@@ -72,6 +73,7 @@ func (c *gRPCConnection) sendLoop() error {
 		if err != nil {
 			return err
 		}
+		c.bandwidth.AddSent(message.Command(), proto.Size(messageProto))
 	}
 	return nil
 }
@@ -94,6 +96,8 @@ func (c *gRPCConnection) receiveLoop() error {
 			return err
 		}
 
+		c.bandwidth.AddReceived(message.Command(), proto.Size(protoMessage))
+
 		messageNumber++
 		message.SetMessageNumber(messageNumber)
 		message.SetReceivedAt(time.Now())
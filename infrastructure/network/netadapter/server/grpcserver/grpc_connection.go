@@ -5,6 +5,7 @@ import (
 	"sync"
 	"sync/atomic"
 
+	"github.com/Hoosat-Oy/HTND/infrastructure/network/netadapter/bandwidth"
 	"github.com/Hoosat-Oy/HTND/infrastructure/network/netadapter/router"
 	"github.com/Hoosat-Oy/HTND/infrastructure/network/netadapter/server/grpcserver/protowire"
 	"github.com/pkg/errors"
@@ -32,6 +33,7 @@ type gRPCConnection struct {
 	stopChan                chan struct{}
 	onDisconnectedHandler   server.OnDisconnectedHandler
 	onInvalidMessageHandler server.OnInvalidMessageHandler
+	bandwidth               *bandwidth.Counter
 
 	isConnected uint32
 }
@@ -50,6 +52,7 @@ func newConnection(server *gRPCServer, address *net.TCPAddr, stream grpcStream,
 		stopChan:                 make(chan struct{}),
 		isConnected:              1,
 		lowLevelClientConnection: lowLevelClientConnection,
+		bandwidth:                bandwidth.NewCounter(),
 	}
 
 	return connection
@@ -127,6 +130,13 @@ func (c *gRPCConnection) Address() *net.TCPAddr {
 	return c.address
 }
 
+// Bandwidth returns the counter of the bytes sent and received over the connection
+//
+// This is part of the Connection interface
+func (c *gRPCConnection) Bandwidth() *bandwidth.Counter {
+	return c.bandwidth
+}
+
 func (c *gRPCConnection) receive() (*protowire.HoosatdMessage, error) {
 	// We use RLock here and in send() because they can work
 	// in parallel. closeSend(), however, must not have either
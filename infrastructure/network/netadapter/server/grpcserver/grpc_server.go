@@ -20,6 +20,9 @@ type gRPCServer struct {
 	server             *grpc.Server
 	name               string
 
+	// wrapListener, if set, wraps the listeners of the server, e.g. to encrypt connections
+	wrapListener func(net.Listener) net.Listener
+
 	maxInboundConnections      int
 	inboundConnectionCount     int
 	inboundConnectionCountLock *sync.Mutex
@@ -58,6 +61,9 @@ func (s *gRPCServer) listenOn(listenAddr string) error {
 	if err != nil {
 		return errors.Wrapf(err, "%s error listening on %s", s.name, listenAddr)
 	}
+	if s.wrapListener != nil {
+		listener = s.wrapListener(listener)
+	}
 
 	spawn(fmt.Sprintf("%s.gRPCServer.listenOn-Serve", s.name), func() {
 		err := s.server.Serve(listener)
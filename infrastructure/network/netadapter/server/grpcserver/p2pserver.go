@@ -3,10 +3,13 @@ package grpcserver
 import (
 	"context"
 	"net"
+	"strconv"
 	"time"
 
+	"github.com/Hoosat-Oy/HTND/app/appmessage"
 	"github.com/Hoosat-Oy/HTND/infrastructure/network/netadapter/server"
 	"github.com/Hoosat-Oy/HTND/infrastructure/network/netadapter/server/grpcserver/protowire"
+	"github.com/Hoosat-Oy/HTND/infrastructure/network/noise"
 	"github.com/Hoosat-Oy/HTND/util/panics"
 	"github.com/pkg/errors"
 	"google.golang.org/grpc"
@@ -17,6 +20,8 @@ import (
 type p2pServer struct {
 	protowire.UnimplementedP2PServer
 	gRPCServer
+	dial      func(string, string, time.Duration) (net.Conn, error)
+	transport *noise.Transport
 }
 
 const p2pMaxMessageSize = 1024 * 1024 * 1024 // 1GB
@@ -27,10 +32,20 @@ const p2pMaxMessageSize = 1024 * 1024 * 1024 // 1GB
 // is handled in the ConnectionManager instead.
 const p2pMaxInboundConnections = 0
 
-// NewP2PServer creates a new P2PServer
-func NewP2PServer(listeningAddresses []string) (server.P2PServer, error) {
+// NewP2PServer creates a new P2PServer. Outbound connections are made with dial,
+// or directly if dial is nil. If transport is not nil, connections are encrypted
+// with it.
+func NewP2PServer(listeningAddresses []string, dial func(string, string, time.Duration) (net.Conn, error),
+	transport *noise.Transport) (server.P2PServer, error) {
+
 	gRPCServer := newGRPCServer(listeningAddresses, p2pMaxMessageSize, p2pMaxInboundConnections, "P2P")
-	p2pServer := &p2pServer{gRPCServer: *gRPCServer}
+	if dial == nil {
+		dial = net.DialTimeout
+	}
+	if transport != nil {
+		gRPCServer.wrapListener = transport.Listener
+	}
+	p2pServer := &p2pServer{gRPCServer: *gRPCServer, dial: dial, transport: transport}
 	protowire.RegisterP2PServer(gRPCServer.server, p2pServer)
 	return p2pServer, nil
 }
@@ -46,11 +61,14 @@ func (p *p2pServer) MessageStream(stream protowire.P2P_MessageStreamServer) erro
 func (p *p2pServer) Connect(address string) (server.Connection, error) {
 	log.Debugf("%s Dialing to %s", p.name, address)
 
-	const dialTimeout = 1 * time.Second
+	// Allows for the extra round trips of the encrypted handshake
+	const dialTimeout = 2 * time.Second
 	ctx, cancel := context.WithTimeout(context.Background(), dialTimeout)
 	defer cancel()
 
-	gRPCClientConnection, err := grpc.DialContext(ctx, address, grpc.WithInsecure(), grpc.WithBlock())
+	// The connection is encrypted by the dialer, if at all, so gRPC itself doesn't need transport security
+	gRPCClientConnection, err := grpc.DialContext(ctx, address, grpc.WithInsecure(), grpc.WithBlock(),
+		grpc.WithContextDialer(p.dialContext))
 	if err != nil {
 		return nil, errors.Wrapf(err, "%s error connecting to %s", p.name, address)
 	}
@@ -82,3 +100,55 @@ func (p *p2pServer) Connect(address string) (server.Connection, error) {
 
 	return connection, nil
 }
+
+// dialContext dials address with the dial function of the server, and encrypts the connection
+// if the server has a transport. Connections through a proxy report the address of the proxy as
+// their remote address, so the returned connection reports the dialed address instead. Onion
+// addresses are reported as their OnionCat IP.
+func (p *p2pServer) dialContext(ctx context.Context, address string) (net.Conn, error) {
+	timeout := time.Duration(0)
+	if deadline, ok := ctx.Deadline(); ok {
+		timeout = time.Until(deadline)
+	}
+	var connection net.Conn
+	var err error
+	if p.transport != nil {
+		connection, err = p.transport.Dial(p.dial, address, timeout)
+	} else {
+		connection, err = p.dial("tcp", address, timeout)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	host, portString, err := net.SplitHostPort(address)
+	if err != nil {
+		return connection, nil
+	}
+	port, err := strconv.ParseUint(portString, 10, 16)
+	if err != nil {
+		return connection, nil
+	}
+	if publicKey, err := appmessage.ParseOnionV3Host(host); err == nil {
+		return &dialedConnection{
+			Conn:       connection,
+			remoteAddr: appmessage.NewNetAddressOnionV3(publicKey, uint16(port)).TCPAddress(),
+		}, nil
+	}
+	if ip := net.ParseIP(host); ip != nil {
+		return &dialedConnection{
+			Conn:       connection,
+			remoteAddr: &net.TCPAddr{IP: ip, Port: int(port)},
+		}, nil
+	}
+	return connection, nil
+}
+
+type dialedConnection struct {
+	net.Conn
+	remoteAddr *net.TCPAddr
+}
+
+func (c *dialedConnection) RemoteAddr() net.Addr {
+	return c.remoteAddr
+}
@@ -103,6 +103,16 @@ func (x *NetAddress) toAppMessage() (*appmessage.NetAddress, error) {
 	if x.Port > math.MaxUint16 {
 		return nil, errors.Errorf("port number is larger than %d", math.MaxUint16)
 	}
+	if len(x.OnionV3PublicKey) != 0 {
+		if len(x.OnionV3PublicKey) != appmessage.OnionV3PublicKeySize {
+			return nil, errors.Errorf("onion public key is of size %d instead of %d",
+				len(x.OnionV3PublicKey), appmessage.OnionV3PublicKeySize)
+		}
+		// The IP of an onion address is always derived from its public key
+		address := appmessage.NewNetAddressOnionV3(x.OnionV3PublicKey, uint16(x.Port))
+		address.Timestamp = mstime.UnixMilliseconds(x.Timestamp)
+		return address, nil
+	}
 	return &appmessage.NetAddress{
 		Timestamp: mstime.UnixMilliseconds(x.Timestamp),
 		IP:        x.Ip,
@@ -112,9 +122,10 @@ func (x *NetAddress) toAppMessage() (*appmessage.NetAddress, error) {
 
 func appMessageNetAddressToProto(address *appmessage.NetAddress) *NetAddress {
 	return &NetAddress{
-		Timestamp: address.Timestamp.UnixMilliseconds(),
-		Ip:        address.IP,
-		Port:      uint32(address.Port),
+		Timestamp:        address.Timestamp.UnixMilliseconds(),
+		Ip:               address.IP,
+		Port:             uint32(address.Port),
+		OnionV3PublicKey: address.OnionV3PublicKey,
 	}
 }
 
@@ -0,0 +1,75 @@
+package protowire
+
+import (
+	"github.com/Hoosat-Oy/HTND/app/appmessage"
+	"github.com/pkg/errors"
+)
+
+func (x *HoosatdMessage_CompactBlock) toAppMessage() (appmessage.Message, error) {
+	if x == nil {
+		return nil, errors.Wrap(errorNil, "HoosatdMessage_CompactBlock is nil")
+	}
+	return x.CompactBlock.toAppMessage()
+}
+
+func (x *HoosatdMessage_CompactBlock) fromAppMessage(msgCompactBlock *appmessage.MsgCompactBlock) error {
+	x.CompactBlock = new(CompactBlockMessage)
+	return x.CompactBlock.fromAppMessage(msgCompactBlock)
+}
+
+func (x *CompactBlockMessage) toAppMessage() (*appmessage.MsgCompactBlock, error) {
+	if x == nil {
+		return nil, errors.Wrap(errorNil, "CompactBlockMessage is nil")
+	}
+	header, err := x.Header.toAppMessage()
+	if err != nil {
+		return nil, err
+	}
+
+	prefilledTransactions := make([]*appmessage.PrefilledTransaction, len(x.PrefilledTransactions))
+	for i, protoPrefilledTransaction := range x.PrefilledTransactions {
+		if protoPrefilledTransaction == nil {
+			return nil, errors.Wrap(errorNil, "PrefilledTransaction is nil")
+		}
+		msgTx, err := protoPrefilledTransaction.Transaction.toAppMessage()
+		if err != nil {
+			return nil, err
+		}
+		prefilledTransactions[i] = &appmessage.PrefilledTransaction{
+			Index: protoPrefilledTransaction.Index,
+			Tx:    msgTx.(*appmessage.MsgTx),
+		}
+	}
+
+	return &appmessage.MsgCompactBlock{
+		Header:                *header,
+		ShortIDNonce:          x.ShortIdNonce,
+		ShortIDs:              x.ShortIds,
+		PrefilledTransactions: prefilledTransactions,
+	}, nil
+}
+
+func (x *CompactBlockMessage) fromAppMessage(msgCompactBlock *appmessage.MsgCompactBlock) error {
+	protoHeader := new(BlockHeader)
+	err := protoHeader.fromAppMessage(&msgCompactBlock.Header)
+	if err != nil {
+		return err
+	}
+
+	protoPrefilledTransactions := make([]*PrefilledTransaction, len(msgCompactBlock.PrefilledTransactions))
+	for i, prefilledTransaction := range msgCompactBlock.PrefilledTransactions {
+		protoTx := new(TransactionMessage)
+		protoTx.fromAppMessage(prefilledTransaction.Tx)
+		protoPrefilledTransactions[i] = &PrefilledTransaction{
+			Index:       prefilledTransaction.Index,
+			Transaction: protoTx,
+		}
+	}
+	*x = CompactBlockMessage{
+		Header:                protoHeader,
+		ShortIdNonce:          msgCompactBlock.ShortIDNonce,
+		ShortIds:              msgCompactBlock.ShortIDs,
+		PrefilledTransactions: protoPrefilledTransactions,
+	}
+	return nil
+}
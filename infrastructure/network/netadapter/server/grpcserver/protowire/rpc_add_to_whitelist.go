@@ -0,0 +1,63 @@
+package protowire
+
+import (
+	"github.com/Hoosat-Oy/HTND/app/appmessage"
+	"github.com/pkg/errors"
+)
+
+func (x *HoosatdMessage_AddToWhitelistRequest) toAppMessage() (appmessage.Message, error) {
+	if x == nil {
+		return nil, errors.Wrapf(errorNil, "HoosatdMessage_AddToWhitelistRequest is nil")
+	}
+	return x.AddToWhitelistRequest.toAppMessage()
+}
+
+func (x *HoosatdMessage_AddToWhitelistRequest) fromAppMessage(message *appmessage.AddToWhitelistRequestMessage) error {
+	x.AddToWhitelistRequest = &AddToWhitelistRequestMessage{
+		Subnet: message.Subnet,
+		Reason: message.Reason,
+	}
+	return nil
+}
+
+func (x *AddToWhitelistRequestMessage) toAppMessage() (appmessage.Message, error) {
+	if x == nil {
+		return nil, errors.Wrapf(errorNil, "AddToWhitelistRequestMessage is nil")
+	}
+	return &appmessage.AddToWhitelistRequestMessage{
+		Subnet: x.Subnet,
+		Reason: x.Reason,
+	}, nil
+}
+
+func (x *HoosatdMessage_AddToWhitelistResponse) toAppMessage() (appmessage.Message, error) {
+	if x == nil {
+		return nil, errors.Wrapf(errorNil, "HoosatdMessage_AddToWhitelistResponse is nil")
+	}
+	return x.AddToWhitelistResponse.toAppMessage()
+}
+
+func (x *HoosatdMessage_AddToWhitelistResponse) fromAppMessage(message *appmessage.AddToWhitelistResponseMessage) error {
+	var err *RPCError
+	if message.Error != nil {
+		err = &RPCError{Message: message.Error.Message}
+	}
+	x.AddToWhitelistResponse = &AddToWhitelistResponseMessage{
+		Error: err,
+	}
+	return nil
+}
+
+func (x *AddToWhitelistResponseMessage) toAppMessage() (appmessage.Message, error) {
+	if x == nil {
+		return nil, errors.Wrapf(errorNil, "AddToWhitelistResponseMessage is nil")
+	}
+	rpcErr, err := x.Error.toAppMessage()
+	// Error is an optional field
+	if err != nil && !errors.Is(err, errorNil) {
+		return nil, err
+	}
+	return &appmessage.AddToWhitelistResponseMessage{
+		Error: rpcErr,
+	}, nil
+}
@@ -17,12 +17,18 @@ func (x *BanRequestMessage) toAppMessage() (appmessage.Message, error) {
 		return nil, errors.Wrapf(errorNil, "BanRequestMessage is nil")
 	}
 	return &appmessage.BanRequestMessage{
-		IP: x.Ip,
+		IP:              x.Ip,
+		Reason:          x.Reason,
+		DurationSeconds: x.DurationSeconds,
 	}, nil
 }
 
 func (x *HoosatdMessage_BanRequest) fromAppMessage(message *appmessage.BanRequestMessage) error {
-	x.BanRequest = &BanRequestMessage{Ip: message.IP}
+	x.BanRequest = &BanRequestMessage{
+		Ip:              message.IP,
+		Reason:          message.Reason,
+		DurationSeconds: message.DurationSeconds,
+	}
 	return nil
 }
 
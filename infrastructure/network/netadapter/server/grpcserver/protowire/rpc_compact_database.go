@@ -0,0 +1,54 @@
+package protowire
+
+import (
+	"github.com/Hoosat-Oy/HTND/app/appmessage"
+	"github.com/pkg/errors"
+)
+
+func (x *HoosatdMessage_CompactDatabaseRequest) toAppMessage() (appmessage.Message, error) {
+	return &appmessage.CompactDatabaseRequestMessage{}, nil
+}
+
+func (x *HoosatdMessage_CompactDatabaseRequest) fromAppMessage(_ *appmessage.CompactDatabaseRequestMessage) error {
+	x.CompactDatabaseRequest = &CompactDatabaseRequestMessage{}
+	return nil
+}
+
+func (x *HoosatdMessage_CompactDatabaseResponse) toAppMessage() (appmessage.Message, error) {
+	if x == nil {
+		return nil, errors.Wrapf(errorNil, "HoosatdMessage_CompactDatabaseResponse is nil")
+	}
+	return x.CompactDatabaseResponse.toAppMessage()
+}
+
+func (x *HoosatdMessage_CompactDatabaseResponse) fromAppMessage(message *appmessage.CompactDatabaseResponseMessage) error {
+	var err *RPCError
+	if message.Error != nil {
+		err = &RPCError{Message: message.Error.Message}
+	}
+	x.CompactDatabaseResponse = &CompactDatabaseResponseMessage{
+		Started:    message.Started,
+		Compaction: databaseCompactionStateFromAppMessage(message.Compaction),
+		Error:      err,
+	}
+	return nil
+}
+
+func (x *CompactDatabaseResponseMessage) toAppMessage() (appmessage.Message, error) {
+	if x == nil {
+		return nil, errors.Wrapf(errorNil, "CompactDatabaseResponseMessage is nil")
+	}
+	rpcErr, err := x.Error.toAppMessage()
+	// Error is an optional field
+	if err != nil && !errors.Is(err, errorNil) {
+		return nil, err
+	}
+	if rpcErr != nil && x.Started {
+		return nil, errors.New("CompactDatabaseResponseMessage contains both an error and a response")
+	}
+	return &appmessage.CompactDatabaseResponseMessage{
+		Started:    x.Started,
+		Compaction: x.Compaction.toAppMessage(),
+		Error:      rpcErr,
+	}, nil
+}
@@ -0,0 +1,80 @@
+package protowire
+
+import (
+	"github.com/Hoosat-Oy/HTND/app/appmessage"
+	"github.com/pkg/errors"
+)
+
+func (x *HoosatdMessage_GetBansRequest) toAppMessage() (appmessage.Message, error) {
+	return &appmessage.GetBansRequestMessage{}, nil
+}
+
+func (x *HoosatdMessage_GetBansRequest) fromAppMessage(_ *appmessage.GetBansRequestMessage) error {
+	x.GetBansRequest = &GetBansRequestMessage{}
+	return nil
+}
+
+func (x *HoosatdMessage_GetBansResponse) toAppMessage() (appmessage.Message, error) {
+	if x == nil {
+		return nil, errors.Wrapf(errorNil, "HoosatdMessage_GetBansResponse is nil")
+	}
+	return x.GetBansResponse.toAppMessage()
+}
+
+func (x *HoosatdMessage_GetBansResponse) fromAppMessage(message *appmessage.GetBansResponseMessage) error {
+	var err *RPCError
+	if message.Error != nil {
+		err = &RPCError{Message: message.Error.Message}
+	}
+	bans := make([]*BanEntry, len(message.Bans))
+	for i, ban := range message.Bans {
+		bans[i] = &BanEntry{
+			Ip:        ban.IP,
+			Reason:    ban.Reason,
+			BannedAt:  ban.BannedAt,
+			ExpiresAt: ban.ExpiresAt,
+		}
+	}
+	x.GetBansResponse = &GetBansResponseMessage{
+		Bans:  bans,
+		Error: err,
+	}
+	return nil
+}
+
+func (x *GetBansResponseMessage) toAppMessage() (appmessage.Message, error) {
+	if x == nil {
+		return nil, errors.Wrapf(errorNil, "GetBansResponseMessage is nil")
+	}
+	rpcErr, err := x.Error.toAppMessage()
+	// Error is an optional field
+	if err != nil && !errors.Is(err, errorNil) {
+		return nil, err
+	}
+	if rpcErr != nil && len(x.Bans) != 0 {
+		return nil, errors.New("GetBansResponseMessage contains both an error and a response")
+	}
+	bans := make([]*appmessage.BanEntry, len(x.Bans))
+	for i, ban := range x.Bans {
+		bans[i], err = ban.toAppMessage()
+		if err != nil {
+			return nil, err
+		}
+	}
+	return &appmessage.GetBansResponseMessage{
+		Bans:  bans,
+		Error: rpcErr,
+	}, nil
+}
+
+func (x *BanEntry) toAppMessage() (*appmessage.BanEntry, error) {
+	if x == nil {
+		return nil, errors.Wrapf(errorNil, "BanEntry is nil")
+	}
+	return &appmessage.BanEntry{
+		IP:        x.Ip,
+		Reason:    x.Reason,
+		BannedAt:  x.BannedAt,
+		ExpiresAt: x.ExpiresAt,
+	}, nil
+}
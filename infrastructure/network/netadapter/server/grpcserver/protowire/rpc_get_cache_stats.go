@@ -0,0 +1,86 @@
+package protowire
+
+import (
+	"github.com/Hoosat-Oy/HTND/app/appmessage"
+	"github.com/pkg/errors"
+)
+
+func (x *HoosatdMessage_GetCacheStatsRequest) toAppMessage() (appmessage.Message, error) {
+	return &appmessage.GetCacheStatsRequestMessage{}, nil
+}
+
+func (x *HoosatdMessage_GetCacheStatsRequest) fromAppMessage(_ *appmessage.GetCacheStatsRequestMessage) error {
+	x.GetCacheStatsRequest = &GetCacheStatsRequestMessage{}
+	return nil
+}
+
+func (x *HoosatdMessage_GetCacheStatsResponse) toAppMessage() (appmessage.Message, error) {
+	if x == nil {
+		return nil, errors.Wrapf(errorNil, "HoosatdMessage_GetCacheStatsResponse is nil")
+	}
+	return x.GetCacheStatsResponse.toAppMessage()
+}
+
+func (x *HoosatdMessage_GetCacheStatsResponse) fromAppMessage(message *appmessage.GetCacheStatsResponseMessage) error {
+	var err *RPCError
+	if message.Error != nil {
+		err = &RPCError{Message: message.Error.Message}
+	}
+	caches := make([]*CacheStats, len(message.Caches))
+	for i, cache := range message.Caches {
+		caches[i] = &CacheStats{
+			Name:     cache.Name,
+			Size:     cache.Size,
+			Capacity: cache.Capacity,
+			Hits:     cache.Hits,
+			Misses:   cache.Misses,
+		}
+	}
+	x.GetCacheStatsResponse = &GetCacheStatsResponseMessage{
+		Caches:      caches,
+		CachePreset: message.CachePreset,
+		CacheMemMiB: message.CacheMemMiB,
+		Error:       err,
+	}
+	return nil
+}
+
+func (x *GetCacheStatsResponseMessage) toAppMessage() (appmessage.Message, error) {
+	if x == nil {
+		return nil, errors.Wrapf(errorNil, "GetCacheStatsResponseMessage is nil")
+	}
+	rpcErr, err := x.Error.toAppMessage()
+	// Error is an optional field
+	if err != nil && !errors.Is(err, errorNil) {
+		return nil, err
+	}
+	if rpcErr != nil && len(x.Caches) != 0 {
+		return nil, errors.New("GetCacheStatsResponseMessage contains both an error and a response")
+	}
+	caches := make([]*appmessage.CacheStats, len(x.Caches))
+	for i, cache := range x.Caches {
+		caches[i], err = cache.toAppMessage()
+		if err != nil {
+			return nil, err
+		}
+	}
+	return &appmessage.GetCacheStatsResponseMessage{
+		Caches:      caches,
+		CachePreset: x.CachePreset,
+		CacheMemMiB: x.CacheMemMiB,
+		Error:       rpcErr,
+	}, nil
+}
+
+func (x *CacheStats) toAppMessage() (*appmessage.CacheStats, error) {
+	if x == nil {
+		return nil, errors.Wrapf(errorNil, "CacheStats is nil")
+	}
+	return &appmessage.CacheStats{
+		Name:     x.Name,
+		Size:     x.Size,
+		Capacity: x.Capacity,
+		Hits:     x.Hits,
+		Misses:   x.Misses,
+	}, nil
+}
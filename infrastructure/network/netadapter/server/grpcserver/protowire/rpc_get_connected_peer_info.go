@@ -27,6 +27,14 @@ func (x *HoosatdMessage_GetConnectedPeerInfoResponse) fromAppMessage(message *ap
 	}
 	infos := make([]*GetConnectedPeerInfoMessage, len(message.Infos))
 	for i, info := range message.Infos {
+		messageBandwidth := make([]*PeerMessageBandwidth, len(info.MessageBandwidth))
+		for j, bandwidth := range info.MessageBandwidth {
+			messageBandwidth[j] = &PeerMessageBandwidth{
+				Command:       bandwidth.Command,
+				BytesSent:     bandwidth.BytesSent,
+				BytesReceived: bandwidth.BytesReceived,
+			}
+		}
 		infos[i] = &GetConnectedPeerInfoMessage{
 			Id:                        info.ID,
 			Address:                   info.Address,
@@ -37,6 +45,10 @@ func (x *HoosatdMessage_GetConnectedPeerInfoResponse) fromAppMessage(message *ap
 			AdvertisedProtocolVersion: info.AdvertisedProtocolVersion,
 			TimeConnected:             info.TimeConnected,
 			IsIbdPeer:                 info.IsIBDPeer,
+			BanScore:                  info.BanScore,
+			BytesSent:                 info.BytesSent,
+			BytesReceived:             info.BytesReceived,
+			MessageBandwidth:          messageBandwidth,
 		}
 	}
 	x.GetConnectedPeerInfoResponse = &GetConnectedPeerInfoResponseMessage{
@@ -78,6 +90,14 @@ func (x *GetConnectedPeerInfoMessage) toAppMessage() (*appmessage.GetConnectedPe
 	if x == nil {
 		return nil, errors.Wrapf(errorNil, "GetConnectedPeerInfoMessage is nil")
 	}
+	messageBandwidth := make([]*appmessage.PeerMessageBandwidth, len(x.MessageBandwidth))
+	for i, bandwidth := range x.MessageBandwidth {
+		messageBandwidth[i] = &appmessage.PeerMessageBandwidth{
+			Command:       bandwidth.Command,
+			BytesSent:     bandwidth.BytesSent,
+			BytesReceived: bandwidth.BytesReceived,
+		}
+	}
 	return &appmessage.GetConnectedPeerInfoMessage{
 		ID:                        x.Id,
 		Address:                   x.Address,
@@ -88,5 +108,9 @@ func (x *GetConnectedPeerInfoMessage) toAppMessage() (*appmessage.GetConnectedPe
 		AdvertisedProtocolVersion: x.AdvertisedProtocolVersion,
 		TimeConnected:             x.TimeOffset,
 		IsIBDPeer:                 x.IsIbdPeer,
+		BanScore:                  x.BanScore,
+		BytesSent:                 x.BytesSent,
+		BytesReceived:             x.BytesReceived,
+		MessageBandwidth:          messageBandwidth,
 	}, nil
 }
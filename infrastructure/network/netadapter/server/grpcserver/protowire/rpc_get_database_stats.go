@@ -0,0 +1,150 @@
+package protowire
+
+import (
+	"github.com/Hoosat-Oy/HTND/app/appmessage"
+	"github.com/pkg/errors"
+)
+
+func (x *HoosatdMessage_GetDatabaseStatsRequest) toAppMessage() (appmessage.Message, error) {
+	if x == nil {
+		return nil, errors.Wrapf(errorNil, "HoosatdMessage_GetDatabaseStatsRequest is nil")
+	}
+	return x.GetDatabaseStatsRequest.toAppMessage()
+}
+
+func (x *HoosatdMessage_GetDatabaseStatsRequest) fromAppMessage(message *appmessage.GetDatabaseStatsRequestMessage) error {
+	x.GetDatabaseStatsRequest = &GetDatabaseStatsRequestMessage{
+		Collect: message.Collect,
+	}
+	return nil
+}
+
+func (x *GetDatabaseStatsRequestMessage) toAppMessage() (appmessage.Message, error) {
+	if x == nil {
+		return nil, errors.Wrapf(errorNil, "GetDatabaseStatsRequestMessage is nil")
+	}
+	return &appmessage.GetDatabaseStatsRequestMessage{
+		Collect: x.Collect,
+	}, nil
+}
+
+func (x *HoosatdMessage_GetDatabaseStatsResponse) toAppMessage() (appmessage.Message, error) {
+	if x == nil {
+		return nil, errors.Wrapf(errorNil, "HoosatdMessage_GetDatabaseStatsResponse is nil")
+	}
+	return x.GetDatabaseStatsResponse.toAppMessage()
+}
+
+func (x *HoosatdMessage_GetDatabaseStatsResponse) fromAppMessage(message *appmessage.GetDatabaseStatsResponseMessage) error {
+	var err *RPCError
+	if message.Error != nil {
+		err = &RPCError{Message: message.Error.Message}
+	}
+	buckets := make([]*DatabaseBucketStats, len(message.Buckets))
+	for i, bucket := range message.Buckets {
+		buckets[i] = &DatabaseBucketStats{
+			Owner:    bucket.Owner,
+			Store:    bucket.Store,
+			Bucket:   bucket.Bucket,
+			KeyCount: bucket.KeyCount,
+			Size:     bucket.Size,
+		}
+	}
+	x.GetDatabaseStatsResponse = &GetDatabaseStatsResponseMessage{
+		Buckets:    buckets,
+		DiskSize:   message.DiskSize,
+		Compaction: databaseCompactionStateFromAppMessage(message.Compaction),
+		Collection: databaseStatsCollectionStateFromAppMessage(message.Collection),
+		Error:      err,
+	}
+	return nil
+}
+
+func (x *GetDatabaseStatsResponseMessage) toAppMessage() (appmessage.Message, error) {
+	if x == nil {
+		return nil, errors.Wrapf(errorNil, "GetDatabaseStatsResponseMessage is nil")
+	}
+	rpcErr, err := x.Error.toAppMessage()
+	// Error is an optional field
+	if err != nil && !errors.Is(err, errorNil) {
+		return nil, err
+	}
+	if rpcErr != nil && len(x.Buckets) != 0 {
+		return nil, errors.New("GetDatabaseStatsResponseMessage contains both an error and a response")
+	}
+	buckets := make([]*appmessage.DatabaseBucketStats, len(x.Buckets))
+	for i, bucket := range x.Buckets {
+		buckets[i], err = bucket.toAppMessage()
+		if err != nil {
+			return nil, err
+		}
+	}
+	return &appmessage.GetDatabaseStatsResponseMessage{
+		Buckets:    buckets,
+		DiskSize:   x.DiskSize,
+		Compaction: x.Compaction.toAppMessage(),
+		Collection: x.Collection.toAppMessage(),
+		Error:      rpcErr,
+	}, nil
+}
+
+func (x *DatabaseBucketStats) toAppMessage() (*appmessage.DatabaseBucketStats, error) {
+	if x == nil {
+		return nil, errors.Wrapf(errorNil, "DatabaseBucketStats is nil")
+	}
+	return &appmessage.DatabaseBucketStats{
+		Owner:    x.Owner,
+		Store:    x.Store,
+		Bucket:   x.Bucket,
+		KeyCount: x.KeyCount,
+		Size:     x.Size,
+	}, nil
+}
+
+// toAppMessage returns nil for a nil DatabaseCompactionState, which responses
+// with an error have
+func (x *DatabaseCompactionState) toAppMessage() *appmessage.DatabaseCompactionState {
+	if x == nil {
+		return nil
+	}
+	return &appmessage.DatabaseCompactionState{
+		IsCompacting: x.IsCompacting,
+		Progress:     x.Progress,
+		LastError:    x.LastError,
+	}
+}
+
+func databaseCompactionStateFromAppMessage(state *appmessage.DatabaseCompactionState) *DatabaseCompactionState {
+	if state == nil {
+		return nil
+	}
+	return &DatabaseCompactionState{
+		IsCompacting: state.IsCompacting,
+		Progress:     state.Progress,
+		LastError:    state.LastError,
+	}
+}
+
+// toAppMessage returns nil for a nil DatabaseStatsCollectionState, which
+// responses with an error have
+func (x *DatabaseStatsCollectionState) toAppMessage() *appmessage.DatabaseStatsCollectionState {
+	if x == nil {
+		return nil
+	}
+	return &appmessage.DatabaseStatsCollectionState{
+		IsCollecting: x.IsCollecting,
+		CollectedAt:  x.CollectedAt,
+		LastError:    x.LastError,
+	}
+}
+
+func databaseStatsCollectionStateFromAppMessage(state *appmessage.DatabaseStatsCollectionState) *DatabaseStatsCollectionState {
+	if state == nil {
+		return nil
+	}
+	return &DatabaseStatsCollectionState{
+		IsCollecting: state.IsCollecting,
+		CollectedAt:  state.CollectedAt,
+		LastError:    state.LastError,
+	}
+}
@@ -15,6 +15,8 @@ func (x *HoosatdMessage_GetUtxosByAddressesRequest) toAppMessage() (appmessage.M
 func (x *HoosatdMessage_GetUtxosByAddressesRequest) fromAppMessage(message *appmessage.GetUTXOsByAddressesRequestMessage) error {
 	x.GetUtxosByAddressesRequest = &GetUtxosByAddressesRequestMessage{
 		Addresses: message.Addresses,
+		Cursor:    message.Cursor,
+		Limit:     message.Limit,
 	}
 	return nil
 }
@@ -25,6 +27,8 @@ func (x *GetUtxosByAddressesRequestMessage) toAppMessage() (appmessage.Message,
 	}
 	return &appmessage.GetUTXOsByAddressesRequestMessage{
 		Addresses: x.Addresses,
+		Cursor:    x.Cursor,
+		Limit:     x.Limit,
 	}, nil
 }
 
@@ -46,8 +50,9 @@ func (x *HoosatdMessage_GetUtxosByAddressesResponse) fromAppMessage(message *app
 		entries[i].fromAppMessage(entry)
 	}
 	x.GetUtxosByAddressesResponse = &GetUtxosByAddressesResponseMessage{
-		Entries: entries,
-		Error:   err,
+		Entries:    entries,
+		NextCursor: message.NextCursor,
+		Error:      err,
 	}
 	return nil
 }
@@ -76,7 +81,8 @@ func (x *GetUtxosByAddressesResponseMessage) toAppMessage() (appmessage.Message,
 	}
 
 	return &appmessage.GetUTXOsByAddressesResponseMessage{
-		Entries: entries,
-		Error:   rpcErr,
+		Entries:    entries,
+		NextCursor: x.NextCursor,
+		Error:      rpcErr,
 	}, nil
 }
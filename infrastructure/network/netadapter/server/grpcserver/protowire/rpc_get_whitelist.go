@@ -0,0 +1,80 @@
+package protowire
+
+import (
+	"github.com/Hoosat-Oy/HTND/app/appmessage"
+	"github.com/pkg/errors"
+)
+
+func (x *HoosatdMessage_GetWhitelistRequest) toAppMessage() (appmessage.Message, error) {
+	return &appmessage.GetWhitelistRequestMessage{}, nil
+}
+
+func (x *HoosatdMessage_GetWhitelistRequest) fromAppMessage(_ *appmessage.GetWhitelistRequestMessage) error {
+	x.GetWhitelistRequest = &GetWhitelistRequestMessage{}
+	return nil
+}
+
+func (x *HoosatdMessage_GetWhitelistResponse) toAppMessage() (appmessage.Message, error) {
+	if x == nil {
+		return nil, errors.Wrapf(errorNil, "HoosatdMessage_GetWhitelistResponse is nil")
+	}
+	return x.GetWhitelistResponse.toAppMessage()
+}
+
+func (x *HoosatdMessage_GetWhitelistResponse) fromAppMessage(message *appmessage.GetWhitelistResponseMessage) error {
+	var err *RPCError
+	if message.Error != nil {
+		err = &RPCError{Message: message.Error.Message}
+	}
+	entries := make([]*WhitelistEntry, len(message.Entries))
+	for i, entry := range message.Entries {
+		entries[i] = &WhitelistEntry{
+			Subnet:       entry.Subnet,
+			Reason:       entry.Reason,
+			AddedAt:      entry.AddedAt,
+			IsFromConfig: entry.IsFromConfig,
+		}
+	}
+	x.GetWhitelistResponse = &GetWhitelistResponseMessage{
+		Entries: entries,
+		Error:   err,
+	}
+	return nil
+}
+
+func (x *GetWhitelistResponseMessage) toAppMessage() (appmessage.Message, error) {
+	if x == nil {
+		return nil, errors.Wrapf(errorNil, "GetWhitelistResponseMessage is nil")
+	}
+	rpcErr, err := x.Error.toAppMessage()
+	// Error is an optional field
+	if err != nil && !errors.Is(err, errorNil) {
+		return nil, err
+	}
+	if rpcErr != nil && len(x.Entries) != 0 {
+		return nil, errors.New("GetWhitelistResponseMessage contains both an error and a response")
+	}
+	entries := make([]*appmessage.WhitelistEntry, len(x.Entries))
+	for i, entry := range x.Entries {
+		entries[i], err = entry.toAppMessage()
+		if err != nil {
+			return nil, err
+		}
+	}
+	return &appmessage.GetWhitelistResponseMessage{
+		Entries: entries,
+		Error:   rpcErr,
+	}, nil
+}
+
+func (x *WhitelistEntry) toAppMessage() (*appmessage.WhitelistEntry, error) {
+	if x == nil {
+		return nil, errors.Wrapf(errorNil, "WhitelistEntry is nil")
+	}
+	return &appmessage.WhitelistEntry{
+		Subnet:       x.Subnet,
+		Reason:       x.Reason,
+		AddedAt:      x.AddedAt,
+		IsFromConfig: x.IsFromConfig,
+	}, nil
+}
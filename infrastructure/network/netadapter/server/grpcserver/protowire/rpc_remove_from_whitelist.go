@@ -0,0 +1,61 @@
+package protowire
+
+import (
+	"github.com/Hoosat-Oy/HTND/app/appmessage"
+	"github.com/pkg/errors"
+)
+
+func (x *HoosatdMessage_RemoveFromWhitelistRequest) toAppMessage() (appmessage.Message, error) {
+	if x == nil {
+		return nil, errors.Wrapf(errorNil, "HoosatdMessage_RemoveFromWhitelistRequest is nil")
+	}
+	return x.RemoveFromWhitelistRequest.toAppMessage()
+}
+
+func (x *HoosatdMessage_RemoveFromWhitelistRequest) fromAppMessage(message *appmessage.RemoveFromWhitelistRequestMessage) error {
+	x.RemoveFromWhitelistRequest = &RemoveFromWhitelistRequestMessage{
+		Subnet: message.Subnet,
+	}
+	return nil
+}
+
+func (x *RemoveFromWhitelistRequestMessage) toAppMessage() (appmessage.Message, error) {
+	if x == nil {
+		return nil, errors.Wrapf(errorNil, "RemoveFromWhitelistRequestMessage is nil")
+	}
+	return &appmessage.RemoveFromWhitelistRequestMessage{
+		Subnet: x.Subnet,
+	}, nil
+}
+
+func (x *HoosatdMessage_RemoveFromWhitelistResponse) toAppMessage() (appmessage.Message, error) {
+	if x == nil {
+		return nil, errors.Wrapf(errorNil, "HoosatdMessage_RemoveFromWhitelistResponse is nil")
+	}
+	return x.RemoveFromWhitelistResponse.toAppMessage()
+}
+
+func (x *HoosatdMessage_RemoveFromWhitelistResponse) fromAppMessage(message *appmessage.RemoveFromWhitelistResponseMessage) error {
+	var err *RPCError
+	if message.Error != nil {
+		err = &RPCError{Message: message.Error.Message}
+	}
+	x.RemoveFromWhitelistResponse = &RemoveFromWhitelistResponseMessage{
+		Error: err,
+	}
+	return nil
+}
+
+func (x *RemoveFromWhitelistResponseMessage) toAppMessage() (appmessage.Message, error) {
+	if x == nil {
+		return nil, errors.Wrapf(errorNil, "RemoveFromWhitelistResponseMessage is nil")
+	}
+	rpcErr, err := x.Error.toAppMessage()
+	// Error is an optional field
+	if err != nil && !errors.Is(err, errorNil) {
+		return nil, err
+	}
+	return &appmessage.RemoveFromWhitelistResponseMessage{
+		Error: rpcErr,
+	}, nil
+}
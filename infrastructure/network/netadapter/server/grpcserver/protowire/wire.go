@@ -359,6 +359,27 @@ func toP2PPayload(message appmessage.Message) (isHoosatdMessage_Payload, error)
 			return nil, err
 		}
 		return payload, nil
+	case *appmessage.MsgCompactBlock:
+		payload := new(HoosatdMessage_CompactBlock)
+		err := payload.fromAppMessage(message)
+		if err != nil {
+			return nil, err
+		}
+		return payload, nil
+	case *appmessage.MsgRequestBlockTransactions:
+		payload := new(HoosatdMessage_RequestBlockTransactions)
+		err := payload.fromAppMessage(message)
+		if err != nil {
+			return nil, err
+		}
+		return payload, nil
+	case *appmessage.MsgBlockTransactions:
+		payload := new(HoosatdMessage_BlockTransactions)
+		err := payload.fromAppMessage(message)
+		if err != nil {
+			return nil, err
+		}
+		return payload, nil
 	default:
 		return nil, nil
 	}
@@ -968,6 +989,104 @@ func toRPCPayload(message appmessage.Message) (isHoosatdMessage_Payload, error)
 			return nil, err
 		}
 		return payload, nil
+	case *appmessage.GetBansRequestMessage:
+		payload := new(HoosatdMessage_GetBansRequest)
+		err := payload.fromAppMessage(message)
+		if err != nil {
+			return nil, err
+		}
+		return payload, nil
+	case *appmessage.GetBansResponseMessage:
+		payload := new(HoosatdMessage_GetBansResponse)
+		err := payload.fromAppMessage(message)
+		if err != nil {
+			return nil, err
+		}
+		return payload, nil
+	case *appmessage.GetWhitelistRequestMessage:
+		payload := new(HoosatdMessage_GetWhitelistRequest)
+		err := payload.fromAppMessage(message)
+		if err != nil {
+			return nil, err
+		}
+		return payload, nil
+	case *appmessage.GetWhitelistResponseMessage:
+		payload := new(HoosatdMessage_GetWhitelistResponse)
+		err := payload.fromAppMessage(message)
+		if err != nil {
+			return nil, err
+		}
+		return payload, nil
+	case *appmessage.AddToWhitelistRequestMessage:
+		payload := new(HoosatdMessage_AddToWhitelistRequest)
+		err := payload.fromAppMessage(message)
+		if err != nil {
+			return nil, err
+		}
+		return payload, nil
+	case *appmessage.AddToWhitelistResponseMessage:
+		payload := new(HoosatdMessage_AddToWhitelistResponse)
+		err := payload.fromAppMessage(message)
+		if err != nil {
+			return nil, err
+		}
+		return payload, nil
+	case *appmessage.RemoveFromWhitelistRequestMessage:
+		payload := new(HoosatdMessage_RemoveFromWhitelistRequest)
+		err := payload.fromAppMessage(message)
+		if err != nil {
+			return nil, err
+		}
+		return payload, nil
+	case *appmessage.RemoveFromWhitelistResponseMessage:
+		payload := new(HoosatdMessage_RemoveFromWhitelistResponse)
+		err := payload.fromAppMessage(message)
+		if err != nil {
+			return nil, err
+		}
+		return payload, nil
+	case *appmessage.GetCacheStatsRequestMessage:
+		payload := new(HoosatdMessage_GetCacheStatsRequest)
+		err := payload.fromAppMessage(message)
+		if err != nil {
+			return nil, err
+		}
+		return payload, nil
+	case *appmessage.GetCacheStatsResponseMessage:
+		payload := new(HoosatdMessage_GetCacheStatsResponse)
+		err := payload.fromAppMessage(message)
+		if err != nil {
+			return nil, err
+		}
+		return payload, nil
+	case *appmessage.GetDatabaseStatsRequestMessage:
+		payload := new(HoosatdMessage_GetDatabaseStatsRequest)
+		err := payload.fromAppMessage(message)
+		if err != nil {
+			return nil, err
+		}
+		return payload, nil
+	case *appmessage.GetDatabaseStatsResponseMessage:
+		payload := new(HoosatdMessage_GetDatabaseStatsResponse)
+		err := payload.fromAppMessage(message)
+		if err != nil {
+			return nil, err
+		}
+		return payload, nil
+	case *appmessage.CompactDatabaseRequestMessage:
+		payload := new(HoosatdMessage_CompactDatabaseRequest)
+		err := payload.fromAppMessage(message)
+		if err != nil {
+			return nil, err
+		}
+		return payload, nil
+	case *appmessage.CompactDatabaseResponseMessage:
+		payload := new(HoosatdMessage_CompactDatabaseResponse)
+		err := payload.fromAppMessage(message)
+		if err != nil {
+			return nil, err
+		}
+		return payload, nil
 	default:
 		return nil, nil
 	}
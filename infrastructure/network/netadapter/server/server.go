@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"net"
 
+	"github.com/Hoosat-Oy/HTND/infrastructure/network/netadapter/bandwidth"
 	"github.com/Hoosat-Oy/HTND/infrastructure/network/netadapter/router"
 )
 
@@ -43,4 +44,5 @@ type Connection interface {
 	SetOnDisconnectedHandler(onDisconnectedHandler OnDisconnectedHandler)
 	SetOnInvalidMessageHandler(onInvalidMessageHandler OnInvalidMessageHandler)
 	Address() *net.TCPAddr
+	Bandwidth() *bandwidth.Counter
 }
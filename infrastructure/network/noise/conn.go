@@ -0,0 +1,98 @@
+package noise
+
+import (
+	"encoding/binary"
+	"io"
+	"net"
+	"sync"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	frameLengthSize     = 2
+	maxFrameSize        = 1<<(8*frameLengthSize) - 1
+	maxFramePayloadSize = maxFrameSize - tagSize
+)
+
+// Conn is an encrypted connection. Every write is sent in frames of up to
+// maxFrameSize bytes, each prefixed with its length.
+type Conn struct {
+	net.Conn
+	send            *cipherState
+	receive         *cipherState
+	remoteStaticKey []byte
+
+	readLock   sync.Mutex
+	readBuffer []byte
+	writeLock  sync.Mutex
+}
+
+func newConn(connection net.Conn, send *cipherState, receive *cipherState, remoteStaticKey []byte) *Conn {
+	return &Conn{
+		Conn:            connection,
+		send:            send,
+		receive:         receive,
+		remoteStaticKey: remoteStaticKey,
+	}
+}
+
+// RemoteStaticKey returns the static public key of the remote node, which
+// identifies it
+func (c *Conn) RemoteStaticKey() []byte {
+	return c.remoteStaticKey
+}
+
+func (c *Conn) Read(b []byte) (int, error) {
+	c.readLock.Lock()
+	defer c.readLock.Unlock()
+
+	if len(c.readBuffer) == 0 {
+		header := make([]byte, frameLengthSize)
+		_, err := io.ReadFull(c.Conn, header)
+		if err != nil {
+			return 0, err
+		}
+		frame := make([]byte, binary.BigEndian.Uint16(header))
+		_, err = io.ReadFull(c.Conn, frame)
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				return 0, io.ErrUnexpectedEOF
+			}
+			return 0, err
+		}
+		c.readBuffer, err = c.receive.decrypt(nil, frame)
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	n := copy(b, c.readBuffer)
+	c.readBuffer = c.readBuffer[n:]
+	return n, nil
+}
+
+func (c *Conn) Write(b []byte) (int, error) {
+	c.writeLock.Lock()
+	defer c.writeLock.Unlock()
+
+	written := 0
+	for written < len(b) {
+		payloadSize := len(b) - written
+		if payloadSize > maxFramePayloadSize {
+			payloadSize = maxFramePayloadSize
+		}
+		ciphertext, err := c.send.encrypt(nil, b[written:written+payloadSize])
+		if err != nil {
+			return written, err
+		}
+		frame := make([]byte, frameLengthSize, frameLengthSize+len(ciphertext))
+		binary.BigEndian.PutUint16(frame, uint16(len(ciphertext)))
+		_, err = c.Conn.Write(append(frame, ciphertext...))
+		if err != nil {
+			return written, err
+		}
+		written += payloadSize
+	}
+	return written, nil
+}
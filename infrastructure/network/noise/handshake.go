@@ -0,0 +1,349 @@
+package noise
+
+import (
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"io"
+	"math"
+	"net"
+
+	"github.com/pkg/errors"
+	"golang.org/x/crypto/chacha20poly1305"
+	"golang.org/x/crypto/curve25519"
+)
+
+const (
+	// protocolName is the name of the Noise protocol that is used. It is exactly
+	// 32 bytes long, so it's used as the initial handshake hash as is.
+	protocolName = "Noise_XX_25519_ChaChaPoly_SHA256"
+	prologue     = "htnd p2p v1"
+
+	keySize = 32
+	tagSize = chacha20poly1305.Overhead
+
+	// The sizes of the handshake messages, without the magic
+	initiatorMessageSize = keySize                                 // -> e
+	responderMessageSize = keySize + (keySize + tagSize) + tagSize // <- e, ee, s, es
+	finalMessageSize     = (keySize + tagSize) + tagSize           // -> s, se
+)
+
+// magic is sent by both sides before their first handshake message. It lets a node
+// tell an encrypted connection from a plain gRPC one, which starts with the HTTP/2
+// connection preface.
+var magic = []byte("HTNNOISE")
+
+// errNotSupported is returned by clientHandshake if the remote node doesn't respond
+// with a handshake, which is what nodes that don't support encryption do
+var errNotSupported = errors.New("the remote node doesn't support encrypted connections")
+
+type cipherState struct {
+	aead  cipher.AEAD
+	nonce uint64
+}
+
+func newCipherState(key []byte) (*cipherState, error) {
+	aead, err := chacha20poly1305.New(key)
+	if err != nil {
+		return nil, err
+	}
+	return &cipherState{aead: aead}, nil
+}
+
+func (cs *cipherState) nextNonce() ([]byte, error) {
+	if cs.nonce == math.MaxUint64 {
+		return nil, errors.New("the nonces of the connection are exhausted")
+	}
+	nonce := make([]byte, chacha20poly1305.NonceSize)
+	binary.LittleEndian.PutUint64(nonce[4:], cs.nonce)
+	cs.nonce++
+	return nonce, nil
+}
+
+func (cs *cipherState) encrypt(additionalData []byte, plaintext []byte) ([]byte, error) {
+	nonce, err := cs.nextNonce()
+	if err != nil {
+		return nil, err
+	}
+	return cs.aead.Seal(nil, nonce, plaintext, additionalData), nil
+}
+
+func (cs *cipherState) decrypt(additionalData []byte, ciphertext []byte) ([]byte, error) {
+	nonce, err := cs.nextNonce()
+	if err != nil {
+		return nil, err
+	}
+	plaintext, err := cs.aead.Open(nil, nonce, ciphertext, additionalData)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to decrypt a message")
+	}
+	return plaintext, nil
+}
+
+// symmetricState is the SymmetricState object of the Noise specification
+type symmetricState struct {
+	chainingKey []byte
+	hash        []byte
+	cipher      *cipherState
+}
+
+func newSymmetricState() *symmetricState {
+	hash := []byte(protocolName)
+	ss := &symmetricState{
+		chainingKey: append([]byte{}, hash...),
+		hash:        hash,
+	}
+	ss.mixHash([]byte(prologue))
+	return ss
+}
+
+func (ss *symmetricState) mixHash(data []byte) {
+	hasher := sha256.New()
+	hasher.Write(ss.hash)
+	hasher.Write(data)
+	ss.hash = hasher.Sum(nil)
+}
+
+func (ss *symmetricState) mixKey(inputKeyMaterial []byte) error {
+	var key []byte
+	ss.chainingKey, key = hkdf(ss.chainingKey, inputKeyMaterial)
+	cipher, err := newCipherState(key)
+	if err != nil {
+		return err
+	}
+	ss.cipher = cipher
+	return nil
+}
+
+func (ss *symmetricState) encryptAndHash(plaintext []byte) ([]byte, error) {
+	ciphertext := plaintext
+	if ss.cipher != nil {
+		var err error
+		ciphertext, err = ss.cipher.encrypt(ss.hash, plaintext)
+		if err != nil {
+			return nil, err
+		}
+	}
+	ss.mixHash(ciphertext)
+	return ciphertext, nil
+}
+
+func (ss *symmetricState) decryptAndHash(ciphertext []byte) ([]byte, error) {
+	plaintext := ciphertext
+	if ss.cipher != nil {
+		var err error
+		plaintext, err = ss.cipher.decrypt(ss.hash, ciphertext)
+		if err != nil {
+			return nil, err
+		}
+	}
+	ss.mixHash(ciphertext)
+	return plaintext, nil
+}
+
+// split returns the cipher of the initiator to the responder, and the cipher of
+// the responder to the initiator
+func (ss *symmetricState) split() (*cipherState, *cipherState, error) {
+	initiatorKey, responderKey := hkdf(ss.chainingKey, nil)
+	initiatorCipher, err := newCipherState(initiatorKey)
+	if err != nil {
+		return nil, nil, err
+	}
+	responderCipher, err := newCipherState(responderKey)
+	if err != nil {
+		return nil, nil, err
+	}
+	return initiatorCipher, responderCipher, nil
+}
+
+func hkdf(chainingKey []byte, inputKeyMaterial []byte) ([]byte, []byte) {
+	tempKey := hmacSHA256(chainingKey, inputKeyMaterial)
+	output1 := hmacSHA256(tempKey, []byte{1})
+	output2 := hmacSHA256(tempKey, append(append([]byte{}, output1...), 2))
+	return output1, output2
+}
+
+func hmacSHA256(key []byte, data []byte) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write(data)
+	return mac.Sum(nil)
+}
+
+func dh(privateKey []byte, publicKey []byte) ([]byte, error) {
+	sharedSecret, err := curve25519.X25519(privateKey, publicKey)
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid public key")
+	}
+	return sharedSecret, nil
+}
+
+func generateKeyPair() (privateKey []byte, publicKey []byte, err error) {
+	privateKey = make([]byte, keySize)
+	_, err = rand.Read(privateKey)
+	if err != nil {
+		return nil, nil, err
+	}
+	publicKey, err = curve25519.X25519(privateKey, curve25519.Basepoint)
+	if err != nil {
+		return nil, nil, err
+	}
+	return privateKey, publicKey, nil
+}
+
+// clientHandshake runs the initiator side of the Noise XX handshake over connection
+func clientHandshake(connection net.Conn, staticKey *StaticKey) (*Conn, error) {
+	ss := newSymmetricState()
+	ephemeralPrivate, ephemeralPublic, err := generateKeyPair()
+	if err != nil {
+		return nil, err
+	}
+
+	// -> e
+	ss.mixHash(ephemeralPublic)
+	payload, err := ss.encryptAndHash(nil)
+	if err != nil {
+		return nil, err
+	}
+	message := append(append(append([]byte{}, magic...), ephemeralPublic...), payload...)
+	_, err = connection.Write(message)
+	if err != nil {
+		return nil, err
+	}
+
+	// <- e, ee, s, es
+	responseMagic := make([]byte, len(magic))
+	_, err = io.ReadFull(connection, responseMagic)
+	if err != nil || !hmac.Equal(responseMagic, magic) {
+		return nil, errNotSupported
+	}
+	response := make([]byte, responderMessageSize)
+	_, err = io.ReadFull(connection, response)
+	if err != nil {
+		return nil, err
+	}
+	remoteEphemeral := response[:keySize]
+	ss.mixHash(remoteEphemeral)
+	err = mixDH(ss, ephemeralPrivate, remoteEphemeral)
+	if err != nil {
+		return nil, err
+	}
+	remoteStatic, err := ss.decryptAndHash(response[keySize : 2*keySize+tagSize])
+	if err != nil {
+		return nil, err
+	}
+	err = mixDH(ss, ephemeralPrivate, remoteStatic)
+	if err != nil {
+		return nil, err
+	}
+	_, err = ss.decryptAndHash(response[2*keySize+tagSize:])
+	if err != nil {
+		return nil, err
+	}
+
+	// -> s, se
+	encryptedStatic, err := ss.encryptAndHash(staticKey.public)
+	if err != nil {
+		return nil, err
+	}
+	err = mixDH(ss, staticKey.private, remoteEphemeral)
+	if err != nil {
+		return nil, err
+	}
+	payload, err = ss.encryptAndHash(nil)
+	if err != nil {
+		return nil, err
+	}
+	_, err = connection.Write(append(encryptedStatic, payload...))
+	if err != nil {
+		return nil, err
+	}
+
+	initiatorCipher, responderCipher, err := ss.split()
+	if err != nil {
+		return nil, err
+	}
+	return newConn(connection, initiatorCipher, responderCipher, remoteStatic), nil
+}
+
+// serverHandshake runs the responder side of the Noise XX handshake over connection.
+// The magic that precedes the first message is expected to have been read already.
+func serverHandshake(connection net.Conn, staticKey *StaticKey) (*Conn, error) {
+	ss := newSymmetricState()
+
+	// -> e
+	message := make([]byte, initiatorMessageSize)
+	_, err := io.ReadFull(connection, message)
+	if err != nil {
+		return nil, err
+	}
+	remoteEphemeral := message
+	ss.mixHash(remoteEphemeral)
+	_, err = ss.decryptAndHash(nil)
+	if err != nil {
+		return nil, err
+	}
+
+	// <- e, ee, s, es
+	ephemeralPrivate, ephemeralPublic, err := generateKeyPair()
+	if err != nil {
+		return nil, err
+	}
+	ss.mixHash(ephemeralPublic)
+	err = mixDH(ss, ephemeralPrivate, remoteEphemeral)
+	if err != nil {
+		return nil, err
+	}
+	encryptedStatic, err := ss.encryptAndHash(staticKey.public)
+	if err != nil {
+		return nil, err
+	}
+	err = mixDH(ss, staticKey.private, remoteEphemeral)
+	if err != nil {
+		return nil, err
+	}
+	payload, err := ss.encryptAndHash(nil)
+	if err != nil {
+		return nil, err
+	}
+	response := append(append([]byte{}, magic...), ephemeralPublic...)
+	response = append(append(response, encryptedStatic...), payload...)
+	_, err = connection.Write(response)
+	if err != nil {
+		return nil, err
+	}
+
+	// -> s, se
+	message = make([]byte, finalMessageSize)
+	_, err = io.ReadFull(connection, message)
+	if err != nil {
+		return nil, err
+	}
+	remoteStatic, err := ss.decryptAndHash(message[:keySize+tagSize])
+	if err != nil {
+		return nil, err
+	}
+	err = mixDH(ss, ephemeralPrivate, remoteStatic)
+	if err != nil {
+		return nil, err
+	}
+	_, err = ss.decryptAndHash(message[keySize+tagSize:])
+	if err != nil {
+		return nil, err
+	}
+
+	initiatorCipher, responderCipher, err := ss.split()
+	if err != nil {
+		return nil, err
+	}
+	return newConn(connection, responderCipher, initiatorCipher, remoteStatic), nil
+}
+
+func mixDH(ss *symmetricState, privateKey []byte, publicKey []byte) error {
+	sharedSecret, err := dh(privateKey, publicKey)
+	if err != nil {
+		return err
+	}
+	return ss.mixKey(sharedSecret)
+}
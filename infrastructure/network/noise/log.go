@@ -0,0 +1,9 @@
+package noise
+
+import (
+	"github.com/Hoosat-Oy/HTND/infrastructure/logger"
+	"github.com/Hoosat-Oy/HTND/util/panics"
+)
+
+var log = logger.RegisterSubSystem("NOIS")
+var spawn = panics.GoroutineWrapperFunc(log)
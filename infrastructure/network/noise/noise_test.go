@@ -0,0 +1,168 @@
+package noise
+
+import (
+	"bytes"
+	"io"
+	"net"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func newTestStaticKey(t *testing.T) *StaticKey {
+	staticKey, err := GenerateStaticKey()
+	if err != nil {
+		t.Fatalf("GenerateStaticKey: %s", err)
+	}
+	return staticKey
+}
+
+// serveEcho accepts connections from listener and echoes back everything they send
+func serveEcho(listener net.Listener) {
+	for {
+		connection, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		go func() {
+			defer connection.Close()
+			_, _ = io.Copy(connection, connection)
+		}()
+	}
+}
+
+// checkEcho sends a message that spans several frames over connection and checks
+// that it's echoed back intact
+func checkEcho(t *testing.T, connection net.Conn) {
+	message := bytes.Repeat([]byte("htnd"), 50000)
+	go func() {
+		_, _ = connection.Write(message)
+	}()
+	echo := make([]byte, len(message))
+	_, err := io.ReadFull(connection, echo)
+	if err != nil {
+		t.Fatalf("ReadFull: %s", err)
+	}
+	if !bytes.Equal(echo, message) {
+		t.Fatalf("The echoed message is different from the one that was sent")
+	}
+}
+
+func listen(t *testing.T) net.Listener {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Listen: %s", err)
+	}
+	return listener
+}
+
+func TestEncryptedConnection(t *testing.T) {
+	serverKey := newTestStaticKey(t)
+	listener := NewTransport(serverKey, nil).Listener(listen(t))
+	defer listener.Close()
+	go serveEcho(listener)
+
+	clientKey := newTestStaticKey(t)
+	address := listener.Addr().String()
+	transport := NewTransport(clientKey, map[string][]byte{address: serverKey.PublicKey()})
+	connection, err := transport.Dial(net.DialTimeout, address, 5*time.Second)
+	if err != nil {
+		t.Fatalf("Dial: %s", err)
+	}
+	defer connection.Close()
+
+	secureConnection, ok := connection.(*Conn)
+	if !ok {
+		t.Fatalf("The connection is not encrypted")
+	}
+	if !bytes.Equal(secureConnection.RemoteStaticKey(), serverKey.PublicKey()) {
+		t.Fatalf("Unexpected remote identity %x", secureConnection.RemoteStaticKey())
+	}
+	checkEcho(t, connection)
+}
+
+func TestPinnedIdentityMismatch(t *testing.T) {
+	listener := NewTransport(newTestStaticKey(t), nil).Listener(listen(t))
+	defer listener.Close()
+	go serveEcho(listener)
+
+	address := listener.Addr().String()
+	transport := NewTransport(newTestStaticKey(t), map[string][]byte{address: newTestStaticKey(t).PublicKey()})
+	_, err := transport.Dial(net.DialTimeout, address, 5*time.Second)
+	if err == nil || !strings.Contains(err.Error(), "instead of the pinned") {
+		t.Fatalf("Unexpected error when dialing a peer with a different identity: %v", err)
+	}
+}
+
+func TestPlaintextFallback(t *testing.T) {
+	// A node that doesn't support encryption rejects the handshake the same way
+	// it rejects any invalid HTTP/2 preface: by closing the connection
+	plaintextListener := listen(t)
+	defer plaintextListener.Close()
+	go func() {
+		for {
+			connection, err := plaintextListener.Accept()
+			if err != nil {
+				return
+			}
+			prefix := make([]byte, len(magic))
+			_, err = io.ReadFull(connection, prefix)
+			if err != nil || bytes.Equal(prefix, magic) {
+				connection.Close()
+				continue
+			}
+			go func() {
+				defer connection.Close()
+				_, _ = connection.Write(prefix)
+				_, _ = io.Copy(connection, connection)
+			}()
+		}
+	}()
+
+	address := plaintextListener.Addr().String()
+	connection, err := NewTransport(newTestStaticKey(t), nil).Dial(net.DialTimeout, address, 5*time.Second)
+	if err != nil {
+		t.Fatalf("Dial: %s", err)
+	}
+	defer connection.Close()
+	if _, ok := connection.(*Conn); ok {
+		t.Fatalf("The connection to a plaintext node is encrypted")
+	}
+	checkEcho(t, connection)
+
+	// A pinned peer must not be downgraded to plaintext
+	transport := NewTransport(newTestStaticKey(t), map[string][]byte{address: newTestStaticKey(t).PublicKey()})
+	_, err = transport.Dial(net.DialTimeout, address, 5*time.Second)
+	if err == nil {
+		t.Fatalf("Dialing a pinned peer that doesn't support encryption unexpectedly succeeded")
+	}
+}
+
+func TestListenerAcceptsPlaintext(t *testing.T) {
+	listener := NewTransport(newTestStaticKey(t), nil).Listener(listen(t))
+	defer listener.Close()
+	go serveEcho(listener)
+
+	connection, err := net.Dial("tcp", listener.Addr().String())
+	if err != nil {
+		t.Fatalf("Dial: %s", err)
+	}
+	defer connection.Close()
+	checkEcho(t, connection)
+}
+
+func TestLoadOrCreateStaticKey(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "p2p.key")
+	staticKey, err := LoadOrCreateStaticKey(path)
+	if err != nil {
+		t.Fatalf("LoadOrCreateStaticKey: %s", err)
+	}
+	loadedStaticKey, err := LoadOrCreateStaticKey(path)
+	if err != nil {
+		t.Fatalf("LoadOrCreateStaticKey: %s", err)
+	}
+	if !bytes.Equal(staticKey.PublicKey(), loadedStaticKey.PublicKey()) {
+		t.Fatalf("The loaded key is different from the created one")
+	}
+}
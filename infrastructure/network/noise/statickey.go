@@ -0,0 +1,71 @@
+package noise
+
+import (
+	"encoding/hex"
+	"os"
+	"strings"
+
+	"github.com/pkg/errors"
+	"golang.org/x/crypto/curve25519"
+)
+
+// StaticKey is the long-term X25519 key pair that identifies a node
+type StaticKey struct {
+	private []byte
+	public  []byte
+}
+
+// GenerateStaticKey returns a new random static key
+func GenerateStaticKey() (*StaticKey, error) {
+	private, public, err := generateKeyPair()
+	if err != nil {
+		return nil, err
+	}
+	return &StaticKey{private: private, public: public}, nil
+}
+
+// LoadOrCreateStaticKey reads the static key that is stored in path, or
+// generates one and stores it there if the file doesn't exist
+func LoadOrCreateStaticKey(path string) (*StaticKey, error) {
+	content, err := os.ReadFile(path)
+	if err == nil {
+		private, err := hex.DecodeString(strings.TrimSpace(string(content)))
+		if err != nil || len(private) != keySize {
+			return nil, errors.Errorf("%s doesn't contain a valid P2P key", path)
+		}
+		public, err := curve25519.X25519(private, curve25519.Basepoint)
+		if err != nil {
+			return nil, err
+		}
+		return &StaticKey{private: private, public: public}, nil
+	}
+	if !os.IsNotExist(err) {
+		return nil, err
+	}
+
+	staticKey, err := GenerateStaticKey()
+	if err != nil {
+		return nil, err
+	}
+	err = os.WriteFile(path, []byte(hex.EncodeToString(staticKey.private)+"\n"), 0600)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to store the P2P key in %s", path)
+	}
+	log.Infof("Generated a new P2P key in %s", path)
+	return staticKey, nil
+}
+
+// PublicKey returns the public part of the key, which peers see as the identity
+// of this node
+func (k *StaticKey) PublicKey() []byte {
+	return k.public
+}
+
+// ParsePublicKey parses a hex encoded public key, as printed for a node's identity
+func ParsePublicKey(publicKeyHex string) ([]byte, error) {
+	publicKey, err := hex.DecodeString(publicKeyHex)
+	if err != nil || len(publicKey) != keySize {
+		return nil, errors.Errorf("%s is not a valid %d-byte hex encoded public key", publicKeyHex, keySize)
+	}
+	return publicKey, nil
+}
@@ -0,0 +1,203 @@
+package noise
+
+import (
+	"bytes"
+	"io"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// handshakeTimeout limits the time an inbound connection has to complete its handshake
+const handshakeTimeout = 10 * time.Second
+
+// DialFunc dials a TCP connection to address
+type DialFunc func(network string, address string, timeout time.Duration) (net.Conn, error)
+
+// Transport encrypts the P2P connections of a node. Encryption is opportunistic:
+// connections with nodes that don't support it fall back to plaintext, except for
+// pinned peers, which must prove their identity.
+type Transport struct {
+	staticKey  *StaticKey
+	pinnedKeys map[string][]byte
+}
+
+// NewTransport returns a Transport that identifies the node with staticKey.
+// pinnedKeys maps the addresses of outbound peers to the public keys they must have.
+func NewTransport(staticKey *StaticKey, pinnedKeys map[string][]byte) *Transport {
+	return &Transport{
+		staticKey:  staticKey,
+		pinnedKeys: pinnedKeys,
+	}
+}
+
+// Dial connects to address using dial, and encrypts the connection if the remote
+// node supports it. timeout covers both the connection and the handshake.
+func (t *Transport) Dial(dial DialFunc, address string, timeout time.Duration) (net.Conn, error) {
+	deadline := time.Time{}
+	if timeout > 0 {
+		deadline = time.Now().Add(timeout)
+	}
+	pinnedKey, isPinned := t.pinnedKeys[address]
+
+	connection, err := dial("tcp", address, timeout)
+	if err != nil {
+		return nil, err
+	}
+	err = connection.SetDeadline(deadline)
+	if err != nil {
+		connection.Close()
+		return nil, err
+	}
+	secureConnection, err := clientHandshake(connection, t.staticKey)
+	if err != nil {
+		connection.Close()
+		if isPinned || !errors.Is(err, errNotSupported) {
+			return nil, errors.Wrapf(err, "encrypted handshake with %s failed", address)
+		}
+
+		log.Debugf("%s doesn't support encrypted connections, connecting without encryption", address)
+		if !deadline.IsZero() {
+			timeout = time.Until(deadline)
+			if timeout <= 0 {
+				return nil, errors.Errorf("timed out connecting to %s", address)
+			}
+		}
+		return dial("tcp", address, timeout)
+	}
+
+	if isPinned && !bytes.Equal(secureConnection.RemoteStaticKey(), pinnedKey) {
+		connection.Close()
+		return nil, errors.Errorf("%s has the identity %x instead of the pinned %x",
+			address, secureConnection.RemoteStaticKey(), pinnedKey)
+	}
+	err = connection.SetDeadline(time.Time{})
+	if err != nil {
+		connection.Close()
+		return nil, err
+	}
+	log.Debugf("Encrypted connection to %s with identity %x", address, secureConnection.RemoteStaticKey())
+	return secureConnection, nil
+}
+
+// Listener returns a listener that runs the handshake with every connection that
+// listener accepts. Connections that don't start with a handshake are returned as is.
+func (t *Transport) Listener(listener net.Listener) net.Listener {
+	secureListener := &secureListener{
+		Listener:  listener,
+		transport: t,
+		accepted:  make(chan acceptResult),
+		quit:      make(chan struct{}),
+	}
+	spawn("secureListener.acceptLoop", secureListener.acceptLoop)
+	return secureListener
+}
+
+type acceptResult struct {
+	connection net.Conn
+	err        error
+}
+
+type secureListener struct {
+	net.Listener
+	transport *Transport
+	accepted  chan acceptResult
+	quit      chan struct{}
+	closeOnce sync.Once
+}
+
+func (l *secureListener) Accept() (net.Conn, error) {
+	select {
+	case result := <-l.accepted:
+		return result.connection, result.err
+	case <-l.quit:
+		return nil, net.ErrClosed
+	}
+}
+
+func (l *secureListener) Close() error {
+	l.closeOnce.Do(func() {
+		close(l.quit)
+	})
+	return l.Listener.Close()
+}
+
+func (l *secureListener) acceptLoop() {
+	for {
+		connection, err := l.Listener.Accept()
+		if err != nil {
+			select {
+			case l.accepted <- acceptResult{err: err}:
+			case <-l.quit:
+				return
+			}
+			if temporaryErr, ok := err.(interface{ Temporary() bool }); ok && temporaryErr.Temporary() {
+				continue
+			}
+			return
+		}
+
+		// Handshakes run in parallel, so that a slow peer doesn't hold up the others
+		spawn("secureListener.handshake", func() {
+			secureConnection, err := l.transport.accept(connection)
+			if err != nil {
+				log.Debugf("Dropping the connection from %s: %s", connection.RemoteAddr(), err)
+				connection.Close()
+				return
+			}
+			select {
+			case l.accepted <- acceptResult{connection: secureConnection}:
+			case <-l.quit:
+				secureConnection.Close()
+			}
+		})
+	}
+}
+
+// accept runs the responder side of the handshake if connection starts with one
+func (t *Transport) accept(connection net.Conn) (net.Conn, error) {
+	err := connection.SetDeadline(time.Now().Add(handshakeTimeout))
+	if err != nil {
+		return nil, err
+	}
+	prefix := make([]byte, len(magic))
+	_, err = io.ReadFull(connection, prefix)
+	if err != nil {
+		return nil, err
+	}
+
+	var acceptedConnection net.Conn
+	if bytes.Equal(prefix, magic) {
+		secureConnection, err := serverHandshake(connection, t.staticKey)
+		if err != nil {
+			return nil, errors.Wrap(err, "encrypted handshake failed")
+		}
+		log.Debugf("Encrypted connection from %s with identity %x",
+			connection.RemoteAddr(), secureConnection.RemoteStaticKey())
+		acceptedConnection = secureConnection
+	} else {
+		acceptedConnection = &prefixedConn{
+			Conn:   connection,
+			reader: io.MultiReader(bytes.NewReader(prefix), connection),
+		}
+	}
+
+	err = connection.SetDeadline(time.Time{})
+	if err != nil {
+		return nil, err
+	}
+	return acceptedConnection, nil
+}
+
+// prefixedConn is a plaintext connection whose first bytes were already read
+// while looking for a handshake
+type prefixedConn struct {
+	net.Conn
+	reader io.Reader
+}
+
+func (c *prefixedConn) Read(b []byte) (int, error) {
+	return c.reader.Read(b)
+}
@@ -0,0 +1,42 @@
+package portmapper
+
+import (
+	"bufio"
+	"encoding/binary"
+	"encoding/hex"
+	"net"
+	"os"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// defaultGateway returns the IPv4 default gateway from the kernel routing table
+func defaultGateway() (net.IP, error) {
+	file, err := os.Open("/proc/net/route")
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		// The columns are Iface, Destination, Gateway and more, with addresses in
+		// little-endian hex. The default route is the one with destination 0.
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 3 || fields[1] != "00000000" {
+			continue
+		}
+		gatewayBytes, err := hex.DecodeString(fields[2])
+		if err != nil || len(gatewayBytes) != net.IPv4len {
+			continue
+		}
+		gateway := make(net.IP, net.IPv4len)
+		binary.BigEndian.PutUint32(gateway, binary.LittleEndian.Uint32(gatewayBytes))
+		return gateway, nil
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return nil, errors.New("no default route was found")
+}
@@ -0,0 +1,16 @@
+//go:build !linux
+// +build !linux
+
+package portmapper
+
+import (
+	"net"
+
+	"github.com/pkg/errors"
+)
+
+// defaultGateway is implemented only on Linux. On other systems NAT-PMP isn't
+// used, and only UPnP routers are discovered.
+func defaultGateway() (net.IP, error) {
+	return nil, errors.New("finding the default gateway is not supported on this system")
+}
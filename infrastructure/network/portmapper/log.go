@@ -0,0 +1,9 @@
+package portmapper
+
+import (
+	"github.com/Hoosat-Oy/HTND/infrastructure/logger"
+	"github.com/Hoosat-Oy/HTND/util/panics"
+)
+
+var log = logger.RegisterSubSystem("PMAP")
+var spawn = panics.GoroutineWrapperFunc(log)
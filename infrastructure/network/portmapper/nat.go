@@ -0,0 +1,62 @@
+package portmapper
+
+import (
+	"net"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// NAT is a router that can map ports of its external address to ports of a
+// host in its local network
+type NAT interface {
+	// Name returns the name of the protocol used to talk to the router
+	Name() string
+
+	// ExternalAddress returns the external IP address of the router
+	ExternalAddress() (net.IP, error)
+
+	// AddPortMapping maps externalPort of the router to internalPort of this host for
+	// the given lease duration, and returns the external port that was actually mapped
+	AddPortMapping(protocol string, internalPort uint16, externalPort uint16, description string,
+		leaseDuration time.Duration) (uint16, error)
+
+	// DeletePortMapping removes a mapping that was added by AddPortMapping
+	DeletePortMapping(protocol string, internalPort uint16, externalPort uint16) error
+}
+
+// ErrNoNATFound is returned from Discover when no router supporting either UPnP or NAT-PMP was found
+var ErrNoNATFound = errors.New("no UPnP or NAT-PMP router was found")
+
+// Discover looks for a router that supports UPnP IGD, and if none is found,
+// for the default gateway supporting NAT-PMP
+func Discover(timeout time.Duration) (NAT, error) {
+	upnp, upnpErr := DiscoverUPnP(timeout)
+	if upnpErr == nil {
+		return upnp, nil
+	}
+	log.Debugf("UPnP discovery failed: %s", upnpErr)
+
+	gateway, err := defaultGateway()
+	if err != nil {
+		log.Debugf("Couldn't find the default gateway for NAT-PMP: %s", err)
+		return nil, ErrNoNATFound
+	}
+	natPMP := NewNATPMP(&net.UDPAddr{IP: gateway, Port: natPMPPort}, timeout)
+	_, err = natPMP.ExternalAddress()
+	if err != nil {
+		log.Debugf("NAT-PMP discovery failed: %s", err)
+		return nil, ErrNoNATFound
+	}
+	return natPMP, nil
+}
+
+// leaseSeconds returns the given lease duration in whole seconds. A lease of 0
+// seconds means a permanent mapping in UPnP and a deletion in NAT-PMP, so the
+// lease is never rounded down below a second.
+func leaseSeconds(leaseDuration time.Duration) uint32 {
+	if leaseDuration < time.Second {
+		return 1
+	}
+	return uint32(leaseDuration / time.Second)
+}
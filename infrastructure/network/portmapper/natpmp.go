@@ -0,0 +1,149 @@
+package portmapper
+
+import (
+	"encoding/binary"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// NAT-PMP is described in RFC 6886
+const (
+	natPMPPort    = 5351
+	natPMPVersion = 0
+
+	natPMPOpExternalAddress = 0
+	natPMPOpMapUDP          = 1
+	natPMPOpMapTCP          = 2
+
+	// natPMPResponseOpOffset is added to the opcode of a request to get the opcode of its response
+	natPMPResponseOpOffset = 128
+
+	natPMPExternalAddressResponseSize = 12
+	natPMPMappingResponseSize         = 16
+
+	natPMPRequestAttempts = 3
+)
+
+var natPMPResultCodes = map[uint16]string{
+	1: "unsupported version",
+	2: "not authorized or refused",
+	3: "network failure",
+	4: "out of resources",
+	5: "unsupported opcode",
+}
+
+type natPMP struct {
+	gateway *net.UDPAddr
+	timeout time.Duration
+}
+
+// NewNATPMP returns a NAT that is controlled by NAT-PMP requests sent to the
+// given gateway address. timeout is the total time to wait for every response.
+func NewNATPMP(gateway *net.UDPAddr, timeout time.Duration) NAT {
+	return &natPMP{
+		gateway: gateway,
+		timeout: timeout,
+	}
+}
+
+func (n *natPMP) Name() string {
+	return "NAT-PMP"
+}
+
+func (n *natPMP) ExternalAddress() (net.IP, error) {
+	response, err := n.request([]byte{natPMPVersion, natPMPOpExternalAddress}, natPMPExternalAddressResponseSize)
+	if err != nil {
+		return nil, err
+	}
+	return net.IPv4(response[8], response[9], response[10], response[11]), nil
+}
+
+func (n *natPMP) AddPortMapping(protocol string, internalPort uint16, externalPort uint16, _ string,
+	leaseDuration time.Duration) (uint16, error) {
+
+	response, err := n.mapPort(protocol, internalPort, externalPort, leaseSeconds(leaseDuration))
+	if err != nil {
+		return 0, err
+	}
+	return binary.BigEndian.Uint16(response[10:12]), nil
+}
+
+func (n *natPMP) DeletePortMapping(protocol string, internalPort uint16, _ uint16) error {
+	// A mapping is deleted by requesting it again with a lifetime and external port of 0
+	_, err := n.mapPort(protocol, internalPort, 0, 0)
+	return err
+}
+
+func (n *natPMP) mapPort(protocol string, internalPort uint16, externalPort uint16, lifetimeSeconds uint32) ([]byte, error) {
+	var op byte
+	switch strings.ToUpper(protocol) {
+	case "UDP":
+		op = natPMPOpMapUDP
+	case "TCP":
+		op = natPMPOpMapTCP
+	default:
+		return nil, errors.Errorf("unsupported protocol %s", protocol)
+	}
+
+	request := make([]byte, 12)
+	request[0] = natPMPVersion
+	request[1] = op
+	binary.BigEndian.PutUint16(request[4:6], internalPort)
+	binary.BigEndian.PutUint16(request[6:8], externalPort)
+	binary.BigEndian.PutUint32(request[8:12], lifetimeSeconds)
+	return n.request(request, natPMPMappingResponseSize)
+}
+
+// request sends the given request to the gateway and returns its response. Since NAT-PMP
+// runs over UDP, the request is resent a few times with doubling timeouts.
+func (n *natPMP) request(request []byte, responseSize int) ([]byte, error) {
+	connection, err := net.DialUDP("udp", nil, n.gateway)
+	if err != nil {
+		return nil, err
+	}
+	defer connection.Close()
+
+	// The attempts wait for 1, 2 and 4 parts of the timeout
+	attemptTimeout := n.timeout / (1<<natPMPRequestAttempts - 1)
+	response := make([]byte, 16)
+	for attempt := 0; attempt < natPMPRequestAttempts; attempt++ {
+		_, err = connection.Write(request)
+		if err != nil {
+			return nil, err
+		}
+
+		err = connection.SetReadDeadline(time.Now().Add(attemptTimeout))
+		if err != nil {
+			return nil, err
+		}
+		attemptTimeout *= 2
+
+		var responseLength int
+		responseLength, err = connection.Read(response)
+		if err != nil {
+			var netErr net.Error
+			if errors.As(err, &netErr) && netErr.Timeout() {
+				continue
+			}
+			return nil, err
+		}
+
+		if responseLength < responseSize || response[1] != request[1]+natPMPResponseOpOffset {
+			// This isn't a response to our request
+			continue
+		}
+		resultCode := binary.BigEndian.Uint16(response[2:4])
+		if resultCode != 0 {
+			reason, ok := natPMPResultCodes[resultCode]
+			if !ok {
+				reason = "unknown error"
+			}
+			return nil, errors.Errorf("NAT-PMP request failed with result code %d: %s", resultCode, reason)
+		}
+		return response[:responseSize], nil
+	}
+	return nil, errors.Wrapf(err, "no NAT-PMP response from %s", n.gateway)
+}
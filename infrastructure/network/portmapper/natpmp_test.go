@@ -0,0 +1,101 @@
+package portmapper
+
+import (
+	"encoding/binary"
+	"net"
+	"testing"
+	"time"
+)
+
+// serveFakeNATPMP answers NAT-PMP requests on connection as a router with the given
+// external IP. Mappings are recorded in mappings, and the first request is dropped
+// to exercise retransmission.
+func serveFakeNATPMP(connection net.PacketConn, externalIP net.IP, mappings chan<- [3]uint32) {
+	buffer := make([]byte, 64)
+	droppedFirst := false
+	for {
+		n, address, err := connection.ReadFrom(buffer)
+		if err != nil {
+			return
+		}
+		if !droppedFirst {
+			droppedFirst = true
+			continue
+		}
+		if n < 2 || buffer[0] != natPMPVersion {
+			continue
+		}
+
+		switch buffer[1] {
+		case natPMPOpExternalAddress:
+			response := make([]byte, natPMPExternalAddressResponseSize)
+			response[1] = natPMPOpExternalAddress + natPMPResponseOpOffset
+			copy(response[8:], externalIP.To4())
+			_, _ = connection.WriteTo(response, address)
+		case natPMPOpMapTCP, natPMPOpMapUDP:
+			internalPort := binary.BigEndian.Uint16(buffer[4:6])
+			externalPort := binary.BigEndian.Uint16(buffer[6:8])
+			lifetime := binary.BigEndian.Uint32(buffer[8:12])
+			mappings <- [3]uint32{uint32(internalPort), uint32(externalPort), lifetime}
+
+			// The router maps a different external port than the one suggested
+			mappedPort := externalPort
+			if lifetime != 0 {
+				mappedPort = externalPort + 1
+			}
+			response := make([]byte, natPMPMappingResponseSize)
+			response[1] = buffer[1] + natPMPResponseOpOffset
+			binary.BigEndian.PutUint16(response[8:10], internalPort)
+			binary.BigEndian.PutUint16(response[10:12], mappedPort)
+			binary.BigEndian.PutUint32(response[12:16], lifetime)
+			_, _ = connection.WriteTo(response, address)
+		default:
+			response := make([]byte, 8)
+			response[1] = buffer[1] + natPMPResponseOpOffset
+			binary.BigEndian.PutUint16(response[2:4], 5)
+			_, _ = connection.WriteTo(response, address)
+		}
+	}
+}
+
+func TestNATPMP(t *testing.T) {
+	connection, err := net.ListenPacket("udp4", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("ListenPacket: %s", err)
+	}
+	defer connection.Close()
+
+	mappings := make(chan [3]uint32, 10)
+	go serveFakeNATPMP(connection, net.ParseIP("198.51.100.3"), mappings)
+
+	nat := NewNATPMP(connection.LocalAddr().(*net.UDPAddr), 3*time.Second)
+
+	externalIP, err := nat.ExternalAddress()
+	if err != nil {
+		t.Fatalf("ExternalAddress: %s", err)
+	}
+	if !externalIP.Equal(net.ParseIP("198.51.100.3")) {
+		t.Fatalf("Unexpected external IP. Want: 198.51.100.3, got: %s", externalIP)
+	}
+
+	mappedPort, err := nat.AddPortMapping("tcp", 42421, 42421, "test", 20*time.Minute)
+	if err != nil {
+		t.Fatalf("AddPortMapping: %s", err)
+	}
+	if mappedPort != 42422 {
+		t.Fatalf("Unexpected mapped port. Want: 42422, got: %d", mappedPort)
+	}
+	mapping := <-mappings
+	if mapping != [3]uint32{42421, 42421, 20 * 60} {
+		t.Fatalf("Unexpected mapping request %v", mapping)
+	}
+
+	err = nat.DeletePortMapping("tcp", 42421, mappedPort)
+	if err != nil {
+		t.Fatalf("DeletePortMapping: %s", err)
+	}
+	mapping = <-mappings
+	if mapping != [3]uint32{42421, 0, 0} {
+		t.Fatalf("Unexpected deletion request %v", mapping)
+	}
+}
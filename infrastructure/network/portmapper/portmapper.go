@@ -0,0 +1,139 @@
+package portmapper
+
+import (
+	"net"
+	"strconv"
+	"time"
+)
+
+const (
+	defaultLeaseDuration    = 20 * time.Minute
+	defaultRetryInterval    = 5 * time.Minute
+	defaultDiscoveryTimeout = 3 * time.Second
+
+	mappingProtocol    = "TCP"
+	mappingDescription = "htnd listen port"
+)
+
+// PortMapper keeps the P2P listening port of this node mapped on the router
+// in front of it, so that nodes behind a NAT can accept inbound connections
+type PortMapper struct {
+	port              uint16
+	onExternalAddress func(ip net.IP, port uint16)
+	discover          func() (NAT, error)
+	leaseDuration     time.Duration
+	retryInterval     time.Duration
+
+	nat              NAT
+	externalPort     uint16
+	lastExternalIP   net.IP
+	lastExternalPort uint16
+	quit             chan struct{}
+	done             chan struct{}
+}
+
+// New returns a PortMapper that maps the given local port using UPnP or NAT-PMP.
+// onExternalAddress is called with the external address of the mapping whenever it
+// is discovered or changes.
+func New(port uint16, onExternalAddress func(ip net.IP, port uint16)) *PortMapper {
+	return newPortMapper(port, onExternalAddress, func() (NAT, error) {
+		return Discover(defaultDiscoveryTimeout)
+	}, defaultLeaseDuration, defaultRetryInterval)
+}
+
+func newPortMapper(port uint16, onExternalAddress func(ip net.IP, port uint16), discover func() (NAT, error),
+	leaseDuration time.Duration, retryInterval time.Duration) *PortMapper {
+
+	return &PortMapper{
+		port:              port,
+		onExternalAddress: onExternalAddress,
+		discover:          discover,
+		leaseDuration:     leaseDuration,
+		retryInterval:     retryInterval,
+		quit:              make(chan struct{}),
+		done:              make(chan struct{}),
+	}
+}
+
+// Start begins mapping the port in the background. The mapping is renewed before
+// its lease expires, and the router is discovered again if renewing fails.
+func (pm *PortMapper) Start() {
+	spawn("PortMapper.run", pm.run)
+}
+
+// Stop stops renewing the mapping and removes it from the router
+func (pm *PortMapper) Stop() {
+	close(pm.quit)
+	<-pm.done
+}
+
+func (pm *PortMapper) run() {
+	defer close(pm.done)
+
+	for {
+		nextRefresh := pm.retryInterval
+		err := pm.refresh()
+		if err != nil {
+			log.Warnf("Failed to map port %d: %s", pm.port, err)
+		} else {
+			// Renew at half the lease, so that a single failed renewal doesn't drop the mapping
+			nextRefresh = pm.leaseDuration / 2
+		}
+
+		select {
+		case <-pm.quit:
+			pm.deleteMapping()
+			return
+		case <-time.After(nextRefresh):
+		}
+	}
+}
+
+func (pm *PortMapper) refresh() error {
+	if pm.nat == nil {
+		nat, err := pm.discover()
+		if err != nil {
+			return err
+		}
+		log.Infof("Found a %s router", nat.Name())
+		pm.nat = nat
+	}
+
+	externalPort := pm.externalPort
+	if externalPort == 0 {
+		externalPort = pm.port
+	}
+	mappedPort, err := pm.nat.AddPortMapping(mappingProtocol, pm.port, externalPort, mappingDescription, pm.leaseDuration)
+	if err != nil {
+		// The router might have been replaced or restarted, so look for it again next time
+		pm.nat = nil
+		return err
+	}
+	pm.externalPort = mappedPort
+
+	externalIP, err := pm.nat.ExternalAddress()
+	if err != nil {
+		return err
+	}
+
+	if !externalIP.Equal(pm.lastExternalIP) || mappedPort != pm.lastExternalPort {
+		log.Infof("Mapped port %d to external address %s using %s", pm.port,
+			net.JoinHostPort(externalIP.String(), strconv.Itoa(int(mappedPort))), pm.nat.Name())
+		pm.lastExternalIP = externalIP
+		pm.lastExternalPort = mappedPort
+		pm.onExternalAddress(externalIP, mappedPort)
+	}
+	return nil
+}
+
+func (pm *PortMapper) deleteMapping() {
+	if pm.nat == nil || pm.externalPort == 0 {
+		return
+	}
+	err := pm.nat.DeletePortMapping(mappingProtocol, pm.port, pm.externalPort)
+	if err != nil {
+		log.Warnf("Failed to remove the mapping of port %d: %s", pm.port, err)
+		return
+	}
+	log.Infof("Removed the mapping of port %d", pm.port)
+}
@@ -0,0 +1,320 @@
+package portmapper
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	ssdpMulticastAddress = "239.255.255.250:1900"
+	igdSearchTarget      = "urn:schemas-upnp-org:device:InternetGatewayDevice:1"
+)
+
+// wanConnectionServiceTypes are the IGD services that can map ports, from the most preferred
+var wanConnectionServiceTypes = []string{
+	"urn:schemas-upnp-org:service:WANIPConnection:2",
+	"urn:schemas-upnp-org:service:WANIPConnection:1",
+	"urn:schemas-upnp-org:service:WANPPPConnection:1",
+}
+
+type upnp struct {
+	client      *http.Client
+	controlURL  string
+	serviceType string
+	localIP     net.IP
+}
+
+// DiscoverUPnP looks for an Internet Gateway Device in the local network using SSDP
+func DiscoverUPnP(timeout time.Duration) (NAT, error) {
+	return discoverUPnP(ssdpMulticastAddress, timeout)
+}
+
+// discoverUPnP sends an SSDP search to ssdpAddress and returns the first Internet
+// Gateway Device that answers and has a WAN connection service
+func discoverUPnP(ssdpAddress string, timeout time.Duration) (NAT, error) {
+	destination, err := net.ResolveUDPAddr("udp4", ssdpAddress)
+	if err != nil {
+		return nil, err
+	}
+	connection, err := net.ListenPacket("udp4", ":0")
+	if err != nil {
+		return nil, err
+	}
+	defer connection.Close()
+
+	search := "M-SEARCH * HTTP/1.1\r\n" +
+		"HOST: " + ssdpMulticastAddress + "\r\n" +
+		"ST: " + igdSearchTarget + "\r\n" +
+		"MAN: \"ssdp:discover\"\r\n" +
+		"MX: 2\r\n\r\n"
+	_, err = connection.WriteTo([]byte(search), destination)
+	if err != nil {
+		return nil, err
+	}
+
+	deadline := time.Now().Add(timeout)
+	err = connection.SetReadDeadline(deadline)
+	if err != nil {
+		return nil, err
+	}
+
+	buffer := make([]byte, 2048)
+	for {
+		n, _, err := connection.ReadFrom(buffer)
+		if err != nil {
+			return nil, errors.Wrap(err, "no Internet Gateway Device answered the SSDP search")
+		}
+
+		location, ok := parseSSDPResponse(buffer[:n])
+		if !ok {
+			continue
+		}
+		nat, err := newUPnPFromLocation(location, time.Until(deadline))
+		if err != nil {
+			log.Debugf("Skipping the UPnP device at %s: %s", location, err)
+			continue
+		}
+		return nat, nil
+	}
+}
+
+// parseSSDPResponse returns the location of the device description of an Internet
+// Gateway Device from an SSDP response
+func parseSSDPResponse(response []byte) (string, bool) {
+	httpResponse, err := http.ReadResponse(bufio.NewReader(bytes.NewReader(response)), nil)
+	if err != nil {
+		return "", false
+	}
+	defer httpResponse.Body.Close()
+
+	if httpResponse.StatusCode != http.StatusOK ||
+		!strings.Contains(httpResponse.Header.Get("St"), "InternetGatewayDevice") {
+		return "", false
+	}
+	location := httpResponse.Header.Get("Location")
+	return location, location != ""
+}
+
+type upnpDevice struct {
+	DeviceType string        `xml:"deviceType"`
+	Devices    []upnpDevice  `xml:"deviceList>device"`
+	Services   []upnpService `xml:"serviceList>service"`
+}
+
+type upnpService struct {
+	ServiceType string `xml:"serviceType"`
+	ControlURL  string `xml:"controlURL"`
+}
+
+type upnpRoot struct {
+	URLBase string     `xml:"URLBase"`
+	Device  upnpDevice `xml:"device"`
+}
+
+func (d *upnpDevice) findService(serviceType string) (*upnpService, bool) {
+	for i := range d.Services {
+		if d.Services[i].ServiceType == serviceType {
+			return &d.Services[i], true
+		}
+	}
+	for i := range d.Devices {
+		service, ok := d.Devices[i].findService(serviceType)
+		if ok {
+			return service, true
+		}
+	}
+	return nil, false
+}
+
+// newUPnPFromLocation reads the device description at location, and returns a NAT
+// that is controlled through its WAN connection service
+func newUPnPFromLocation(location string, timeout time.Duration) (NAT, error) {
+	client := &http.Client{Timeout: timeout}
+	response, err := client.Get(location)
+	if err != nil {
+		return nil, err
+	}
+	defer response.Body.Close()
+	if response.StatusCode != http.StatusOK {
+		return nil, errors.Errorf("fetching the device description failed with status %s", response.Status)
+	}
+
+	root := &upnpRoot{}
+	err = xml.NewDecoder(response.Body).Decode(root)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to parse the device description")
+	}
+
+	for _, serviceType := range wanConnectionServiceTypes {
+		service, ok := root.Device.findService(serviceType)
+		if !ok {
+			continue
+		}
+
+		baseURL, err := url.Parse(location)
+		if err != nil {
+			return nil, err
+		}
+		if root.URLBase != "" {
+			baseURL, err = url.Parse(root.URLBase)
+			if err != nil {
+				return nil, err
+			}
+		}
+		controlURL, err := baseURL.Parse(service.ControlURL)
+		if err != nil {
+			return nil, err
+		}
+
+		localIP, err := localIPTowards(controlURL.Host)
+		if err != nil {
+			return nil, err
+		}
+
+		return &upnp{
+			client:      client,
+			controlURL:  controlURL.String(),
+			serviceType: serviceType,
+			localIP:     localIP,
+		}, nil
+	}
+	return nil, errors.New("the device has no WAN connection service")
+}
+
+// localIPTowards returns the IP of the local interface that is used to reach the given host
+func localIPTowards(hostPort string) (net.IP, error) {
+	connection, err := net.Dial("udp", hostPort)
+	if err != nil {
+		return nil, err
+	}
+	defer connection.Close()
+	return connection.LocalAddr().(*net.UDPAddr).IP, nil
+}
+
+func (u *upnp) Name() string {
+	return "UPnP"
+}
+
+func (u *upnp) ExternalAddress() (net.IP, error) {
+	response, err := u.soapRequest("GetExternalIPAddress", nil)
+	if err != nil {
+		return nil, err
+	}
+	externalIPString, err := findXMLElement(response, "NewExternalIPAddress")
+	if err != nil {
+		return nil, err
+	}
+	externalIP := net.ParseIP(strings.TrimSpace(externalIPString))
+	if externalIP == nil {
+		return nil, errors.Errorf("the router returned an invalid external IP %s", externalIPString)
+	}
+	return externalIP, nil
+}
+
+func (u *upnp) AddPortMapping(protocol string, internalPort uint16, externalPort uint16, description string,
+	leaseDuration time.Duration) (uint16, error) {
+
+	_, err := u.soapRequest("AddPortMapping", []soapArgument{
+		{"NewRemoteHost", ""},
+		{"NewExternalPort", strconv.Itoa(int(externalPort))},
+		{"NewProtocol", strings.ToUpper(protocol)},
+		{"NewInternalPort", strconv.Itoa(int(internalPort))},
+		{"NewInternalClient", u.localIP.String()},
+		{"NewEnabled", "1"},
+		{"NewPortMappingDescription", description},
+		{"NewLeaseDuration", strconv.Itoa(int(leaseSeconds(leaseDuration)))},
+	})
+	if err != nil {
+		return 0, err
+	}
+	return externalPort, nil
+}
+
+func (u *upnp) DeletePortMapping(protocol string, _ uint16, externalPort uint16) error {
+	_, err := u.soapRequest("DeletePortMapping", []soapArgument{
+		{"NewRemoteHost", ""},
+		{"NewExternalPort", strconv.Itoa(int(externalPort))},
+		{"NewProtocol", strings.ToUpper(protocol)},
+	})
+	return err
+}
+
+type soapArgument struct {
+	name  string
+	value string
+}
+
+func (u *upnp) soapRequest(action string, arguments []soapArgument) ([]byte, error) {
+	body := &bytes.Buffer{}
+	body.WriteString(`<?xml version="1.0"?>` +
+		`<s:Envelope xmlns:s="http://schemas.xmlsoap.org/soap/envelope/" ` +
+		`s:encodingStyle="http://schemas.xmlsoap.org/soap/encoding/"><s:Body>`)
+	fmt.Fprintf(body, `<u:%s xmlns:u="%s">`, action, u.serviceType)
+	for _, argument := range arguments {
+		fmt.Fprintf(body, "<%s>", argument.name)
+		err := xml.EscapeText(body, []byte(argument.value))
+		if err != nil {
+			return nil, err
+		}
+		fmt.Fprintf(body, "</%s>", argument.name)
+	}
+	fmt.Fprintf(body, `</u:%s></s:Body></s:Envelope>`, action)
+
+	request, err := http.NewRequest(http.MethodPost, u.controlURL, body)
+	if err != nil {
+		return nil, err
+	}
+	request.Header.Set("Content-Type", `text/xml; charset="utf-8"`)
+	request.Header.Set("SOAPAction", fmt.Sprintf(`"%s#%s"`, u.serviceType, action))
+
+	response, err := u.client.Do(request)
+	if err != nil {
+		return nil, err
+	}
+	defer response.Body.Close()
+
+	responseBody, err := io.ReadAll(response.Body)
+	if err != nil {
+		return nil, err
+	}
+	if response.StatusCode != http.StatusOK {
+		errorDescription, findErr := findXMLElement(responseBody, "errorDescription")
+		if findErr != nil {
+			errorDescription = response.Status
+		}
+		return nil, errors.Errorf("UPnP %s failed: %s", action, errorDescription)
+	}
+	return responseBody, nil
+}
+
+// findXMLElement returns the text of the first element with the given local name in document
+func findXMLElement(document []byte, name string) (string, error) {
+	decoder := xml.NewDecoder(bytes.NewReader(document))
+	for {
+		token, err := decoder.Token()
+		if err != nil {
+			return "", errors.Errorf("element %s was not found", name)
+		}
+		startElement, ok := token.(xml.StartElement)
+		if !ok || startElement.Name.Local != name {
+			continue
+		}
+		var text string
+		err = decoder.DecodeElement(&text, &startElement)
+		if err != nil {
+			return "", err
+		}
+		return text, nil
+	}
+}
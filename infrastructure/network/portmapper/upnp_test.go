@@ -0,0 +1,249 @@
+package portmapper
+
+import (
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+const fakeIGDDescription = `<?xml version="1.0"?>
+<root xmlns="urn:schemas-upnp-org:device-1-0">
+	<device>
+		<deviceType>urn:schemas-upnp-org:device:InternetGatewayDevice:1</deviceType>
+		<deviceList>
+			<device>
+				<deviceType>urn:schemas-upnp-org:device:WANDevice:1</deviceType>
+				<deviceList>
+					<device>
+						<deviceType>urn:schemas-upnp-org:device:WANConnectionDevice:1</deviceType>
+						<serviceList>
+							<service>
+								<serviceType>urn:schemas-upnp-org:service:WANIPConnection:1</serviceType>
+								<controlURL>/control/wanip</controlURL>
+							</service>
+						</serviceList>
+					</device>
+				</deviceList>
+			</device>
+		</deviceList>
+	</device>
+</root>`
+
+// fakeIGD is an Internet Gateway Device that answers SSDP searches and the
+// SOAP actions of a WANIPConnection service
+type fakeIGD struct {
+	t          *testing.T
+	httpServer *httptest.Server
+	ssdp       net.PacketConn
+	externalIP string
+
+	mutex    sync.Mutex
+	mappings map[string]string // external port -> internal client:port
+	actions  []string
+}
+
+func newFakeIGD(t *testing.T) *fakeIGD {
+	igd := &fakeIGD{
+		t:          t,
+		externalIP: "203.0.113.7",
+		mappings:   map[string]string{},
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/description.xml", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = io.WriteString(w, fakeIGDDescription)
+	})
+	mux.HandleFunc("/control/wanip", igd.handleControl)
+	igd.httpServer = httptest.NewServer(mux)
+
+	ssdp, err := net.ListenPacket("udp4", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("ListenPacket: %s", err)
+	}
+	igd.ssdp = ssdp
+	go igd.serveSSDP()
+
+	return igd
+}
+
+func (igd *fakeIGD) close() {
+	igd.ssdp.Close()
+	igd.httpServer.Close()
+}
+
+func (igd *fakeIGD) serveSSDP() {
+	buffer := make([]byte, 2048)
+	for {
+		n, address, err := igd.ssdp.ReadFrom(buffer)
+		if err != nil {
+			return
+		}
+		if !strings.HasPrefix(string(buffer[:n]), "M-SEARCH") ||
+			!strings.Contains(string(buffer[:n]), igdSearchTarget) {
+			continue
+		}
+		response := "HTTP/1.1 200 OK\r\n" +
+			"CACHE-CONTROL: max-age=120\r\n" +
+			"ST: " + igdSearchTarget + "\r\n" +
+			"LOCATION: " + igd.httpServer.URL + "/description.xml\r\n\r\n"
+		_, _ = igd.ssdp.WriteTo([]byte(response), address)
+	}
+}
+
+func (igd *fakeIGD) handleControl(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		igd.t.Errorf("ReadAll: %s", err)
+		return
+	}
+	soapAction := r.Header.Get("SOAPAction")
+	action := soapAction[strings.Index(soapAction, "#")+1 : len(soapAction)-1]
+
+	element := func(name string) string {
+		value, err := findXMLElement(body, name)
+		if err != nil {
+			igd.t.Errorf("%s request has no %s: %s", action, name, err)
+		}
+		return value
+	}
+
+	igd.mutex.Lock()
+	defer igd.mutex.Unlock()
+	igd.actions = append(igd.actions, action)
+
+	result := ""
+	switch action {
+	case "GetExternalIPAddress":
+		result = "<NewExternalIPAddress>" + igd.externalIP + "</NewExternalIPAddress>"
+	case "AddPortMapping":
+		if element("NewLeaseDuration") == "0" {
+			igd.t.Errorf("AddPortMapping requested a permanent mapping")
+		}
+		igd.mappings[element("NewExternalPort")] = element("NewInternalClient") + ":" + element("NewInternalPort")
+	case "DeletePortMapping":
+		externalPort := element("NewExternalPort")
+		if _, ok := igd.mappings[externalPort]; !ok {
+			w.WriteHeader(http.StatusInternalServerError)
+			_, _ = io.WriteString(w, `<s:Envelope xmlns:s="http://schemas.xmlsoap.org/soap/envelope/"><s:Body>`+
+				`<s:Fault><detail><UPnPError><errorCode>714</errorCode>`+
+				`<errorDescription>NoSuchEntryInArray</errorDescription></UPnPError></detail></s:Fault>`+
+				`</s:Body></s:Envelope>`)
+			return
+		}
+		delete(igd.mappings, externalPort)
+	default:
+		igd.t.Errorf("Unexpected action %s", action)
+	}
+
+	_, _ = fmt.Fprintf(w, `<s:Envelope xmlns:s="http://schemas.xmlsoap.org/soap/envelope/"><s:Body>`+
+		`<u:%sResponse xmlns:u="urn:schemas-upnp-org:service:WANIPConnection:1">%s</u:%sResponse>`+
+		`</s:Body></s:Envelope>`, action, result, action)
+}
+
+func (igd *fakeIGD) mapping(externalPort string) (string, bool) {
+	igd.mutex.Lock()
+	defer igd.mutex.Unlock()
+	internalAddress, ok := igd.mappings[externalPort]
+	return internalAddress, ok
+}
+
+func TestUPnP(t *testing.T) {
+	igd := newFakeIGD(t)
+	defer igd.close()
+
+	nat, err := discoverUPnP(igd.ssdp.LocalAddr().String(), 5*time.Second)
+	if err != nil {
+		t.Fatalf("discoverUPnP: %s", err)
+	}
+
+	externalIP, err := nat.ExternalAddress()
+	if err != nil {
+		t.Fatalf("ExternalAddress: %s", err)
+	}
+	if externalIP.String() != igd.externalIP {
+		t.Fatalf("Unexpected external IP. Want: %s, got: %s", igd.externalIP, externalIP)
+	}
+
+	mappedPort, err := nat.AddPortMapping("tcp", 42421, 42421, "test", time.Hour)
+	if err != nil {
+		t.Fatalf("AddPortMapping: %s", err)
+	}
+	if mappedPort != 42421 {
+		t.Fatalf("Unexpected mapped port. Want: 42421, got: %d", mappedPort)
+	}
+	internalAddress, ok := igd.mapping("42421")
+	if !ok {
+		t.Fatalf("The port was not mapped")
+	}
+	if internalAddress != "127.0.0.1:42421" {
+		t.Fatalf("Unexpected internal address. Want: 127.0.0.1:42421, got: %s", internalAddress)
+	}
+
+	err = nat.DeletePortMapping("tcp", 42421, 42421)
+	if err != nil {
+		t.Fatalf("DeletePortMapping: %s", err)
+	}
+	if _, ok := igd.mapping("42421"); ok {
+		t.Fatalf("The mapping was not deleted")
+	}
+
+	// Deleting it again should return the error description of the router
+	err = nat.DeletePortMapping("tcp", 42421, 42421)
+	if err == nil || !strings.Contains(err.Error(), "NoSuchEntryInArray") {
+		t.Fatalf("Unexpected error when deleting a missing mapping: %v", err)
+	}
+}
+
+func TestPortMapper(t *testing.T) {
+	igd := newFakeIGD(t)
+	defer igd.close()
+
+	externalAddresses := make(chan string, 10)
+	portMapper := newPortMapper(42421, func(ip net.IP, port uint16) {
+		externalAddresses <- net.JoinHostPort(ip.String(), fmt.Sprint(port))
+	}, func() (NAT, error) {
+		return discoverUPnP(igd.ssdp.LocalAddr().String(), 5*time.Second)
+	}, 200*time.Millisecond, time.Second)
+	portMapper.Start()
+
+	select {
+	case externalAddress := <-externalAddresses:
+		if externalAddress != "203.0.113.7:42421" {
+			t.Fatalf("Unexpected external address. Want: 203.0.113.7:42421, got: %s", externalAddress)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatalf("Timed out waiting for the external address")
+	}
+
+	// Wait for a few renewals of the lease
+	time.Sleep(500 * time.Millisecond)
+	igd.mutex.Lock()
+	addPortMappingCount := 0
+	for _, action := range igd.actions {
+		if action == "AddPortMapping" {
+			addPortMappingCount++
+		}
+	}
+	igd.mutex.Unlock()
+	if addPortMappingCount < 2 {
+		t.Fatalf("The lease was not renewed. AddPortMapping was called %d times", addPortMappingCount)
+	}
+
+	// The external address didn't change, so it shouldn't be reported again
+	select {
+	case externalAddress := <-externalAddresses:
+		t.Fatalf("Unexpected report of an unchanged external address %s", externalAddress)
+	default:
+	}
+
+	portMapper.Stop()
+	if _, ok := igd.mapping("42421"); ok {
+		t.Fatalf("The mapping was not removed on stop")
+	}
+}
@@ -0,0 +1,20 @@
+package rpcclient
+
+import "github.com/Hoosat-Oy/HTND/app/appmessage"
+
+// AddToWhitelist sends an RPC request respective to the function's name and returns the RPC server's response
+func (c *RPCClient) AddToWhitelist(subnet string, reason string) (*appmessage.AddToWhitelistResponseMessage, error) {
+	err := c.rpcRouter.outgoingRoute().Enqueue(appmessage.NewAddToWhitelistRequestMessage(subnet, reason))
+	if err != nil {
+		return nil, err
+	}
+	response, err := c.route(appmessage.CmdAddToWhitelistResponseMessage).DequeueWithTimeout(c.timeout)
+	if err != nil {
+		return nil, err
+	}
+	addToWhitelistResponse := response.(*appmessage.AddToWhitelistResponseMessage)
+	if addToWhitelistResponse.Error != nil {
+		return nil, c.convertRPCError(addToWhitelistResponse.Error)
+	}
+	return addToWhitelistResponse, nil
+}
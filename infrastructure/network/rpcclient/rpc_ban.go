@@ -3,12 +3,12 @@ package rpcclient
 import "github.com/Hoosat-Oy/HTND/app/appmessage"
 
 // Ban sends an RPC request respective to the function's name and returns the RPC server's response
-func (c *RPCClient) Ban(ip string) (*appmessage.BanResponseMessage, error) {
-	err := c.rpcRouter.outgoingRoute().Enqueue(appmessage.NewBanRequestMessage(ip))
+func (c *RPCClient) Ban(ip string, reason string, durationSeconds int64) (*appmessage.BanResponseMessage, error) {
+	err := c.rpcRouter.outgoingRoute().Enqueue(appmessage.NewBanRequestMessage(ip, reason, durationSeconds))
 	if err != nil {
 		return nil, err
 	}
-	response, err := c.route(appmessage.CmdBanRequestMessage).DequeueWithTimeout(c.timeout)
+	response, err := c.route(appmessage.CmdBanResponseMessage).DequeueWithTimeout(c.timeout)
 	if err != nil {
 		return nil, err
 	}
@@ -0,0 +1,20 @@
+package rpcclient
+
+import "github.com/Hoosat-Oy/HTND/app/appmessage"
+
+// GetBans sends an RPC request respective to the function's name and returns the RPC server's response
+func (c *RPCClient) GetBans() (*appmessage.GetBansResponseMessage, error) {
+	err := c.rpcRouter.outgoingRoute().Enqueue(appmessage.NewGetBansRequestMessage())
+	if err != nil {
+		return nil, err
+	}
+	response, err := c.route(appmessage.CmdGetBansResponseMessage).DequeueWithTimeout(c.timeout)
+	if err != nil {
+		return nil, err
+	}
+	getBansResponse := response.(*appmessage.GetBansResponseMessage)
+	if getBansResponse.Error != nil {
+		return nil, c.convertRPCError(getBansResponse.Error)
+	}
+	return getBansResponse, nil
+}
@@ -0,0 +1,20 @@
+package rpcclient
+
+import "github.com/Hoosat-Oy/HTND/app/appmessage"
+
+// GetDatabaseStats sends an RPC request respective to the function's name and returns the RPC server's response
+func (c *RPCClient) GetDatabaseStats(collect bool) (*appmessage.GetDatabaseStatsResponseMessage, error) {
+	err := c.rpcRouter.outgoingRoute().Enqueue(appmessage.NewGetDatabaseStatsRequestMessage(collect))
+	if err != nil {
+		return nil, err
+	}
+	response, err := c.route(appmessage.CmdGetDatabaseStatsResponseMessage).DequeueWithTimeout(c.timeout)
+	if err != nil {
+		return nil, err
+	}
+	getDatabaseStatsResponse := response.(*appmessage.GetDatabaseStatsResponseMessage)
+	if getDatabaseStatsResponse.Error != nil {
+		return nil, c.convertRPCError(getDatabaseStatsResponse.Error)
+	}
+	return getDatabaseStatsResponse, nil
+}
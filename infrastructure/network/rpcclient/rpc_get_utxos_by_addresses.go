@@ -18,3 +18,24 @@ func (c *RPCClient) GetUTXOsByAddresses(addresses []string) (*appmessage.GetUTXO
 	}
 	return getUTXOsByAddressesResponse, nil
 }
+
+// GetUTXOsByAddressesPage sends an RPC request respective to the function's name and returns the RPC server's response.
+// The UTXOs of all the addresses are paged through by passing the NextCursor of each response to the following
+// request, until it's empty.
+func (c *RPCClient) GetUTXOsByAddressesPage(addresses []string, cursor string, limit uint32) (
+	*appmessage.GetUTXOsByAddressesResponseMessage, error) {
+
+	err := c.rpcRouter.outgoingRoute().Enqueue(appmessage.NewGetUTXOsByAddressesPageRequestMessage(addresses, cursor, limit))
+	if err != nil {
+		return nil, err
+	}
+	response, err := c.route(appmessage.CmdGetUTXOsByAddressesResponseMessage).DequeueWithTimeout(c.timeout)
+	if err != nil {
+		return nil, err
+	}
+	getUTXOsByAddressesResponse := response.(*appmessage.GetUTXOsByAddressesResponseMessage)
+	if getUTXOsByAddressesResponse.Error != nil {
+		return nil, c.convertRPCError(getUTXOsByAddressesResponse.Error)
+	}
+	return getUTXOsByAddressesResponse, nil
+}
@@ -0,0 +1,20 @@
+package rpcclient
+
+import "github.com/Hoosat-Oy/HTND/app/appmessage"
+
+// GetWhitelist sends an RPC request respective to the function's name and returns the RPC server's response
+func (c *RPCClient) GetWhitelist() (*appmessage.GetWhitelistResponseMessage, error) {
+	err := c.rpcRouter.outgoingRoute().Enqueue(appmessage.NewGetWhitelistRequestMessage())
+	if err != nil {
+		return nil, err
+	}
+	response, err := c.route(appmessage.CmdGetWhitelistResponseMessage).DequeueWithTimeout(c.timeout)
+	if err != nil {
+		return nil, err
+	}
+	getWhitelistResponse := response.(*appmessage.GetWhitelistResponseMessage)
+	if getWhitelistResponse.Error != nil {
+		return nil, c.convertRPCError(getWhitelistResponse.Error)
+	}
+	return getWhitelistResponse, nil
+}
@@ -0,0 +1,20 @@
+package rpcclient
+
+import "github.com/Hoosat-Oy/HTND/app/appmessage"
+
+// RemoveFromWhitelist sends an RPC request respective to the function's name and returns the RPC server's response
+func (c *RPCClient) RemoveFromWhitelist(subnet string) (*appmessage.RemoveFromWhitelistResponseMessage, error) {
+	err := c.rpcRouter.outgoingRoute().Enqueue(appmessage.NewRemoveFromWhitelistRequestMessage(subnet))
+	if err != nil {
+		return nil, err
+	}
+	response, err := c.route(appmessage.CmdRemoveFromWhitelistResponseMessage).DequeueWithTimeout(c.timeout)
+	if err != nil {
+		return nil, err
+	}
+	removeFromWhitelistResponse := response.(*appmessage.RemoveFromWhitelistResponseMessage)
+	if removeFromWhitelistResponse.Error != nil {
+		return nil, c.convertRPCError(removeFromWhitelistResponse.Error)
+	}
+	return removeFromWhitelistResponse, nil
+}
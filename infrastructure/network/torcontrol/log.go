@@ -0,0 +1,7 @@
+package torcontrol
+
+import (
+	"github.com/Hoosat-Oy/HTND/infrastructure/logger"
+)
+
+var log = logger.RegisterSubSystem("TORC")
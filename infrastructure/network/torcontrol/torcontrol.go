@@ -0,0 +1,180 @@
+// Package torcontrol implements the parts of the Tor control protocol that are needed
+// to create an ephemeral onion service for the P2P listener.
+// The protocol is described in https://spec.torproject.org/control-spec
+package torcontrol
+
+import (
+	"encoding/hex"
+	"net"
+	"net/textproto"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Hoosat-Oy/HTND/app/appmessage"
+	"github.com/pkg/errors"
+)
+
+const (
+	dialTimeout = 10 * time.Second
+
+	statusOK = 250
+)
+
+// Controller is an authenticated connection to the control port of a Tor daemon.
+// Onion services that are added through it are removed by Tor once it's closed.
+type Controller struct {
+	connection *textproto.Conn
+}
+
+// Connect connects to the Tor control port at address and authenticates. password
+// is used if the control port requires password authentication. Otherwise, cookie
+// authentication is used if it's required.
+func Connect(address string, password string) (*Controller, error) {
+	connection, err := net.DialTimeout("tcp", address, dialTimeout)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to connect to the Tor control port at %s", address)
+	}
+	controller := &Controller{connection: textproto.NewConn(connection)}
+
+	err = controller.authenticate(password)
+	if err != nil {
+		controller.Close()
+		return nil, err
+	}
+	return controller, nil
+}
+
+// Close closes the control connection
+func (c *Controller) Close() error {
+	return c.connection.Close()
+}
+
+// request sends the given command and returns the lines of its response
+func (c *Controller) request(format string, args ...interface{}) ([]string, error) {
+	_, err := c.connection.Cmd(format, args...)
+	if err != nil {
+		return nil, err
+	}
+	_, message, err := c.connection.ReadResponse(statusOK)
+	if err != nil {
+		return nil, errors.Wrap(err, "the Tor control port returned an error")
+	}
+	return strings.Split(message, "\n"), nil
+}
+
+func (c *Controller) authenticate(password string) error {
+	lines, err := c.request("PROTOCOLINFO 1")
+	if err != nil {
+		return err
+	}
+
+	var methods []string
+	cookieFile := ""
+	for _, line := range lines {
+		if !strings.HasPrefix(line, "AUTH ") {
+			continue
+		}
+		for _, field := range splitFields(strings.TrimPrefix(line, "AUTH ")) {
+			keyAndValue := strings.SplitN(field, "=", 2)
+			if len(keyAndValue) != 2 {
+				continue
+			}
+			key, value := keyAndValue[0], keyAndValue[1]
+			switch key {
+			case "METHODS":
+				methods = strings.Split(value, ",")
+			case "COOKIEFILE":
+				cookieFile, err = strconv.Unquote(value)
+				if err != nil {
+					return errors.Wrapf(err, "invalid cookie file %s", value)
+				}
+			}
+		}
+	}
+
+	supports := func(method string) bool {
+		for _, supportedMethod := range methods {
+			if supportedMethod == method {
+				return true
+			}
+		}
+		return false
+	}
+
+	switch {
+	case supports("NULL"):
+		_, err = c.request("AUTHENTICATE")
+	case supports("HASHEDPASSWORD") && password != "":
+		_, err = c.request("AUTHENTICATE %s", strconv.Quote(password))
+	case supports("COOKIE") && cookieFile != "":
+		var cookie []byte
+		cookie, err = os.ReadFile(cookieFile)
+		if err != nil {
+			return errors.Wrap(err, "failed to read the Tor authentication cookie")
+		}
+		_, err = c.request("AUTHENTICATE %s", hex.EncodeToString(cookie))
+	case supports("HASHEDPASSWORD"):
+		return errors.New("the Tor control port requires a password. Set it with --torpassword")
+	default:
+		return errors.Errorf("the Tor control port supports none of the authentication methods %s",
+			strings.Join(methods, ","))
+	}
+	if err != nil {
+		return errors.Wrap(err, "failed to authenticate to the Tor control port")
+	}
+	return nil
+}
+
+// AddOnion creates a new ephemeral v3 onion service that forwards virtualPort to
+// target, and returns the public key of the service. The service lasts until the
+// controller is closed.
+func (c *Controller) AddOnion(virtualPort uint16, target string) ([]byte, error) {
+	lines, err := c.request("ADD_ONION NEW:ED25519-V3 Flags=DiscardPK Port=%d,%s", virtualPort, target)
+	if err != nil {
+		return nil, err
+	}
+	for _, line := range lines {
+		if !strings.HasPrefix(line, "ServiceID=") {
+			continue
+		}
+		serviceID := strings.TrimPrefix(line, "ServiceID=")
+		publicKey, err := appmessage.ParseOnionV3Host(serviceID + ".onion")
+		if err != nil {
+			return nil, errors.Wrapf(err, "Tor returned an invalid service ID %s", serviceID)
+		}
+		log.Infof("Created the onion service %s.onion", serviceID)
+		return publicKey, nil
+	}
+	return nil, errors.New("Tor didn't return the ID of the new onion service")
+}
+
+// splitFields splits a line into space separated fields, keeping quoted strings whole
+func splitFields(line string) []string {
+	var fields []string
+	current := strings.Builder{}
+	inQuotes := false
+	escaped := false
+	for _, r := range line {
+		switch {
+		case escaped:
+			escaped = false
+		case r == '\\' && inQuotes:
+			escaped = true
+		case r == '"':
+			inQuotes = !inQuotes
+		case r == ' ' && !inQuotes:
+			if current.Len() > 0 {
+				fields = append(fields, current.String())
+				current.Reset()
+			}
+			continue
+		}
+		current.WriteRune(r)
+	}
+	if current.Len() > 0 {
+		fields = append(fields, current.String())
+	}
+	return fields
+}
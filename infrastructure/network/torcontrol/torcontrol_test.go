@@ -0,0 +1,165 @@
+package torcontrol
+
+import (
+	"bufio"
+	"encoding/hex"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/Hoosat-Oy/HTND/app/appmessage"
+)
+
+const testServiceID = "pg6mmjiyjmcrsslvykfwnntlaru7p5svn6y2ymmju6nubxndf4pscryd"
+
+// fakeControlPort is a Tor control port that accepts a single connection and
+// answers PROTOCOLINFO, AUTHENTICATE and ADD_ONION
+type fakeControlPort struct {
+	listener    net.Listener
+	authMethods string
+	cookieFile  string
+	credential  string // the expected argument of AUTHENTICATE
+	commands    chan string
+}
+
+func newFakeControlPort(t *testing.T, authMethods string, cookieFile string, credential string) *fakeControlPort {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Listen: %s", err)
+	}
+	controlPort := &fakeControlPort{
+		listener:    listener,
+		authMethods: authMethods,
+		cookieFile:  cookieFile,
+		credential:  credential,
+		commands:    make(chan string, 10),
+	}
+	go controlPort.serve()
+	return controlPort
+}
+
+func (f *fakeControlPort) address() string {
+	return f.listener.Addr().String()
+}
+
+func (f *fakeControlPort) close() {
+	f.listener.Close()
+}
+
+func (f *fakeControlPort) serve() {
+	connection, err := f.listener.Accept()
+	if err != nil {
+		return
+	}
+	defer connection.Close()
+
+	reader := bufio.NewReader(connection)
+	authenticated := false
+	for {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			return
+		}
+		command := strings.TrimRight(line, "\r\n")
+		f.commands <- command
+
+		var response string
+		switch {
+		case command == "PROTOCOLINFO 1":
+			auth := "250-AUTH METHODS=" + f.authMethods
+			if f.cookieFile != "" {
+				auth += fmt.Sprintf(" COOKIEFILE=%q", f.cookieFile)
+			}
+			response = "250-PROTOCOLINFO 1\r\n" + auth + "\r\n250-VERSION Tor=\"0.4.8.10\"\r\n250 OK\r\n"
+		case strings.HasPrefix(command, "AUTHENTICATE"):
+			if strings.TrimSpace(strings.TrimPrefix(command, "AUTHENTICATE")) != f.credential {
+				response = "515 Authentication failed: Password did not match HashedControlPassword value.\r\n"
+				break
+			}
+			authenticated = true
+			response = "250 OK\r\n"
+		case !authenticated:
+			response = "514 Authentication required.\r\n"
+		case strings.HasPrefix(command, "ADD_ONION "):
+			response = "250-ServiceID=" + testServiceID + "\r\n250 OK\r\n"
+		default:
+			response = "510 Unrecognized command\r\n"
+		}
+		_, err = connection.Write([]byte(response))
+		if err != nil {
+			return
+		}
+	}
+}
+
+func TestAddOnionWithPassword(t *testing.T) {
+	controlPort := newFakeControlPort(t, "HASHEDPASSWORD", "", `"pass \"word\""`)
+	defer controlPort.close()
+
+	controller, err := Connect(controlPort.address(), `pass "word"`)
+	if err != nil {
+		t.Fatalf("Connect: %s", err)
+	}
+	defer controller.Close()
+
+	publicKey, err := controller.AddOnion(42421, "127.0.0.1:42421")
+	if err != nil {
+		t.Fatalf("AddOnion: %s", err)
+	}
+	if appmessage.OnionV3Host(publicKey) != testServiceID+".onion" {
+		t.Fatalf("Unexpected onion host. Want: %s.onion, got: %s", testServiceID, appmessage.OnionV3Host(publicKey))
+	}
+
+	expectedCommands := []string{
+		"PROTOCOLINFO 1",
+		`AUTHENTICATE "pass \"word\""`,
+		"ADD_ONION NEW:ED25519-V3 Flags=DiscardPK Port=42421,127.0.0.1:42421",
+	}
+	for _, expectedCommand := range expectedCommands {
+		command := <-controlPort.commands
+		if command != expectedCommand {
+			t.Fatalf("Unexpected command. Want: %s, got: %s", expectedCommand, command)
+		}
+	}
+}
+
+func TestConnectWithCookie(t *testing.T) {
+	cookie := []byte("0123456789abcdef0123456789abcdef")
+	cookieFile := filepath.Join(t.TempDir(), "control_auth_cookie")
+	err := os.WriteFile(cookieFile, cookie, 0600)
+	if err != nil {
+		t.Fatalf("WriteFile: %s", err)
+	}
+
+	controlPort := newFakeControlPort(t, "COOKIE,SAFECOOKIE", cookieFile, hex.EncodeToString(cookie))
+	defer controlPort.close()
+
+	controller, err := Connect(controlPort.address(), "")
+	if err != nil {
+		t.Fatalf("Connect: %s", err)
+	}
+	controller.Close()
+}
+
+func TestConnectWithWrongPassword(t *testing.T) {
+	controlPort := newFakeControlPort(t, "HASHEDPASSWORD", "", `"password"`)
+	defer controlPort.close()
+
+	_, err := Connect(controlPort.address(), "wrong")
+	if err == nil || !strings.Contains(err.Error(), "Authentication failed") {
+		t.Fatalf("Unexpected error when authenticating with a wrong password: %v", err)
+	}
+}
+
+func TestConnectWithoutPassword(t *testing.T) {
+	controlPort := newFakeControlPort(t, "HASHEDPASSWORD", "", `"password"`)
+	defer controlPort.close()
+
+	_, err := Connect(controlPort.address(), "")
+	if err == nil || !strings.Contains(err.Error(), "--torpassword") {
+		t.Fatalf("Unexpected error when the password is missing: %v", err)
+	}
+}
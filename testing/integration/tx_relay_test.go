@@ -6,8 +6,6 @@ import (
 	"testing"
 	"time"
 
-	"github.com/Hoosat-Oy/HTND/app/protocol/flowcontext"
-
 	"github.com/Hoosat-Oy/HTND/domain/consensus/utils/utxo"
 
 	"github.com/Hoosat-Oy/HTND/app/appmessage"
@@ -46,10 +44,6 @@ func TestTxRelay(t *testing.T) {
 		waitForPayeeToReceiveBlock(t, payeeBlockAddedChan)
 	}
 
-	// Sleep for `TransactionIDPropagationInterval` to make sure that our transaction will
-	// be propagated
-	time.Sleep(flowcontext.TransactionIDPropagationInterval)
-
 	msgTx := generateTx(t, secondBlock.Transactions[transactionhelper.CoinbaseTransactionIndex], payer, payee)
 	domainTransaction := appmessage.MsgTxToDomainTransaction(msgTx)
 	rpcTransaction := appmessage.DomainTransactionToRPCTransaction(domainTransaction)